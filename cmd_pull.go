@@ -248,6 +248,8 @@ func statementModifiersForPull(config *mybase.Config, instance *tengo.Instance)
 	} else {
 		mods.Flavor = instFlavor
 	}
+	mods.DefaultTableEncryption = instance.DefaultTableEncryption()
+	mods.DefaultRowFormat = instance.DefaultRowFormat()
 	// Unless user specifically wants to update partitioning clauses, apply a
 	// statement modifier to make some partitioning-related AlterClause types
 	// return an empty statement, to exclude them from being rewritten if their
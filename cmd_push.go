@@ -27,7 +27,12 @@ func init() {
 		"An exit code of 0 will be returned if the operation was fully successful; 1 if " +
 		"at least one table could not be updated due to use of unsupported features, or if " +
 		"the --dry-run option was used and differences were found; or 2+ if a fatal error " +
-		"occurred."
+		"occurred.\n\n" +
+		"Potentially destructive operations are only run if the --allow-unsafe option is " +
+		"used, or if the table is smaller than --safe-below-size. In addition to dropping " +
+		"tables or columns, this includes changing a column from NULL to NOT NULL, as " +
+		"well as re-partitioning an already-partitioned table when using " +
+		"--partitioning=modify."
 
 	cmd := mybase.NewCommand("push", summary, desc, PushHandler)
 
@@ -39,7 +44,7 @@ func init() {
 		mybase.BoolOption("lax-comments", 0, false, "When comparing tables or routines, don't modify them if they only differ by comment clauses"),
 		mybase.StringOption("alter-lock", 0, "", `Apply a LOCK clause to all ALTER TABLEs (valid values: "none", "shared", "exclusive")`),
 		mybase.StringOption("alter-algorithm", 0, "", `Apply an ALGORITHM clause to all ALTER TABLEs (valid values: "inplace", "copy", "instant", "nocopy")`),
-		mybase.StringOption("partitioning", 0, "keep", `Specify handling of partitioning status on the database side (valid values: "keep", "remove", "modify")`),
+		mybase.StringOption("partitioning", 0, "keep", `Specify handling of partitioning status on the database side (valid values: "keep", "remove", "modify"; re-partitioning with "modify" requires --allow-unsafe)`),
 	)
 
 	cmd.AddOptions("External tool",
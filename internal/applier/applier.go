@@ -54,7 +54,12 @@ type Plan struct {
 }
 
 // Run prints each statement in the plan, and also executes them if the Target's
-// configuration indicates that this is not a dry-run.
+// configuration indicates that this is not a dry-run. Statements are executed
+// individually and sequentially, without any wrapping transaction: since DDL
+// causes an implicit commit, statements that executed successfully prior to an
+// error cannot be rolled back. If an error occurs, the remaining statements are
+// skipped, and the number of skipped statements (including the failed one) is
+// returned.
 func (plan *Plan) Run(printer Printer) (skipCount int) {
 	dryRun := plan.Target.Dir.Config.GetBool("dry-run")
 	for i, stmt := range plan.Statements {
@@ -137,6 +142,9 @@ func ApplyTarget(t *Target, printer Printer) (Result, error) {
 		return result, err
 	}
 	schemaFromDir := t.SchemaFromDir()
+	for _, warning := range inheritedCharSetWarnings(t.DesiredSchema.LogicalSchema, schemaFromInstance, schemaFromDir, t.Instance.Flavor(), t.DesiredSchema.Flavor) {
+		log.Warn(warning)
+	}
 
 	if t.Dir.Config.GetBool("dry-run") {
 		log.Infof("Generating diff of %s vs %s%c*.sql", t, t.Dir, os.PathSeparator)
@@ -153,6 +161,8 @@ func ApplyTarget(t *Target, printer Printer) (Result, error) {
 		return result, ConfigError(err.Error())
 	}
 	mods.Flavor = t.Instance.Flavor()
+	mods.DefaultTableEncryption = t.Instance.DefaultTableEncryption()
+	mods.DefaultRowFormat = t.Instance.DefaultRowFormat()
 	if mods.Partitioning == tengo.PartitioningRemove {
 		// With partitioning=remove, forcibly treat all filesystem definitions as if
 		// they didn't have a partitioning clause. This is designed to aid in the
@@ -330,6 +340,36 @@ func CreatePlanForTarget(t *Target, diff *tengo.SchemaDiff, mods tengo.Statement
 	return plan, fatalErr
 }
 
+// inheritedCharSetWarnings returns a warning for each table whose default
+// character set differs between current (introspected from a database server
+// of currentFlavor) and desired (obtained by executing logicalSchema in a
+// workspace of desiredFlavor), in cases where the difference may only be caused
+// by desired inheriting the workspace's server default character set. This
+// occurs when the flavors have different server defaults, and neither the
+// table's CREATE statement nor the logical schema specifies a character set or
+// collation explicitly. No warnings are returned if either flavor is unknown.
+func inheritedCharSetWarnings(logicalSchema *fs.LogicalSchema, current, desired *tengo.Schema, currentFlavor, desiredFlavor tengo.Flavor) (warnings []string) {
+	currentDefault, desiredDefault := currentFlavor.DefaultCharSet(), desiredFlavor.DefaultCharSet()
+	if currentDefault == desiredDefault || currentDefault == "" || desiredDefault == "" {
+		return nil
+	} else if logicalSchema.CharSet != "" || logicalSchema.Collation != "" {
+		return nil
+	}
+	currentTables := current.TablesByName()
+	for _, desiredTable := range desired.Tables {
+		currentTable := currentTables[desiredTable.Name]
+		if currentTable == nil || currentTable.CharSet == desiredTable.CharSet || desiredTable.CharSet != desiredDefault {
+			continue
+		}
+		if stmt := logicalSchema.Creates[desiredTable.ObjectKey()]; stmt == nil || stmt.SpecifiesTableCharSet() {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Table %s does not specify a default character set, so it inherited %s from the workspace's server default for %s, which differs from the server default of %s for %s. Specify a character set explicitly in its CREATE TABLE, or configure default-character-set for this directory, to avoid changing this table's character set unintentionally.",
+			tengo.EscapeIdentifier(desiredTable.Name), desiredDefault, desiredFlavor.Family(), currentDefault, currentFlavor.Family()))
+	}
+	return warnings
+}
+
 // supply 1 noun if pluralized form just adds an s; otherwise supply singular
 // and plural nouns separately
 func countAndNoun(n int, nouns ...string) string {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/skeema/mybase"
@@ -73,6 +74,132 @@ func TestResultError(t *testing.T) {
 	}
 }
 
+func TestInheritedCharSetWarnings(t *testing.T) {
+	mariaFlavor, mysqlFlavor := tengo.ParseFlavor("mariadb:10.6"), tengo.ParseFlavor("mysql:8.0")
+	logicalSchema := fs.NewLogicalSchema()
+	for _, create := range []string{
+		"CREATE TABLE t1 (id int);\n",
+		"CREATE TABLE t2 (id int, name varchar(20) CHARACTER SET latin1);\n",
+		"CREATE TABLE t3 (id int) DEFAULT CHARSET=utf8mb4;\n",
+		"CREATE TABLE t4 (id int);\n",
+	} {
+		stmts, err := tengo.ParseStatementsInString(create)
+		if err != nil || len(stmts) != 1 {
+			t.Fatalf("Unexpected result parsing %q: %+v, %v", create, stmts, err)
+		}
+		if err := logicalSchema.AddStatement(stmts[0]); err != nil {
+			t.Fatalf("Unexpected error from AddStatement: %v", err)
+		}
+	}
+	newSchema := func(charSet string, tableCharSets ...string) *tengo.Schema {
+		s := &tengo.Schema{Name: "s1", CharSet: charSet}
+		for n, cs := range tableCharSets {
+			s.Tables = append(s.Tables, &tengo.Table{Name: fmt.Sprintf("t%d", n+1), CharSet: cs})
+		}
+		return s
+	}
+
+	// Tables t1 and t2 have no explicit table-level charset, and inherited
+	// utf8mb4 from a MySQL 8 workspace; t3 specifies utf8mb4 explicitly; t4 has
+	// the same charset on both sides
+	current := newSchema("latin1", "latin1", "latin1", "latin1", "utf8mb4")
+	desired := newSchema("utf8mb4", "utf8mb4", "utf8mb4", "utf8mb4", "utf8mb4")
+	warnings := inheritedCharSetWarnings(logicalSchema, current, desired, mariaFlavor, mysqlFlavor)
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, instead found %d: %v", len(warnings), warnings)
+	}
+	for n, warning := range warnings {
+		name := fmt.Sprintf("`t%d`", n+1)
+		if !strings.Contains(warning, name) || !strings.Contains(warning, "inherited utf8mb4 from the workspace's server default for mysql:8.0") {
+			t.Errorf("Unexpected warning for %s: %s", name, warning)
+		}
+	}
+
+	// No warnings if the flavors' server defaults are the same, either flavor is
+	// unknown, or the logical schema has an explicit character set
+	for _, currentFlavor := range []tengo.Flavor{tengo.ParseFlavor("mariadb:11.8"), tengo.FlavorUnknown} {
+		if warnings := inheritedCharSetWarnings(logicalSchema, current, desired, currentFlavor, mysqlFlavor); warnings != nil {
+			t.Errorf("Expected no warnings comparing %s to %s, instead found %v", currentFlavor, mysqlFlavor, warnings)
+		}
+	}
+	logicalSchema.CharSet = "utf8mb4"
+	if warnings := inheritedCharSetWarnings(logicalSchema, current, desired, mariaFlavor, mysqlFlavor); warnings != nil {
+		t.Errorf("Expected no warnings with explicit schema-level charset, instead found %v", warnings)
+	}
+}
+
+// fakeStatement is a PlannedStatement which tracks its execution in a shared
+// log, without connecting to any database.
+type fakeStatement struct {
+	stmt    string
+	fail    bool
+	execLog *[]string
+}
+
+func (fake fakeStatement) Execute() error {
+	*fake.execLog = append(*fake.execLog, fake.stmt)
+	if fake.fail {
+		return fmt.Errorf("fake error executing %s", fake.stmt)
+	}
+	return nil
+}
+
+func (fake fakeStatement) Statement() string        { return fake.stmt }
+func (fake fakeStatement) ClientState() ClientState { return ClientState{Delimiter: ";"} }
+
+type nopPrinter struct{}
+
+func (nopPrinter) Print(PlannedStatement) {}
+
+func TestPlanRunNoTransaction(t *testing.T) {
+	var execLog []string
+	plan := &Plan{
+		Target: &Target{
+			Dir: &fs.Dir{Config: mybase.SimpleConfig(map[string]string{"dry-run": "0"})},
+		},
+		Statements: []PlannedStatement{
+			fakeStatement{stmt: "CREATE TABLE t1 (id int)", execLog: &execLog},
+			fakeStatement{stmt: "ALTER TABLE t2 ADD COLUMN c int", execLog: &execLog, fail: true},
+			fakeStatement{stmt: "DROP TABLE t3", execLog: &execLog},
+		},
+	}
+
+	// DDL implicitly commits, so Run must execute each statement exactly as-is,
+	// without any BEGIN/COMMIT/ROLLBACK around them; and upon failure, it must
+	// skip the remaining statements rather than attempting a rollback of the
+	// ones that already succeeded.
+	skipCount := plan.Run(nopPrinter{})
+	if skipCount != 2 {
+		t.Errorf("Expected skipCount of 2, instead found %d", skipCount)
+	}
+	expectedLog := []string{"CREATE TABLE t1 (id int)", "ALTER TABLE t2 ADD COLUMN c int"}
+	if fmt.Sprint(execLog) != fmt.Sprint(expectedLog) {
+		t.Errorf("Unexpected execution log: expected %q, found %q", expectedLog, execLog)
+	}
+}
+
+func TestDDLStatementAtomic(t *testing.T) {
+	cases := map[string]bool{
+		"mysql:5.7":    false,
+		"mysql:8.0":    true,
+		"mariadb:10.5": false,
+		"mariadb:10.6": true,
+	}
+	for flavorString, expected := range cases {
+		inst, err := tengo.NewInstance("mysql", "root:password@tcp(127.0.0.1:3306)/")
+		if err != nil {
+			t.Fatalf("Unexpected error from NewInstance: %v", err)
+		}
+		if err := inst.SetFlavor(tengo.ParseFlavor(flavorString)); err != nil {
+			t.Fatalf("Unexpected error from SetFlavor: %v", err)
+		}
+		ddl := &DDLStatement{stmt: "ALTER TABLE foo ADD COLUMN bar int", instance: inst}
+		if actual := ddl.Atomic(); actual != expected {
+			t.Errorf("Expected Atomic() to return %t for flavor %s, instead found %t", expected, flavorString, actual)
+		}
+	}
+}
+
 func TestIntegration(t *testing.T) {
 	images := tengo.SkeemaTestImages(t)
 	suite := &ApplierIntegrationSuite{}
@@ -230,7 +230,9 @@ func getConnectParams(diff tengo.ObjectDiff, config *mybase.Config) string {
 }
 
 // Execute runs the DDL statement, either by running a SQL query against a DB,
-// or shelling out to an external program, as appropriate.
+// or shelling out to an external program, as appropriate. The statement is
+// intentionally not wrapped in a transaction, since all DDL causes an implicit
+// commit in MySQL and MariaDB.
 func (ddl *DDLStatement) Execute() error {
 	if ddl.shellOut != nil {
 		return ddl.shellOut.Run()
@@ -243,6 +245,18 @@ func (ddl *DDLStatement) Execute() error {
 	return err
 }
 
+// Atomic returns true if the DDL statement will be executed directly against a
+// database server whose flavor supports atomic DDL, meaning that a failure or
+// crash during execution of this one statement will not leave it partially
+// applied. Shell-out statements are never considered atomic, since the behavior
+// of external programs is unknown.
+// Regardless of this method's return value, DDL always causes an implicit
+// commit, so a DDLStatement is never run inside of a transaction and cannot be
+// rolled back after it completes.
+func (ddl *DDLStatement) Atomic() bool {
+	return ddl.shellOut == nil && ddl.instance.Flavor().AtomicDDL()
+}
+
 // Statement returns a string representation of ddl. If an external command is
 // in use, the returned string will be prefixed with "\!", the MySQL CLI command
 // shortcut for "system" shellout.
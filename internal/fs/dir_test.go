@@ -318,8 +318,7 @@ func TestParseDirRedundantDelimiter(t *testing.T) {
 
 func TestParseDirCreateUnsupported(t *testing.T) {
 	// This dir contains 2 normal non-ignored tables, 1 normal ignored table,
-	// 2 system-versioned tables (one of which is bitemporal), 1 create...select,
-	// and one gibberish statement.
+	// 3 create...select statements, and one gibberish statement.
 	dir, err := ParseDir("testdata/createunsupported", getValidConfig(t))
 	if err != nil {
 		t.Fatalf("Unexpected error from ParseDir: %v", err)
@@ -335,6 +334,21 @@ func TestParseDirCreateUnsupported(t *testing.T) {
 	}
 }
 
+func TestParseDirCreateSystemVersioned(t *testing.T) {
+	// This dir contains 2 system-versioned tables (one of which is bitemporal),
+	// which are supported and should be treated as normal CREATEs
+	dir, err := ParseDir("testdata/createversioned", getValidConfig(t))
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseDir: %v", err)
+	}
+	if len(dir.LogicalSchemas[0].Creates) != 2 {
+		t.Errorf("Expected 2 CREATES in the logical schema, instead found %d", len(dir.LogicalSchemas[0].Creates))
+	}
+	if len(dir.IgnorePatterns) != 0 || len(dir.UnparsedStatements) != 0 {
+		t.Errorf("Expected no IgnorePatterns or UnparsedStatements, instead found %d and %d", len(dir.IgnorePatterns), len(dir.UnparsedStatements))
+	}
+}
+
 func TestParseDirBOM(t *testing.T) {
 	// The .skeema file and tables.sql file in this dir both have a UTF8 byte-order
 	// marker prefix char, which should not interfere with the ability to parse the
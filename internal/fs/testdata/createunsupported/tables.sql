@@ -12,8 +12,8 @@ CREATE TABLE `three` (
 
 CREATE TABLE four (
 	name varchar(30)
-) with /*lol*/ SYSTEM -- hmm
-	versionING;
+) /*lol*/ select -- hmm
+	name FROM one;
 
 create table fourfour (
 	beat int default 4
@@ -24,11 +24,5 @@ create SUPERTABLE fourfourfour (
 );
 
 create TABLE five (
-	mycounter int,
-	apptime1 date,
-	apptime2 date,
-	row_start timestamp(6) as row start invisible,
-	row_end timestamp(6) as row end invisible,
-	PERIOD FOR application_time(apptime1, apptime2),
-	PERIOD FOR system_time(row_start, row_end)
-) with system versioning;
\ No newline at end of file
+	mycounter int
+) ignore SELECT id AS mycounter FROM one;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE four (
+	name varchar(30)
+) with /*lol*/ SYSTEM -- hmm
+	versionING;
+
+create TABLE five (
+	mycounter int,
+	apptime1 date,
+	apptime2 date,
+	row_start timestamp(6) as row start invisible,
+	row_end timestamp(6) as row end invisible,
+	PERIOD FOR application_time(apptime1, apptime2),
+	PERIOD FOR system_time(row_start, row_end)
+) with system versioning;
\ No newline at end of file
@@ -33,6 +33,23 @@ func characterMaxBytes(charset string) int {
 	return 1
 }
 
+// charSetCanRepresent returns true if every character which can be stored in
+// charset from can also be stored in charset to. This is the case when the
+// character sets are equivalent, when to covers all of Unicode, when to covers
+// the Unicode BMP and from is a BMP-only character set, or when to is binary
+// (since the stored bytes are retained as-is). In all other cases, false is
+// returned, even if from is a strict subset of to in practice.
+func charSetCanRepresent(from, to string) bool {
+	from, to = canonicalCharSet(from), canonicalCharSet(to)
+	switch to {
+	case from, "binary", "utf8mb4", "utf16", "utf16le", "utf32":
+		return from != "binary" || to == "binary"
+	case "utf8mb3", "ucs2":
+		return from == "ascii" || from == "latin1" || from == "utf8mb3" || from == "ucs2"
+	}
+	return from == "ascii" && to == "latin1"
+}
+
 // DefaultCollationForCharset returns the default collation for the supplied
 // character set, using the flavor of the supplied instance. If the instance's
 // flavor is MariaDB 11.2+, then this function also queries the instance's
@@ -2,29 +2,34 @@ package tengo
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 )
 
 // Column represents a single column of a table.
 type Column struct {
-	Name                string     `json:"name"`
-	Type                ColumnType `json:"type"`
-	Nullable            bool       `json:"nullable,omitempty"`
-	AutoIncrement       bool       `json:"autoIncrement,omitempty"`
-	Default             string     `json:"default,omitempty"` // Stored as an expression, i.e. quote-wrapped if string
-	OnUpdate            string     `json:"onUpdate,omitempty"`
-	GenerationExpr      string     `json:"generationExpression,omitempty"` // Only populated if generated column
-	Virtual             bool       `json:"virtual,omitempty"`
-	CharSet             string     `json:"charSet,omitempty"`       // Only populated if textual type
-	Collation           string     `json:"collation,omitempty"`     // Only populated if textual type
-	ShowCharSet         bool       `json:"showCharSet,omitempty"`   // Include CHARACTER SET in SHOW CREATE TABLE: always true if different than table default, sometimes true in other cases
-	ShowCollation       bool       `json:"showCollation,omitempty"` // Include COLLATE in SHOW CREATE TABLE: logic differs by flavor
-	Compression         string     `json:"compression,omitempty"`   // Only non-empty if using column compression in Percona Server or MariaDB
-	Comment             string     `json:"comment,omitempty"`
-	Invisible           bool       `json:"invisible,omitempty"` // True if an invisible column (MariaDB 10.3+, MySQL 8.0.23+)
-	CheckClause         string     `json:"check,omitempty"`     // Only non-empty for MariaDB inline check constraint clause
-	SpatialReferenceID  uint32     `json:"srid,omitempty"`      // Can be non-zero only for spatial types in MySQL 8+
-	HasSpatialReference bool       `json:"has_srid,omitempty"`  // True if SRID attribute present; disambiguates SRID 0 vs no SRID
+	Name                     string     `json:"name"`
+	Type                     ColumnType `json:"type"`
+	Nullable                 bool       `json:"nullable,omitempty"`
+	AutoIncrement            bool       `json:"autoIncrement,omitempty"`
+	Default                  string     `json:"default,omitempty"` // Stored as an expression, i.e. quote-wrapped if string
+	OnUpdate                 string     `json:"onUpdate,omitempty"`
+	GenerationExpr           string     `json:"generationExpression,omitempty"` // Only populated if generated column
+	Virtual                  bool       `json:"virtual,omitempty"`
+	CharSet                  string     `json:"charSet,omitempty"`       // Only populated if textual type
+	Collation                string     `json:"collation,omitempty"`     // Only populated if textual type
+	ShowCharSet              bool       `json:"showCharSet,omitempty"`   // Include CHARACTER SET in SHOW CREATE TABLE: always true if different than table default, sometimes true in other cases
+	ShowCollation            bool       `json:"showCollation,omitempty"` // Include COLLATE in SHOW CREATE TABLE: logic differs by flavor
+	Compression              string     `json:"compression,omitempty"`   // Only non-empty if using column compression in Percona Server or MariaDB
+	Comment                  string     `json:"comment,omitempty"`
+	Invisible                bool       `json:"invisible,omitempty"`                // True if an invisible column (MariaDB 10.3+, MySQL 8.0.23+)
+	CheckClause              string     `json:"check,omitempty"`                    // Only non-empty for MariaDB inline check constraint clause
+	SpatialReferenceID       uint32     `json:"srid,omitempty"`                     // Can be non-zero only for spatial types in MySQL 8+
+	HasSpatialReference      bool       `json:"has_srid,omitempty"`                 // True if SRID attribute present; disambiguates SRID 0 vs no SRID
+	SystemTime               string     `json:"systemTime,omitempty"`               // "ROW START" or "ROW END" for explicit MariaDB system-versioning period columns
+	EngineAttribute          string     `json:"engineAttribute,omitempty"`          // MySQL 8.0.21+: JSON value of ENGINE_ATTRIBUTE option
+	SecondaryEngineAttribute string     `json:"secondaryEngineAttribute,omitempty"` // MySQL 8.0.21+: JSON value of SECONDARY_ENGINE_ATTRIBUTE option
 }
 
 // Definition returns this column's definition clause, for use as part of a DDL
@@ -66,8 +71,11 @@ func (c *Column) Definition(flavor Flavor) string {
 		clauses = append(clauses, "GENERATED ALWAYS AS ("+c.GenerationExpr+") "+genKind)
 	}
 
-	// Nullability
-	if !c.Nullable {
+	// Nullability, or MariaDB 10.3+ system-versioning period column, which is
+	// always implicitly NOT NULL
+	if c.SystemTime != "" && flavor.MinMariaDB(10, 3) {
+		clauses = append(clauses, "GENERATED ALWAYS AS "+c.SystemTime)
+	} else if !c.Nullable {
 		clauses = append(clauses, "NOT NULL")
 	} else if c.Type.Base == "timestamp" {
 		// Oddly the timestamp type always displays nullability, other types never do
@@ -120,6 +128,16 @@ func (c *Column) Definition(flavor Flavor) string {
 		clauses = append(clauses, "COMMENT '"+EscapeValueForCreateTable(c.Comment)+"'")
 	}
 
+	// Engine attributes in MySQL 8.0.21+
+	if flavor.MinMySQL(8, 0, 21) {
+		if c.EngineAttribute != "" {
+			clauses = append(clauses, engineAttributeClause("ENGINE_ATTRIBUTE", c.EngineAttribute, false))
+		}
+		if c.SecondaryEngineAttribute != "" {
+			clauses = append(clauses, engineAttributeClause("SECONDARY_ENGINE_ATTRIBUTE", c.SecondaryEngineAttribute, false))
+		}
+	}
+
 	// Inline CHECK constraint (field is only ever set for MariaDB)
 	if c.CheckClause != "" {
 		clauses = append(clauses, "CHECK ("+c.CheckClause+")")
@@ -150,7 +168,8 @@ func (c *Column) Equals(other *Column) bool {
 // column was created with CHARACTER SET or COLLATION clauses that are
 // unnecessary (equal to table's default); or when comparing a table across
 // different versions of MySQL 8 (one which supports int display widths, and
-// one that removes them).
+// one that removes them); or when ENGINE_ATTRIBUTE or SECONDARY_ENGINE_ATTRIBUTE
+// JSON values only differ in formatting or key order.
 // Note that, for the purposes of this method, column comments are NOT
 // considered cosmetic. This method returns false if c and other only differ
 // by a comment.
@@ -186,23 +205,294 @@ func (c *Column) Equivalent(other *Column) bool {
 	if collationsEquivalent(c.Collation, other.Collation) {
 		selfCopy.Collation = other.Collation
 	}
+	if engineAttributesEquivalent(c.EngineAttribute, other.EngineAttribute) {
+		selfCopy.EngineAttribute = other.EngineAttribute
+	}
+	if engineAttributesEquivalent(c.SecondaryEngineAttribute, other.SecondaryEngineAttribute) {
+		selfCopy.SecondaryEngineAttribute = other.SecondaryEngineAttribute
+	}
 	return selfCopy == *other
 }
 
-func charsetsEquivalent(a, b string) bool {
-	// Account for flavor differences in how utf8mb3 is expressed
-	return (a == b) || (a == "utf8mb3" && b == "utf8") || (a == "utf8" && b == "utf8mb3")
+// EquivalentForFlavor behaves like Equivalent, but additionally tolerates
+// differences which flavor would erase upon creating the column. Column types
+// expressed using the synonyms BOOL, BOOLEAN, or SERIAL are compared in their
+// expanded forms; and if flavor omits int display widths, any difference in
+// display width (not just presence vs lack) is ignored, aside from the special
+// cases retained by the server. Default expressions which only differ in casing
+// of function names or keywords, whitespace, or a redundant CAST to the column's
+// type are also considered equivalent, if flavor supports default expressions.
+// ModifyColumn uses this method to determine if a MODIFY COLUMN clause is a
+// no-op.
+// A Column has no knowledge of its table's default character set or collation,
+// so this method cannot resolve a charset or collation which is omitted in one
+// column but not the other. Callers must supply columns whose CharSet and
+// Collation fields hold their effective values, as is always the case for
+// introspected columns; in that situation, presence vs lack of the charset and
+// collation clauses is already ignored by Equivalent. The implicit UNIQUE index
+// of a SERIAL column is also outside the scope of this method.
+func (c *Column) EquivalentForFlavor(other *Column, flavor Flavor) bool {
+	if c == nil || other == nil {
+		return c.Equivalent(other)
+	}
+	selfCopy, otherCopy := c.expandSynonyms(flavor), other.expandSynonyms(flavor)
+	if defaultsEquivalent(selfCopy, otherCopy, flavor) {
+		selfCopy.Default = otherCopy.Default
+	}
+	return selfCopy.Equivalent(otherCopy)
 }
 
-func collationsEquivalent(a, b string) bool {
-	if a == b {
+// expandSynonyms returns a copy of c with any column type synonym replaced by
+// the type that the server would actually use, as determined by
+// ParsePseudoType. If flavor omits int display widths, the copy's display width
+// is also stripped.
+func (c *Column) expandSynonyms(flavor Flavor) *Column {
+	colCopy := *c
+	if ct, serial, ok := ParsePseudoType(colCopy.Type.Base, flavor); ok {
+		colCopy.Type = ct
+		if serial {
+			colCopy.Nullable = false
+			colCopy.AutoIncrement = true
+			if colCopy.Default == "NULL" {
+				colCopy.Default = ""
+			}
+		}
+	}
+	if flavor.OmitIntDisplayWidth() {
+		colCopy.Type.StripDisplayWidth()
+	}
+	return &colCopy
+}
+
+// DefaultExpression returns the column's default expression, along with true
+// if the column's default is an expression rather than a literal value. For
+// MySQL 8.0.13+, default expressions are paren-wrapped in SHOW CREATE TABLE
+// and Column.Default; the returned expression omits these outer parens. For
+// MariaDB 10.2+, default expressions are not paren-wrapped, so anything other
+// than a quoted string, numeric, bit-value, NULL, or CURRENT_TIMESTAMP literal
+// is considered to be an expression. Other flavors do not support default
+// expressions, so false is always returned for those.
+// In all flavors, CURRENT_TIMESTAMP defaults for temporal types are treated as
+// literals, not expressions.
+func (c *Column) DefaultExpression(flavor Flavor) (expr string, ok bool) {
+	if c.Default == "" || defaultIsCurrentTimestamp(c.Default) {
+		return "", false
+	}
+	if flavor.MinMySQL(8, 0, 13) {
+		if inner, isExpr := stripDefaultExprParens(c.Default); isExpr {
+			return inner, true
+		}
+	} else if flavor.MinMariaDB(10, 2) {
+		if !defaultIsLiteral(c.Default) {
+			return c.Default, true
+		}
+	}
+	return "", false
+}
+
+// stripDefaultExprParens returns the supplied default value with its outer
+// wrapping parens removed, along with true if the input was paren-wrapped in
+// the manner of a MySQL 8 default expression.
+func stripDefaultExprParens(def string) (string, bool) {
+	if len(def) > 1 && def[0] == '(' && def[len(def)-1] == ')' {
+		return def[1 : len(def)-1], true
+	}
+	return def, false
+}
+
+// defaultIsCurrentTimestamp returns true if def is a CURRENT_TIMESTAMP default,
+// with or without fractional precision, in any casing.
+func defaultIsCurrentTimestamp(def string) bool {
+	before, after, _ := strings.Cut(def, "(")
+	if !strings.EqualFold(before, "current_timestamp") {
+		return false
+	}
+	return after == "" || strings.TrimLeft(after, "0123456789") == ")"
+}
+
+// defaultIsLiteral returns true if def is a non-expression default, as
+// formatted by MariaDB 10.2+: a quoted string, NULL, a bit-value or hex
+// literal, or a numeric literal.
+func defaultIsLiteral(def string) bool {
+	if def == "NULL" || def[0] == '\'' {
+		return true
+	}
+	if len(def) > 2 && def[1] == '\'' && (def[0] == 'b' || def[0] == 'x' || def[0] == 'X') {
+		return true
+	}
+	_, err := strconv.ParseFloat(def, 64)
+	return err == nil
+}
+
+// normalizeDefaultExpression returns a normalized form of a default
+// expression, for purposes of comparing two expressions which may only differ
+// in the casing of function names or keywords, or in whitespace. Quoted
+// strings and identifiers are left as-is. If the entire expression is wrapped
+// in a CAST to the column's own type ct, the redundant CAST is removed.
+func normalizeDefaultExpression(expr string, ct ColumnType) string {
+	tokens := TokenizeString(expr)
+	for n, token := range tokens {
+		if token[0] != '\'' && token[0] != '"' && token[0] != '`' {
+			tokens[n] = strings.ToUpper(token)
+		}
+	}
+	return strings.Join(stripRedundantCast(tokens, ct), " ")
+}
+
+// castTargetForColumnType returns the tokens of a CAST target type which is
+// exactly equivalent to the column type ct, or nil if there is no such type.
+// This intentionally only handles a conservative subset of types; notably
+// string types are excluded, since their CAST target would also need to
+// account for character sets.
+func castTargetForColumnType(ct ColumnType) []string {
+	base := strings.ToUpper(ct.Base)
+	switch ct.Base {
+	case "date", "json", "double", "float":
+		if ct.Size == 0 {
+			return []string{base}
+		}
+	case "datetime", "time":
+		if ct.Size == 0 {
+			return []string{base}
+		}
+		return []string{base, "(", strconv.Itoa(int(ct.Size)), ")"}
+	case "decimal":
+		return []string{base, "(", strconv.Itoa(int(ct.Size)), ",", strconv.Itoa(int(ct.Scale)), ")"}
+	}
+	return nil
+}
+
+// stripRedundantCast examines upper-cased expression tokens. If the tokens
+// consist entirely of a CAST to the column type ct, the tokens of the inner
+// expression are returned. Otherwise, tokens is returned unchanged.
+func stripRedundantCast(tokens []string, ct ColumnType) []string {
+	if len(tokens) < 6 || tokens[0] != "CAST" || tokens[1] != "(" || tokens[len(tokens)-1] != ")" {
+		return tokens
+	}
+	target := castTargetForColumnType(ct)
+	if target == nil {
+		return tokens
+	}
+	// Find the AS at the CAST's own nesting level, and confirm the CAST's closing
+	// paren is the final token
+	depth, asPos := 0, -1
+	for n := 1; n < len(tokens); n++ {
+		switch tokens[n] {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth == 0 && n < len(tokens)-1 {
+				return tokens
+			}
+		case "AS":
+			if depth == 1 {
+				asPos = n
+			}
+		}
+	}
+	if asPos < 3 || !slices.Equal(tokens[asPos+1:len(tokens)-1], target) {
+		return tokens
+	}
+	return tokens[2:asPos]
+}
+
+// defaultsEquivalent returns true if a and b have identical defaults, or if
+// both have default expressions (as determined by Column.DefaultExpression for
+// flavor) which only differ in casing of function names or keywords,
+// whitespace, or a redundant CAST to a's column type.
+func defaultsEquivalent(a, b *Column, flavor Flavor) bool {
+	if a.Default == b.Default {
 		return true
 	}
-	// Account for flavor differences in how utf8mb3's collations are expressed
-	aCharset, aRest, _ := strings.Cut(a, "_")
-	bCharset, bRest, _ := strings.Cut(b, "_")
-	if aRest != bRest {
+	aExpr, aIsExpr := a.DefaultExpression(flavor)
+	bExpr, bIsExpr := b.DefaultExpression(flavor)
+	if !aIsExpr || !bIsExpr {
+		return false
+	}
+	return normalizeDefaultExpression(aExpr, a.Type) == normalizeDefaultExpression(bExpr, a.Type)
+}
+
+// collationOnlyChange returns true if c and other have equivalent character
+// sets but different collations, and do not have any other functional
+// differences.
+func (c *Column) collationOnlyChange(other *Column) bool {
+	if c == nil || other == nil || !charsetsEquivalent(c.CharSet, other.CharSet) || collationsEquivalent(c.Collation, other.Collation) {
+		return false
+	}
+	selfCopy := *c
+	selfCopy.Collation = other.Collation
+	return selfCopy.Equivalent(other)
+}
+
+// charSetOnlyChange returns true if c and other differ in their character set
+// and/or collation, and do not have any other functional differences.
+func (c *Column) charSetOnlyChange(other *Column) bool {
+	if c == nil || other == nil || (charsetsEquivalent(c.CharSet, other.CharSet) && collationsEquivalent(c.Collation, other.Collation)) {
+		return false
+	}
+	selfCopy := *c
+	selfCopy.CharSet, selfCopy.Collation = other.CharSet, other.Collation
+	return selfCopy.Equivalent(other)
+}
+
+// generatedStorageChange returns true if c and other are both generated
+// columns, but one is VIRTUAL and the other is STORED.
+func (c *Column) generatedStorageChange(other *Column) bool {
+	if c == nil || other == nil || c.GenerationExpr == "" || other.GenerationExpr == "" {
+		return false
+	}
+	return c.Virtual != other.Virtual
+}
+
+// HasZeroTimestampDefault returns true if c is a TIMESTAMP column with a
+// zero-date default value, for example from a legacy DEFAULT 0 clause.
+func (c *Column) HasZeroTimestampDefault() bool {
+	if c.Type.Base != "timestamp" {
+		return false
+	}
+	value, ok := strings.CutPrefix(c.Default, "'0000-00-00 00:00:00")
+	if !ok || !strings.HasSuffix(value, "'") {
+		return false
+	}
+	fraction := strings.TrimSuffix(value, "'")
+	return fraction == "" || strings.Trim(fraction, "0") == "."
+}
+
+// zeroTimestampDefaultChange returns true if c and other are NOT NULL TIMESTAMP
+// columns which only differ in that one has a zero-date default and the other
+// has no default at all. When explicit_defaults_for_timestamp is disabled, the
+// server implicitly adds a zero-date default to such columns (aside from the
+// first TIMESTAMP column in the table), so this difference can arise merely
+// from comparing servers with different settings.
+func (c *Column) zeroTimestampDefaultChange(other *Column) bool {
+	if c == nil || other == nil || c.Nullable || other.Nullable {
+		return false
+	} else if !(c.HasZeroTimestampDefault() && other.Default == "") && !(other.HasZeroTimestampDefault() && c.Default == "") {
+		return false
+	}
+	selfCopy := *c
+	selfCopy.Default = other.Default
+	return selfCopy.Equivalent(other)
+}
+
+// spatialReferenceChange returns true if c and other differ in presence or
+// value of an SRID attribute.
+func (c *Column) spatialReferenceChange(other *Column) bool {
+	if c == nil || other == nil {
 		return false
 	}
-	return (aCharset == "utf8" && bCharset == "utf8mb3") || (aCharset == "utf8mb3" && bCharset == "utf8")
+	return c.HasSpatialReference != other.HasSpatialReference || c.SpatialReferenceID != other.SpatialReferenceID
+}
+
+// charsetsEquivalent returns true if a and b are the same character set,
+// accounting for flavor differences in how utf8mb3 is expressed. This is only
+// used for comparison purposes; rendering is unaffected.
+func charsetsEquivalent(a, b string) bool {
+	return canonicalCharSet(a) == canonicalCharSet(b)
+}
+
+// collationsEquivalent returns true if a and b are the same collation,
+// accounting for flavor differences in how utf8mb3's collations are expressed.
+func collationsEquivalent(a, b string) bool {
+	return canonicalCollation(a) == canonicalCollation(b)
 }
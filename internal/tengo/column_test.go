@@ -1,6 +1,7 @@
 package tengo
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -102,3 +103,384 @@ func TestColumnEquivalent(t *testing.T) {
 	*a, *b = *b, *a
 	assertEquivalent(true)
 }
+
+func TestColumnEquivalentForFlavor(t *testing.T) {
+	mysql57, mysql80 := ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.32")
+	cases := []struct {
+		a, b        Column
+		expected57  bool // expected result for mysql:5.7, and also FlavorUnknown
+		expected80  bool // expected result for mysql:8.0.32
+		description string
+	}{
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("int(10) unsigned")},
+			b:           Column{Name: "c", Type: ParseColumnType("int(11) unsigned")},
+			expected57:  false,
+			expected80:  true,
+			description: "int display width change",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("int(10) unsigned")},
+			b:           Column{Name: "c", Type: ParseColumnType("int unsigned")},
+			expected57:  true,
+			expected80:  true,
+			description: "int display width presence",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("tinyint(1)")},
+			b:           Column{Name: "c", Type: ParseColumnType("tinyint(2)")},
+			expected57:  false,
+			expected80:  false,
+			description: "tinyint(1) display width retained",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("bool")},
+			b:           Column{Name: "c", Type: ParseColumnType("tinyint(1)")},
+			expected57:  true,
+			expected80:  true,
+			description: "bool synonym",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("boolean"), Default: "NULL", Nullable: true},
+			b:           Column{Name: "c", Type: ParseColumnType("tinyint(4)"), Default: "NULL", Nullable: true},
+			expected57:  false,
+			expected80:  false,
+			description: "boolean vs other tinyint",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("serial")},
+			b:           Column{Name: "c", Type: ParseColumnType("bigint(20) unsigned"), AutoIncrement: true},
+			expected57:  true,
+			expected80:  true,
+			description: "serial synonym",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("serial")},
+			b:           Column{Name: "c", Type: ParseColumnType("bigint unsigned"), AutoIncrement: true},
+			expected57:  true,
+			expected80:  true,
+			description: "serial synonym without display width",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("serial")},
+			b:           Column{Name: "c", Type: ParseColumnType("bigint(20) unsigned")},
+			expected57:  false,
+			expected80:  false,
+			description: "serial vs non-auto-increment bigint",
+		},
+		{
+			a:           Column{Name: "c", Type: ParseColumnType("varchar(20)"), CharSet: "utf8", Collation: "utf8_general_ci"},
+			b:           Column{Name: "c", Type: ParseColumnType("varchar(20)"), CharSet: "utf8mb3", Collation: "utf8mb3_general_ci", ShowCharSet: true},
+			expected57:  true,
+			expected80:  true,
+			description: "utf8 vs utf8mb3 with explicit charset clause",
+		},
+	}
+	for _, tc := range cases {
+		for _, flavor := range []Flavor{FlavorUnknown, mysql57, mysql80} {
+			expected := tc.expected57
+			if flavor == mysql80 {
+				expected = tc.expected80
+			}
+			if actual := tc.a.EquivalentForFlavor(&tc.b, flavor); actual != expected {
+				t.Errorf("%s: expected EquivalentForFlavor(%s) to return %t, instead found %t", tc.description, flavor, expected, actual)
+			} else if actual = tc.b.EquivalentForFlavor(&tc.a, flavor); actual != expected {
+				t.Errorf("%s: expected reversed EquivalentForFlavor(%s) to return %t, instead found %t", tc.description, flavor, expected, actual)
+			}
+
+			// Confirm ModifyColumn.Clause reaches the same verdict
+			mc := ModifyColumn{OldColumn: &tc.a, NewColumn: &tc.b}
+			if clause := mc.Clause(StatementModifiers{Flavor: flavor}); (clause == "") != expected {
+				t.Errorf("%s: ModifyColumn.Clause with %s returned %q, inconsistent with expected equivalence %t", tc.description, flavor, clause, expected)
+			}
+		}
+	}
+
+	// Nil handling matches Equivalent
+	var nilCol *Column
+	if !nilCol.EquivalentForFlavor(nil, mysql80) {
+		t.Error("Expected two nil columns to be equivalent")
+	}
+	if nilCol.EquivalentForFlavor(&cases[0].a, mysql80) || cases[0].a.EquivalentForFlavor(nil, mysql80) {
+		t.Error("Expected nil and non-nil columns to not be equivalent")
+	}
+}
+
+func TestColumnCollationOnlyChange(t *testing.T) {
+	a := &Column{
+		Name:          "col",
+		Type:          ParseColumnType("varchar(20)"),
+		CharSet:       "utf8mb4",
+		Collation:     "utf8mb4_0900_ai_ci",
+		Default:       "'x'",
+		ShowCharSet:   true,
+		ShowCollation: true,
+	}
+	b := &Column{}
+	*b = *a
+	b.Collation = "utf8mb4_0900_as_cs"
+	if !a.collationOnlyChange(b) || !b.collationOnlyChange(a) {
+		t.Error("Expected collationOnlyChange to return true, but it did not")
+	}
+	mc := ModifyColumn{OldColumn: a, NewColumn: b}
+	expected := "MODIFY COLUMN `col` varchar(20) COLLATE utf8mb4_0900_as_cs NOT NULL DEFAULT 'x'"
+	if clause := mc.Clause(StatementModifiers{}); clause != expected {
+		t.Errorf("Expected clause %q, instead found %q", expected, clause)
+	}
+	expected = "MODIFY COLUMN `col` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs NOT NULL DEFAULT 'x'"
+	if clause := mc.Clause(StatementModifiers{StrictColumnDefinition: true}); clause != expected {
+		t.Errorf("With StrictColumnDefinition, expected clause %q, instead found %q", expected, clause)
+	}
+
+	// Any additional change means it is no longer a collation-only change
+	b.Type = ParseColumnType("varchar(30)")
+	if a.collationOnlyChange(b) {
+		t.Error("Expected collationOnlyChange to return false due to type change, but it did not")
+	}
+	b.Type = a.Type
+	b.CharSet, b.Collation = "latin1", "latin1_bin"
+	if a.collationOnlyChange(b) {
+		t.Error("Expected collationOnlyChange to return false due to charset change, but it did not")
+	}
+	if a.collationOnlyChange(a) {
+		t.Error("Expected collationOnlyChange to return false for identical columns, but it did not")
+	}
+}
+
+func TestColumnZeroTimestampDefault(t *testing.T) {
+	cases := map[string]bool{
+		"timestamp|'0000-00-00 00:00:00'":        true,
+		"timestamp(3)|'0000-00-00 00:00:00.000'": true,
+		"timestamp|'0000-00-00 00:00:01'":        false,
+		"timestamp|CURRENT_TIMESTAMP":            false,
+		"timestamp|":                             false,
+		"datetime|'0000-00-00 00:00:00'":         false,
+	}
+	for input, expected := range cases {
+		typ, def, _ := strings.Cut(input, "|")
+		col := &Column{Name: "ts", Type: ParseColumnType(typ), Default: def}
+		if actual := col.HasZeroTimestampDefault(); actual != expected {
+			t.Errorf("Expected HasZeroTimestampDefault to return %t for %s, instead found %t", expected, input, actual)
+		}
+	}
+
+	a := &Column{Name: "ts", Type: ParseColumnType("timestamp"), Default: "'0000-00-00 00:00:00'"}
+	b := &Column{Name: "ts", Type: ParseColumnType("timestamp")}
+	if !a.zeroTimestampDefaultChange(b) || !b.zeroTimestampDefaultChange(a) {
+		t.Error("Expected zeroTimestampDefaultChange to return true, but it did not")
+	}
+	mc := ModifyColumn{OldColumn: a, NewColumn: b}
+	if clause := mc.Clause(StatementModifiers{}); clause != "MODIFY COLUMN `ts` timestamp NOT NULL" {
+		t.Errorf("Unexpected clause: %q", clause)
+	}
+	if clause := mc.Clause(StatementModifiers{LaxZeroTimestamps: true}); clause != "" {
+		t.Errorf("With LaxZeroTimestamps, expected blank clause, instead found %q", clause)
+	}
+	mc.PositionFirst = true
+	if clause := mc.Clause(StatementModifiers{LaxZeroTimestamps: true}); clause != "MODIFY COLUMN `ts` timestamp NOT NULL FIRST" {
+		t.Errorf("With LaxZeroTimestamps and a position change, unexpected clause %q", clause)
+	}
+
+	// Any additional change means it is no longer just a zero default change
+	b.Type = ParseColumnType("timestamp(3)")
+	if a.zeroTimestampDefaultChange(b) {
+		t.Error("Expected zeroTimestampDefaultChange to return false due to type change, but it did not")
+	}
+	b.Type = a.Type
+	b.Default = "CURRENT_TIMESTAMP"
+	if a.zeroTimestampDefaultChange(b) {
+		t.Error("Expected zeroTimestampDefaultChange to return false due to non-zero default, but it did not")
+	}
+	a.Nullable, b.Nullable, b.Default = true, true, ""
+	if a.zeroTimestampDefaultChange(b) {
+		t.Error("Expected zeroTimestampDefaultChange to return false for nullable columns, but it did not")
+	}
+}
+
+func TestColumnEquivalentDefaultExpression(t *testing.T) {
+	mysql80 := ParseFlavor("mysql:8.0.32")
+	a := &Column{
+		Name:     "col",
+		Type:     ParseColumnType("varchar(36)"),
+		Default:  "(uuid())",
+		Nullable: true,
+	}
+	b := &Column{}
+	*b = *a
+	cases := map[string]bool{
+		"(UUID())":               true,
+		"(Uuid( ))":              true,
+		"(uuid_short())":         false,
+		"uuid()":                 false,
+		"'(uuid())'":             false,
+		"(concat(`d`,'x'))":      false,
+		"(CONCAT(`d`, 'x'))":     false,
+		"(current_timestamp())":  false,
+		"(CURRENT_TIMESTAMP())":  false,
+		"CURRENT_TIMESTAMP":      false,
+		"(uuid() /* comment */)": true,
+	}
+	for input, expected := range cases {
+		b.Default = input
+		if actual := a.EquivalentForFlavor(b, mysql80); actual != expected {
+			t.Errorf("Expected EquivalentForFlavor to return %t for default %s vs %s, instead found %t", expected, a.Default, b.Default, actual)
+		}
+	}
+
+	// String literals must remain case-sensitive
+	a.Default, b.Default = "(concat(`d`,'x'))", "(CONCAT(`d`,'X'))"
+	if a.EquivalentForFlavor(b, mysql80) {
+		t.Errorf("Expected defaults %s and %s to not be equivalent", a.Default, b.Default)
+	}
+	b.Default = "(CONCAT(`d`,'x'))"
+	if !a.EquivalentForFlavor(b, mysql80) {
+		t.Errorf("Expected defaults %s and %s to be equivalent", a.Default, b.Default)
+	}
+
+	// JSON default expressions are normalized the same way
+	a.Type, b.Type = ParseColumnType("json"), ParseColumnType("json")
+	a.Default, b.Default = "(json_array())", "(JSON_ARRAY( ))"
+	if !a.EquivalentForFlavor(b, mysql80) {
+		t.Errorf("Expected defaults %s and %s to be equivalent", a.Default, b.Default)
+	}
+	b.Default = "(json_object())"
+	if a.EquivalentForFlavor(b, mysql80) {
+		t.Errorf("Expected defaults %s and %s to not be equivalent", a.Default, b.Default)
+	}
+
+	// Current-timestamp literal handling must be unaffected
+	a.Type, b.Type = ParseColumnType("timestamp"), ParseColumnType("timestamp")
+	a.Default, b.Default = "CURRENT_TIMESTAMP", "current_timestamp()"
+	if a.EquivalentForFlavor(b, mysql80) {
+		t.Errorf("Expected defaults %s and %s to not be equivalent", a.Default, b.Default)
+	}
+
+	// Normalization only applies in flavors which support default expressions.
+	// MariaDB expressions lack the outer parens, and are still normalized.
+	a.Type, b.Type = ParseColumnType("varchar(36)"), ParseColumnType("varchar(36)")
+	a.Default, b.Default = "(uuid())", "(UUID())"
+	for _, flavor := range []Flavor{FlavorUnknown, ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.12")} {
+		if a.EquivalentForFlavor(b, flavor) {
+			t.Errorf("Expected defaults %s and %s to not be equivalent in flavor %s", a.Default, b.Default, flavor)
+		}
+	}
+	if a.Equivalent(b) {
+		t.Errorf("Expected defaults %s and %s to not be equivalent without a flavor", a.Default, b.Default)
+	}
+	a.Default, b.Default = "uuid()", "UUID( )"
+	if !a.EquivalentForFlavor(b, ParseFlavor("mariadb:10.6")) {
+		t.Errorf("Expected defaults %s and %s to be equivalent in MariaDB", a.Default, b.Default)
+	} else if a.EquivalentForFlavor(b, mysql80) {
+		t.Errorf("Expected defaults %s and %s to not be equivalent in MySQL", a.Default, b.Default)
+	}
+}
+
+func TestColumnEquivalentDefaultExpressionCast(t *testing.T) {
+	cases := []struct {
+		colType  string
+		a, b     string
+		expected bool
+	}{
+		{"date", "(curdate())", "(cast(curdate() as date))", true},
+		{"date", "(CAST(now() AS DATE))", "(now())", true},
+		{"date", "(cast(now() as datetime))", "(now())", false},
+		{"datetime(3)", "(cast(now(3) as datetime(3)))", "(now(3))", true},
+		{"datetime(3)", "(cast(now(3) as datetime))", "(now(3))", false},
+		{"decimal(10,2)", "(cast((rand() * 100) as decimal(10,2)))", "((rand() * 100))", true},
+		{"decimal(10,2)", "(cast((rand() * 100) as decimal(10,3)))", "((rand() * 100))", false},
+		{"json", "(cast('[]' as json))", "('[]')", true},
+		{"varchar(20)", "(cast(uuid() as char(20)))", "(uuid())", false},
+		{"date", "(cast(now() as date) + interval 1 day)", "(now() + interval 1 day)", false},
+		{"date", "(cast(now() as date))", "cast(now() as date)", false},
+	}
+	for _, tc := range cases {
+		a := &Column{Name: "col", Type: ParseColumnType(tc.colType), Default: tc.a, Nullable: true}
+		b := &Column{Name: "col", Type: ParseColumnType(tc.colType), Default: tc.b, Nullable: true}
+		if actual := a.EquivalentForFlavor(b, ParseFlavor("mysql:8.0.32")); actual != tc.expected {
+			t.Errorf("Expected EquivalentForFlavor to return %t for %s defaults %s vs %s, instead found %t", tc.expected, tc.colType, tc.a, tc.b, actual)
+		}
+	}
+}
+
+func TestColumnDefaultExpression(t *testing.T) {
+	mysql8013 := ParseFlavor("mysql:8.0.13")
+	mysql57 := ParseFlavor("mysql:5.7")
+	maria102 := ParseFlavor("mariadb:10.2")
+	cases := []struct {
+		flavor       Flavor
+		defaultValue string
+		expectedExpr string
+		expectedOK   bool
+	}{
+		{mysql8013, "(uuid())", "uuid()", true},
+		{mysql8013, "(`a` * `a`)", "`a` * `a`", true},
+		{mysql8013, "'hello'", "", false},
+		{mysql8013, "NULL", "", false},
+		{mysql8013, "CURRENT_TIMESTAMP", "", false},
+		{mysql8013, "CURRENT_TIMESTAMP(4)", "", false},
+		{mysql8013, "", "", false},
+		{mysql57, "(uuid())", "", false},
+		{maria102, "uuid()", "uuid()", true},
+		{maria102, "(`a` * `a`)", "(`a` * `a`)", true},
+		{maria102, "current_timestamp()", "", false},
+		{maria102, "current_timestamp(2)", "", false},
+		{maria102, "'hello'", "", false},
+		{maria102, "NULL", "", false},
+		{maria102, "1", "", false},
+		{maria102, "-1.5", "", false},
+		{maria102, "b'101'", "", false},
+	}
+	for _, c := range cases {
+		col := &Column{Name: "col", Default: c.defaultValue}
+		expr, ok := col.DefaultExpression(c.flavor)
+		if expr != c.expectedExpr || ok != c.expectedOK {
+			t.Errorf("Unexpected return from DefaultExpression for default %q in flavor %s: expected %q,%t; found %q,%t", c.defaultValue, c.flavor, c.expectedExpr, c.expectedOK, expr, ok)
+		}
+	}
+}
+
+func TestColumnDefinitionNullability(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0")
+	cases := []struct {
+		col      Column
+		expected string
+	}{
+		{Column{Name: "j", Type: ParseColumnType("json"), Nullable: true, Default: "NULL"}, "`j` json DEFAULT NULL"},
+		{Column{Name: "j", Type: ParseColumnType("json")}, "`j` json NOT NULL"},
+		{Column{Name: "g", Type: ParseColumnType("geometry"), Nullable: true, Default: "NULL"}, "`g` geometry DEFAULT NULL"},
+		{Column{Name: "g", Type: ParseColumnType("geometry"), HasSpatialReference: true, SpatialReferenceID: 4326}, "`g` geometry NOT NULL /*!80003 SRID 4326 */"},
+		{Column{Name: "p", Type: ParseColumnType("point"), Nullable: true, Default: "NULL", HasSpatialReference: true}, "`p` point /*!80003 SRID 0 */ DEFAULT NULL"},
+		{Column{Name: "p", Type: ParseColumnType("point")}, "`p` point NOT NULL"},
+	}
+	for _, c := range cases {
+		if actual := c.col.Definition(flavor); actual != c.expected {
+			t.Errorf("Unexpected result from Definition(): expected %q, found %q", c.expected, actual)
+		}
+	}
+
+	// Changing only the nullability of a JSON or spatial column must result in a
+	// non-empty MODIFY COLUMN clause
+	for n := 0; n < len(cases); n += 2 {
+		mc := ModifyColumn{OldColumn: &cases[n].col, NewColumn: &cases[n+1].col}
+		if cases[n].col.Equivalent(&cases[n+1].col) || mc.Clause(StatementModifiers{Flavor: flavor}) == "" {
+			t.Errorf("Nullability change for column %s unexpectedly ignored", cases[n].col.Name)
+		}
+	}
+}
+
+func TestColumnDefinitionGenerated(t *testing.T) {
+	stored := Column{Name: "doubled", Type: ParseColumnType("int"), Nullable: true, GenerationExpr: "`id` * 2"}
+	virtual := stored
+	virtual.Virtual = true
+
+	// MariaDB's PERSISTENT is a synonym for STORED, and STORED is always used in
+	// SHOW CREATE TABLE, so rendering does not vary by flavor
+	for _, flavor := range []Flavor{ParseFlavor("mysql:8.0"), ParseFlavor("mariadb:10.6")} {
+		if actual, expected := stored.Definition(flavor), "`doubled` int GENERATED ALWAYS AS (`id` * 2) STORED"; actual != expected {
+			t.Errorf("Unexpected result from Definition() in %s: expected %q, found %q", flavor, expected, actual)
+		}
+		if actual, expected := virtual.Definition(flavor), "`doubled` int GENERATED ALWAYS AS (`id` * 2) VIRTUAL"; actual != expected {
+			t.Errorf("Unexpected result from Definition() in %s: expected %q, found %q", flavor, expected, actual)
+		}
+	}
+}
@@ -55,6 +55,24 @@ func ParseColumnType(input string) (ct ColumnType) {
 	return ct
 }
 
+// ParsePseudoType converts a data type alias into the ColumnType that the
+// server expands it to in SHOW CREATE TABLE: BOOL and BOOLEAN become
+// tinyint(1), and SERIAL becomes an unsigned bigint. The serial return value
+// is true for SERIAL, which additionally implies NOT NULL AUTO_INCREMENT UNIQUE.
+// If input is not a pseudo-type, ok is false. Matching is case-insensitive.
+func ParsePseudoType(input string, flavor Flavor) (ct ColumnType, serial, ok bool) {
+	switch strings.ToLower(input) {
+	case "bool", "boolean":
+		return ParseColumnType("tinyint(1)"), false, true
+	case "serial":
+		if flavor.OmitIntDisplayWidth() {
+			return ParseColumnType("bigint unsigned"), true, true
+		}
+		return ParseColumnType("bigint(20) unsigned"), true, true
+	}
+	return ColumnType{}, false, false
+}
+
 func (ct ColumnType) String() string {
 	// Only permit ParseColumnType construction; otherwise, equality comparisons
 	// may be incorrect due to lack of cached str value.
@@ -119,6 +137,40 @@ func (ct ColumnType) hasDisplayWidth() bool {
 	return ct.Size > 0 && (ct.Integer() || ct.Base == "year")
 }
 
+// DefaultPermitted returns true if flavor permits the column type to have a
+// non-NULL default value. In MySQL, BLOB, TEXT, JSON, and spatial types cannot
+// have literal defaults, and may only have default expressions in MySQL
+// 8.0.13+. MariaDB 10.2+ permits defaults for these types. For all other
+// types, this method returns true regardless of flavor.
+func (ct ColumnType) DefaultPermitted(flavor Flavor) bool {
+	if !ct.lobOrSpatial() || flavor.MinMariaDB(10, 2) {
+		return true
+	}
+	return flavor.MinMySQL(8, 0, 13)
+}
+
+// lobOrSpatial returns true if ct is a BLOB, TEXT, JSON, or spatial type.
+func (ct ColumnType) lobOrSpatial() bool {
+	if strings.HasSuffix(ct.Base, "blob") || strings.HasSuffix(ct.Base, "text") {
+		return true
+	}
+	switch ct.Base {
+	case "json", "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		return true
+	}
+	return false
+}
+
+// hasCharSet returns true if ct is a textual type, which has a character set
+// and collation.
+func (ct ColumnType) hasCharSet() bool {
+	switch ct.Base {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return true
+	}
+	return false
+}
+
 // StringMaxBytes returns the maximum number of bytes that can be stored in
 // this column type, if it is a string-type and has the supplied charset.
 // If ct is not a string type, 0,false is returned.
@@ -165,23 +217,35 @@ func (ct ColumnType) BinaryMaxBytes() (maxBytes uint64, ok bool) {
 
 // Equivalent returns true if the types are identical. It also returns true if
 // both Base values are integer or year types, and one has a display width while the
-// other does not.
+// other does not, as occurs when comparing MySQL 8.0.19+ to older flavors.
+// However, display width remains meaningful for tinyint(1) and zerofill types,
+// since MySQL 8.0.19+ retains it in those cases.
 func (ct ColumnType) Equivalent(other ColumnType) bool {
 	if ct.Base != other.Base || ct.Unsigned != other.Unsigned || ct.Zerofill != other.Zerofill || ct.Scale != other.Scale || ct.values != other.values {
 		return false
 	}
 	if ct.Size != other.Size {
+		if ct.keepsDisplayWidth() || other.keepsDisplayWidth() {
+			return false
+		}
 		return ct.hasDisplayWidth() != other.hasDisplayWidth()
 	}
 	return true
 }
 
+// keepsDisplayWidth returns true if ct's display width is retained by MySQL
+// 8.0.19+: signed tinyint(1), commonly used for booleans, and any zerofill
+// integer type.
+func (ct ColumnType) keepsDisplayWidth() bool {
+	isBool := ct.Base == "tinyint" && ct.Size == 1 && !ct.Unsigned
+	return ct.hasDisplayWidth() && (ct.Zerofill || isBool)
+}
+
 // StripDisplayWidth mutates ct to remove any integer or year display width.
 // As a special case, display width is not stripped from tinyint(1), nor from
 // zerofill integers; this matches MySQL 8.0.19+ behavior.
 func (ct *ColumnType) StripDisplayWidth() (didStrip bool) {
-	isBool := ct.Base == "tinyint" && ct.Size == 1 && !ct.Unsigned
-	if ct.hasDisplayWidth() && !ct.Zerofill && !isBool {
+	if ct.hasDisplayWidth() && !ct.keepsDisplayWidth() {
 		ct.Size = 0
 		ct.str = ct.generatedString()
 		return true
@@ -55,6 +55,32 @@ func TestParseColumnType(t *testing.T) {
 	}
 }
 
+func TestParsePseudoType(t *testing.T) {
+	mysql57, mysql8 := ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.32")
+	cases := []struct {
+		input    string
+		flavor   Flavor
+		expected string
+		serial   bool
+	}{
+		{"bool", mysql57, "tinyint(1)", false},
+		{"BOOLEAN", mysql8, "tinyint(1)", false},
+		{"SERIAL", mysql57, "bigint(20) unsigned", true},
+		{"serial", mysql8, "bigint unsigned", true},
+	}
+	for _, c := range cases {
+		ct, serial, ok := ParsePseudoType(c.input, c.flavor)
+		if !ok || ct.String() != c.expected || serial != c.serial {
+			t.Errorf("Unexpected return from ParsePseudoType(%q, %s): %q, %t, %t", c.input, c.flavor, ct, serial, ok)
+		}
+	}
+	for _, input := range []string{"tinyint(1)", "bigint unsigned", "int", "bit"} {
+		if _, _, ok := ParsePseudoType(input, mysql8); ok {
+			t.Errorf("Expected ParsePseudoType(%q) to return ok=false, but it did not", input)
+		}
+	}
+}
+
 func TestColumnTypeIntegerRange(t *testing.T) {
 	cases := []struct {
 		input     string
@@ -114,6 +140,44 @@ func TestColumnTypeStripDisplayWidth(t *testing.T) {
 	}
 }
 
+func TestColumnTypeEquivalentDisplayWidth(t *testing.T) {
+	// Simulate the same column types being introspected from a flavor that
+	// includes int display widths vs one that omits them
+	oldFlavor, newFlavor := ParseFlavor("mysql:8.0.18"), ParseFlavor("mysql:8.0.19")
+	if oldFlavor.OmitIntDisplayWidth() || !newFlavor.OmitIntDisplayWidth() {
+		t.Fatal("Test assumptions about OmitIntDisplayWidth are incorrect")
+	}
+	for _, input := range []string{"int(11)", "int(10) unsigned", "bigint(20)", "bigint(20) unsigned", "tinyint(1)", "tinyint(4)", "int(5) unsigned zerofill", "year(4)"} {
+		oldType, newType := ParseColumnType(input), ParseColumnType(input)
+		newType.StripDisplayWidth()
+		if !oldType.Equivalent(newType) || !newType.Equivalent(oldType) {
+			t.Errorf("Expected %q (%s) to be equivalent to %q (%s), but it was not", oldType, oldFlavor, newType, newFlavor)
+		}
+	}
+
+	// Display width remains meaningful for tinyint(1) and zerofill
+	cases := []struct {
+		a, b       string
+		equivalent bool
+	}{
+		{"int(11)", "int(5)", false},
+		{"int(11)", "bigint", false},
+		{"tinyint(1)", "tinyint", false},
+		{"tinyint(1)", "tinyint(4)", false},
+		{"tinyint(1) unsigned", "tinyint unsigned", true},
+		{"tinyint(2)", "tinyint", true},
+		{"int(5) unsigned zerofill", "int unsigned zerofill", false},
+		{"int(5) unsigned zerofill", "int(8) unsigned zerofill", false},
+		{"int(5) unsigned zerofill", "int(5) unsigned", false},
+	}
+	for _, c := range cases {
+		a, b := ParseColumnType(c.a), ParseColumnType(c.b)
+		if a.Equivalent(b) != c.equivalent || b.Equivalent(a) != c.equivalent {
+			t.Errorf("Expected Equivalent on %q vs %q to return %t, but it did not", a, b, c.equivalent)
+		}
+	}
+}
+
 func TestColumnTypeValues(t *testing.T) {
 	cases := map[string][]string{
 		"enum('a','b','c')":        {"a", "b", "c"},
@@ -140,3 +204,31 @@ func TestColumnTypeValues(t *testing.T) {
 		}
 	}
 }
+
+func TestColumnTypeDefaultPermitted(t *testing.T) {
+	mysql57, mysql8012, mysql8013 := ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.12"), ParseFlavor("mysql:8.0.13")
+	maria101, maria102 := ParseFlavor("mariadb:10.1"), ParseFlavor("mariadb:10.2")
+	cases := []struct {
+		colType  string
+		flavor   Flavor
+		expected bool
+	}{
+		{"int(10) unsigned", mysql57, true},
+		{"varchar(20)", mysql8012, true},
+		{"text", mysql57, false},
+		{"mediumblob", mysql8012, false},
+		{"json", mysql8012, false},
+		{"point", mysql57, false},
+		{"text", mysql8013, true},
+		{"json", mysql8013, true},
+		{"geometry", mysql8013, true},
+		{"longtext", maria101, false},
+		{"longtext", maria102, true},
+		{"blob", maria102, true},
+	}
+	for _, c := range cases {
+		if actual := ParseColumnType(c.colType).DefaultPermitted(c.flavor); actual != c.expected {
+			t.Errorf("Expected DefaultPermitted for %s in %s to return %t, instead found %t", c.colType, c.flavor, c.expected, actual)
+		}
+	}
+}
@@ -67,24 +67,48 @@ const (
 	PartitioningKeep                               // negate REMOVE PARTITIONING clauses from ALTERs
 )
 
+// ExistsGuardMode values control whether IF EXISTS and IF NOT EXISTS
+// guards are added to ALTER TABLE clauses, which makes re-running an ALTER
+// safe. Only MariaDB supports these guards in ALTER TABLE.
+type ExistsGuardMode uint8
+
+// Constants for how to handle existence guards in ALTER TABLE clauses.
+const (
+	ExistsGuardsNone    ExistsGuardMode = iota // never add IF [NOT] EXISTS guards
+	ExistsGuardsMariaDB                        // add guards if Flavor is MariaDB; no-op for other flavors
+	ExistsGuardsRequire                        // add guards if Flavor is MariaDB; return an error from TableDiff.Statement for other flavors
+)
+
 // StatementModifiers are options that may be applied to adjust the DDL emitted
 // for a particular table, and/or generate errors if certain clauses are
 // present.
 type StatementModifiers struct {
 	NextAutoInc            NextAutoIncMode  // How to handle differences in next-auto-inc values
 	Partitioning           PartitioningMode // How to handle differences in partitioning status
+	ExistsGuards           ExistsGuardMode  // Whether to add IF [NOT] EXISTS guards to column, index, foreign key, and partition clauses (MariaDB only)
 	AllowUnsafe            bool             // Whether to allow potentially-destructive DDL (drop table, drop column, modify col type, etc)
 	LockClause             string           // Include a LOCK=[value] clause in generated ALTER TABLE
+	LaxLockClause          bool             // If true, omit LockClause from ALTER TABLE if Flavor is known to reject it for the ALTER's clauses, instead of letting the ALTER fail
 	AlgorithmClause        string           // Include an ALGORITHM=[value] clause in generated ALTER TABLE
 	StrictIndexOrder       bool             // If true, maintain index order even in cases where there is no functional difference
 	StrictCheckConstraints bool             // If true, maintain check constraint definition even if differences are cosmetic (name change; relative order of check definitions in MariaDB)
 	StrictForeignKeyNaming bool             // If true, maintain foreign key definition even if differences are cosmetic (name change, RESTRICT vs NO ACTION, etc)
+	SkipFKValidation       bool             // If true, new foreign keys must not validate existing rows; since no flavor supports this in ALTER TABLE syntax, adding a foreign key returns an UnsupportedDiffError
 	StrictColumnDefinition bool             // If true, maintain column properties that are purely cosmetic (only affects MySQL 8)
 	LaxColumnOrder         bool             // If true, don't modify columns if they only differ by position
-	LaxComments            bool             // If true, don't modify tables/columns/indexes/routines if they only differ by comment clauses
+	LaxComments            bool             // If true, don't modify tables/columns/indexes/partitions/routines if they only differ by comment clauses
+	LaxZeroTimestamps      bool             // If true, don't modify NOT NULL timestamp columns if they only differ by presence of a zero-date default, which varies by explicit_defaults_for_timestamp
 	CompareMetadata        bool             // If true, compare creation-time sql_mode and db collation for stored programs
 	VirtualColValidation   bool             // If true, add WITH VALIDATION clause for ALTER TABLE affecting virtual columns
+	NullableAddColumns     bool             // If true, columns added as NOT NULL without a default are instead added as nullable
+	PreferConvertCharSet   bool             // If true, use CONVERT TO CHARACTER SET instead of per-column modifications when a table's default charset changes and all text columns inherit it
 	SkipPreDropAlters      bool             // If true, skip ALTERs that were only generated to make DROP TABLE faster
+	LowerCaseKeywords      bool             // If true, emit keywords in table DDL in lower-case instead of upper-case; see LowerCaseKeywords function
+	QualifySchema          string           // If non-empty, qualify the table name (and same-schema foreign key references) in table DDL with this schema name
+	ANSIQuotes             bool             // If true, wrap identifiers in table DDL in double quotes instead of backticks, for use with sql_mode ANSI_QUOTES; see ANSIQuoteIdentifiers function
+	IgnoreTableOptions     []string         // Names of table-level create options (e.g. "ROW_FORMAT", "KEY_BLOCK_SIZE", "COMMENT") to leave unchanged in ALTER TABLE
+	DefaultRowFormat       string           // InnoDB row format used by the server for tables which omit ROW_FORMAT, e.g. from Instance.DefaultRowFormat; if blank, Flavor.DefaultRowFormat is used instead
+	DefaultTableEncryption bool             // If true, the server's default_table_encryption is enabled, so an explicit ENCRYPTION='N' is not equivalent to omitting the option
 	Flavor                 Flavor           // Adjust generated DDL to match vendor/version. Zero value is FlavorUnknown which makes no adjustments.
 }
 
@@ -97,6 +121,18 @@ type SchemaDiff struct {
 	ToSchema     *Schema
 	TableDiffs   []*TableDiff   // a set of statements that, if run, would turn tables in FromSchema into ToSchema
 	RoutineDiffs []*RoutineDiff // " but for funcs and procs
+
+	// SequenceDiffs are not yet included in ObjectDiffs(), since sequences are
+	// not yet included in Schema.Objects().
+	SequenceDiffs []*SequenceDiff
+
+	// TriggerDiffs are likewise not yet included in ObjectDiffs(). All drops are
+	// ordered before all creates.
+	TriggerDiffs []*TriggerDiff
+
+	// ViewDiffs are likewise not yet included in ObjectDiffs(). All drops are
+	// ordered before all creates and alters.
+	ViewDiffs []*ViewDiff
 }
 
 // NewSchemaDiff computes the set of differences between two database schemas.
@@ -112,6 +148,9 @@ func NewSchemaDiff(from, to *Schema) *SchemaDiff {
 
 	result.TableDiffs = compareTables(from, to)
 	result.RoutineDiffs = compareRoutines(from, to)
+	result.SequenceDiffs = compareSequences(from, to)
+	result.TriggerDiffs = compareTriggers(from, to)
+	result.ViewDiffs = compareViews(from, to)
 	return result
 }
 
@@ -247,7 +286,7 @@ func (dd *DatabaseDiff) Statement(_ StatementModifiers) (string, error) {
 	case DiffTypeDrop:
 		stmt := dd.From.DropStatement()
 		var err error
-		if len(dd.From.Objects()) > 0 {
+		if len(dd.From.Objects()) > 0 || len(dd.From.Sequences) > 0 {
 			err = &UnsafeDiffError{
 				Reason: "Desired drop of " + dd.ObjectKey().String() + " would cause data loss.",
 			}
@@ -292,10 +331,11 @@ type UnsupportedDiffError struct {
 }
 
 // Error returns a string with information about why the diff is not supported.
+// Any credentials in the CREATE statements are redacted.
 func (e *UnsupportedDiffError) Error() string {
 	diff := difflib.UnifiedDiff{
-		A:        difflib.SplitLines(e.ExpectedCreate),
-		B:        difflib.SplitLines(e.ActualCreate),
+		A:        difflib.SplitLines(RedactSecrets(e.ExpectedCreate)),
+		B:        difflib.SplitLines(RedactSecrets(e.ActualCreate)),
 		FromFile: e.ExpectedDesc,
 		ToFile:   e.ActualDesc,
 		Context:  0,
@@ -49,9 +49,12 @@ func IsDatabaseError(err error, specificErrors ...uint16) bool {
 	return false
 }
 
-// IsSyntaxError returns true if err is a SQL syntax or parsing error.
+// IsSyntaxError returns true if err is a SQL syntax or parsing error. This
+// includes errors returned by a database server, as well as a
+// *MalformedSQLError from parsing SQL locally.
 func IsSyntaxError(err error) bool {
-	return IsDatabaseError(err, ER_PARSE_ERROR, ER_SYNTAX_ERROR)
+	var mse *MalformedSQLError
+	return errors.As(err, &mse) || IsDatabaseError(err, ER_PARSE_ERROR, ER_SYNTAX_ERROR)
 }
 
 // IsObjectNotFoundError returns true if err is a response from SHOW CREATE
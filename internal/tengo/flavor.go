@@ -83,15 +83,17 @@ func (ver Version) matchesSlice(other []uint16) bool {
 
 // ParseVersion converts the supplied string in dot-separated format into a
 // Version, or returns an error if parsing fails. Any non-digit prefix or suffix
-// is ignored.
+// is ignored, even if the string omits the minor and/or patch components.
 func ParseVersion(s string) (ver Version, err error) {
-	for n, spart := range strings.SplitN(s, ".", 3) {
+	isNonDigit := func(r rune) bool { return !unicode.IsDigit(r) }
+	parts := strings.SplitN(s, ".", 3)
+	for n, spart := range parts {
 		if n == 0 { // strip leading non-digits before major version
 			if firstDigitPos := strings.IndexFunc(spart, unicode.IsDigit); firstDigitPos > -1 {
 				spart = spart[firstDigitPos:]
 			}
-		} else if n == 2 { // strip anything after first non-digit
-			isNonDigit := func(r rune) bool { return !unicode.IsDigit(r) }
+		}
+		if n == len(parts)-1 { // strip anything after first non-digit in final component
 			if firstNonDigitPos := strings.IndexFunc(spart, isNonDigit); firstNonDigitPos > -1 {
 				spart = spart[0:firstNonDigitPos]
 			}
@@ -318,6 +320,16 @@ func (fl Flavor) String() string {
 	return fmt.Sprintf("%s:%d.%d", base, fl.Version[0], fl.Version[1])
 }
 
+// Major returns the major component of the flavor's version number.
+func (fl Flavor) Major() uint16 { return fl.Version.Major() }
+
+// Minor returns the minor component of the flavor's version number.
+func (fl Flavor) Minor() uint16 { return fl.Version.Minor() }
+
+// Patch returns the patch component of the flavor's version number. This is
+// 0 if the flavor was parsed from a string lacking a patch version.
+func (fl Flavor) Patch() uint16 { return fl.Version.Patch() }
+
 // Family returns a copy of the receiver with a zeroed-out patch version.
 func (fl Flavor) Family() Flavor {
 	fl.Version[2] = 0
@@ -475,6 +487,34 @@ func (fl Flavor) OmitIntDisplayWidth() bool {
 	return fl.MinMySQL(8, 0, 19)
 }
 
+// DefaultRowFormat returns the InnoDB row format used by default for the flavor,
+// when a table does not specify one explicitly. This reflects the default
+// value of innodb_default_row_format, which was introduced in MySQL 5.7.9 and
+// MariaDB 10.2.2; prior versions used COMPACT. A blank string is returned if
+// the flavor is unknown.
+func (fl Flavor) DefaultRowFormat() string {
+	if !fl.Known() {
+		return ""
+	} else if fl.MinMySQL(5, 7, 9) || fl.MinMariaDB(10, 2, 2) {
+		return "DYNAMIC"
+	}
+	return "COMPACT"
+}
+
+// DefaultCharSet returns the default value of character_set_server for the
+// flavor, which is inherited by any schema or table that does not specify a
+// character set explicitly. This is utf8mb4 in MySQL 8.0+ and MariaDB 11.6+,
+// and latin1 in prior versions. A blank string is returned if the flavor is
+// unknown.
+func (fl Flavor) DefaultCharSet() string {
+	if !fl.Known() {
+		return ""
+	} else if fl.MinMySQL(8) || fl.MinMariaDB(11, 6) {
+		return "utf8mb4"
+	}
+	return "latin1"
+}
+
 // HasCheckConstraints returns true if the flavor supports check constraints
 // and exposes them in information_schema.
 func (fl Flavor) HasCheckConstraints() bool {
@@ -484,6 +524,15 @@ func (fl Flavor) HasCheckConstraints() bool {
 	return fl.IsMariaDB(10, 2) && fl.Version.Patch() >= 22
 }
 
+// AtomicDDL returns true if the flavor supports atomic (crash-safe) DDL, in
+// which each individual DDL statement either fully succeeds or is fully rolled
+// back. Note that DDL always causes an implicit commit, regardless of flavor:
+// atomic DDL does NOT mean that multiple DDL statements may be grouped into a
+// single transaction, nor that a completed DDL statement can be rolled back.
+func (fl Flavor) AtomicDDL() bool {
+	return fl.MinMySQL(8) || fl.MinMariaDB(10, 6)
+}
+
 // ModernCipherSuites returns true if the flavor is typically compiled with
 // OpenSSL 1.1+ and supports elliptic curve cipher suites compatible with the
 // default set of cipher suites in Go 1.22+. If the flavor is not known, this
@@ -36,6 +36,9 @@ func TestParseVersion(t *testing.T) {
 		"5.7.9300000000000000000":              {5, 7, 65535}, // uint64 int overflow on patch number
 		"v1.2.3rc1":                            {1, 2, 3},
 		"10.abc123def.12":                      {10, 0, 12},
+		"8.4-log":                              {8, 4, 0},
+		"11.4-MariaDB":                         {11, 4, 0},
+		"9-beta":                               {9, 0, 0},
 	}
 	for input, expected := range cases {
 		actual, _ := ParseVersion(input)
@@ -174,6 +177,43 @@ func TestIdentifyFlavor(t *testing.T) {
 	}
 }
 
+func TestFlavorComponents(t *testing.T) {
+	type testcase struct {
+		versionString  string
+		versionComment string
+		vendor         Vendor
+		major          uint16
+		minor          uint16
+		patch          uint16
+	}
+	cases := []testcase{
+		{"8.0.36", "MySQL Community Server - GPL", VendorMySQL, 8, 0, 36},
+		{"8.4.0-log", "MySQL Community Server - GPL", VendorMySQL, 8, 4, 0},
+		{"8.4-log", "MySQL Community Server - GPL", VendorMySQL, 8, 4, 0},
+		{"5.7.23-23", "Percona Server (GPL), Release 23, Revision 500fcf5", VendorMySQL, 5, 7, 23},
+		{"10.6.16-MariaDB-1:10.6.16+maria~ubu2004", "mariadb.org binary distribution", VendorMariaDB, 10, 6, 16},
+		{"11.4-MariaDB", "MariaDB Server", VendorMariaDB, 11, 4, 0},
+		{"10.11", "Source distribution", VendorMariaDB, 10, 11, 0},
+		{"webscalesql", "webscalesql", VendorUnknown, 0, 0, 0},
+	}
+	for _, tc := range cases {
+		fl := IdentifyFlavor(tc.versionString, tc.versionComment)
+		if fl.Vendor != tc.vendor || fl.Major() != tc.major || fl.Minor() != tc.minor || fl.Patch() != tc.patch {
+			t.Errorf("Unexpected components from IdentifyFlavor(%q, %q): expected %s %d.%d.%d, found %s %d.%d.%d", tc.versionString, tc.versionComment, tc.vendor, tc.major, tc.minor, tc.patch, fl.Vendor, fl.Major(), fl.Minor(), fl.Patch())
+		}
+	}
+
+	// Confirm components of flavors parsed from strings with and without patch
+	fl := ParseFlavor("mysql:8.0")
+	if fl.Vendor != VendorMySQL || fl.Major() != 8 || fl.Minor() != 0 || fl.Patch() != 0 {
+		t.Errorf("Unexpected components from %s: %d.%d.%d", fl, fl.Major(), fl.Minor(), fl.Patch())
+	}
+	fl = ParseFlavor("percona:5.7.44")
+	if fl.Vendor != VendorMySQL || fl.Major() != 5 || fl.Minor() != 7 || fl.Patch() != 44 {
+		t.Errorf("Unexpected components from %s: %d.%d.%d", fl, fl.Major(), fl.Minor(), fl.Patch())
+	}
+}
+
 func TestFlavorString(t *testing.T) {
 	cases := map[Flavor]string{
 		{VendorMySQL, Version{5, 5, 33}, VariantNone}:    "mysql:5.5.33",
@@ -588,6 +628,52 @@ func TestFlavorOmitIntDisplayWidth(t *testing.T) {
 	}
 }
 
+func TestFlavorDefaultRowFormat(t *testing.T) {
+	type testcase struct {
+		receiver string
+		expected string
+	}
+	cases := []testcase{
+		{"mysql:5.5", "COMPACT"},
+		{"mysql:5.6", "COMPACT"},
+		{"mysql:5.7.8", "COMPACT"},
+		{"mysql:5.7.9", "DYNAMIC"},
+		{"mysql:8.0", "DYNAMIC"},
+		{"percona:5.6", "COMPACT"},
+		{"percona:8.0", "DYNAMIC"},
+		{"mariadb:10.1", "COMPACT"},
+		{"mariadb:10.2.1", "COMPACT"},
+		{"mariadb:10.2.2", "DYNAMIC"},
+		{"mariadb:11.4", "DYNAMIC"},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		actual := ParseFlavor(tc.receiver).DefaultRowFormat()
+		if actual != tc.expected {
+			t.Errorf("Expected %s.DefaultRowFormat() to return %q, instead found %q", tc.receiver, tc.expected, actual)
+		}
+	}
+}
+
+func TestFlavorDefaultCharSet(t *testing.T) {
+	cases := map[string]string{
+		"mysql:5.7":    "latin1",
+		"percona:5.7":  "latin1",
+		"mysql:8.0":    "utf8mb4",
+		"mysql:8.4":    "utf8mb4",
+		"mariadb:10.6": "latin1",
+		"mariadb:11.4": "latin1",
+		"mariadb:11.6": "utf8mb4",
+		"mariadb:11.8": "utf8mb4",
+		"":             "",
+	}
+	for input, expected := range cases {
+		if actual := ParseFlavor(input).DefaultCharSet(); actual != expected {
+			t.Errorf("Expected %s.DefaultCharSet() to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
 func TestFlavorHasCheckConstraints(t *testing.T) {
 	cases := map[string]bool{
 		"mysql:5.7":       false,
@@ -612,6 +698,24 @@ func TestFlavorHasCheckConstraints(t *testing.T) {
 	}
 }
 
+func TestFlavorAtomicDDL(t *testing.T) {
+	cases := map[string]bool{
+		"mysql:5.7":      false,
+		"percona:5.7":    false,
+		"mysql:8.0":      true,
+		"aurora:8.0.32":  true,
+		"mysql:8.4":      true,
+		"mariadb:10.5":   false,
+		"mariadb:10.6":   true,
+		"mariadb:11.4.2": true,
+	}
+	for input, expected := range cases {
+		if ParseFlavor(input).AtomicDDL() != expected {
+			t.Errorf("Expected %s.AtomicDDL() to return %t, but it did not", input, expected)
+		}
+	}
+}
+
 func TestFlavorModernCipherSuites(t *testing.T) {
 	cases := map[string]bool{
 		"mysql:5.5":       false,
@@ -2,6 +2,7 @@ package tengo
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -10,6 +11,10 @@ import (
 // *Table, *[]Column to avoid potentially having to introspect multiple schemas
 // in a particular order. Also, the referenced side is not gauranteed to exist,
 // especially if foreign_key_checks=0 has been used at any point in the past.
+// Unlike Check, ForeignKey intentionally has no Enforced field: the NOT
+// ENFORCED attribute added in MySQL 8.0.16 only applies to check constraints,
+// and no version of MySQL or MariaDB accepts it for foreign keys. Referential
+// checks can only be bypassed at the session level via foreign_key_checks=0.
 type ForeignKey struct {
 	Name                  string   `json:"name"`
 	ColumnNames           []string `json:"columnNames"`
@@ -20,6 +25,19 @@ type ForeignKey struct {
 	DeleteRule            string   `json:"deleteRule"`
 }
 
+// TableReference identifies the parent table of a foreign key. SchemaName is
+// blank if the parent table is in the same schema as the child table.
+type TableReference struct {
+	SchemaName string `json:"schemaName,omitempty"`
+	TableName  string `json:"tableName"`
+}
+
+// String returns the referenced table's name, schema-qualified if SchemaName
+// is non-blank, with identifiers escaped.
+func (ref TableReference) String() string {
+	return EscapeQualifiedIdentifier(ref.SchemaName, ref.TableName)
+}
+
 // Definition returns this ForeignKey's definition clause, for use as part of a DDL
 // statement.
 func (fk *ForeignKey) Definition(flavor Flavor) string {
@@ -29,10 +47,7 @@ func (fk *ForeignKey) Definition(flavor Flavor) string {
 	}
 	childCols := strings.Join(colParts, ", ")
 
-	referencedTable := EscapeIdentifier(fk.ReferencedTableName)
-	if fk.ReferencedSchemaName != "" {
-		referencedTable = fmt.Sprintf("%s.%s", EscapeIdentifier(fk.ReferencedSchemaName), referencedTable)
-	}
+	referencedTable := EscapeQualifiedIdentifier(fk.ReferencedSchemaName, fk.ReferencedTableName)
 
 	for n, col := range fk.ReferencedColumnNames {
 		colParts[n] = EscapeIdentifier(col)
@@ -108,3 +123,83 @@ func (fk *ForeignKey) normalizedDeleteRule() string {
 	}
 	return fk.DeleteRule
 }
+
+// DeferredForeignKey is a foreign key which cannot be included in its table's
+// CREATE TABLE, because it is part of a cycle of foreign key references between
+// tables. Instead, it must be added by an ALTER TABLE after all tables in the
+// cycle have been created.
+type DeferredForeignKey struct {
+	Table      *Table
+	ForeignKey *ForeignKey
+}
+
+// Statement returns an ALTER TABLE statement which adds the foreign key.
+func (dfk DeferredForeignKey) Statement(flavor Flavor) string {
+	afk := AddForeignKey{ForeignKey: dfk.ForeignKey}
+	return dfk.Table.AlterStatement() + " " + afk.Clause(StatementModifiers{Flavor: flavor})
+}
+
+// SortTablesForCreate returns the supplied tables, which should all be in the
+// same schema, reordered such that every table is preceded by all other tables
+// that its foreign keys reference. This permits the tables to be created in
+// order without disabling foreign_key_checks. Relative order of the input is
+// otherwise preserved. References to tables outside of the input, including
+// tables in other schemas, are ignored, as are self-referential foreign keys.
+//
+// If the foreign keys form a cycle, there is no such ordering. In this case,
+// the cycle is broken by deferring one or more foreign keys: each table is
+// still returned, but the deferred foreign keys must be removed from their
+// tables' CREATE TABLE statements and instead added afterwards, using the
+// DeferredForeignKey values in the second return value.
+func SortTablesForCreate(tables []*Table) (sorted []*Table, deferred []DeferredForeignKey) {
+	byName := make(map[string]*Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	// dependsOn returns true if fk requires another unplaced table to be created
+	// before fk's table
+	placed := make(map[string]bool, len(tables))
+	dependsOn := func(t *Table, fk *ForeignKey) bool {
+		return fk.ReferencedSchemaName == "" && fk.ReferencedTableName != t.Name && byName[fk.ReferencedTableName] != nil && !placed[fk.ReferencedTableName]
+	}
+	ready := func(t *Table) bool {
+		for _, fk := range t.ForeignKeys {
+			if dependsOn(t, fk) {
+				return false
+			}
+		}
+		return true
+	}
+
+	remaining := slices.Clone(tables)
+	sorted = make([]*Table, 0, len(tables))
+	for len(remaining) > 0 {
+		// Place the first table whose dependencies have all been placed. If there
+		// is none, a cycle exists. Follow unmet dependencies from the first
+		// remaining table until a table repeats, which means that table is part of
+		// a cycle; then defer its unmet foreign keys in order to place it.
+		n := slices.IndexFunc(remaining, ready)
+		if n == -1 {
+			t := remaining[0]
+			for visited := make(map[string]bool); !visited[t.Name]; {
+				visited[t.Name] = true
+				for _, fk := range t.ForeignKeys {
+					if dependsOn(t, fk) {
+						t = byName[fk.ReferencedTableName]
+						break
+					}
+				}
+			}
+			for _, fk := range t.ForeignKeys {
+				if dependsOn(t, fk) {
+					deferred = append(deferred, DeferredForeignKey{Table: t, ForeignKey: fk})
+				}
+			}
+			n = slices.Index(remaining, t)
+		}
+		placed[remaining[n].Name] = true
+		sorted = append(sorted, remaining[n])
+		remaining = slices.Delete(remaining, n, n+1)
+	}
+	return sorted, deferred
+}
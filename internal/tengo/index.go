@@ -4,21 +4,26 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"strconv"
 	"strings"
 )
 
 // Index represents a single index (primary key, unique secondary index, or non-
 // unique secondard index) in a table.
 type Index struct {
-	Name           string      `json:"name"`
-	Parts          []IndexPart `json:"parts"`
-	PrimaryKey     bool        `json:"primaryKey,omitempty"`
-	Unique         bool        `json:"unique,omitempty"`
-	Invisible      bool        `json:"invisible,omitempty"` // MySQL 8+, also used for MariaDB 10.6's IGNORED indexes
-	Comment        string      `json:"comment,omitempty"`
-	Type           string      `json:"type"`
-	FullTextParser string      `json:"parser,omitempty"`
-	Attributes     string      `json:"attributes,omitempty"` // For MariaDB vector indexes; stored as string but compared more intelligently
+	Name                     string      `json:"name"`
+	Parts                    []IndexPart `json:"parts"`
+	PrimaryKey               bool        `json:"primaryKey,omitempty"`
+	Unique                   bool        `json:"unique,omitempty"`
+	Invisible                bool        `json:"invisible,omitempty"` // MySQL 8+, also used for MariaDB 10.6's IGNORED indexes
+	Comment                  string      `json:"comment,omitempty"`
+	Type                     string      `json:"type"`
+	FullTextParser           string      `json:"parser,omitempty"`
+	Attributes               string      `json:"attributes,omitempty"`               // For MariaDB vector indexes; stored as string but compared more intelligently
+	WithoutOverlaps          string      `json:"withoutOverlaps,omitempty"`          // MariaDB 10.5+: name of application-time period, for unique indexes using WITHOUT OVERLAPS
+	KeyBlockSize             uint32      `json:"keyBlockSize,omitempty"`             // only nonzero if differs from table's KEY_BLOCK_SIZE; not used by InnoDB
+	EngineAttribute          string      `json:"engineAttribute,omitempty"`          // MySQL 8.0.21+: JSON value of ENGINE_ATTRIBUTE option
+	SecondaryEngineAttribute string      `json:"secondaryEngineAttribute,omitempty"` // MySQL 8.0.21+: JSON value of SECONDARY_ENGINE_ATTRIBUTE option
 }
 
 // IndexPart represents an individual indexed column or expression. Each index
@@ -37,7 +42,10 @@ func (idx *Index) Definition(flavor Flavor) string {
 	for n := range idx.Parts {
 		parts[n] = idx.Parts[n].Definition(flavor)
 	}
-	var typeAndName, comment, invis, parser, attributes string
+	if idx.WithoutOverlaps != "" {
+		parts = append(parts, EscapeIdentifier(idx.WithoutOverlaps)+" WITHOUT OVERLAPS")
+	}
+	var typeAndName, keyBlockSize, comment, invis, parser, attributes string
 	if idx.PrimaryKey {
 		if !idx.Unique {
 			panic(errors.New("Index is primary key, but isn't marked as unique"))
@@ -50,6 +58,9 @@ func (idx *Index) Definition(flavor Flavor) string {
 	} else {
 		typeAndName = "KEY " + EscapeIdentifier(idx.Name)
 	}
+	if idx.KeyBlockSize > 0 {
+		keyBlockSize = fmt.Sprintf(" KEY_BLOCK_SIZE=%d", idx.KeyBlockSize)
+	}
 	if idx.Comment != "" {
 		comment = " COMMENT '" + EscapeValueForCreateTable(idx.Comment) + "'"
 	}
@@ -72,7 +83,15 @@ func (idx *Index) Definition(flavor Flavor) string {
 	if idx.Attributes != "" {
 		attributes = " " + idx.Attributes
 	}
-	return typeAndName + " (" + strings.Join(parts, ",") + ")" + comment + invis + parser + attributes
+	if flavor.MinMySQL(8, 0, 21) {
+		if idx.EngineAttribute != "" {
+			attributes += " " + engineAttributeClause("ENGINE_ATTRIBUTE", idx.EngineAttribute, false)
+		}
+		if idx.SecondaryEngineAttribute != "" {
+			attributes += " " + engineAttributeClause("SECONDARY_ENGINE_ATTRIBUTE", idx.SecondaryEngineAttribute, false)
+		}
+	}
+	return typeAndName + " (" + strings.Join(parts, ",") + ")" + keyBlockSize + comment + invis + parser + attributes
 }
 
 // Equals returns true if two indexes are completely identical, false otherwise.
@@ -80,9 +99,29 @@ func (idx *Index) Equals(other *Index) bool {
 	if idx == nil || other == nil {
 		return idx == other // only equal if BOTH are nil
 	}
+	if idx.EngineAttribute != other.EngineAttribute || idx.SecondaryEngineAttribute != other.SecondaryEngineAttribute {
+		return false
+	}
 	return idx.Name == other.Name && idx.Comment == other.Comment && idx.Invisible == other.Invisible && idx.Equivalent(other)
 }
 
+// MergeThreshold returns the InnoDB MERGE_THRESHOLD specified in the index's
+// comment, along with true if a valid threshold (1 to 50) was present. InnoDB
+// treats a comment containing "MERGE_THRESHOLD=N" as a functional setting
+// controlling when index pages are merged, rather than as a mere comment.
+func (idx *Index) MergeThreshold() (threshold uint8, ok bool) {
+	_, after, found := strings.Cut(idx.Comment, "MERGE_THRESHOLD=")
+	if !found {
+		return 0, false
+	}
+	digits := after[:len(after)-len(strings.TrimLeft(after, "0123456789"))]
+	value, err := strconv.ParseUint(digits, 10, 8)
+	if err != nil || value < 1 || value > 50 {
+		return 0, false
+	}
+	return uint8(value), true
+}
+
 // sameParts returns true if two Indexes' Parts slices are identical.
 func (idx *Index) sameParts(other *Index) bool {
 	if len(idx.Parts) != len(other.Parts) {
@@ -126,12 +165,16 @@ func (idx *Index) sameAttributes(other *Index) bool {
 
 // Equivalent returns true if two Indexes are functionally equivalent,
 // regardless of whether or not they have the same names, comments, or
-// visibility.
+// visibility. ENGINE_ATTRIBUTE and SECONDARY_ENGINE_ATTRIBUTE values are
+// compared as JSON documents, ignoring formatting and key order.
 func (idx *Index) Equivalent(other *Index) bool {
 	if idx == nil || other == nil {
 		return idx == other // only equivalent if BOTH are nil
 	}
-	if idx.PrimaryKey != other.PrimaryKey || idx.Unique != other.Unique || idx.Type != other.Type || idx.FullTextParser != other.FullTextParser {
+	if idx.PrimaryKey != other.PrimaryKey || idx.Unique != other.Unique || idx.Type != other.Type || idx.FullTextParser != other.FullTextParser || idx.WithoutOverlaps != other.WithoutOverlaps || idx.KeyBlockSize != other.KeyBlockSize {
+		return false
+	}
+	if !engineAttributesEquivalent(idx.EngineAttribute, other.EngineAttribute) || !engineAttributesEquivalent(idx.SecondaryEngineAttribute, other.SecondaryEngineAttribute) {
 		return false
 	}
 	return idx.sameParts(other) && idx.sameAttributes(other)
@@ -157,8 +200,8 @@ func (idx *Index) RedundantTo(other *Index) bool {
 	}
 	if idx.Unique && other.Unique {
 		// Since unique indexes are also unique *constraints*, two unique indexes are
-		// non-redundant unless they have identical parts.
-		return idx.sameParts(other)
+		// non-redundant unless they have identical parts and overlap semantics.
+		return idx.sameParts(other) && idx.WithoutOverlaps == other.WithoutOverlaps
 	} else if idx.Type == "VECTOR" {
 		return idx.sameParts(other) && idx.sameAttributes(other)
 	} else if idx.Type == "FULLTEXT" && len(idx.Parts) != len(other.Parts) {
@@ -189,6 +232,18 @@ func (idx *Index) Functional() bool {
 	return false
 }
 
+// ColumnNames returns the names of columns referenced by idx's parts, in
+// order. Parts which are expressions rather than columns are omitted.
+func (idx *Index) ColumnNames() []string {
+	names := make([]string, 0, len(idx.Parts))
+	for _, part := range idx.Parts {
+		if part.ColumnName != "" {
+			names = append(names, part.ColumnName)
+		}
+	}
+	return names
+}
+
 // Definition returns this index part's definition clause.
 func (part *IndexPart) Definition(_ Flavor) string {
 	var base, prefix, collation string
@@ -1,6 +1,7 @@
 package tengo
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -39,6 +40,96 @@ func TestIndexDefinition(t *testing.T) {
 	}
 }
 
+func TestIndexDefinitionFullTextParser(t *testing.T) {
+	index := Index{
+		Name:           "ft_body",
+		Parts:          []IndexPart{{ColumnName: "body"}},
+		Type:           "FULLTEXT",
+		FullTextParser: "ngram",
+	}
+	cases := map[string]string{
+		"mysql:8.0":     "FULLTEXT KEY `ft_body` (`body`) /*!50100 WITH PARSER `ngram` */ ",
+		"mariadb:10.11": "FULLTEXT KEY `ft_body` (`body`) /*!50100 WITH PARSER `ngram` */ ",
+		"mariadb:11.8":  "FULLTEXT KEY `ft_body` (`body`) WITH PARSER `ngram`",
+	}
+	for flavorStr, expected := range cases {
+		if actual := index.Definition(ParseFlavor(flavorStr)); actual != expected {
+			t.Errorf("Index.Definition(%s) expected %q, instead found %q", flavorStr, expected, actual)
+		}
+	}
+
+	// FULLTEXT keyword must be retained without a parser, and parser differences
+	// must prevent equivalence
+	other := index
+	other.FullTextParser = ""
+	if expected, actual := "FULLTEXT KEY `ft_body` (`body`)", other.Definition(FlavorUnknown); actual != expected {
+		t.Errorf("Index.Definition() expected %q, instead found %q", expected, actual)
+	}
+	if index.Equivalent(&other) || other.Equivalent(&index) {
+		t.Error("Expected indexes with different parsers to not be equivalent")
+	}
+}
+
+func TestIndexKeyBlockSize(t *testing.T) {
+	index := Index{
+		Name:         "idx_name",
+		Parts:        []IndexPart{{ColumnName: "name"}},
+		Comment:      "hello",
+		Type:         "BTREE",
+		KeyBlockSize: 1024,
+	}
+	if expected, actual := "KEY `idx_name` (`name`) KEY_BLOCK_SIZE=1024 COMMENT 'hello'", index.Definition(FlavorUnknown); actual != expected {
+		t.Errorf("Index.Definition() expected %q, instead found %q", expected, actual)
+	}
+	other := index
+	other.KeyBlockSize = 0
+	if index.Equivalent(&other) || other.Equivalent(&index) {
+		t.Error("Expected indexes with different KEY_BLOCK_SIZE to not be equivalent")
+	}
+
+	// Confirm parsing from SHOW CREATE TABLE, for both primary key and secondary
+	// index placements
+	table := aTable(1)
+	table.Engine = "MyISAM"
+	table.CreateStatement = table.GeneratedCreateStatement(FlavorUnknown)
+	table.CreateStatement = strings.Replace(table.CreateStatement, "PRIMARY KEY (`actor_id`)", "PRIMARY KEY (`actor_id`) KEY_BLOCK_SIZE=2048", 1)
+	table.CreateStatement = strings.Replace(table.CreateStatement, "KEY `idx_actor_name` (`last_name`(10),`first_name`(1))", "KEY `idx_actor_name` (`last_name`(10),`first_name`(1)) KEY_BLOCK_SIZE=4096", 1)
+	if table.CreateStatement == table.GeneratedCreateStatement(FlavorUnknown) {
+		t.Fatal("Failed to set up test properly: string replacements did not match")
+	}
+	fixIndexKeyBlockSizes(&table, FlavorUnknown)
+	if table.PrimaryKey.KeyBlockSize != 2048 || table.SecondaryIndexes[1].KeyBlockSize != 4096 {
+		t.Errorf("Unexpected KeyBlockSize values after parsing: %d, %d", table.PrimaryKey.KeyBlockSize, table.SecondaryIndexes[1].KeyBlockSize)
+	}
+	if table.CreateStatement != table.GeneratedCreateStatement(FlavorUnknown) {
+		t.Errorf("Generated CREATE does not match after parsing:\n%s", table.GeneratedCreateStatement(FlavorUnknown))
+	}
+}
+
+func TestIndexMergeThreshold(t *testing.T) {
+	cases := map[string]uint8{
+		"MERGE_THRESHOLD=40":           40,
+		"MERGE_THRESHOLD=1":            1,
+		"MERGE_THRESHOLD=50":           50,
+		"hot rows; MERGE_THRESHOLD=45": 45,
+		"MERGE_THRESHOLD=45 hot rows":  45,
+		"MERGE_THRESHOLD=0":            0,
+		"MERGE_THRESHOLD=51":           0,
+		"MERGE_THRESHOLD=300":          0,
+		"MERGE_THRESHOLD=":             0,
+		"merge_threshold=40":           0,
+		"hello":                        0,
+		"":                             0,
+	}
+	for comment, expected := range cases {
+		index := Index{Name: "idx", Comment: comment}
+		threshold, ok := index.MergeThreshold()
+		if threshold != expected || ok != (expected > 0) {
+			t.Errorf("Unexpected return from MergeThreshold for comment %q: %d, %t", comment, threshold, ok)
+		}
+	}
+}
+
 func TestIndexRedundantTo(t *testing.T) {
 	columns := []*Column{
 		{Name: "col0"},
@@ -3,6 +3,7 @@ package tengo
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"maps"
 	"net/url"
@@ -37,6 +38,13 @@ type Instance struct {
 	maxUserConns    int
 	lowerCaseNames  int
 	sqlMode         []string
+	explicitDefs    bool
+	defaultEncrypt  bool
+	defaultRowFmt   string
+	bulkIntrospect  bool
+	retainRawCreate bool
+	introspectViews bool
+	introspectTrigs bool
 	valid           bool // true if any conn has ever successfully been made yet
 }
 
@@ -283,6 +291,19 @@ func (instance *Instance) ForceFlavor(flavor Flavor) {
 	instance.flavor = flavor
 }
 
+// SetBulkIntrospection controls whether subsequent schema introspection may
+// skip SHOW CREATE TABLE for tables which can be fully reconstructed from
+// information_schema. This reduces the number of round-trips for schemas with
+// many tables, but is experimental and currently only affects MariaDB. When
+// enabled, reconstructed tables are assumed to be supported for diff operations
+// (UnsupportedDDL is always false for them), and their CreateStatement is the
+// generated form rather than the server's own SHOW CREATE TABLE output.
+func (instance *Instance) SetBulkIntrospection(enabled bool) {
+	instance.m.Lock()
+	defer instance.m.Unlock()
+	instance.bulkIntrospect = enabled
+}
+
 // NameCaseMode represents different values of the lower_case_table_names
 // read-only global server variable.
 type NameCaseMode int
@@ -322,6 +343,43 @@ func (instance *Instance) SQLMode() string {
 	return strings.Join(instance.sqlMode, ",")
 }
 
+// ExplicitDefaultsForTimestamp returns the session-level value of
+// explicit_defaults_for_timestamp for connections using default parameters.
+// When this is false, the server implicitly adds defaults to NOT NULL TIMESTAMP
+// columns which lack one. If the variable could not be queried, false is
+// returned, matching the behavior of older servers which lack the variable.
+func (instance *Instance) ExplicitDefaultsForTimestamp() bool {
+	if ok, _ := instance.Valid(); !ok {
+		return false
+	}
+	return instance.explicitDefs
+}
+
+// DefaultTableEncryption returns the session-level value of
+// default_table_encryption for connections using default parameters. When this
+// is true, new schemas are encrypted by default, and so are tables which lack
+// an explicit ENCRYPTION clause. If the variable could not be queried, false is
+// returned, matching the behavior of flavors which lack the variable.
+func (instance *Instance) DefaultTableEncryption() bool {
+	if ok, _ := instance.Valid(); !ok {
+		return false
+	}
+	return instance.defaultEncrypt
+}
+
+// DefaultRowFormat returns the InnoDB row format used by default for tables
+// which do not specify one explicitly, based on the global value of
+// innodb_default_row_format. If the variable could not be queried, the
+// flavor's default is returned instead; see Flavor.DefaultRowFormat.
+func (instance *Instance) DefaultRowFormat() string {
+	if ok, _ := instance.Valid(); !ok {
+		return ""
+	} else if instance.defaultRowFmt != "" {
+		return instance.defaultRowFmt
+	}
+	return instance.flavor.DefaultRowFormat()
+}
+
 // hydrateVars populates several non-exported Instance fields by querying
 // various global and session variables. Failures are ignored; these variables
 // are designed to help inform behavior but are not strictly mandatory.
@@ -365,6 +423,15 @@ func (instance *Instance) hydrateVars(db *sqlx.DB, lock bool) {
 	instance.waitTimeout = result.WaitTimeout
 	instance.lockWaitTimeout = result.LockWaitTimeout
 	instance.lowerCaseNames = result.LowerCaseTableNames
+
+	// explicit_defaults_for_timestamp does not exist in all supported flavors, so
+	// it is queried separately, and any error is ignored. The same is true of
+	// default_table_encryption, which was added in MySQL 8.0.16, and
+	// innodb_default_row_format, which was added in MySQL 5.7.9 and MariaDB
+	// 10.2.2.
+	db.Get(&instance.explicitDefs, "SELECT @@session.explicit_defaults_for_timestamp")
+	db.Get(&instance.defaultEncrypt, "SELECT @@session.default_table_encryption")
+	db.Get(&instance.defaultRowFmt, "SELECT UPPER(@@global.innodb_default_row_format)")
 	if result.MaxUserConns > 0 {
 		instance.maxUserConns = result.MaxUserConns
 	} else {
@@ -442,6 +509,25 @@ func (instance *Instance) SchemaNames() ([]string, error) {
 // more schema names as args to filter the result to just those schemas.
 // Note that the ordering of the resulting slice is not guaranteed.
 func (instance *Instance) Schemas(onlyNames ...string) ([]*Schema, error) {
+	schemas, err := instance.SchemasConcurrently(1, onlyNames...)
+	if err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// SchemasConcurrently behaves like Schemas, but introspects up to concurrency
+// schemas in parallel. The concurrency is reduced if necessary to avoid
+// exceeding the server's max_connections or max_user_connections, and the
+// per-schema connection pools are sized to share the same limit.
+// Unlike Schemas, a failure to introspect one schema does not prevent
+// introspection of the others. In this situation, the returned slice omits the
+// schemas that could not be introspected, and the returned error joins together
+// the errors for each failed schema. If only some individual objects in a schema
+// could not be introspected, the schema is still returned without those objects,
+// and its portion of the joined error is an *ObjectIntrospectionError listing
+// the failed objects.
+func (instance *Instance) SchemasConcurrently(concurrency int, onlyNames ...string) ([]*Schema, error) {
 	db, err := instance.CachedConnectionPool("", "")
 	if err != nil {
 		return nil, err
@@ -483,48 +569,220 @@ func (instance *Instance) Schemas(onlyNames ...string) ([]*Schema, error) {
 		return nil, err
 	}
 
+	// Determine how many schemas to introspect at once, as well as the max conns
+	// per schema. Normally each schema's introspection may use up to 20 conns.
+	// However we must keep the total below the limit used by rawConnectionPool,
+	// which already reserves some conns for other purposes.
+	maxConnsPerSchema := 20
+	if instance.maxUserConns < 30 {
+		maxConnsPerSchema = 0 // use the limit from rawConnectionPool as-is
+		concurrency = 1
+	} else if budget := instance.maxUserConns - 10; concurrency*maxConnsPerSchema > budget {
+		concurrency = min(concurrency, budget/2)
+		maxConnsPerSchema = budget / concurrency
+	}
+	concurrency = max(concurrency, 1)
+
 	schemas := make([]*Schema, len(rawSchemas))
+	errs := make([]error, len(rawSchemas))
+	var g errgroup.Group
+	g.SetLimit(concurrency)
 	for n, rawSchema := range rawSchemas {
 		schemas[n] = &Schema{
 			Name:      rawSchema.Name,
 			CharSet:   rawSchema.CharSet,
 			Collation: rawSchema.Collation,
 		}
-		// Create a non-cached connection pool with this schema as the default
-		// database. The instance.querySchemaX calls below can establish a lot of
-		// connections, so we will explicitly close the pool afterwards, to avoid
-		// keeping a very large number of conns open. (Although idle conns eventually
-		// get closed automatically, this may take too long.)
-		flavor := instance.Flavor()
-		schemaDB, err := instance.ConnectionPool(rawSchema.Name, instance.introspectionParams())
-		if err != nil {
-			return nil, err
-		}
-		if instance.maxUserConns >= 30 {
-			// Limit concurrency to 20, unless limit is already lower than this due to
-			// having a low maxUserConns (see logic in Instance.rawConnectionPool)
-			schemaDB.SetMaxOpenConns(20)
-
-			// Also increase max idle conns above the Golang default of 2, to ensure
-			// concurrent introspection queries reuse conns more effectively.
-			schemaDB.SetMaxIdleConns(20)
+		g.Go(func() error {
+			errs[n] = instance.introspectSchema(schemas[n], maxConnsPerSchema)
+			if _, partial := errs[n].(*ObjectIntrospectionError); errs[n] != nil && !partial {
+				errs[n] = fmt.Errorf("Error introspecting schema %s: %w", EscapeIdentifier(rawSchema.Name), errs[n])
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	// Remove any schemas which failed introspection entirely
+	result := make([]*Schema, 0, len(schemas))
+	for n := range schemas {
+		if _, partial := errs[n].(*ObjectIntrospectionError); errs[n] == nil || partial {
+			result = append(result, schemas[n])
 		}
-		g, ctx := errgroup.WithContext(context.Background())
+	}
+	return result, errors.Join(errs...)
+}
+
+// SetRetainRawCreate controls whether subsequent schema introspection retains
+// the unmodified SHOW CREATE TABLE output for each table, accessible via
+// Table.RawCreate. This is disabled by default to avoid the memory overhead of
+// storing a second copy of each CREATE statement. Tables which were
+// reconstructed via bulk introspection (see SetBulkIntrospection) never have
+// raw SHOW CREATE TABLE output.
+func (instance *Instance) SetRetainRawCreate(enabled bool) {
+	instance.m.Lock()
+	defer instance.m.Unlock()
+	instance.retainRawCreate = enabled
+}
+
+// SetViewIntrospection controls whether subsequent schema introspection
+// populates Schema.Views. This is disabled by default, since it requires an
+// additional SHOW CREATE VIEW query per view, which fails if the user lacks the
+// SHOW VIEW privilege.
+func (instance *Instance) SetViewIntrospection(enabled bool) {
+	instance.m.Lock()
+	defer instance.m.Unlock()
+	instance.introspectViews = enabled
+}
+
+// SetTriggerIntrospection controls whether subsequent schema introspection
+// populates Schema.Triggers. This is disabled by default, since it requires an
+// additional SHOW CREATE TRIGGER query per trigger, which fails if the user
+// lacks the TRIGGER privilege.
+func (instance *Instance) SetTriggerIntrospection(enabled bool) {
+	instance.m.Lock()
+	defer instance.m.Unlock()
+	instance.introspectTrigs = enabled
+}
+
+// introspectSchema populates the tables, routines, triggers and views (only if
+// enabled via SetTriggerIntrospection and SetViewIntrospection respectively),
+// and sequences (MariaDB 10.3+ only) of the supplied schema, which should
+// already have its name, charset, and collation set. If maxConns is positive, it limits
+// the number of concurrent connections used.
+// If some individual objects cannot be introspected, they are omitted from the
+// schema, and an *ObjectIntrospectionError is returned after introspecting the
+// rest of the schema. Any other error means the schema could not be
+// introspected at all.
+func (instance *Instance) introspectSchema(schema *Schema, maxConns int) error {
+	// Create a non-cached connection pool with this schema as the default
+	// database. The instance.querySchemaX calls below can establish a lot of
+	// connections, so we will explicitly close the pool afterwards, to avoid
+	// keeping a very large number of conns open. (Although idle conns eventually
+	// get closed automatically, this may take too long.)
+	flavor := instance.Flavor()
+	instance.m.Lock()
+	opts := introspectionOptions{
+		bulk:            instance.bulkIntrospect,
+		retainRawCreate: instance.retainRawCreate,
+	}
+	introspectViews, introspectTrigs := instance.introspectViews, instance.introspectTrigs
+	instance.m.Unlock()
+	schemaDB, err := instance.ConnectionPool(schema.Name, instance.introspectionParams())
+	if err != nil {
+		return err
+	}
+	defer schemaDB.Close()
+	if maxConns > 0 {
+		schemaDB.SetMaxOpenConns(maxConns)
+
+		// Also increase max idle conns above the Golang default of 2, to ensure
+		// concurrent introspection queries reuse conns more effectively.
+		schemaDB.SetMaxIdleConns(maxConns)
+	}
+	failures := &objectFailures{}
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() (err error) {
+		schema.Tables, err = querySchemaTables(ctx, schemaDB, schema.Name, flavor, opts, failures)
+		return err
+	})
+	g.Go(func() (err error) {
+		schema.Routines, err = querySchemaRoutines(ctx, schemaDB, schema.Name, flavor, failures)
+		return err
+	})
+	if introspectTrigs {
 		g.Go(func() (err error) {
-			schemas[n].Tables, err = querySchemaTables(ctx, schemaDB, rawSchema.Name, flavor)
+			schema.Triggers, err = querySchemaTriggers(ctx, schemaDB, schema.Name, flavor, failures)
 			return err
 		})
+	}
+	if introspectViews {
 		g.Go(func() (err error) {
-			schemas[n].Routines, err = querySchemaRoutines(ctx, schemaDB, rawSchema.Name, flavor)
+			schema.Views, err = querySchemaViews(ctx, schemaDB, schema.Name, failures)
 			return err
 		})
-		err = g.Wait()
-		schemaDB.Close()
-		if err != nil {
-			return nil, err
-		}
 	}
-	return schemas, nil
+	if flavor.MinMariaDB(10, 3) {
+		g.Go(func() (err error) {
+			schema.Sequences, err = querySchemaSequences(ctx, schemaDB, schema.Name, failures)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return failures.err(schema.Name)
+}
+
+// ObjectIntrospectionError is returned when one or more individual objects in
+// a schema could not be introspected, for example due to a failing SHOW CREATE
+// query. The schema's other objects were still introspected successfully.
+type ObjectIntrospectionError struct {
+	SchemaName string
+	Failures   map[ObjectKey]error
+}
+
+// Error satisfies the builtin error interface. The message lists each object
+// which failed introspection, along with its error.
+func (oie *ObjectIntrospectionError) Error() string {
+	keys := slices.SortedFunc(maps.Keys(oie.Failures), func(a, b ObjectKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "Error introspecting %d object(s) in schema %s:", len(keys), EscapeIdentifier(oie.SchemaName))
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n    %s: %s", key, oie.Failures[key])
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors for each failed object, permitting use of
+// errors.Is and errors.As.
+func (oie *ObjectIntrospectionError) Unwrap() []error {
+	return slices.Collect(maps.Values(oie.Failures))
+}
+
+// objectFailures collects the errors from introspecting individual objects, so
+// that one problematic object does not prevent introspection of the rest of its
+// schema. It is safe for concurrent use.
+type objectFailures struct {
+	m    sync.Mutex
+	errs map[ObjectKey]error
+}
+
+func (of *objectFailures) add(key ObjectKey, err error) {
+	of.m.Lock()
+	defer of.m.Unlock()
+	if of.errs == nil {
+		of.errs = make(map[ObjectKey]error)
+	}
+	of.errs[key] = err
+}
+
+func (of *objectFailures) failed(key ObjectKey) bool {
+	of.m.Lock()
+	defer of.m.Unlock()
+	_, failed := of.errs[key]
+	return failed
+}
+
+// err returns an *ObjectIntrospectionError if any failures were collected, or
+// nil otherwise.
+func (of *objectFailures) err(schemaName string) error {
+	of.m.Lock()
+	defer of.m.Unlock()
+	if len(of.errs) == 0 {
+		return nil
+	}
+	return &ObjectIntrospectionError{SchemaName: schemaName, Failures: maps.Clone(of.errs)}
+}
+
+// withoutFailures removes any objects which failed introspection from the
+// supplied slice, modifying it in-place, and returns the shortened slice.
+func withoutFailures[T ObjectKeyer](objects []T, failures *objectFailures) []T {
+	return slices.DeleteFunc(objects, func(obj T) bool {
+		return failures.failed(obj.ObjectKey())
+	})
 }
 
 // SchemasByName returns a map of schema name string to *Schema.  If
@@ -656,6 +914,39 @@ func (instance *Instance) TableSize(schema, table string) (int64, error) {
 	return result, err
 }
 
+// PartitionRowEstimates returns approximate row counts for each partition of
+// the table, based on information_schema.partitions. The result maps partition
+// name to row count; if the table uses subpartitions, each partition's count is
+// the sum of its subpartitions. Unpartitioned tables are represented by a single
+// entry with an empty-string key. If the table or schema does not exist on this
+// instance, the error will be sql.ErrNoRows.
+// For InnoDB tables these counts are only estimates, with the same accuracy
+// caveats as TableSize.
+func (instance *Instance) PartitionRowEstimates(schema, table string) (map[string]int64, error) {
+	db, err := instance.CachedConnectionPool("", instance.introspectionParams())
+	if err != nil {
+		return nil, err
+	}
+	var rawPartitions []struct {
+		Name sql.NullString `db:"partition_name"`
+		Rows sql.NullInt64  `db:"table_rows"`
+	}
+	query := `
+		SELECT   partition_name AS partition_name, table_rows AS table_rows
+		FROM     information_schema.partitions
+		WHERE    table_schema = ? AND table_name = ?`
+	if err := db.Select(&rawPartitions, query, schema, table); err != nil {
+		return nil, err
+	} else if len(rawPartitions) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	result := make(map[string]int64, len(rawPartitions))
+	for _, rp := range rawPartitions {
+		result[rp.Name.String] += rp.Rows.Int64
+	}
+	return result, nil
+}
+
 // TableHasRows returns true if the table has at least one row. If an error
 // occurs in querying, also returns true (along with the error) since a false
 // positive is generally less dangerous in this case than a false negative.
@@ -669,7 +960,7 @@ func (instance *Instance) TableHasRows(schema, table string) (bool, error) {
 
 func tableHasRows(db *sqlx.DB, schema, table string) (bool, error) {
 	var result []int
-	query := fmt.Sprintf("SELECT 1 FROM %s.%s LIMIT 1", EscapeIdentifier(schema), EscapeIdentifier(table))
+	query := "SELECT 1 FROM " + EscapeQualifiedIdentifier(schema, table) + " LIMIT 1"
 	if err := db.Select(&result, query); err != nil {
 		return true, fmt.Errorf("Error checking if table %s.%s is empty: %w", EscapeIdentifier(schema), EscapeIdentifier(table), err)
 	}
@@ -2,8 +2,10 @@ package tengo
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -438,6 +440,36 @@ func TestInstanceGrantChecksRegexes(t *testing.T) {
 	}
 }
 
+func TestObjectFailures(t *testing.T) {
+	failures := &objectFailures{}
+	if err := failures.err("foo"); err != nil {
+		t.Errorf("Expected nil error with no failures, instead found %v", err)
+	}
+
+	tables := []*Table{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	views := []*View{{Name: "b"}}
+	failures.add(ObjectKey{Type: ObjectTypeTable, Name: "b"}, sql.ErrNoRows)
+	failures.add(ObjectKey{Type: ObjectTypeTable, Name: "a"}, errors.New("oops"))
+	if tables = withoutFailures(tables, failures); len(tables) != 1 || tables[0].Name != "c" {
+		t.Errorf("Unexpected result from withoutFailures: %+v", tables)
+	}
+	if views = withoutFailures(views, failures); len(views) != 1 {
+		t.Errorf("Expected view to be unaffected by failure of table with same name, instead found %+v", views)
+	}
+
+	err := failures.err("foo")
+	var oie *ObjectIntrospectionError
+	if !errors.As(err, &oie) || oie.SchemaName != "foo" || len(oie.Failures) != 2 {
+		t.Fatalf("Unexpected error from objectFailures.err: %+v", err)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		t.Error("Expected errors.Is to find wrapped per-object error, but it did not")
+	}
+	expected := "Error introspecting 2 object(s) in schema `foo`:\n    table `a`: oops\n    table `b`: " + sql.ErrNoRows.Error()
+	if actual := err.Error(); actual != expected {
+		t.Errorf("Unexpected error message: expected %q, found %q", expected, actual)
+	}
+}
+
 func (s TengoIntegrationSuite) TestInstanceSchemas(t *testing.T) {
 	s.SourceTestSQL(t, "integration-ext.sql")
 
@@ -506,6 +538,60 @@ func (s TengoIntegrationSuite) TestInstanceSchemas(t *testing.T) {
 	}
 }
 
+func (s TengoIntegrationSuite) TestInstanceSchemasBulkIntrospection(t *testing.T) {
+	s.SourceTestSQL(t, "integration-ext.sql")
+	schemas, err := s.d.SchemasByName()
+	if err != nil {
+		t.Fatalf("Unexpected error from SchemasByName: %v", err)
+	}
+	s.d.SetBulkIntrospection(true)
+	defer s.d.SetBulkIntrospection(false)
+	bulkSchemas, err := s.d.SchemasByName()
+	if err != nil {
+		t.Fatalf("Unexpected error from SchemasByName in bulk mode: %v", err)
+	}
+	if len(bulkSchemas) != len(schemas) {
+		t.Fatalf("Expected %d schemas in bulk mode, instead found %d", len(schemas), len(bulkSchemas))
+	}
+
+	// Tables which are supported for diff in the per-table path must be identical
+	// in the bulk path
+	for name, schema := range schemas {
+		bulkTables := bulkSchemas[name].TablesByName()
+		for _, table := range schema.Tables {
+			if table.UnsupportedDDL {
+				continue
+			}
+			if bulkTable := bulkTables[table.Name]; !reflect.DeepEqual(table, bulkTable) {
+				t.Errorf("Table %s.%s differs in bulk introspection mode:\nexpected %+v\nfound    %+v", name, table.Name, table, bulkTable)
+			}
+		}
+	}
+}
+
+func (s TengoIntegrationSuite) TestInstanceSchemasRetainRawCreate(t *testing.T) {
+	s.SourceTestSQL(t, "integration-ext.sql")
+	schema := s.GetSchema(t, "testing")
+	for _, table := range schema.Tables {
+		if raw := table.RawCreate(); raw != "" {
+			t.Errorf("Expected table %s to have no raw CREATE by default, instead found %q", table.Name, raw)
+		}
+	}
+
+	s.d.SetRetainRawCreate(true)
+	defer s.d.SetRetainRawCreate(false)
+	schema = s.GetSchema(t, "testing")
+	for _, table := range schema.Tables {
+		expected, err := s.d.ShowCreateTable("testing", table.Name)
+		if err != nil {
+			t.Fatalf("Unexpected error from ShowCreateTable: %v", err)
+		}
+		if raw := table.RawCreate(); raw != expected {
+			t.Errorf("Table %s: expected RawCreate() to return SHOW CREATE TABLE output\n%s\ninstead found\n%s", table.Name, expected, raw)
+		}
+	}
+}
+
 func (s TengoIntegrationSuite) TestInstanceShowCreateTable(t *testing.T) {
 	t1create, err1 := s.d.ShowCreateTable("testing", "actor")
 	t2create, err2 := s.d.ShowCreateTable("testing", "actor_in_film")
@@ -546,6 +632,41 @@ func (s TengoIntegrationSuite) TestInstanceTableSize(t *testing.T) {
 	}
 }
 
+func (s TengoIntegrationSuite) TestInstancePartitionRowEstimates(t *testing.T) {
+	s.SourceTestSQL(t, "rows.sql", "partition.sql")
+	db, err := s.d.CachedConnectionPool("", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	for _, query := range []string{"INSERT INTO partitionparty.plist (id) VALUES (1), (5), (2)", "ANALYZE TABLE partitionparty.plist, testing.has_rows"} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Unexpected error from query %q: %v", query, err)
+		}
+	}
+
+	estimates, err := s.d.PartitionRowEstimates("partitionparty", "plist")
+	if err != nil {
+		t.Fatalf("Unexpected error from PartitionRowEstimates: %v", err)
+	} else if len(estimates) != 4 {
+		t.Errorf("Expected 4 partitions, instead found %d: %v", len(estimates), estimates)
+	} else if estimates["r0"] < 1 || estimates["r1"] < 1 || estimates["r2"] != 0 || estimates["r3"] != 0 {
+		t.Errorf("Unexpected row estimates: %v", estimates)
+	}
+
+	// Unpartitioned table should have a single entry with empty key
+	estimates, err = s.d.PartitionRowEstimates("testing", "has_rows")
+	if err != nil {
+		t.Fatalf("Unexpected error from PartitionRowEstimates: %v", err)
+	} else if _, ok := estimates[""]; len(estimates) != 1 || !ok {
+		t.Errorf("Unexpected estimates for unpartitioned table: %v", estimates)
+	}
+
+	// Nonexistent table
+	if _, err := s.d.PartitionRowEstimates("testing", "doesnt_exist"); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows for nonexistent table, instead found %v", err)
+	}
+}
+
 func (s TengoIntegrationSuite) TestInstanceTableHasRows(t *testing.T) {
 	s.SourceTestSQL(t, "rows.sql")
 	if hasRows, err := s.d.TableHasRows("testing", "has_rows"); err != nil {
@@ -844,3 +965,74 @@ func (s TengoIntegrationSuite) TestInstanceProcessList(t *testing.T) {
 		t.Error("Instance.ProcessList unexpectedly returned 0 rows")
 	}
 }
+
+func (s TengoIntegrationSuite) TestInstanceSchemasConcurrently(t *testing.T) {
+	s.SourceTestSQL(t, "integration-ext.sql")
+	serial, err := s.d.SchemasByName()
+	if err != nil {
+		t.Fatalf("Unexpected error from SchemasByName: %v", err)
+	}
+	concurrent, err := s.d.SchemasConcurrently(4)
+	if err != nil {
+		t.Fatalf("Unexpected error from SchemasConcurrently: %v", err)
+	} else if len(concurrent) != len(serial) {
+		t.Fatalf("Expected SchemasConcurrently to return %d schemas, instead found %d", len(serial), len(concurrent))
+	}
+	for _, schema := range concurrent {
+		if diff := serial[schema.Name].Diff(schema); len(diff.ObjectDiffs()) > 0 {
+			t.Errorf("Schema %s differs between serial and concurrent introspection", schema.Name)
+		}
+	}
+}
+
+// BenchmarkSchemasConcurrently measures introspection of several schemas with a
+// total of 400 tables, using various concurrency levels. It uses the first
+// image listed in the SKEEMA_TEST_IMAGES env var, and is skipped if that var is
+// not set.
+func BenchmarkSchemasConcurrently(b *testing.B) {
+	image, _, _ := strings.Cut(strings.TrimSpace(os.Getenv("SKEEMA_TEST_IMAGES")), ",")
+	if image == "" {
+		b.Skip("SKEEMA_TEST_IMAGES env var is not set")
+	}
+	opts := DockerizedInstanceOptions{
+		Name:         fmt.Sprintf("skeema-test-%s", ContainerNameForImage(image)),
+		Image:        image,
+		RootPassword: "fakepw",
+		DataTmpfs:    true,
+	}
+	d, err := GetOrCreateDockerizedInstance(opts)
+	if err != nil {
+		b.Fatalf("Unable to obtain DockerizedInstance: %v", err)
+	}
+	if err := d.NukeData(); err != nil {
+		b.Fatalf("Unable to clean DockerizedInstance: %v", err)
+	}
+	defer d.NukeData()
+
+	db, err := d.CachedConnectionPool("", "")
+	if err != nil {
+		b.Fatalf("Unable to connect to DockerizedInstance: %v", err)
+	}
+	for n := range 8 {
+		schemaName := fmt.Sprintf("bench%d", n)
+		if _, err := db.Exec("CREATE DATABASE " + schemaName); err != nil {
+			b.Fatalf("Unexpected error creating schema: %v", err)
+		}
+		for m := range 50 {
+			query := fmt.Sprintf("CREATE TABLE %s.t%d (id int unsigned NOT NULL AUTO_INCREMENT, name varchar(30), created_at timestamp NULL, PRIMARY KEY (id), KEY name_created (name, created_at))", schemaName, m)
+			if _, err := db.Exec(query); err != nil {
+				b.Fatalf("Unexpected error creating table: %v", err)
+			}
+		}
+	}
+
+	for _, concurrency := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for range b.N {
+				if _, err := d.SchemasConcurrently(concurrency); err != nil {
+					b.Fatalf("Unexpected error from SchemasConcurrently: %v", err)
+				}
+			}
+		})
+	}
+}
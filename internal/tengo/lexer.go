@@ -480,3 +480,118 @@ func TokenizeString(input string) []string {
 		}
 	}
 }
+
+// LowerCaseKeywords returns a copy of the supplied SQL with its keywords
+// converted to lower-case. Since DDL generated by this package always wraps
+// identifiers in backticks, any bare word consisting only of upper-case letters,
+// digits, and underscores is considered a keyword. Identifiers, strings,
+// numbers, mixed-case or lower-case bare words (such as "InnoDB" or "utf8mb4"),
+// and any bare word directly following an "=" (such as "MEMORY" in
+// "ENGINE=MEMORY") are left as-is. The contents of version-gated comments are
+// processed recursively, while other comments and whitespace are unchanged.
+func LowerCaseKeywords(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	lexer := NewLexer(strings.NewReader(input), "\000", 1024)
+	var prevSymbol string // value of previous non-filler token if it was a TokenSymbol
+	for {
+		val, typ, err := lexer.Scan()
+		if err != nil {
+			return b.String()
+		}
+		switch typ {
+		case TokenWord:
+			if prevSymbol != "=" && isUpperCaseWord(val) {
+				b.WriteString(strings.ToLower(string(val)))
+			} else {
+				b.Write(val)
+			}
+		case TokenFiller:
+			transformVersionComments(&b, val, LowerCaseKeywords)
+		default:
+			b.Write(val)
+		}
+		if typ == TokenSymbol {
+			prevSymbol = string(val)
+		} else if typ != TokenFiller {
+			prevSymbol = ""
+		}
+	}
+}
+
+// isUpperCaseWord returns true if word contains at least one upper-case ASCII
+// letter, and otherwise consists only of upper-case ASCII letters, digits, and
+// underscores.
+func isUpperCaseWord(word []byte) (hasUpper bool) {
+	for _, b := range word {
+		if b >= 'A' && b <= 'Z' {
+			hasUpper = true
+		} else if (b < '0' || b > '9') && b != '_' {
+			return false
+		}
+	}
+	return hasUpper
+}
+
+// ANSIQuoteIdentifiers returns a copy of the supplied SQL with each
+// backtick-wrapped identifier converted to be wrapped in double quotes instead,
+// as required when the ANSI_QUOTES sql_mode is enabled. Embedded backticks are
+// un-doubled and embedded double quotes are doubled; see EscapeIdentifierANSI.
+// Since DDL generated by this package always uses single quotes for string
+// literals, double-quoted strings are not expected in the input and are left
+// as-is. The contents of version-gated comments are processed recursively,
+// while other comments and whitespace are unchanged.
+func ANSIQuoteIdentifiers(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	lexer := NewLexer(strings.NewReader(input), "\000", 1024)
+	for {
+		val, typ, err := lexer.Scan()
+		if err != nil {
+			return b.String()
+		}
+		switch typ {
+		case TokenIdent:
+			ident := strings.ReplaceAll(string(val[1:len(val)-1]), "``", "`")
+			b.WriteString(EscapeIdentifierANSI(ident))
+		case TokenFiller:
+			transformVersionComments(&b, val, ANSIQuoteIdentifiers)
+		default:
+			b.Write(val)
+		}
+	}
+}
+
+// transformVersionComments writes filler to b, applying fn to the contents of
+// any version-gated comments (such as "/*!50100 ... */" or "/*M!100301 ... */").
+// The comment's opening sequence, including its version number, is retained
+// as-is.
+func transformVersionComments(b *strings.Builder, filler []byte, fn func(string) string) {
+	for {
+		start := bytes.Index(filler, []byte("/*"))
+		if start < 0 {
+			b.Write(filler)
+			return
+		}
+		end := bytes.Index(filler[start+2:], needleCloseComment)
+		if end < 0 {
+			b.Write(filler)
+			return
+		}
+		end += start + 2
+		b.Write(filler[:start])
+		comment := filler[start:end]
+		if bytes.HasPrefix(comment, []byte("/*!")) || bytes.HasPrefix(comment, []byte("/*M!")) {
+			n := bytes.IndexByte(comment, '!') + 1
+			for n < len(comment) && comment[n] >= '0' && comment[n] <= '9' {
+				n++
+			}
+			b.Write(comment[:n])
+			b.WriteString(fn(string(comment[n:])))
+		} else {
+			b.Write(comment)
+		}
+		b.Write(needleCloseComment)
+		filler = filler[end+len(needleCloseComment):]
+	}
+}
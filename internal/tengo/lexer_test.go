@@ -128,3 +128,89 @@ func TestLexerSplitBuffer(t *testing.T) {
 	}
 
 }
+
+func TestLowerCaseKeywords(t *testing.T) {
+	table := partitionedTable(FlavorUnknown)
+	table.Columns[2].Comment = "KEEP THIS"
+	table.SecondaryIndexes = append(table.SecondaryIndexes, &Index{
+		Name:      "info",
+		Parts:     []IndexPart{{ColumnName: "info", PrefixLength: 20}},
+		Type:      "BTREE",
+		Invisible: true,
+	})
+	table.CreateStatement = table.GeneratedCreateStatement(ParseFlavor("mysql:8.0"))
+	expected := "create table `prange` (\n" +
+		"  `id` int(10) unsigned not null auto_increment,\n" +
+		"  `customer_id` int(10) unsigned not null,\n" +
+		"  `info` text comment 'KEEP THIS',\n" +
+		"  primary key (`id`,`customer_id`),\n" +
+		"  key `info` (`info`(20)) /*!80000 invisible */\n" +
+		") engine=InnoDB default charset=latin1 row_format=REDUNDANT\n" +
+		"/*!50100 partition by range (customer_id)\n" +
+		"(partition p0 values less than (123) engine = InnoDB,\n" +
+		" partition p1 values less than (456) engine = InnoDB,\n" +
+		" partition p2 values less than maxvalue engine = InnoDB) */"
+	if actual := LowerCaseKeywords(table.CreateStatement); actual != expected {
+		t.Errorf("Unexpected result from LowerCaseKeywords.\nExpected:\n%s\nFound:\n%s", expected, actual)
+	}
+	if actual := LowerCaseKeywords(expected); actual != expected {
+		t.Errorf("Expected LowerCaseKeywords to be idempotent, but found:\n%s", actual)
+	}
+	if actual := LowerCaseKeywords("ALTER TABLE `t1` ENGINE=MEMORY, ROW_FORMAT=DYNAMIC /*M!100301 COMMENT 'X' */"); actual != "alter table `t1` engine=MEMORY, row_format=DYNAMIC /*M!100301 comment 'X' */" {
+		t.Errorf("Unexpected result from LowerCaseKeywords: %q", actual)
+	}
+
+	// Confirm StatementModifiers.LowerCaseKeywords affects both Statement and
+	// Clauses of a TableDiff
+	from, to := aTable(1), aTable(1)
+	to.Columns = append(to.Columns, &Column{
+		Name:     "NICKNAME",
+		Type:     ParseColumnType("varchar(20)"),
+		Nullable: true,
+		Default:  "'NONE'",
+	})
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	mods := StatementModifiers{AlgorithmClause: "inplace"}
+	if stmt, _ := td.Statement(mods); stmt != "ALTER TABLE `actor` ALGORITHM=INPLACE, ADD COLUMN `NICKNAME` varchar(20) DEFAULT 'NONE'" {
+		t.Errorf("Unexpected upper-case statement: %q", stmt)
+	}
+	mods.LowerCaseKeywords = true
+	if stmt, _ := td.Statement(mods); stmt != "alter table `actor` algorithm=INPLACE, add column `NICKNAME` varchar(20) default 'NONE'" {
+		t.Errorf("Unexpected lower-case statement: %q", stmt)
+	}
+	if clauses, _ := td.Clauses(mods); clauses != "algorithm=INPLACE, add column `NICKNAME` varchar(20) default 'NONE'" {
+		t.Errorf("Unexpected lower-case clauses: %q", clauses)
+	}
+	td = NewCreateTable(&to)
+	if clauses, _ := td.Clauses(mods); !strings.HasPrefix(clauses, "(\n  `actor_id` smallint(5) unsigned not null auto_increment,") {
+		t.Errorf("Unexpected lower-case clauses: %q", clauses)
+	}
+}
+
+func TestANSIQuoteIdentifiers(t *testing.T) {
+	input := "ALTER TABLE `t1` ADD COLUMN `a\"b` int DEFAULT NULL COMMENT 'x`y', ADD KEY `k` (`a\"b`) /*!80000 INVISIBLE */ /*!50100 PARTITION BY KEY (`a\"b`) */"
+	expected := `ALTER TABLE "t1" ADD COLUMN "a""b" int DEFAULT NULL COMMENT 'x` + "`" + `y', ADD KEY "k" ("a""b") /*!80000 INVISIBLE */ /*!50100 PARTITION BY KEY ("a""b") */`
+	if actual := ANSIQuoteIdentifiers(input); actual != expected {
+		t.Errorf("Unexpected result from ANSIQuoteIdentifiers.\nExpected: %s\nFound:    %s", expected, actual)
+	}
+
+	// Confirm StatementModifiers.ANSIQuotes affects both Statement and Clauses of
+	// a TableDiff, and combines properly with LowerCaseKeywords
+	from, to := aTable(1), aTable(1)
+	to.Columns = append(to.Columns, &Column{
+		Name:     "nick`name",
+		Type:     ParseColumnType("varchar(20)"),
+		Nullable: true,
+	})
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	mods := StatementModifiers{ANSIQuotes: true}
+	if stmt, _ := td.Statement(mods); stmt != `ALTER TABLE "actor" ADD COLUMN "nick`+"`"+`name" varchar(20)` {
+		t.Errorf("Unexpected ANSI_QUOTES statement: %q", stmt)
+	}
+	mods.LowerCaseKeywords = true
+	if clauses, _ := td.Clauses(mods); clauses != `add column "nick`+"`"+`name" varchar(20)` {
+		t.Errorf("Unexpected ANSI_QUOTES clauses: %q", clauses)
+	}
+}
@@ -21,6 +21,16 @@ type Token struct {
 	offset uint32 // starting position of val inside of Statement.Text
 }
 
+// start returns the token's starting byte offset, as an int.
+func (t Token) start() int {
+	return int(t.offset)
+}
+
+// end returns the byte offset immediately after the token.
+func (t Token) end() int {
+	return int(t.offset) + len(t.val)
+}
+
 // ParseStatements splits the contents of the supplied io.Reader into
 // distinct SQL statements. The filePath is descriptive and only used in error
 // messages.
@@ -612,15 +622,12 @@ func processCreateTable(p *parser, tokens []Token) (*Statement, error) {
 		p.stmt.ObjectType = ObjectTypeTable
 	}
 
-	// A different StatementType is used in these cases:
-	// * CREATE...SELECT: not supported since it mixes DDL with DML, isn't allowed
-	//   on database servers using GTID in MySQL 5.6-8.0.20, causes problems with
-	//   Skeema's workspace operation model, and presents potential security
-	//   problems in multi-tenant environments running Skeema with elevated grants
-	// * MariaDB system-versioned tables: these use a nonstandard value in
-	//   information_schema.tables.table_type, and Skeema does not yet introspect
-	//   them, causing `skeema pull` to delete their filesystem definition
-	_, tokens, found := p.skipUntilSequence(tokens, "SELECT", "WITH SYSTEM VERSIONING")
+	// A different StatementType is used for CREATE...SELECT: not supported since
+	// it mixes DDL with DML, isn't allowed on database servers using GTID in MySQL
+	// 5.6-8.0.20, causes problems with Skeema's workspace operation model, and
+	// presents potential security problems in multi-tenant environments running
+	// Skeema with elevated grants
+	_, tokens, found := p.skipUntilSequence(tokens, "SELECT")
 	if found {
 		p.stmt.Type = StatementTypeCreateUnsupported
 	}
@@ -761,8 +768,8 @@ func stripBackticks(input string) string {
 // String replacers for un-quoting/un-escaping strings, stored as globals to
 // avoid unnecessary duplication of effort
 var (
-	replacerSingleQuoted = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`, `\n`, "\n", `\r`, "\r", `\0`, "\000", `''`, `'`)
-	replacerDoubleQuoted = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`, `\n`, "\n", `\r`, "\r", `\0`, "\000", `""`, `"`)
+	replacerSingleQuoted = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`, `\n`, "\n", `\r`, "\r", `\0`, "\000", `\Z`, "\032", `\t`, "\t", `\b`, "\b", `''`, `'`)
+	replacerDoubleQuoted = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`, `\n`, "\n", `\r`, "\r", `\0`, "\000", `\Z`, "\032", `\t`, "\t", `\b`, "\b", `""`, `"`)
 )
 
 func stripAnyQuote(input string) string {
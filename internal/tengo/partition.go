@@ -2,6 +2,8 @@ package tengo
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -22,7 +24,8 @@ const (
 // populated, the rest of this package does not fully support subpartitioning
 // yet.
 type TablePartitioning struct {
-	Method             string            `json:"method"`              // one of "RANGE", "RANGE COLUMNS", "LIST", "LIST COLUMNS", "HASH", "LINEAR HASH", "KEY", or "LINEAR KEY"
+	Method             string            `json:"method"`              // one of "RANGE", "RANGE COLUMNS", "LIST", "LIST COLUMNS", "HASH", or "KEY"
+	Linear             bool              `json:"linear,omitempty"`    // true for LINEAR HASH or LINEAR KEY
 	SubMethod          string            `json:"subMethod,omitempty"` // one of "" (no sub-partitioning), "HASH", "LINEAR HASH", "KEY", or "LINEAR KEY"; not fully supported yet
 	Expression         string            `json:"expression"`
 	SubExpression      string            `json:"subExpression,omitempty"` // empty string if no sub-partitioning; not fully supported yet
@@ -41,7 +44,7 @@ func (tp *TablePartitioning) Definition(flavor Flavor) string {
 	if plMode == PartitionListDefault {
 		plMode = PartitionListCount
 		for n, p := range tp.Partitions {
-			if p.Values != "" || p.Comment != "" || p.DataDir != "" || p.Name != fmt.Sprintf("p%d", n) {
+			if p.Values != "" || p.Comment != "" || p.DataDir != "" || p.IndexDir != "" || p.Tablespace != "" || p.Name != fmt.Sprintf("p%d", n) {
 				plMode = PartitionListExplicit
 				break
 			}
@@ -71,10 +74,19 @@ func (tp *TablePartitioning) Definition(flavor Flavor) string {
 	return fmt.Sprintf("\n%s PARTITION BY %s%s%s", opener, tp.partitionBy(flavor), partitionsClause, closer)
 }
 
+// FullMethod returns the partitioning method, including the LINEAR keyword if
+// applicable, in the same form as information_schema.partitions.
+func (tp *TablePartitioning) FullMethod() string {
+	if tp.Linear {
+		return "LINEAR " + tp.Method
+	}
+	return tp.Method
+}
+
 // partitionBy returns the partitioning method and expression, formatted to
 // match SHOW CREATE TABLE's extremely arbitrary, completely inconsistent way.
 func (tp *TablePartitioning) partitionBy(flavor Flavor) string {
-	method, expr := fmt.Sprintf("%s ", tp.Method), tp.Expression
+	method, expr := tp.FullMethod()+" ", tp.Expression
 
 	if tp.Method == "RANGE COLUMNS" {
 		method = "RANGE  COLUMNS"
@@ -88,16 +100,33 @@ func (tp *TablePartitioning) partitionBy(flavor Flavor) string {
 	// TODO handle edge cases where the backticks are still present: column name is
 	// a keyword (even if not a *reserved* word) or contains special characters.
 	// See https://github.com/skeema/skeema/issues/199
-	if (strings.HasSuffix(tp.Method, "COLUMNS") || strings.HasSuffix(tp.Method, "KEY")) && !flavor.MinMariaDB(10, 2) {
+	if (strings.HasSuffix(tp.Method, "COLUMNS") || tp.Method == "KEY") && !flavor.MinMariaDB(10, 2) {
 		expr = strings.ReplaceAll(expr, "`", "")
 	}
 
-	return method + tp.AlgoClause + "(" + expr + ")"
+	return method + tp.algoClause() + "(" + expr + ")"
+}
+
+// algoClause returns the KEY ALGORITHM clause in the canonical form used by
+// SHOW CREATE TABLE, including a trailing space, or an empty string if there
+// is no ALGORITHM clause. This permits AlgoClause to be supplied with or
+// without surrounding whitespace or spaces around the equals sign.
+func (tp *TablePartitioning) algoClause() string {
+	if tp.AlgoClause == "" {
+		return ""
+	}
+	if matches := reAlgoClause.FindStringSubmatch(tp.AlgoClause); matches != nil {
+		return "ALGORITHM = " + matches[1] + " "
+	}
+	return strings.TrimSpace(tp.AlgoClause) + " "
 }
 
+var reAlgoClause = regexp.MustCompile(`(?i)^\s*ALGORITHM\s*=\s*(\d+)\s*$`)
+
 // Diff returns a set of differences between this TablePartitioning and another
 // TablePartitioning. If supported==true, the returned clauses (if executed)
-// would transform tp into other.
+// would transform tp into other. If supported==false, UnsupportedReasons may be
+// used to obtain a human-readable explanation of each unsupported difference.
 func (tp *TablePartitioning) Diff(other *TablePartitioning) (clauses []TableAlterClause, supported bool) {
 	// Handle cases where one or both sides are nil, meaning one or both tables are
 	// unpartitioned
@@ -110,9 +139,9 @@ func (tp *TablePartitioning) Diff(other *TablePartitioning) (clauses []TableAlte
 	}
 
 	// Modifications to partitioning method or expression: re-partition
-	if tp.Method != other.Method || tp.SubMethod != other.SubMethod ||
+	if tp.Method != other.Method || tp.Linear != other.Linear || tp.SubMethod != other.SubMethod ||
 		tp.Expression != other.Expression || tp.SubExpression != other.SubExpression ||
-		tp.AlgoClause != other.AlgoClause {
+		tp.algoClause() != other.algoClause() {
 		clause := PartitionBy{
 			Partitioning: other,
 			RePartition:  true,
@@ -120,38 +149,247 @@ func (tp *TablePartitioning) Diff(other *TablePartitioning) (clauses []TableAlte
 		return []TableAlterClause{clause}, true
 	}
 
+	// RANGE and LIST tables cannot drop their last partition, so an attempt to
+	// drop down to zero partitions is handled by removing partitioning instead
+	if len(tp.Partitions) > 0 && len(other.Partitions) == 0 && tp.partitionListIgnored() {
+		clause := RemovePartitioning{
+			Warning: fmt.Sprintf("all %d %s partitions would be dropped, which is not permitted; removing partitioning instead", len(tp.Partitions), tp.FullMethod()),
+		}
+		return []TableAlterClause{clause}, true
+	}
+
 	// Modifications to partition list: ignored for RANGE, RANGE COLUMNS, LIST,
 	// LIST COLUMNS via generation of a no-op placeholder clause. This is done
 	// to side-step the safety mechanism at the end of Table.Diff() which treats 0
 	// clauses as indicative of an unsupported diff.
 	// For other partitioning methods, changing the partition list is currently
 	// unsupported.
-	var foundPartitionsDiff bool
-	if len(tp.Partitions) != len(other.Partitions) {
-		foundPartitionsDiff = true
-	} else {
-		for n := range tp.Partitions {
-			// all Partition fields are scalars, so simple comparison is fine
-			if *tp.Partitions[n] != *other.Partitions[n] {
-				foundPartitionsDiff = true
-				break
+	// Changes to the storage location of existing RANGE or LIST partitions are
+	// not ignored though: these are expressed by reorganizing the affected
+	// partitions into themselves.
+	reasons := tp.partitionListDiffReasons(other)
+	if len(reasons) > 0 && tp.partitionListIgnored() {
+		if clause, ok := tp.relocatePartitions(other); ok {
+			return []TableAlterClause{clause}, true
+		}
+		return []TableAlterClause{ModifyPartitions{}}, true
+	}
+	return nil, len(reasons) == 0
+}
+
+// UnsupportedReasons returns a slice of human-readable reasons explaining why
+// the difference between tp and other cannot be expressed by Diff. If Diff
+// would return supported==true, the result is nil.
+func (tp *TablePartitioning) UnsupportedReasons(other *TablePartitioning) []string {
+	if tp == nil || other == nil {
+		return nil
+	} else if _, supported := tp.Diff(other); supported {
+		return nil
+	}
+	return tp.partitionListDiffReasons(other)
+}
+
+// DecomposedDiff returns the same differences as Diff, except that for tables
+// using RANGE, RANGE COLUMNS, LIST, or LIST COLUMNS partitioning, a change to
+// the partition list is expressed as an ordered slice of granular
+// AddPartitions, DropPartitions, and ReorganizePartitions clauses. Each clause
+// must be run in a separate ALTER TABLE, in the order returned. This permits
+// partition changes on very large tables to be performed in smaller discrete
+// steps.
+// If the partition list change cannot be decomposed, the result is instead a
+// single PartitionBy clause which re-partitions the table, and fallbackReason
+// explains why decomposition was not possible.
+func (tp *TablePartitioning) DecomposedDiff(other *TablePartitioning) (clauses []TableAlterClause, fallbackReason string) {
+	clauses, supported := tp.Diff(other)
+	rePartition := []TableAlterClause{PartitionBy{Partitioning: other, RePartition: true}}
+	if !supported {
+		return rePartition, fmt.Sprintf("changes to the partition list are not supported for %s partitioning", tp.FullMethod())
+	} else if len(clauses) != 1 {
+		return clauses, ""
+	}
+	switch clause := clauses[0].(type) {
+	case PartitionBy:
+		if clause.RePartition {
+			return clauses, "partitioning method or expression is changing"
+		}
+		return clauses, ""
+	case ModifyPartitions:
+		if tp.SubMethod != "" {
+			return rePartition, "subpartitioned tables cannot be modified one partition at a time"
+		}
+		decomposed, reason := tp.decomposePartitionList(other)
+		if reason != "" {
+			return rePartition, reason
+		}
+		return decomposed, ""
+	}
+	return clauses, ""
+}
+
+// decomposePartitionList returns granular clauses transforming the partition
+// list of tp into that of other. Partitions which are identical on both sides
+// are used as fixed anchor points; each run of differing partitions between
+// anchors is converted into one clause. If this is not possible, a non-empty
+// reason is returned instead.
+func (tp *TablePartitioning) decomposePartitionList(other *TablePartitioning) (clauses []TableAlterClause, reason string) {
+	fromPos := make(map[string]int, len(tp.Partitions))
+	for n, p := range tp.Partitions {
+		fromPos[p.Name] = n
+	}
+	var fromStart, toStart int
+	for toEnd := 0; toEnd <= len(other.Partitions); toEnd++ {
+		fromEnd, tail := len(tp.Partitions), (toEnd == len(other.Partitions))
+		if !tail {
+			n, ok := fromPos[other.Partitions[toEnd].Name]
+			if !ok || n < fromStart || !tp.Partitions[n].equals(other.Partitions[toEnd], tp.Method) {
+				continue // not an anchor point
+			}
+			fromEnd = n
+		}
+		fromRun, toRun := tp.Partitions[fromStart:fromEnd], other.Partitions[toStart:toEnd]
+		for _, p := range toRun {
+			if n, ok := fromPos[p.Name]; ok && (n < fromStart || n >= fromEnd) {
+				return nil, fmt.Sprintf("partition %s would be moved relative to other partitions", p.Name)
+			}
+		}
+		if len(fromRun) == 0 && len(toRun) > 0 && tail {
+			clauses = append(clauses, AddPartitions{Method: tp.Method, Partitions: toRun})
+		} else if len(fromRun) == 0 && len(toRun) > 0 {
+			// Inserting partitions before an anchor requires reorganizing the anchor
+			clauses = append(clauses, ReorganizePartitions{
+				Method: tp.Method,
+				From:   tp.Partitions[fromEnd : fromEnd+1],
+				To:     other.Partitions[toStart : toEnd+1],
+			})
+		} else if len(toRun) == 0 && len(fromRun) > 0 {
+			clauses = append(clauses, DropPartitions{Partitions: fromRun})
+		} else if len(fromRun) > 0 {
+			// Aside from the final partition, reorganizing RANGE partitions cannot
+			// change the overall range covered
+			if !tail && strings.HasPrefix(tp.Method, "RANGE") && !fromRun[len(fromRun)-1].sameValues(toRun[len(toRun)-1], tp.Method) {
+				return nil, fmt.Sprintf("reorganizing partitions %s into %s would change the range of values covered", partitionNames(fromRun), partitionNames(toRun))
 			}
+			clauses = append(clauses, ReorganizePartitions{Method: tp.Method, From: fromRun, To: toRun})
 		}
+		fromStart, toStart = fromEnd+1, toEnd+1
 	}
-	if foundPartitionsDiff && (strings.HasPrefix(tp.Method, "RANGE") || strings.HasPrefix(tp.Method, "LIST")) {
-		return []TableAlterClause{ModifyPartitions{}}, true
+	return clauses, ""
+}
+
+// withTableEngine returns tp if all of its partitions have an explicit storage
+// engine. Otherwise, it returns a copy of tp in which each partition lacking an
+// engine uses the supplied table engine instead, since all partitions of a
+// table share the table's storage engine. This permits comparing and rendering
+// partitions regardless of whether their engine was specified explicitly.
+func (tp *TablePartitioning) withTableEngine(engine string) *TablePartitioning {
+	if tp == nil || !slices.ContainsFunc(tp.Partitions, func(p *Partition) bool { return p.Engine == "" }) {
+		return tp
 	}
-	return nil, !foundPartitionsDiff
+	tpCopy := *tp
+	tpCopy.Partitions = make([]*Partition, len(tp.Partitions))
+	for n, p := range tp.Partitions {
+		pCopy := *p
+		if pCopy.Engine == "" {
+			pCopy.Engine = engine
+		}
+		tpCopy.Partitions[n] = &pCopy
+	}
+	return &tpCopy
+}
+
+// relocatePartitions returns a ReorganizePartitions clause which moves the
+// partitions of tp to the data directory, index directory, or tablespace used
+// by the corresponding partitions of other. ok is false if any other aspect of
+// the partition list differs, or if no storage locations differ, or if tp is
+// subpartitioned.
+func (tp *TablePartitioning) relocatePartitions(other *TablePartitioning) (clause ReorganizePartitions, ok bool) {
+	if tp.SubMethod != "" || len(tp.Partitions) != len(other.Partitions) {
+		return clause, false
+	}
+	first, last := -1, -1
+	for n, from := range tp.Partitions {
+		to := other.Partitions[n]
+		if from.Name != to.Name || from.SubName != to.SubName || from.Engine != to.Engine || !from.sameValues(to, tp.Method) {
+			return clause, false
+		}
+		if from.DataDir != to.DataDir || from.IndexDir != to.IndexDir || from.Tablespace != to.Tablespace {
+			if first == -1 {
+				first = n
+			}
+			last = n
+		}
+	}
+	if first == -1 {
+		return clause, false
+	}
+	clause = ReorganizePartitions{
+		Method: tp.Method,
+		From:   tp.Partitions[first : last+1],
+		To:     other.Partitions[first : last+1],
+	}
+	return clause, true
+}
+
+// partitionListIgnored returns true if tp's partitioning method is one where
+// changes to the partition list are intentionally ignored by Diff.
+func (tp *TablePartitioning) partitionListIgnored() bool {
+	return strings.HasPrefix(tp.Method, "RANGE") || strings.HasPrefix(tp.Method, "LIST")
+}
+
+// partitionListDiffReasons returns a description of each difference in the
+// partition lists of tp and other, in a stable order. The result is nil if the
+// partition lists are identical.
+func (tp *TablePartitioning) partitionListDiffReasons(other *TablePartitioning) (reasons []string) {
+	if len(tp.Partitions) != len(other.Partitions) {
+		return []string{fmt.Sprintf("changing %s partition count from %d to %d", tp.FullMethod(), len(tp.Partitions), len(other.Partitions))}
+	}
+	for n, from := range tp.Partitions {
+		to := other.Partitions[n]
+		// all Partition fields are scalars, so simple comparison is fine
+		if *from == *to {
+			continue
+		}
+		if from.Name != to.Name {
+			reasons = append(reasons, fmt.Sprintf("renaming %s partition %s to %s", tp.FullMethod(), from.Name, to.Name))
+		}
+		if from.SubName != to.SubName {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s subpartition name", tp.FullMethod(), to.Name))
+		}
+		if !from.sameValues(to, tp.Method) {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s values", tp.FullMethod(), to.Name))
+		}
+		if from.Comment != to.Comment {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s comment", tp.FullMethod(), to.Name))
+		}
+		if from.Engine != to.Engine {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s storage engine", tp.FullMethod(), to.Name))
+		}
+		if from.DataDir != to.DataDir {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s data directory", tp.FullMethod(), to.Name))
+		}
+		if from.IndexDir != to.IndexDir {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s index directory", tp.FullMethod(), to.Name))
+		}
+		if from.Tablespace != to.Tablespace {
+			reasons = append(reasons, fmt.Sprintf("changing %s partition %s tablespace", tp.FullMethod(), to.Name))
+		}
+	}
+	return reasons
 }
 
-// Partition stores information on a single partition.
+// Partition stores information on a single partition. Per-partition COMPRESSION
+// and ENCRYPTION attributes are not tracked, since neither MySQL nor MariaDB
+// permits these at the partition level; they can only be set for the table as
+// a whole.
 type Partition struct {
-	Name    string `json:"name"`
-	SubName string `json:"subName,omitempty"` // empty string if no sub-partitioning; not fully supported yet
-	Values  string `json:"values,omitempty"`  // only populated for RANGE or LIST
-	Comment string `json:"comment,omitempty"`
-	Engine  string `json:"engine"`
-	DataDir string `json:"dataDir,omitempty"`
+	Name       string `json:"name"`
+	SubName    string `json:"subName,omitempty"` // empty string if no sub-partitioning; not fully supported yet
+	Values     string `json:"values,omitempty"`  // only populated for RANGE or LIST
+	Comment    string `json:"comment,omitempty"`
+	Engine     string `json:"engine"`
+	DataDir    string `json:"dataDir,omitempty"`
+	IndexDir   string `json:"indexDir,omitempty"`   // only meaningful for MyISAM
+	Tablespace string `json:"tablespace,omitempty"` // only supported by MySQL
 }
 
 // Definition returns this partition's definition clause, for use as part of a
@@ -175,15 +413,65 @@ func (p *Partition) Definition(flavor Flavor, method string) string {
 		values = fmt.Sprintf("VALUES IN (%s) ", p.Values)
 	}
 
-	var dataDir string
+	// MySQL 8.0 wraps partition tablespace names in backticks, whereas 5.7 does
+	// not. MariaDB and older MySQL accept but ignore per-partition tablespaces.
+	var tablespace string
+	if p.Tablespace != "" && flavor.MinMySQL(8) {
+		tablespace = fmt.Sprintf("TABLESPACE = %s ", EscapeIdentifier(p.Tablespace))
+	} else if p.Tablespace != "" && !flavor.IsMariaDB() && !flavor.IsMySQL(5, 5) && !flavor.IsMySQL(5, 6) {
+		tablespace = fmt.Sprintf("TABLESPACE = %s ", p.Tablespace)
+	}
+
+	var dataDir, indexDir string
 	if p.DataDir != "" {
 		dataDir = fmt.Sprintf("DATA DIRECTORY = '%s' ", p.DataDir) // any necessary escaping is already present in p.DataDir
 	}
+	if p.IndexDir != "" {
+		indexDir = fmt.Sprintf("INDEX DIRECTORY = '%s' ", p.IndexDir) // same as above
+	}
 
 	var comment string
 	if p.Comment != "" {
 		comment = fmt.Sprintf("COMMENT = '%s' ", EscapeValueForCreateTable(p.Comment))
 	}
 
-	return fmt.Sprintf("PARTITION %s %s%s%sENGINE = %s", name, values, dataDir, comment, p.Engine)
+	return fmt.Sprintf("PARTITION %s %s%s%s%s%sENGINE = %s", name, values, tablespace, dataDir, indexDir, comment, p.Engine)
+}
+
+// rangeColumnsValues splits the Values of a RANGE COLUMNS partition into one
+// bound per partitioning column. Each bound is either a literal or MAXVALUE,
+// which may be used in any position of the tuple. MAXVALUE is normalized to
+// uppercase, and any whitespace between tokens is removed.
+func (p *Partition) rangeColumnsValues() (result []string) {
+	var b strings.Builder
+	for _, token := range TokenizeString(p.Values) {
+		if token == "," {
+			result = append(result, b.String())
+			b.Reset()
+		} else if strings.EqualFold(token, "MAXVALUE") {
+			b.WriteString("MAXVALUE")
+		} else {
+			b.WriteString(token)
+		}
+	}
+	return append(result, b.String())
+}
+
+// equals returns true if p and other are identical, treating equivalent
+// Values as equal for the supplied partitioning method.
+func (p *Partition) equals(other *Partition, method string) bool {
+	pCopy, otherCopy := *p, *other
+	pCopy.Values, otherCopy.Values = "", ""
+	return pCopy == otherCopy && p.sameValues(other, method)
+}
+
+// sameValues returns true if p and other have equivalent Values for the
+// supplied partitioning method.
+func (p *Partition) sameValues(other *Partition, method string) bool {
+	if p.Values == other.Values {
+		return true
+	} else if method != "RANGE COLUMNS" {
+		return false
+	}
+	return slices.Equal(p.rangeColumnsValues(), other.rangeColumnsValues())
 }
@@ -3,6 +3,7 @@ package tengo
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -142,6 +143,390 @@ func TestTableAlterPartitioningOther(t *testing.T) {
 	p1.Partitioning.Method, p2.Partitioning.Method = "HASH", "HASH"
 	assertUnsupported(&p1, &p2)
 	assertUnsupported(&p2, &p1)
+
+	// Dropping all partitions of a RANGE table must instead remove partitioning
+	p1, p2 = partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	p2.Partitioning.Partitions = []*Partition{}
+	p2.CreateStatement = ""
+	if tableAlters, supported := p1.Diff(&p2); !supported || len(tableAlters) != 1 {
+		t.Errorf("Unexpected return from Diff: %d alters / %t supported", len(tableAlters), supported)
+	} else if rp, ok := tableAlters[0].(RemovePartitioning); !ok {
+		t.Errorf("Expected RemovePartitioning clause, instead found %T", tableAlters[0])
+	} else if rp.Warning == "" || rp.Clause(StatementModifiers{}) != "REMOVE PARTITIONING" {
+		t.Errorf("Unexpected RemovePartitioning: warning=%q clause=%q", rp.Warning, rp.Clause(StatementModifiers{}))
+	}
+	if rp, _ := partitionedTable(FlavorUnknown).Partitioning.Diff(nil); rp[0].(RemovePartitioning).Warning != "" {
+		t.Error("Expected no warning when removing partitioning from the desired table entirely")
+	}
+}
+
+func TestTablePartitioningDecomposedDiff(t *testing.T) {
+	rangeParts := func(defs ...string) *TablePartitioning {
+		tp := &TablePartitioning{Method: "RANGE", Expression: "customer_id"}
+		for _, def := range defs {
+			name, values, _ := strings.Cut(def, " ")
+			tp.Partitions = append(tp.Partitions, &Partition{Name: name, Values: values, Engine: "InnoDB"})
+		}
+		return tp
+	}
+	assertDecomposed := func(from, to *TablePartitioning, expected ...string) {
+		t.Helper()
+		clauses, reason := from.DecomposedDiff(to)
+		if reason != "" {
+			t.Errorf("Unexpected fallback reason: %s", reason)
+		}
+		var actual []string
+		for _, clause := range clauses {
+			actual = append(actual, clause.Clause(StatementModifiers{}))
+		}
+		if !slices.Equal(actual, expected) {
+			t.Errorf("Unexpected clauses from DecomposedDiff:\nexpected %q\nfound    %q", expected, actual)
+		}
+	}
+	assertFallback := func(from, to *TablePartitioning, reasonSubstring string) {
+		t.Helper()
+		clauses, reason := from.DecomposedDiff(to)
+		if !strings.Contains(reason, reasonSubstring) {
+			t.Errorf("Expected fallback reason containing %q, instead found %q", reasonSubstring, reason)
+		}
+		if len(clauses) != 1 {
+			t.Fatalf("Expected 1 clause, instead found %d", len(clauses))
+		} else if pb, ok := clauses[0].(PartitionBy); !ok || !pb.RePartition || pb.Partitioning != to {
+			t.Errorf("Expected fallback to re-partition, instead found %+v", clauses[0])
+		}
+	}
+
+	from := rangeParts("p0 10", "p1 20")
+	assertDecomposed(from, rangeParts("p0 10", "p1 20"))
+	assertDecomposed(from, rangeParts("p0 10", "p1 20", "p2 30", "p3 40"),
+		"ADD PARTITION (PARTITION p2 VALUES LESS THAN (30) ENGINE = InnoDB, PARTITION p3 VALUES LESS THAN (40) ENGINE = InnoDB)",
+	)
+	assertDecomposed(rangeParts("p0 10", "p1 20", "p2 30"), rangeParts("p1 20", "p2 30"),
+		"DROP PARTITION `p0`",
+	)
+	assertDecomposed(rangeParts("p0 10", "p2 30"), rangeParts("p0 10", "p1 20", "p2 30"),
+		"REORGANIZE PARTITION `p2` INTO (PARTITION p1 VALUES LESS THAN (20) ENGINE = InnoDB, PARTITION p2 VALUES LESS THAN (30) ENGINE = InnoDB)",
+	)
+	to := rangeParts("p1 20", "p2 30", "p3 40")
+	to.Partitions[1].Comment = "hot"
+	assertDecomposed(rangeParts("p0 10", "p1 20", "p2 30"), to,
+		"DROP PARTITION `p0`",
+		"REORGANIZE PARTITION `p2` INTO (PARTITION p2 VALUES LESS THAN (30) COMMENT = 'hot' ENGINE = InnoDB, PARTITION p3 VALUES LESS THAN (40) ENGINE = InnoDB)",
+	)
+	assertDecomposed(rangeParts("p0 10", "p1 20", "p2 30", "p3 40"), rangeParts("p0 10", "p1a 15", "p1b 30", "p3 40"),
+		"REORGANIZE PARTITION `p1`, `p2` INTO (PARTITION p1a VALUES LESS THAN (15) ENGINE = InnoDB, PARTITION p1b VALUES LESS THAN (30) ENGINE = InnoDB)",
+	)
+
+	// With LaxComments, reorganizing solely to change comments is suppressed,
+	// but other reorganizations are not
+	to = rangeParts("p0 10", "p1 20", "p2 30")
+	to.Partitions[1].Comment = "hot"
+	clauses, _ := rangeParts("p0 10", "p1 20", "p2 30").DecomposedDiff(to)
+	if len(clauses) != 1 {
+		t.Errorf("Expected 1 clause, instead found %d", len(clauses))
+	} else if clause := clauses[0].Clause(StatementModifiers{LaxComments: true}); clause != "" {
+		t.Errorf("Expected LaxComments to suppress comment-only reorganization, instead found %q", clause)
+	} else if clause := clauses[0].Clause(StatementModifiers{}); clause == "" {
+		t.Error("Expected comment-only reorganization to be emitted without LaxComments")
+	}
+	to.Partitions[2].Values = "40"
+	clauses, _ = rangeParts("p0 10", "p1 20", "p2 30").DecomposedDiff(to)
+	if len(clauses) != 1 {
+		t.Errorf("Expected 1 clause, instead found %d", len(clauses))
+	} else if _, ok := clauses[0].(ReorganizePartitions); !ok || clauses[0].Clause(StatementModifiers{LaxComments: true}) == "" {
+		t.Errorf("Expected LaxComments to have no effect on reorganization which changes values; clauses=%+v", clauses)
+	}
+
+	// Situations where decomposition is not possible
+	assertFallback(rangeParts("p0 10", "p1 20", "p2 30"), rangeParts("p0 10", "p1 25", "p2 30"), "range of values")
+	listFrom, listTo := rangeParts("a 1", "b 2", "c 3"), rangeParts("a 1", "c 3", "b 2")
+	listFrom.Method, listTo.Method = "LIST", "LIST"
+	assertFallback(listFrom, listTo, "partition b would be moved")
+	to = rangeParts("p0 10", "p1 20", "p2 30")
+	to.Method = "LIST"
+	assertFallback(from, to, "method or expression")
+	hashFrom, hashTo := &TablePartitioning{Method: "HASH", Expression: "id"}, &TablePartitioning{Method: "HASH", Expression: "id"}
+	hashFrom.Partitions = []*Partition{{Name: "p0", Engine: "InnoDB"}}
+	hashTo.Partitions = []*Partition{{Name: "p0", Engine: "InnoDB"}, {Name: "p1", Engine: "InnoDB"}}
+	assertFallback(hashFrom, hashTo, "not supported for HASH")
+	subFrom, subTo := rangeParts("p0 10"), rangeParts("p0 10", "p1 20")
+	subFrom.SubMethod, subTo.SubMethod = "HASH", "HASH"
+	assertFallback(subFrom, subTo, "subpartitioned")
+
+	// Table-level wrapper emits a separate ALTER TABLE per clause
+	fromTable, toTable := partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	toTable.Partitioning.Partitions = []*Partition{
+		{Name: "p1", Values: "456"},
+		{Name: "p2a", Values: "789"},
+		{Name: "p2", Values: "MAXVALUE"},
+	}
+	alters, reason := PartitionAlters(&fromTable, &toTable)
+	if reason != "" || len(alters) != 2 {
+		t.Fatalf("Unexpected return from PartitionAlters: %d alters, reason %q", len(alters), reason)
+	}
+	mods := StatementModifiers{LockClause: "none", AlgorithmClause: "inplace"}
+	if stmt, err := alters[0].Statement(mods); stmt != "ALTER TABLE `"+fromTable.Name+"` DROP PARTITION `p0`" || !IsUnsafeDiff(err) {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	expected := "ALTER TABLE `" + fromTable.Name + "` REORGANIZE PARTITION `p2` INTO (PARTITION p2a VALUES LESS THAN (789) ENGINE = InnoDB, PARTITION p2 VALUES LESS THAN MAXVALUE ENGINE = InnoDB)"
+	if stmt, err := alters[1].Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+}
+
+func TestExchangePartition(t *testing.T) {
+	ep := ExchangePartition{PartitionName: "p1", StagingTable: "staging"}
+	cases := map[string]string{
+		"mysql:5.6":    "EXCHANGE PARTITION `p1` WITH TABLE `staging`",
+		"mysql:8.0":    "EXCHANGE PARTITION `p1` WITH TABLE `staging` WITHOUT VALIDATION",
+		"mariadb:10.6": "EXCHANGE PARTITION `p1` WITH TABLE `staging`",
+		"mariadb:11.4": "EXCHANGE PARTITION `p1` WITH TABLE `staging` WITHOUT VALIDATION",
+	}
+	for flavorString, expected := range cases {
+		mods := StatementModifiers{Flavor: ParseFlavor(flavorString)}
+		ep.WithoutValidation = false
+		if clause := ep.Clause(mods); clause != cases["mysql:5.6"] {
+			t.Errorf("Unexpected clause for flavor %s with validation: %q", flavorString, clause)
+		}
+		ep.WithoutValidation = true
+		if clause := ep.Clause(mods); clause != expected {
+			t.Errorf("Unexpected clause for flavor %s without validation: expected %q, found %q", flavorString, expected, clause)
+		}
+	}
+	if unsafe, _ := ep.Unsafe(StatementModifiers{}); !unsafe {
+		t.Error("Expected ExchangePartition to be unsafe, but it was not")
+	}
+
+	// ALGORITHM and LOCK clauses must be omitted, since EXCHANGE PARTITION cannot
+	// be combined with other clauses
+	table := partitionedTable(FlavorUnknown)
+	td := &TableDiff{
+		Type:         DiffTypeAlter,
+		From:         &table,
+		To:           &table,
+		alterClauses: []TableAlterClause{ep},
+		supported:    true,
+	}
+	mods := StatementModifiers{Flavor: ParseFlavor("mysql:8.0"), AllowUnsafe: true, LockClause: "none", AlgorithmClause: "inplace"}
+	expected := "ALTER TABLE `" + table.Name + "` EXCHANGE PARTITION `p1` WITH TABLE `staging` WITHOUT VALIDATION"
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected return from Statement(): %q / %v", stmt, err)
+	}
+}
+
+func TestTablePartitioningUnsupportedReasons(t *testing.T) {
+	p1, p2 := partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	p1.Partitioning.Method, p2.Partitioning.Method = "HASH", "HASH"
+	for _, p := range append(p1.Partitioning.Partitions, p2.Partitioning.Partitions...) {
+		p.Values = ""
+	}
+	assertReasons := func(expected ...string) {
+		t.Helper()
+		p2.CreateStatement = "" // bypass diff logic short-circuit on matching CreateStatement
+		actual := p1.Partitioning.UnsupportedReasons(p2.Partitioning)
+		if !slices.Equal(actual, expected) {
+			t.Errorf("Unexpected return from UnsupportedReasons: expected %q, found %q", expected, actual)
+		}
+		td := NewAlterTable(&p1, &p2)
+		if len(expected) == 0 {
+			if td != nil {
+				t.Errorf("Expected nil TableDiff, instead found %+v", td)
+			}
+		} else if tdReasons := td.UnsupportedReasons(); !slices.Equal(tdReasons, expected) {
+			t.Errorf("Unexpected return from TableDiff.UnsupportedReasons: expected %q, found %q", expected, tdReasons)
+		}
+	}
+
+	assertReasons()
+	p2.Partitioning.Partitions[1].DataDir = "/some/weird/dir"
+	p2.Partitioning.Partitions[2].Comment = "hello world"
+	assertReasons("changing HASH partition p1 data directory", "changing HASH partition p2 comment")
+	p2.Partitioning.Partitions = p2.Partitioning.Partitions[0:2]
+	assertReasons("changing HASH partition count from 3 to 2")
+
+	// Reasons include the full partitioning method, and cover each attribute of
+	// individual partitions
+	p1, p2 = partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	for _, tbl := range []*Table{&p1, &p2} {
+		tbl.Partitioning.Method, tbl.Partitioning.Linear = "KEY", true
+		for _, p := range tbl.Partitioning.Partitions {
+			p.Values = ""
+		}
+	}
+	p2.Partitioning.Partitions[0].Name = "p_zero"
+	p2.Partitioning.Partitions[1].IndexDir = "/some/idx/dir"
+	p2.Partitioning.Partitions[2].Tablespace = "innodb_file_per_table"
+	assertReasons("renaming LINEAR KEY partition p0 to p_zero", "changing LINEAR KEY partition p1 index directory", "changing LINEAR KEY partition p2 tablespace")
+
+	// Partition list changes are ignored for RANGE, so no reasons should be
+	// returned
+	p1, p2 = partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	p2.Partitioning.Partitions[1].DataDir = "/some/weird/dir"
+	if reasons := p1.Partitioning.UnsupportedReasons(p2.Partitioning); reasons != nil {
+		t.Errorf("Expected nil reasons for RANGE partition list change, instead found %q", reasons)
+	}
+
+	// Nil partitioning on either side never has reasons
+	if reasons := p1.Partitioning.UnsupportedReasons(nil); reasons != nil {
+		t.Errorf("Expected nil reasons for removing partitioning, instead found %q", reasons)
+	}
+}
+
+func TestTablePartitioningLinear(t *testing.T) {
+	p1, p2 := partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	for _, tbl := range []*Table{&p1, &p2} {
+		tbl.Partitioning.Method = "HASH"
+		tbl.Partitioning.Partitions = []*Partition{{Name: "p0"}, {Name: "p1"}}
+		tbl.CreateStatement = tbl.GeneratedCreateStatement(FlavorUnknown)
+	}
+	if tableAlters, supported := p1.Diff(&p2); len(tableAlters) != 0 || !supported {
+		t.Fatalf("Unexpected return from Diff on identical tables: %d alters / %t supported", len(tableAlters), supported)
+	}
+
+	// Toggling LINEAR must be detected as a repartition, in either direction
+	p2.Partitioning.Linear = true
+	p2.CreateStatement = p2.GeneratedCreateStatement(FlavorUnknown)
+	if !strings.Contains(p2.CreateStatement, "PARTITION BY LINEAR HASH (") {
+		t.Errorf("Expected CREATE to contain LINEAR HASH, instead found:\n%s", p2.CreateStatement)
+	}
+	if p2.Partitioning.FullMethod() != "LINEAR HASH" || p1.Partitioning.FullMethod() != "HASH" {
+		t.Errorf("Unexpected FullMethod results: %q, %q", p2.Partitioning.FullMethod(), p1.Partitioning.FullMethod())
+	}
+	for _, pair := range [][2]*Table{{&p1, &p2}, {&p2, &p1}} {
+		tableAlters, supported := pair[0].Diff(pair[1])
+		if len(tableAlters) != 1 || !supported {
+			t.Fatalf("Unexpected return from Diff: %d alters / %t supported", len(tableAlters), supported)
+		}
+		pb, ok := tableAlters[0].(PartitionBy)
+		if !ok || !pb.RePartition {
+			t.Fatalf("Expected repartitioning PartitionBy clause, instead found %T %+v", tableAlters[0], tableAlters[0])
+		}
+		expected := pair[1].Partitioning.FullMethod() + " ("
+		if clause := pb.Clause(StatementModifiers{}); !strings.Contains(clause, "PARTITION BY "+expected) {
+			t.Errorf("Expected clause to contain %q, instead found %q", expected, clause)
+		}
+	}
+
+	// LINEAR KEY renders identically to the combined method string
+	p2.Partitioning.Method = "KEY"
+	if def := p2.Partitioning.Definition(FlavorUnknown); !strings.Contains(def, "PARTITION BY LINEAR KEY (") {
+		t.Errorf("Unexpected partitioning definition: %s", def)
+	}
+}
+
+func TestTablePartitioningKeyAlgorithm(t *testing.T) {
+	cases := []struct {
+		linear     bool
+		algoClause string
+		expr       string
+		expected   string
+	}{
+		{false, "ALGORITHM = 2 ", "`col3`,`col1`", "PARTITION BY KEY ALGORITHM = 2 (col3,col1)"},
+		{false, "ALGORITHM = 1 ", "`col3`", "PARTITION BY KEY ALGORITHM = 1 (col3)"},
+		{false, "ALGORITHM=1", "", "PARTITION BY KEY ALGORITHM = 1 ()"},
+		{true, " algorithm =2", "", "PARTITION BY LINEAR KEY ALGORITHM = 2 ()"},
+		{false, "", "", "PARTITION BY KEY ()"},
+		{true, "", "`col1`", "PARTITION BY LINEAR KEY (col1)"},
+	}
+	for _, c := range cases {
+		tp := &TablePartitioning{
+			Method:             "KEY",
+			Linear:             c.linear,
+			Expression:         c.expr,
+			AlgoClause:         c.algoClause,
+			ForcePartitionList: PartitionListCount,
+			Partitions:         []*Partition{{Name: "p0"}, {Name: "p1"}},
+		}
+		expected := "\n/*!50100 " + c.expected + "\nPARTITIONS 2 */"
+		if def := tp.Definition(FlavorUnknown); def != expected {
+			t.Errorf("Unexpected partitioning definition: expected %q, found %q", expected, def)
+		}
+	}
+
+	// Differences in whitespace of AlgoClause should not be considered a diff, but
+	// differences in the actual algorithm should be
+	tp1 := &TablePartitioning{Method: "KEY", AlgoClause: "ALGORITHM = 1 ", Partitions: []*Partition{{Name: "p0"}, {Name: "p1"}}}
+	tp2 := &TablePartitioning{Method: "KEY", AlgoClause: "ALGORITHM=1", Partitions: []*Partition{{Name: "p0"}, {Name: "p1"}}}
+	if clauses, supported := tp1.Diff(tp2); len(clauses) != 0 || !supported {
+		t.Errorf("Unexpected return from Diff: %+v / %t", clauses, supported)
+	}
+	tp2.AlgoClause = "ALGORITHM = 2 "
+	if clauses, supported := tp1.Diff(tp2); len(clauses) != 1 || !supported {
+		t.Errorf("Unexpected return from Diff: %+v / %t", clauses, supported)
+	}
+}
+
+func TestTablePartitioningImplicitEngine(t *testing.T) {
+	explicit, implicit := partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	for _, p := range implicit.Partitioning.Partitions {
+		p.Engine = ""
+	}
+	if implicit.GeneratedCreateStatement(FlavorUnknown) != explicit.CreateStatement {
+		t.Errorf("Expected implicit partition engines to render identically to explicit ones; instead found:\n%s", implicit.GeneratedCreateStatement(FlavorUnknown))
+	}
+	implicit.CreateStatement = "" // bypass diff logic short-circuit on matching CreateStatement
+	for _, pair := range [][2]*Table{{&explicit, &implicit}, {&implicit, &explicit}} {
+		if tableAlters, supported := pair[0].Diff(pair[1]); len(tableAlters) != 0 || !supported {
+			t.Errorf("Unexpected return from Diff: %d alters / %t supported", len(tableAlters), supported)
+		}
+	}
+	if explicit.Partitioning.Partitions[0].Engine != "InnoDB" || implicit.Partitioning.Partitions[0].Engine != "" {
+		t.Error("Diff unexpectedly modified partition engines of the original tables")
+	}
+
+	// A partition engine which differs from the table's engine is still a
+	// difference, and is not supported for HASH partitioning
+	for _, tbl := range []*Table{&explicit, &implicit} {
+		tbl.Partitioning.Method = "HASH"
+		for _, p := range tbl.Partitioning.Partitions {
+			p.Values = ""
+		}
+	}
+	implicit.Partitioning.Partitions[1].Engine = "MyISAM"
+	if reasons := explicit.Partitioning.withTableEngine(explicit.Engine).UnsupportedReasons(implicit.Partitioning.withTableEngine(implicit.Engine)); len(reasons) != 1 || !strings.Contains(reasons[0], "storage engine") {
+		t.Errorf("Unexpected UnsupportedReasons: %v", reasons)
+	}
+}
+
+func TestTablePartitioningRangeColumnsMaxValue(t *testing.T) {
+	makePartitioning := func(p1Values string) *TablePartitioning {
+		return &TablePartitioning{
+			Method:     "RANGE COLUMNS",
+			Expression: "`a`,`b`",
+			Partitions: []*Partition{
+				{Name: "p0", Values: "100,50", Engine: "InnoDB"},
+				{Name: "p1", Values: p1Values, Engine: "InnoDB"},
+				{Name: "p2", Values: "MAXVALUE,MAXVALUE", Engine: "InnoDB"},
+			},
+		}
+	}
+	tp := makePartitioning("100, maxvalue")
+	def := tp.Definition(FlavorUnknown)
+	for _, expected := range []string{
+		"PARTITION p0 VALUES LESS THAN (100,50) ENGINE = InnoDB",
+		"PARTITION p1 VALUES LESS THAN (100, maxvalue) ENGINE = InnoDB", // values rendered as-is
+		"PARTITION p2 VALUES LESS THAN (MAXVALUE,MAXVALUE) ENGINE = InnoDB",
+	} {
+		if !strings.Contains(def, expected) {
+			t.Errorf("Expected partitioning definition to contain %q, instead found:\n%s", expected, def)
+		}
+	}
+
+	// Differences in only MAXVALUE casing or whitespace are not differences
+	if reasons := tp.partitionListDiffReasons(makePartitioning("100,MAXVALUE")); reasons != nil {
+		t.Errorf("Expected no differences, instead found %q", reasons)
+	}
+	for _, values := range []string{"200,MAXVALUE", "100,60", "MAXVALUE,100"} {
+		if reasons := tp.partitionListDiffReasons(makePartitioning(values)); len(reasons) != 1 || reasons[0] != "changing RANGE COLUMNS partition p1 values" {
+			t.Errorf("Unexpected diff reasons for values %q: %q", values, reasons)
+		}
+	}
+
+	// Plain RANGE still renders MAXVALUE without parens
+	p := &Partition{Name: "pmax", Values: "MAXVALUE", Engine: "InnoDB"}
+	if def := p.Definition(FlavorUnknown, "RANGE"); def != "PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB" {
+		t.Errorf("Unexpected partition definition: %s", def)
+	}
 }
 
 func TestTableUnpartitionedCreateStatement(t *testing.T) {
@@ -249,6 +634,68 @@ func TestPartitioningDataDirectory(t *testing.T) {
 	}
 }
 
+// TestPartitioningStorageLocations confirms that per-partition INDEX DIRECTORY
+// and TABLESPACE clauses are parsed and rendered correctly, and that changes to
+// them are not ignored by Diff for RANGE partitioning.
+func TestPartitioningStorageLocations(t *testing.T) {
+	for _, flavor := range []Flavor{ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0")} {
+		table := partitionedTable(flavor)
+		tablespace := "innodb_file_per_table"
+		if flavor.MinMySQL(8) {
+			tablespace = "`innodb_file_per_table`"
+		}
+		table.CreateStatement = strings.Replace(table.CreateStatement, "LESS THAN (456)", "LESS THAN (456) TABLESPACE = "+tablespace+" DATA DIRECTORY = '/some/weird/dir' INDEX DIRECTORY = '/some/idx/dir'", 1)
+		if table.CreateStatement == table.GeneratedCreateStatement(flavor) {
+			t.Fatal("Failed to set up test properly: string replacements did not match")
+		}
+		fixPartitioningEdgeCases(&table, flavor)
+		if p := table.Partitioning.Partitions[1]; p.Tablespace != "innodb_file_per_table" || p.DataDir != "/some/weird/dir" || p.IndexDir != "/some/idx/dir" {
+			t.Errorf("Flavor %s: unexpected partition fields after parsing: %+v", flavor, *p)
+		}
+		if table.CreateStatement != table.GeneratedCreateStatement(flavor) {
+			t.Errorf("Flavor %s: failed to extract storage locations; post-fix partitioning statement generated as %s", flavor, table.Partitioning.Definition(flavor))
+		}
+	}
+
+	// MariaDB ignores per-partition tablespaces entirely
+	p := Partition{Name: "p0", Engine: "InnoDB", Tablespace: "innodb_file_per_table"}
+	if def := p.Definition(ParseFlavor("mariadb:10.6"), "HASH"); def != "PARTITION `p0` ENGINE = InnoDB" {
+		t.Errorf("Unexpected MariaDB partition definition: %s", def)
+	}
+
+	// Changing storage locations of RANGE partitions reorganizes just the
+	// affected span of partitions
+	p1, p2 := partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	p2.Partitioning.Partitions[1].IndexDir = "/some/idx/dir"
+	p2.Partitioning.Partitions[2].Tablespace = "innodb_file_per_table"
+	clauses, supported := p1.Partitioning.Diff(p2.Partitioning)
+	if !supported || len(clauses) != 1 {
+		t.Fatalf("Unexpected return from Diff: %d clauses / %t supported", len(clauses), supported)
+	}
+	expected := "REORGANIZE PARTITION `p1`, `p2` INTO (PARTITION p1 VALUES LESS THAN (456) INDEX DIRECTORY = '/some/idx/dir' ENGINE = InnoDB, PARTITION p2 VALUES LESS THAN MAXVALUE TABLESPACE = innodb_file_per_table ENGINE = InnoDB)"
+	if actual := clauses[0].Clause(StatementModifiers{}); actual != expected {
+		t.Errorf("Unexpected clause from Diff:\nexpected %s\nfound    %s", expected, actual)
+	}
+
+	// If other aspects of the partition list change too, the placeholder is used
+	p2.Partitioning.Partitions[0].Comment = "hello world"
+	p2.Partitioning.Partitions = p2.Partitioning.Partitions[1:]
+	if clauses, supported := p1.Partitioning.Diff(p2.Partitioning); !supported || len(clauses) != 1 {
+		t.Errorf("Unexpected return from Diff: %d clauses / %t supported", len(clauses), supported)
+	} else if _, ok := clauses[0].(ModifyPartitions); !ok {
+		t.Errorf("Expected ModifyPartitions placeholder, instead found %T", clauses[0])
+	}
+
+	// Storage location changes are unsupported for HASH partitioning
+	p1, p2 = partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	p1.Partitioning.Method, p2.Partitioning.Method = "HASH", "HASH"
+	p2.Partitioning.Partitions[1].Tablespace = "innodb_file_per_table"
+	expectReasons := []string{"changing HASH partition p1 tablespace"}
+	if reasons := p1.Partitioning.UnsupportedReasons(p2.Partitioning); !slices.Equal(reasons, expectReasons) {
+		t.Errorf("Expected reasons %q, instead found %q", expectReasons, reasons)
+	}
+}
+
 func (s TengoIntegrationSuite) TestPartitionedIntrospection(t *testing.T) {
 	s.SourceTestSQL(t, "partition.sql")
 	schema := s.GetSchema(t, "partitionparty")
@@ -265,6 +712,36 @@ func (s TengoIntegrationSuite) TestPartitionedIntrospection(t *testing.T) {
 		t.Errorf("Diff of partitioned table unexpectedly found %d clauses; expected 0. Clauses: %+v", len(clauses), clauses)
 	}
 
+	// Ensure partitions are introspected in definition order, rather than by name
+	expectedNames := []string{"pz_old", "pm_mid", "pa_new", "p_future"}
+	if parts := schema.Table("prangeorder").Partitioning.Partitions; len(parts) != len(expectedNames) {
+		t.Errorf("Expected table prangeorder to have %d partitions, instead found %d", len(expectedNames), len(parts))
+	} else {
+		for n, part := range parts {
+			if part.Name != expectedNames[n] {
+				t.Errorf("Expected partition %d of prangeorder to be %s, instead found %s", n, expectedNames[n], part.Name)
+			}
+		}
+		if parts[0].Values != "2000" || parts[3].Values != "MAXVALUE" {
+			t.Errorf("Unexpected partition values for prangeorder: first=%s, last=%s", parts[0].Values, parts[3].Values)
+		}
+	}
+
+	// Ensure MAXVALUE within a RANGE COLUMNS tuple is introspected properly
+	if parts := schema.Table("prangecolmax").Partitioning.Partitions; len(parts) != 3 {
+		t.Errorf("Expected table prangecolmax to have 3 partitions, instead found %d", len(parts))
+	} else if bounds := parts[1].rangeColumnsValues(); len(bounds) != 2 || bounds[0] != "100" || bounds[1] != "MAXVALUE" {
+		t.Errorf("Unexpected partition bounds for prangecolmax partition p1: %q", bounds)
+	}
+
+	// Ensure LINEAR is introspected separately from the base method
+	for name, method := range map[string]string{"plinearhash": "HASH", "plinearkey": "KEY", "prange": "RANGE"} {
+		tp := schema.Table(name).Partitioning
+		if expectLinear := strings.HasPrefix(name, "plinear"); tp.Method != method || tp.Linear != expectLinear {
+			t.Errorf("Unexpected partitioning method for %s: Method=%q Linear=%t", name, tp.Method, tp.Linear)
+		}
+	}
+
 	// Ensure that instance.go's tablesToPartitions() returns the same result as
 	// Schema.tablesToPartitions() on the introspected schema.
 	db, err := s.d.CachedConnectionPool("partitionparty", "")
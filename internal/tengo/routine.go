@@ -20,6 +20,7 @@ type Routine struct {
 	Definer           Definer    `json:"definer"`
 	DatabaseCollation string     `json:"dbCollation"` // from creation time
 	Comment           string     `json:"comment,omitempty"`
+	Language          string     `json:"language,omitempty"` // from information_schema.routines.routine_body; always "SQL" in current flavors
 	Deterministic     bool       `json:"deterministic,omitempty"`
 	SQLDataAccess     string     `json:"sqlDataAccess,omitempty"`
 	SecurityType      string     `json:"securityType"`
@@ -68,6 +69,9 @@ func (r *Routine) head(_ Flavor) string {
 	}
 
 	clauses := []string{}
+	if r.Language != "" && r.Language != "SQL" {
+		clauses = append(clauses, "LANGUAGE "+r.Language)
+	}
 	if r.SQLDataAccess != "CONTAINS SQL" {
 		clauses = append(clauses, r.SQLDataAccess)
 	}
@@ -125,8 +129,8 @@ func (r *Routine) equalsIgnoringCharacteristics(other *Routine) bool {
 	if r.Name != other.Name || r.Type != other.Type || r.Body != other.Body || r.Definer != other.Definer {
 		return false
 	}
-	if r.Deterministic != other.Deterministic {
-		return false // arguably a characteristic, but nonetheless not supported for ALTER...
+	if r.Deterministic != other.Deterministic || r.Language != other.Language {
+		return false // arguably characteristics, but nonetheless not supported for ALTER...
 	}
 	if r.ParamString != other.ParamString || r.ReturnDataType != other.ReturnDataType {
 		return false
@@ -137,6 +141,62 @@ func (r *Routine) equalsIgnoringCharacteristics(other *Routine) bool {
 	return true
 }
 
+// RoutineAspect identifies a portion of a routine's definition which may
+// differ between two versions of the routine. Characteristics such as the
+// comment or security context are tracked separately from the body, since
+// each carries a different level of risk when modified.
+type RoutineAspect string
+
+// Constants enumerating aspects of a routine which may be compared.
+const (
+	RoutineAspectBody          RoutineAspect = "body"
+	RoutineAspectSignature     RoutineAspect = "signature" // param list or return type
+	RoutineAspectDefiner       RoutineAspect = "definer"
+	RoutineAspectLanguage      RoutineAspect = "language"
+	RoutineAspectDeterministic RoutineAspect = "deterministic"
+	RoutineAspectDataAccess    RoutineAspect = "sqlDataAccess"
+	RoutineAspectSecurity      RoutineAspect = "securityType"
+	RoutineAspectComment       RoutineAspect = "comment"
+	RoutineAspectMetadata      RoutineAspect = "metadata" // creation-time sql_mode or db collation
+)
+
+// ChangedAspects returns which aspects of the routine differ between r and
+// other, in a consistent order. The result is nil if the routines are equal,
+// or if either one is nil.
+func (r *Routine) ChangedAspects(other *Routine) (aspects []RoutineAspect) {
+	if r == nil || other == nil || r.Equals(other) {
+		return nil
+	}
+	if r.Body != other.Body {
+		aspects = append(aspects, RoutineAspectBody)
+	}
+	if r.ParamString != other.ParamString || r.ReturnDataType != other.ReturnDataType {
+		aspects = append(aspects, RoutineAspectSignature)
+	}
+	if r.Definer != other.Definer {
+		aspects = append(aspects, RoutineAspectDefiner)
+	}
+	if r.Language != other.Language {
+		aspects = append(aspects, RoutineAspectLanguage)
+	}
+	if r.Deterministic != other.Deterministic {
+		aspects = append(aspects, RoutineAspectDeterministic)
+	}
+	if r.SQLDataAccess != other.SQLDataAccess {
+		aspects = append(aspects, RoutineAspectDataAccess)
+	}
+	if r.SecurityType != other.SecurityType {
+		aspects = append(aspects, RoutineAspectSecurity)
+	}
+	if r.Comment != other.Comment {
+		aspects = append(aspects, RoutineAspectComment)
+	}
+	if r.SQLMode != other.SQLMode || r.DatabaseCollation != other.DatabaseCollation {
+		aspects = append(aspects, RoutineAspectMetadata)
+	}
+	return aspects
+}
+
 // DropStatement returns a SQL statement that, if run, would drop this routine.
 func (r *Routine) DropStatement() string {
 	return fmt.Sprintf("DROP %s %s", r.Type.Caps(), EscapeIdentifier(r.Name))
@@ -184,6 +244,15 @@ func (r *Routine) parseCreateStatement(flavor Flavor, schema string) error {
 	return fmt.Errorf("Failed to parse SHOW CREATE %s %s.%s: %s", r.Type.Caps(), EscapeIdentifier(schema), EscapeIdentifier(r.Name), r.CreateStatement)
 }
 
+// normalizeBody converts Windows-style line endings to Unix-style, and strips
+// any trailing whitespace after the final statement of a stored object's body.
+// Depending on the introspection path and server version, trailing whitespace
+// from the original CREATE may or may not be retained, which would otherwise
+// cause spurious diffs for unchanged routines or triggers.
+func normalizeBody(body string) string {
+	return strings.TrimRight(strings.ReplaceAll(body, "\r\n", "\n"), " \t\r\n")
+}
+
 ///// Diff logic ///////////////////////////////////////////////////////////////
 
 // RoutineDiff represents a difference between two routines. For diffs modifying
@@ -221,6 +290,18 @@ func (rd *RoutineDiff) DiffType() DiffType {
 	return rd.Type
 }
 
+// ChangedAspects returns which aspects of the routine are being modified by
+// this diff. This permits callers to distinguish comment-only or
+// security-only changes from modifications to the body, even in cases where
+// the diff requires dropping and re-creating the routine. The result is nil
+// for diffs that create or drop a routine without replacing it.
+func (rd *RoutineDiff) ChangedAspects() []RoutineAspect {
+	if rd == nil {
+		return nil
+	}
+	return rd.From.ChangedAspects(rd.To)
+}
+
 // Statement returns the full DDL statement corresponding to the RoutineDiff. A
 // blank string may be returned if the mods indicate the statement should be
 // skipped. If the mods indicate the statement should be disallowed, it will
@@ -382,7 +463,7 @@ func compareRoutinesByName(fromByName map[string]*Routine, toByName map[string]*
 
 ///// Introspection logic //////////////////////////////////////////////////////
 
-func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Routine, error) {
+func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor, failures *objectFailures) ([]*Routine, error) {
 	// Obtain the routines in the schema
 	// We completely exclude routines that the user can call, but not examine --
 	// e.g. user has EXECUTE priv but missing other vital privs. In this case
@@ -391,6 +472,7 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 		Name              string `db:"routine_name"`
 		Type              string `db:"routine_type"`
 		IsDeterministic   string `db:"is_deterministic"`
+		Language          string `db:"routine_body"`
 		SQLDataAccess     string `db:"sql_data_access"`
 		SecurityType      string `db:"security_type"`
 		SQLMode           string `db:"sql_mode"`
@@ -411,6 +493,7 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 		SELECT SQL_BUFFER_RESULT
 		       r.routine_name AS routine_name, UPPER(r.routine_type) AS routine_type,
 		       UPPER(r.is_deterministic) AS is_deterministic,
+		       UPPER(r.routine_body) AS routine_body,
 		       UPPER(r.sql_data_access) AS sql_data_access,
 		       UPPER(r.security_type) AS security_type,
 		       r.sql_mode AS sql_mode, r.routine_comment AS routine_comment,
@@ -432,6 +515,7 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 			Definer:           Definer(rawRoutine.Definer),
 			DatabaseCollation: rawRoutine.DatabaseCollation,
 			Comment:           rawRoutine.Comment,
+			Language:          rawRoutine.Language,
 			Deterministic:     rawRoutine.IsDeterministic == "YES",
 			SQLDataAccess:     rawRoutine.SQLDataAccess,
 			SecurityType:      rawRoutine.SecurityType,
@@ -473,14 +557,13 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 			if routine, ok := dict[key]; ok {
 				routine.ParamString = strings.ReplaceAll(meta.ParamList, "\r\n", "\n")
 				routine.ReturnDataType = meta.Returns
-				routine.Body = strings.ReplaceAll(meta.Body, "\r\n", "\n")
+				routine.Body = normalizeBody(meta.Body)
 				routine.CreateStatement = routine.Definition(flavor)
 				alreadyObtained++
 			}
 		}
 	}
 
-	var err error
 	if alreadyObtained < len(routines) {
 		g, subCtx := errgroup.WithContext(ctx)
 		for _, r := range routines {
@@ -488,19 +571,24 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 				g.Go(func() (err error) {
 					r.CreateStatement, err = showCreateRoutine(subCtx, db, r.Name, r.Type)
 					if err == nil {
-						r.CreateStatement = strings.ReplaceAll(r.CreateStatement, "\r\n", "\n")
+						r.CreateStatement = normalizeBody(r.CreateStatement)
 						err = r.parseCreateStatement(flavor, schema)
 					} else {
 						err = fmt.Errorf("Error executing SHOW CREATE %s for %s.%s: %w", r.Type.Caps(), EscapeIdentifier(schema), EscapeIdentifier(r.Name), err)
 					}
-					return err
+					if err != nil {
+						failures.add(r.ObjectKey(), err)
+					}
+					return nil
 				})
 			}
 		}
-		err = g.Wait()
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
 	}
 
-	return routines, err
+	return withoutFailures(routines, failures), nil
 }
 
 func showCreateRoutine(ctx context.Context, db *sqlx.DB, routine string, ot ObjectType) (create string, err error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -51,16 +52,19 @@ func (s TengoIntegrationSuite) TestInstanceRoutineIntrospection(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Unexpected error from ConnectionPool: %v", err)
 		}
-		fastResults, err := querySchemaRoutines(context.Background(), db, "testing", s.d.Flavor())
+		failures := &objectFailures{}
+		fastResults, err := querySchemaRoutines(context.Background(), db, "testing", s.d.Flavor(), failures)
 		if err != nil {
 			t.Fatalf("Unexpected error from querySchemaRoutines: %v", err)
 		}
 		oldFlavor := s.d.Flavor()
 		s.d.ForceFlavor(ParseFlavor("mysql:8.0"))
-		slowResults, err := querySchemaRoutines(context.Background(), db, "testing", s.d.Flavor())
+		slowResults, err := querySchemaRoutines(context.Background(), db, "testing", s.d.Flavor(), failures)
 		s.d.ForceFlavor(oldFlavor)
 		if err != nil {
 			t.Fatalf("Unexpected error from querySchemaRoutines: %v", err)
+		} else if err := failures.err("testing"); err != nil {
+			t.Fatalf("Unexpected per-object failures from querySchemaRoutines: %v", err)
 		}
 		for n, r := range fastResults {
 			if !r.Equals(slowResults[n]) {
@@ -271,6 +275,86 @@ func TestSchemaDiffRoutines(t *testing.T) {
 	}
 }
 
+func TestRoutineChangedAspects(t *testing.T) {
+	from := aProc("latin1_swedish_ci", "")
+	assertAspects := func(to *Routine, expectedType DiffType, expected ...RoutineAspect) {
+		t.Helper()
+		to.CreateStatement = to.Definition(FlavorUnknown)
+		fromSchema, toSchema := aSchema("s1"), aSchema("s1")
+		fromSchema.Routines = []*Routine{&from}
+		toSchema.Routines = []*Routine{to}
+		sd := NewSchemaDiff(&fromSchema, &toSchema)
+		if len(sd.RoutineDiffs) == 0 || sd.RoutineDiffs[0].DiffType() != expectedType {
+			t.Fatalf("Expected first RoutineDiff to be %s, instead found %+v", expectedType, sd.RoutineDiffs)
+		}
+		for _, rd := range sd.RoutineDiffs {
+			if actual := rd.ChangedAspects(); !slices.Equal(actual, expected) {
+				t.Errorf("Expected ChangedAspects to return %v, instead found %v", expected, actual)
+			}
+		}
+	}
+
+	// Comment-only change: handled by ALTER
+	to := aProc("latin1_swedish_ci", "")
+	to.Comment = "new comment"
+	assertAspects(&to, DiffTypeAlter, RoutineAspectComment)
+
+	// Clearing a comment requires DROP and re-CREATE, but is still reported as
+	// just a comment change
+	from.Comment = "old comment"
+	to.Comment = ""
+	assertAspects(&to, DiffTypeDrop, RoutineAspectComment)
+	from.Comment = ""
+
+	// Security-only change: handled by ALTER
+	to = aProc("latin1_swedish_ci", "")
+	to.SecurityType = "DEFINER"
+	assertAspects(&to, DiffTypeAlter, RoutineAspectSecurity)
+
+	// Body and characteristic changes together
+	to.Body = "BEGIN\n  SELECT 1;\n  END"
+	to.Deterministic = true
+	assertAspects(&to, DiffTypeDrop, RoutineAspectBody, RoutineAspectDeterministic, RoutineAspectSecurity)
+
+	// Param list and definer changes require DROP and re-CREATE, and any
+	// characteristic changes alongside them are reported as well
+	to = aProc("latin1_swedish_ci", "")
+	to.ParamString = "\n    IN iterations int(10) unsigned\n"
+	to.SQLDataAccess = "MODIFIES SQL DATA"
+	to.Definer = "app@%"
+	assertAspects(&to, DiffTypeDrop, RoutineAspectSignature, RoutineAspectDefiner, RoutineAspectDataAccess)
+
+	// Function return type change
+	fromFunc, toFunc := aFunc("latin1_swedish_ci", ""), aFunc("latin1_swedish_ci", "")
+	toFunc.ReturnDataType = "bigint(20) unsigned"
+	if aspects := fromFunc.ChangedAspects(&toFunc); !slices.Equal(aspects, []RoutineAspect{RoutineAspectSignature}) {
+		t.Errorf("Expected ChangedAspects to return only signature, instead found %v", aspects)
+	}
+
+	// Creating or dropping a routine has no changed aspects
+	if aspects := (&RoutineDiff{Type: DiffTypeCreate, To: &to}).ChangedAspects(); aspects != nil {
+		t.Errorf("Expected nil ChangedAspects for a CREATE, instead found %v", aspects)
+	}
+	if aspects := from.ChangedAspects(&from); aspects != nil {
+		t.Errorf("Expected nil ChangedAspects for identical routines, instead found %v", aspects)
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	cases := map[string]string{
+		"BEGIN\r\n  SELECT 1;\r\nEND":    "BEGIN\n  SELECT 1;\nEND",
+		"BEGIN\n  SELECT 1;\nEND \n\t\n": "BEGIN\n  SELECT 1;\nEND",
+		"RETURN 'foo  '\r\n":             "RETURN 'foo  '",
+		"  RETURN 1":                     "  RETURN 1",
+		"":                               "",
+	}
+	for input, expected := range cases {
+		if actual := normalizeBody(input); actual != expected {
+			t.Errorf("Expected normalizeBody(%q) to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
 func aProc(dbCollation, sqlMode string) Routine {
 	r := Routine{
 		Name: "proc1",
@@ -283,6 +367,7 @@ func aProc(dbCollation, sqlMode string) Routine {
 		Definer:           "root@%",
 		DatabaseCollation: dbCollation,
 		Comment:           "",
+		Language:          "SQL",
 		Deterministic:     false,
 		SQLDataAccess:     "READS SQL DATA",
 		SecurityType:      "INVOKER",
@@ -302,6 +387,7 @@ func aFunc(dbCollation, sqlMode string) Routine {
 		Definer:           "root@%",
 		DatabaseCollation: dbCollation,
 		Comment:           "hello world",
+		Language:          "SQL",
 		Deterministic:     true,
 		SQLDataAccess:     "NO SQL",
 		SecurityType:      "DEFINER",
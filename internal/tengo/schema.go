@@ -1,6 +1,7 @@
 package tengo
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -11,6 +12,21 @@ type Schema struct {
 	Collation string     `json:"defaultCollation"`
 	Tables    []*Table   `json:"tables,omitempty"`
 	Routines  []*Routine `json:"routines,omitempty"`
+
+	// Sequences are only introspected in MariaDB 10.3+. They are not yet
+	// included in Objects(), since the SQL statement parser does not handle
+	// CREATE SEQUENCE.
+	Sequences []*Sequence `json:"sequences,omitempty"`
+
+	// Triggers are likewise not yet included in Objects(), since the SQL
+	// statement parser does not handle CREATE TRIGGER. Introspection only
+	// populates this field if enabled via Instance.SetTriggerIntrospection.
+	Triggers []*Trigger `json:"triggers,omitempty"`
+
+	// Views are likewise not yet included in Objects(), since the SQL statement
+	// parser does not handle CREATE VIEW. Introspection only populates this field
+	// if enabled via Instance.SetViewIntrospection.
+	Views []*View `json:"views,omitempty"`
 }
 
 // ObjectKey returns a value useful for uniquely refering to a Schema, for
@@ -64,6 +80,53 @@ func (s *Schema) Table(name string) *Table {
 	return nil
 }
 
+// RenameTableWarnings returns human-readable warnings about foreign keys in the
+// schema which reference the table oldName, including self-referencing foreign
+// keys. If the table is renamed to newName, the server automatically updates
+// these foreign keys to reference the new name, which may affect diffs of the
+// referencing tables.
+func (s *Schema) RenameTableWarnings(oldName, newName string) (warnings []string) {
+	if s == nil {
+		return nil
+	}
+	for _, t := range s.Tables {
+		for _, fk := range t.ForeignKeys {
+			if fk.ReferencedTableName == oldName && (fk.ReferencedSchemaName == "" || fk.ReferencedSchemaName == s.Name) {
+				warnings = append(warnings, fmt.Sprintf("foreign key %s in table %s references table %s, and will be changed to reference %s", EscapeIdentifier(fk.Name), EscapeIdentifier(t.Name), EscapeIdentifier(oldName), EscapeIdentifier(newName)))
+			}
+		}
+	}
+	return warnings
+}
+
+// MissingPrimaryKey describes a table which lacks an explicit PRIMARY KEY.
+type MissingPrimaryKey struct {
+	TableName       string
+	PromotableIndex *Index // unique index on non-nullable, non-expression columns which could become the PRIMARY KEY; nil if none
+}
+
+// TablesWithoutPrimaryKey returns information about all tables in the schema
+// which lack an explicit PRIMARY KEY. Servers enforcing sql_require_primary_key
+// (MySQL 8.0.13+) reject creating or altering such tables, even if the server
+// would otherwise treat a non-nullable unique index as the primary key. For
+// each table, PromotableIndex indicates whether an existing unique index is
+// eligible to be converted into the PRIMARY KEY. This method does not query
+// any server; it only examines the already-introspected tables.
+func (s *Schema) TablesWithoutPrimaryKey() (result []MissingPrimaryKey) {
+	if s == nil {
+		return nil
+	}
+	for _, t := range s.Tables {
+		if t.PrimaryKey == nil {
+			result = append(result, MissingPrimaryKey{
+				TableName:       t.Name,
+				PromotableIndex: t.promotedPrimaryKey(),
+			})
+		}
+	}
+	return result
+}
+
 // ProceduresByName returns a mapping of stored procedure names to Routine
 // struct pointers, for all stored procedures in the schema.
 func (s *Schema) ProceduresByName() map[string]*Routine {
@@ -89,6 +152,45 @@ func (s *Schema) routinesByNameAndType(ot ObjectType) map[string]*Routine {
 	return result
 }
 
+// SequencesByName returns a mapping of sequence names to Sequence struct
+// pointers, for all sequences in the schema.
+func (s *Schema) SequencesByName() map[string]*Sequence {
+	if s == nil {
+		return map[string]*Sequence{}
+	}
+	result := make(map[string]*Sequence, len(s.Sequences))
+	for _, seq := range s.Sequences {
+		result[seq.Name] = seq
+	}
+	return result
+}
+
+// TriggersByName returns a mapping of trigger names to Trigger struct pointers,
+// for all triggers in the schema.
+func (s *Schema) TriggersByName() map[string]*Trigger {
+	if s == nil {
+		return map[string]*Trigger{}
+	}
+	result := make(map[string]*Trigger, len(s.Triggers))
+	for _, trig := range s.Triggers {
+		result[trig.Name] = trig
+	}
+	return result
+}
+
+// ViewsByName returns a mapping of view names to View struct pointers, for all
+// views in the schema.
+func (s *Schema) ViewsByName() map[string]*View {
+	if s == nil {
+		return map[string]*View{}
+	}
+	result := make(map[string]*View, len(s.Views))
+	for _, view := range s.Views {
+		result[view.Name] = view
+	}
+	return result
+}
+
 // Objects returns DefKeyers for all objects in the schema, excluding the schema
 // itself. The result is a map, keyed by ObjectKey (type+name).
 func (s *Schema) Objects() map[ObjectKey]DefKeyer {
@@ -118,6 +220,12 @@ func (s *Schema) StripMatches(removePatterns []ObjectPattern) {
 			s.Tables = stripMatchingObjects(s.Tables, pattern)
 		case ObjectTypeProc, ObjectTypeFunc:
 			s.Routines = stripMatchingObjects(s.Routines, pattern)
+		case ObjectTypeSequence:
+			s.Sequences = stripMatchingObjects(s.Sequences, pattern)
+		case ObjectTypeTrigger:
+			s.Triggers = stripMatchingObjects(s.Triggers, pattern)
+		case ObjectTypeView:
+			s.Views = stripMatchingObjects(s.Views, pattern)
 		}
 	}
 }
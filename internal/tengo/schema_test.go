@@ -146,3 +146,28 @@ func (s TengoIntegrationSuite) TestSchemaStripTablePartitioning(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaTablesWithoutPrimaryKey(t *testing.T) {
+	withPK, promotable, noIndexes := aTable(1), aTable(1), aTable(1)
+	promotable.Name, promotable.PrimaryKey = "promotable", nil
+	noIndexes.Name, noIndexes.PrimaryKey, noIndexes.SecondaryIndexes = "no_indexes", nil, nil
+	schema := &Schema{
+		Name:   "testing",
+		Tables: []*Table{&withPK, &promotable, &noIndexes},
+	}
+	result := schema.TablesWithoutPrimaryKey()
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 tables without primary key, instead found %d: %+v", len(result), result)
+	}
+	if result[0].TableName != "promotable" || result[0].PromotableIndex == nil || !result[0].PromotableIndex.Unique {
+		t.Errorf("Unexpected result for table %s: %+v", promotable.Name, result[0])
+	}
+	if result[1].TableName != "no_indexes" || result[1].PromotableIndex != nil {
+		t.Errorf("Unexpected result for table %s: %+v", noIndexes.Name, result[1])
+	}
+
+	var nilSchema *Schema
+	if result := nilSchema.TablesWithoutPrimaryKey(); result != nil {
+		t.Errorf("Expected nil schema to return nil, instead found %+v", result)
+	}
+}
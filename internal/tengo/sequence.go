@@ -0,0 +1,330 @@
+package tengo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
+)
+
+// Sequence represents a SEQUENCE object, which is only supported in MariaDB
+// 10.3+. Although MariaDB implements sequences as a special type of table, they
+// are introspected and diff'ed separately from Tables.
+type Sequence struct {
+	Name            string `json:"name"`
+	DataType        string `json:"dataType,omitempty"` // Only present in MariaDB 11.5+, and only if not the default bigint
+	StartWith       int64  `json:"startWith"`
+	MinValue        int64  `json:"minValue"`
+	MaxValue        int64  `json:"maxValue"`
+	IncrementBy     int64  `json:"incrementBy"`
+	Cache           uint64 `json:"cache"` // 0 means NOCACHE
+	Cycle           bool   `json:"cycle,omitempty"`
+	Engine          string `json:"storageEngine"`
+	Comment         string `json:"comment,omitempty"`
+	CreateStatement string `json:"showCreate"`            // complete SHOW CREATE SEQUENCE obtained from an instance
+	UnsupportedDDL  bool   `json:"unsupported,omitempty"` // If true, sequence cannot be diff'ed due to unexpected SHOW CREATE output
+}
+
+// ObjectKey returns a value useful for uniquely refering to a Sequence within
+// a single Schema, for example as a map key.
+func (seq *Sequence) ObjectKey() ObjectKey {
+	if seq == nil {
+		return ObjectKey{}
+	}
+	return ObjectKey{
+		Type: ObjectTypeSequence,
+		Name: seq.Name,
+	}
+}
+
+// Def returns the sequence's CREATE statement as a string.
+func (seq *Sequence) Def() string {
+	return seq.CreateStatement
+}
+
+// Definition generates and returns a canonical CREATE SEQUENCE statement based
+// on the Sequence's Go field values. The formatting matches that of MariaDB's
+// SHOW CREATE SEQUENCE.
+func (seq *Sequence) Definition() string {
+	var b strings.Builder
+	b.WriteString("CREATE SEQUENCE " + EscapeIdentifier(seq.Name))
+	if seq.DataType != "" {
+		b.WriteString(" as " + seq.DataType)
+	}
+	fmt.Fprintf(&b, " start with %d minvalue %d maxvalue %d increment by %d", seq.StartWith, seq.MinValue, seq.MaxValue, seq.IncrementBy)
+	if seq.Cache > 0 {
+		fmt.Fprintf(&b, " cache %d", seq.Cache)
+	} else {
+		b.WriteString(" nocache")
+	}
+	if seq.Cycle {
+		b.WriteString(" cycle")
+	} else {
+		b.WriteString(" nocycle")
+	}
+	if seq.Engine != "" {
+		b.WriteString(" ENGINE=" + seq.Engine)
+	}
+	if seq.Comment != "" {
+		b.WriteString(" COMMENT='" + EscapeValueForCreateTable(seq.Comment) + "'")
+	}
+	return b.String()
+}
+
+// Equals returns true if two sequences are identical, false otherwise.
+func (seq *Sequence) Equals(other *Sequence) bool {
+	// shortcut if both nil pointers, or both pointing to same underlying struct
+	if seq == other {
+		return true
+	}
+	// if one is nil, but the two pointers aren't equal, then one is non-nil
+	if seq == nil || other == nil {
+		return false
+	}
+
+	// All fields are simple scalars, so we can just use equality check once we
+	// know neither is nil
+	return *seq == *other
+}
+
+// DropStatement returns a SQL statement that, if run, would drop this sequence.
+func (seq *Sequence) DropStatement() string {
+	return "DROP SEQUENCE " + EscapeIdentifier(seq.Name)
+}
+
+var reSequenceCreate = regexp.MustCompile("^CREATE SEQUENCE `(?:[^`]|``)+`(?: as ([a-z]+(?: unsigned)?))? start with (-?\\d+) minvalue (-?\\d+) maxvalue (-?\\d+) increment by (-?\\d+) (?:cache (\\d+)|nocache) (cycle|nocycle)")
+
+// parseCreateStatement populates the sequence's numeric attributes and cycle
+// option by parsing CreateStatement. The sequence's Name, Engine, and Comment
+// should already be set prior to calling this method. If the CREATE cannot be
+// fully parsed, or if it contains unexpected clauses, UnsupportedDDL is set to
+// true.
+func (seq *Sequence) parseCreateStatement() {
+	seq.UnsupportedDDL = true
+	matches := reSequenceCreate.FindStringSubmatch(seq.CreateStatement)
+	if matches == nil {
+		return
+	}
+	var err error
+	seq.DataType = matches[1]
+	for n, field := range []*int64{&seq.StartWith, &seq.MinValue, &seq.MaxValue, &seq.IncrementBy} {
+		if *field, err = strconv.ParseInt(matches[n+2], 10, 64); err != nil {
+			return
+		}
+	}
+	if matches[6] != "" {
+		if seq.Cache, err = strconv.ParseUint(matches[6], 10, 64); err != nil {
+			return
+		}
+	}
+	seq.Cycle = (matches[7] == "cycle")
+	seq.UnsupportedDDL = (seq.CreateStatement != seq.Definition())
+}
+
+///// Diff logic ///////////////////////////////////////////////////////////////
+
+// SequenceDiff represents a difference between two sequences. Modifications to
+// an existing sequence are represented as a single SequenceDiff with
+// DiffTypeAlter, which emits an ALTER SEQUENCE statement.
+type SequenceDiff struct {
+	Type DiffType
+	From *Sequence
+	To   *Sequence
+}
+
+// ObjectKey returns a value representing the type and name of the sequence
+// being diff'ed. The name will be the From side sequence, unless this is a
+// Create, in which case the To side sequence name is used.
+func (sd *SequenceDiff) ObjectKey() ObjectKey {
+	if sd != nil && sd.From != nil {
+		return sd.From.ObjectKey()
+	} else if sd != nil && sd.To != nil {
+		return sd.To.ObjectKey()
+	}
+	return ObjectKey{}
+}
+
+// DiffType returns the type of diff operation.
+func (sd *SequenceDiff) DiffType() DiffType {
+	if sd == nil {
+		return DiffTypeNone
+	}
+	return sd.Type
+}
+
+// Statement returns the full DDL statement corresponding to the SequenceDiff. A
+// blank string may be returned if the mods indicate the statement should be
+// skipped. If the mods indicate the statement should be disallowed, it will
+// still be returned as-is, but the error will be non-nil. Be sure not to
+// ignore the error value of this method.
+func (sd *SequenceDiff) Statement(mods StatementModifiers) (string, error) {
+	if sd == nil {
+		return "", nil
+	}
+	switch sd.Type {
+	case DiffTypeCreate:
+		return sd.To.CreateStatement, nil
+	case DiffTypeDrop:
+		var err error
+		if !mods.AllowUnsafe {
+			err = &UnsafeDiffError{
+				Reason: "Desired drop of " + sd.ObjectKey().String() + " would discard its current value, and application queries may fail if they still use it.",
+			}
+		}
+		return sd.From.DropStatement(), err
+	case DiffTypeAlter:
+		return sd.alterStatement(mods)
+	}
+	// DiffTypeRename not used, no equivalent syntax
+	return "", fmt.Errorf("Unsupported diff type %d", sd.DiffType())
+}
+
+func (sd *SequenceDiff) alterStatement(mods StatementModifiers) (string, error) {
+	if sd.To.UnsupportedDDL {
+		return "", &UnsupportedDiffError{
+			Reason:         "The desired state (\"to\" side of diff) contains unexpected or unsupported clauses in SHOW CREATE SEQUENCE.",
+			ExpectedCreate: sd.To.Definition(),
+			ExpectedDesc:   "desired state expected CREATE",
+			ActualCreate:   sd.To.CreateStatement,
+			ActualDesc:     "desired state actual SHOW CREATE",
+		}
+	} else if sd.From.UnsupportedDDL {
+		return "", &UnsupportedDiffError{
+			Reason:         "The original state (\"from\" side of diff) contains unexpected or unsupported clauses in SHOW CREATE SEQUENCE.",
+			ExpectedCreate: sd.From.Definition(),
+			ExpectedDesc:   "original state expected CREATE",
+			ActualCreate:   sd.From.CreateStatement,
+			ActualDesc:     "original state actual SHOW CREATE",
+		}
+	}
+
+	// ALTER SEQUENCE cannot change the data type, storage engine, or comment
+	from, to := *sd.From, *sd.To
+	if mods.LaxComments {
+		from.Comment = to.Comment
+	}
+	if from.DataType != to.DataType || from.Engine != to.Engine || from.Comment != to.Comment {
+		return "", &UnsupportedDiffError{
+			Reason:         "Skeema does not support generation of the necessary DDL to convert the original sequence definition to the desired state.",
+			ExpectedCreate: sd.From.CreateStatement,
+			ExpectedDesc:   "original state actual SHOW CREATE",
+			ActualCreate:   sd.To.CreateStatement,
+			ActualDesc:     "desired state actual SHOW CREATE",
+		}
+	}
+
+	// START WITH alone only affects future restarts, so a changed start value
+	// also requires RESTART to take effect on the sequence's current value
+	var clauses []string
+	var err error
+	if from.StartWith != to.StartWith {
+		clauses = append(clauses, fmt.Sprintf("START WITH %d RESTART WITH %d", to.StartWith, to.StartWith))
+		if !mods.AllowUnsafe {
+			err = &UnsafeDiffError{
+				Reason: "Desired alter of " + sd.ObjectKey().String() + " would restart it at a new value, discarding its current value; application queries may receive values which were already issued.",
+			}
+		}
+	}
+	if from.MinValue != to.MinValue {
+		clauses = append(clauses, fmt.Sprintf("MINVALUE %d", to.MinValue))
+	}
+	if from.MaxValue != to.MaxValue {
+		clauses = append(clauses, fmt.Sprintf("MAXVALUE %d", to.MaxValue))
+	}
+	if from.IncrementBy != to.IncrementBy {
+		clauses = append(clauses, fmt.Sprintf("INCREMENT BY %d", to.IncrementBy))
+	}
+	if from.Cache != to.Cache && to.Cache > 0 {
+		clauses = append(clauses, fmt.Sprintf("CACHE %d", to.Cache))
+	} else if from.Cache != to.Cache {
+		clauses = append(clauses, "NOCACHE")
+	}
+	if from.Cycle != to.Cycle && to.Cycle {
+		clauses = append(clauses, "CYCLE")
+	} else if from.Cycle != to.Cycle {
+		clauses = append(clauses, "NOCYCLE")
+	}
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return "ALTER SEQUENCE " + EscapeIdentifier(to.Name) + " " + strings.Join(clauses, " "), err
+}
+
+func compareSequences(from, to *Schema) (sequenceDiffs []*SequenceDiff) {
+	fromByName := from.SequencesByName()
+	toByName := to.SequencesByName()
+	for name, fromSeq := range fromByName {
+		toSeq, stillExists := toByName[name]
+		if !stillExists {
+			sequenceDiffs = append(sequenceDiffs, &SequenceDiff{Type: DiffTypeDrop, From: fromSeq})
+		} else if !fromSeq.Equals(toSeq) {
+			sequenceDiffs = append(sequenceDiffs, &SequenceDiff{Type: DiffTypeAlter, From: fromSeq, To: toSeq})
+		}
+	}
+	for name, toSeq := range toByName {
+		if _, alreadyExists := fromByName[name]; !alreadyExists {
+			sequenceDiffs = append(sequenceDiffs, &SequenceDiff{Type: DiffTypeCreate, To: toSeq})
+		}
+	}
+	return sequenceDiffs
+}
+
+///// Introspection logic //////////////////////////////////////////////////////
+
+// querySchemaSequences introspects all sequences in the schema. Callers should
+// only use this with MariaDB 10.3+, since other flavors lack sequence support.
+// Sequences which fail SHOW CREATE SEQUENCE are recorded in failures and omitted
+// from the result.
+func querySchemaSequences(ctx context.Context, db *sqlx.DB, schema string, failures *objectFailures) ([]*Sequence, error) {
+	var rawSequences []struct {
+		Name    string `db:"table_name"`
+		Engine  string `db:"engine"`
+		Comment string `db:"table_comment"`
+	}
+	query := `
+		SELECT SQL_BUFFER_RESULT
+		       table_name AS table_name, engine AS engine,
+		       table_comment AS table_comment
+		FROM   information_schema.tables
+		WHERE  table_schema = ?
+		AND    table_type = 'SEQUENCE'`
+	if err := db.SelectContext(ctx, &rawSequences, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.tables for sequences in schema %s: %s", schema, err)
+	}
+	sequences := make([]*Sequence, len(rawSequences))
+	for n, rawSequence := range rawSequences {
+		sequences[n] = &Sequence{
+			Name:    rawSequence.Name,
+			Engine:  rawSequence.Engine,
+			Comment: rawSequence.Comment,
+		}
+	}
+
+	// information_schema does not expose sequence attributes until MariaDB 11.5,
+	// so we obtain them by parsing SHOW CREATE SEQUENCE instead
+	g, subCtx := errgroup.WithContext(ctx)
+	for _, seq := range sequences {
+		g.Go(func() (err error) {
+			var row struct {
+				Name            string `db:"Table"`
+				CreateStatement string `db:"Create Table"`
+			}
+			query := "SHOW CREATE SEQUENCE " + EscapeIdentifier(seq.Name)
+			if err := db.GetContext(subCtx, &row, query); err != nil {
+				failures.add(seq.ObjectKey(), fmt.Errorf("Error executing SHOW CREATE SEQUENCE for %s.%s: %w", EscapeIdentifier(schema), EscapeIdentifier(seq.Name), err))
+				return nil
+			}
+			seq.CreateStatement = row.CreateStatement
+			seq.parseCreateStatement()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return withoutFailures(sequences, failures), nil
+}
@@ -0,0 +1,198 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func aSequence() Sequence {
+	seq := Sequence{
+		Name:        "order_ids",
+		StartWith:   1,
+		MinValue:    1,
+		MaxValue:    9223372036854775806,
+		IncrementBy: 1,
+		Cache:       1000,
+		Engine:      "InnoDB",
+	}
+	seq.CreateStatement = seq.Definition()
+	return seq
+}
+
+func TestSequenceParseCreateStatement(t *testing.T) {
+	cases := []struct {
+		create      string
+		expected    Sequence
+		unsupported bool
+	}{
+		{
+			create:   "CREATE SEQUENCE `order_ids` start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 cache 1000 nocycle ENGINE=InnoDB",
+			expected: aSequence(),
+		},
+		{
+			create:   "CREATE SEQUENCE `s2` start with -10 minvalue -100 maxvalue 100 increment by -5 nocache cycle ENGINE=InnoDB COMMENT='it''s cyclical'",
+			expected: Sequence{Name: "s2", StartWith: -10, MinValue: -100, MaxValue: 100, IncrementBy: -5, Cycle: true, Engine: "InnoDB", Comment: "it's cyclical"},
+		},
+		{
+			create:   "CREATE SEQUENCE `s3` as tinyint unsigned start with 1 minvalue 1 maxvalue 254 increment by 1 cache 10 nocycle ENGINE=MyISAM",
+			expected: Sequence{Name: "s3", DataType: "tinyint unsigned", StartWith: 1, MinValue: 1, MaxValue: 254, IncrementBy: 1, Cache: 10, Engine: "MyISAM"},
+		},
+		{
+			create:      "CREATE SEQUENCE `s4` start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 cache 1000 nocycle ENGINE=InnoDB PAGE_CHECKSUM=1",
+			expected:    Sequence{Name: "s4", StartWith: 1, MinValue: 1, MaxValue: 9223372036854775806, IncrementBy: 1, Cache: 1000, Engine: "InnoDB"},
+			unsupported: true,
+		},
+		{
+			create:      "CREATE SEQUENCE `s5` as bigint unsigned start with 1 minvalue 1 maxvalue 18446744073709551614 increment by 1 cache 1000 nocycle ENGINE=InnoDB",
+			expected:    Sequence{Name: "s5", Engine: "InnoDB"},
+			unsupported: true,
+		},
+	}
+	for _, c := range cases {
+		seq := Sequence{Name: c.expected.Name, Engine: c.expected.Engine, Comment: c.expected.Comment, CreateStatement: c.create}
+		seq.parseCreateStatement()
+		if seq.UnsupportedDDL != c.unsupported {
+			t.Errorf("Expected UnsupportedDDL=%t for %s, instead found %t", c.unsupported, c.create, seq.UnsupportedDDL)
+		}
+		if c.unsupported {
+			continue
+		}
+		c.expected.CreateStatement = c.create
+		if !seq.Equals(&c.expected) {
+			t.Errorf("Unexpected result from parsing %s:\nexpected %+v\nfound    %+v", c.create, c.expected, seq)
+		}
+	}
+}
+
+func TestSequenceDiff(t *testing.T) {
+	from, to := aSequence(), aSequence()
+	fromSchema := &Schema{Name: "s1", Sequences: []*Sequence{&from}}
+	toSchema := &Schema{Name: "s1", Sequences: []*Sequence{&to}}
+	if sd := fromSchema.Diff(toSchema); len(sd.SequenceDiffs) != 0 {
+		t.Errorf("Expected no diffs between identical sequences, instead found %d", len(sd.SequenceDiffs))
+	}
+
+	assertAlter := func(mods StatementModifiers, expected string) {
+		t.Helper()
+		to.CreateStatement = to.Definition()
+		sd := fromSchema.Diff(toSchema)
+		if len(sd.SequenceDiffs) != 1 || sd.SequenceDiffs[0].DiffType() != DiffTypeAlter {
+			t.Fatalf("Expected 1 SequenceDiff of type ALTER, instead found %+v", sd.SequenceDiffs)
+		}
+		if stmt, err := sd.SequenceDiffs[0].Statement(mods); stmt != expected || err != nil {
+			t.Errorf("Expected statement %q, instead found %q with err=%v", expected, stmt, err)
+		}
+	}
+	to.IncrementBy = 10
+	to.MaxValue = 1000000
+	assertAlter(StatementModifiers{}, "ALTER SEQUENCE `order_ids` MAXVALUE 1000000 INCREMENT BY 10")
+	to.Cache = 0
+	to.Cycle = true
+	assertAlter(StatementModifiers{}, "ALTER SEQUENCE `order_ids` MAXVALUE 1000000 INCREMENT BY 10 NOCACHE CYCLE")
+	to = aSequence()
+	to.Comment = "for orders"
+	assertAlter(StatementModifiers{LaxComments: true}, "")
+
+	// Changing the start value requires a restart, which is unsafe
+	to = aSequence()
+	to.StartWith = 100
+	to.MinValue = 100
+	assertAlter(StatementModifiers{AllowUnsafe: true}, "ALTER SEQUENCE `order_ids` START WITH 100 RESTART WITH 100 MINVALUE 100")
+	if stmt, err := fromSchema.Diff(toSchema).SequenceDiffs[0].Statement(StatementModifiers{}); !strings.HasPrefix(stmt, "ALTER SEQUENCE ") || !IsUnsafeDiff(err) {
+		t.Errorf("Expected unsafe diff error, instead found %q with err=%v", stmt, err)
+	}
+	to = aSequence()
+	to.Comment = "for orders"
+
+	// Changes not supported by ALTER SEQUENCE
+	for _, mods := range []StatementModifiers{{}, {LaxComments: true, AllowUnsafe: true}} {
+		if mods.LaxComments {
+			to.Engine = "Aria"
+		}
+		to.CreateStatement = to.Definition()
+		sd := fromSchema.Diff(toSchema)
+		if stmt, err := sd.SequenceDiffs[0].Statement(mods); stmt != "" || !IsUnsupportedDiff(err) {
+			t.Errorf("Expected unsupported diff error, instead found %q with err=%v", stmt, err)
+		}
+	}
+	to = aSequence()
+	to.CreateStatement += " PAGE_CHECKSUM=1"
+	to.UnsupportedDDL = true
+	if stmt, err := fromSchema.Diff(toSchema).SequenceDiffs[0].Statement(StatementModifiers{}); stmt != "" || !IsUnsupportedDiff(err) {
+		t.Errorf("Expected unsupported diff error, instead found %q with err=%v", stmt, err)
+	}
+
+	// Create and drop
+	sd := NewSchemaDiff(&Schema{Name: "s1"}, toSchema)
+	if len(sd.SequenceDiffs) != 1 || sd.SequenceDiffs[0].DiffType() != DiffTypeCreate {
+		t.Fatalf("Expected 1 SequenceDiff of type CREATE, instead found %+v", sd.SequenceDiffs)
+	} else if stmt, err := sd.SequenceDiffs[0].Statement(StatementModifiers{}); stmt != to.CreateStatement || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	sd = NewSchemaDiff(fromSchema, &Schema{Name: "s1"})
+	if len(sd.SequenceDiffs) != 1 || sd.SequenceDiffs[0].DiffType() != DiffTypeDrop {
+		t.Fatalf("Expected 1 SequenceDiff of type DROP, instead found %+v", sd.SequenceDiffs)
+	} else if stmt, err := sd.SequenceDiffs[0].Statement(StatementModifiers{}); stmt != "DROP SEQUENCE `order_ids`" || !IsUnsafeDiff(err) {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	} else if _, err := sd.SequenceDiffs[0].Statement(StatementModifiers{AllowUnsafe: true}); err != nil {
+		t.Errorf("Unexpected error from Statement with AllowUnsafe: %v", err)
+	}
+	if dd := NewSchemaDiff(fromSchema, nil).DatabaseDiff(); dd == nil {
+		t.Error("Expected DatabaseDiff to be non-nil")
+	} else if _, err := dd.Statement(StatementModifiers{}); !IsUnsafeDiff(err) {
+		t.Errorf("Expected dropping a database containing only a sequence to be unsafe, instead err=%v", err)
+	}
+}
+
+func (s TengoIntegrationSuite) TestSequenceIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMariaDB(10, 3) {
+		t.Skipf("Sequences not supported in flavor %s", flavor)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	if _, err := db.Exec("CREATE SEQUENCE order_ids"); err != nil {
+		t.Fatalf("Unexpected error creating sequence: %v", err)
+	}
+	if _, err := db.Exec("CREATE SEQUENCE s2 START WITH -10 MINVALUE -100 MAXVALUE 100 INCREMENT BY -5 NOCACHE CYCLE COMMENT 'cyclical'"); err != nil {
+		t.Fatalf("Unexpected error creating sequence: %v", err)
+	}
+
+	schema := s.GetSchema(t, "testing")
+	if len(schema.Sequences) != 2 {
+		t.Fatalf("Expected schema to have 2 sequences, instead found %d", len(schema.Sequences))
+	}
+	for _, table := range schema.Tables {
+		if table.Name == "order_ids" || table.Name == "s2" {
+			t.Errorf("Sequence %s unexpectedly introspected as a table", table.Name)
+		}
+	}
+	seqs := schema.SequencesByName()
+	for _, seq := range seqs {
+		if seq.UnsupportedDDL {
+			t.Errorf("Sequence %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", seq.Name, seq.Definition(), seq.CreateStatement)
+		}
+	}
+	if seq := seqs["s2"]; seq.StartWith != -10 || seq.MinValue != -100 || seq.MaxValue != 100 || seq.IncrementBy != -5 || seq.Cache != 0 || !seq.Cycle || seq.Comment != "cyclical" {
+		t.Errorf("Unexpected introspection of sequence s2: %+v", *seq)
+	}
+
+	// Confirm the generated ALTER SEQUENCE yields the expected sequence
+	to := *seqs["order_ids"]
+	to.IncrementBy = 10
+	to.Cache = 50
+	to.CreateStatement = to.Definition()
+	sd := &SequenceDiff{Type: DiffTypeAlter, From: seqs["order_ids"], To: &to}
+	stmt, err := sd.Statement(StatementModifiers{Flavor: flavor})
+	if err != nil || !strings.HasPrefix(stmt, "ALTER SEQUENCE ") {
+		t.Fatalf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+	}
+	if altered := s.GetSchema(t, "testing").SequencesByName()["order_ids"]; !altered.Equals(&to) {
+		t.Errorf("Unexpected sequence after %q:\n%s", stmt, altered.CreateStatement)
+	}
+}
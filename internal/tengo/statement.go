@@ -114,6 +114,37 @@ func (stmt *Statement) NormalizeTrailer() {
 	}
 }
 
+// SpecifiesTableCharSet returns true if stmt is a CREATE TABLE whose table
+// options explicitly include a default character set or collation. Column-level
+// character sets and collations are not considered, since they do not affect the
+// table's default. If this returns false for a CREATE TABLE, the table inherits
+// its default from the schema, or from the server if the schema does not have
+// one either.
+func (stmt *Statement) SpecifiesTableCharSet() bool {
+	if stmt == nil || stmt.ObjectType != ObjectTypeTable {
+		return false
+	}
+	var depth int
+	var afterBody bool
+	for _, token := range TokenizeString(stmt.Body()) {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			afterBody = afterBody || depth == 0
+		default:
+			if afterBody && depth == 0 {
+				switch strings.ToUpper(token) {
+				case "CHARSET", "CHARACTER", "COLLATE":
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 // Compounder is implemented by types that have the ability to represent
 // compound statements, requiring special delimiter handling.
 type Compounder interface {
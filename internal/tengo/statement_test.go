@@ -128,3 +128,25 @@ func TestStatementNormalizeTrailer(t *testing.T) {
 		}
 	}
 }
+
+func TestStatementSpecifiesTableCharSet(t *testing.T) {
+	cases := map[string]bool{
+		"CREATE TABLE t1 (id int)": false,
+		"CREATE TABLE t1 (name varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin)": false,
+		"CREATE TABLE t1 (id int) ENGINE=InnoDB COMMENT='charset'":                     false,
+		"CREATE TABLE t1 (id int) DEFAULT CHARSET=latin1":                              true,
+		"create table t1 (id int) engine=InnoDB character set utf8mb4":                 true,
+		"CREATE TABLE t1 (id int) COLLATE=utf8mb4_unicode_ci":                          true,
+		"CREATE TABLE t1 (id int) PARTITION BY HASH (id) PARTITIONS 4":                 false,
+	}
+	for input, expected := range cases {
+		stmt := &Statement{Text: input + ";\n", Delimiter: ";", Type: StatementTypeCreate, ObjectType: ObjectTypeTable, ObjectName: "t1"}
+		if actual := stmt.SpecifiesTableCharSet(); actual != expected {
+			t.Errorf("Expected SpecifiesTableCharSet() to return %t for %q, instead found %t", expected, input, actual)
+		}
+	}
+	proc := &Statement{Text: "CREATE PROCEDURE p1() SELECT 1 COLLATE utf8mb4_bin;\n", Delimiter: ";", Type: StatementTypeCreate, ObjectType: ObjectTypeProc, ObjectName: "p1"}
+	if proc.SpecifiesTableCharSet() {
+		t.Error("Expected SpecifiesTableCharSet() to return false for a non-table statement")
+	}
+}
@@ -1,29 +1,38 @@
 package tengo
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 )
 
 // Table represents a single database table.
 type Table struct {
-	Name              string             `json:"name"`
-	Engine            string             `json:"storageEngine"`
-	CharSet           string             `json:"defaultCharSet"`
-	Collation         string             `json:"defaultCollation"`
-	ShowCollation     bool               `json:"showCollation,omitempty"` // Include default COLLATE in SHOW CREATE TABLE: logic differs by flavor
-	CreateOptions     string             `json:"createOptions,omitempty"` // row_format, stats_persistent, stats_auto_recalc, etc
-	Columns           []*Column          `json:"columns"`
-	PrimaryKey        *Index             `json:"primaryKey,omitempty"`
-	SecondaryIndexes  []*Index           `json:"secondaryIndexes,omitempty"`
-	ForeignKeys       []*ForeignKey      `json:"foreignKeys,omitempty"`
-	Checks            []*Check           `json:"checks,omitempty"`
-	Comment           string             `json:"comment,omitempty"`
-	Tablespace        string             `json:"tablespace,omitempty"`
-	NextAutoIncrement uint64             `json:"nextAutoIncrement,omitempty"`
-	Partitioning      *TablePartitioning `json:"partitioning,omitempty"`       // nil if table isn't partitioned
-	UnsupportedDDL    bool               `json:"unsupportedForDiff,omitempty"` // If true, tengo cannot diff this table or auto-generate its CREATE TABLE
-	CreateStatement   string             `json:"showCreateTable"`              // complete SHOW CREATE TABLE obtained from an instance
+	Name                     string             `json:"name"`
+	Engine                   string             `json:"storageEngine"`
+	CharSet                  string             `json:"defaultCharSet"`
+	Collation                string             `json:"defaultCollation"`
+	ShowCollation            bool               `json:"showCollation,omitempty"` // Include default COLLATE in SHOW CREATE TABLE: logic differs by flavor
+	CreateOptions            string             `json:"createOptions,omitempty"` // row_format, stats_persistent, stats_auto_recalc, etc
+	Columns                  []*Column          `json:"columns"`
+	PrimaryKey               *Index             `json:"primaryKey,omitempty"`
+	SecondaryIndexes         []*Index           `json:"secondaryIndexes,omitempty"`
+	ForeignKeys              []*ForeignKey      `json:"foreignKeys,omitempty"`
+	Checks                   []*Check           `json:"checks,omitempty"`
+	Comment                  string             `json:"comment,omitempty"`
+	EngineAttribute          string             `json:"engineAttribute,omitempty"`          // MySQL 8.0.21+: JSON value of ENGINE_ATTRIBUTE table option
+	SecondaryEngineAttribute string             `json:"secondaryEngineAttribute,omitempty"` // MySQL 8.0.21+: JSON value of SECONDARY_ENGINE_ATTRIBUTE table option
+	Tablespace               string             `json:"tablespace,omitempty"`
+	NextAutoIncrement        uint64             `json:"nextAutoIncrement,omitempty"`
+	Partitioning             *TablePartitioning `json:"partitioning,omitempty"`       // nil if table isn't partitioned
+	SystemVersioned          bool               `json:"systemVersioned,omitempty"`    // True if MariaDB 10.3+ WITH SYSTEM VERSIONING
+	ApplicationPeriod        *ApplicationPeriod `json:"applicationPeriod,omitempty"`  // nil if table lacks a MariaDB 10.4+ application-time period
+	MergeOptions             *MergeOptions      `json:"mergeOptions,omitempty"`       // nil unless table is ENGINE=MERGE with INSERT_METHOD and/or UNION
+	UnsupportedDDL           bool               `json:"unsupportedForDiff,omitempty"` // If true, tengo cannot diff this table or auto-generate its CREATE TABLE
+	CreateStatement          string             `json:"showCreateTable"`              // complete SHOW CREATE TABLE obtained from an instance
+	rawCreate                string             // unmodified SHOW CREATE TABLE, only if retention was enabled at introspection time
 }
 
 // ObjectKey returns a value useful for uniquely refering to a Table within a
@@ -38,6 +47,16 @@ func (t *Table) ObjectKey() ObjectKey {
 	}
 }
 
+// MarshalJSON encodes the table as JSON, redacting any secrets in its CREATE
+// statement and create options. See RedactSecrets for details.
+func (t *Table) MarshalJSON() ([]byte, error) {
+	type plainTable Table // avoid infinite recursion into this method
+	tableCopy := plainTable(*t)
+	tableCopy.CreateStatement = RedactSecrets(t.CreateStatement)
+	tableCopy.CreateOptions = RedactSecrets(t.CreateOptions)
+	return json.Marshal(tableCopy)
+}
+
 // Def returns the table's CREATE statement as a string.
 func (t *Table) Def() string {
 	return t.CreateStatement
@@ -53,6 +72,47 @@ func (t *Table) DropStatement() string {
 	return fmt.Sprintf("DROP TABLE %s", EscapeIdentifier(t.Name))
 }
 
+// CreateLikeStatement returns a SQL statement that, if run, would create an
+// empty table named newName with the same structure as this table. Since
+// CREATE TABLE ... LIKE does not copy foreign keys, a human-readable warning is
+// also returned for each of this table's foreign keys. Note that partitioning
+// IS copied, so a staging table for EXCHANGE PARTITION must subsequently have
+// its partitioning removed.
+func (t *Table) CreateLikeStatement(newName string) (stmt string, warnings []string) {
+	for _, fk := range t.ForeignKeys {
+		warnings = append(warnings, fmt.Sprintf("foreign key %s in table %s will not be copied to table %s", EscapeIdentifier(fk.Name), EscapeIdentifier(t.Name), EscapeIdentifier(newName)))
+	}
+	return fmt.Sprintf("CREATE TABLE %s LIKE %s", EscapeIdentifier(newName), EscapeIdentifier(t.Name)), warnings
+}
+
+// CreateMode specifies the opening keywords of a CREATE TABLE statement.
+type CreateMode int
+
+// Constants for CreateMode
+const (
+	CreateModeDefault     CreateMode = iota // CREATE TABLE
+	CreateModeIfNotExists                   // CREATE TABLE IF NOT EXISTS
+	CreateModeOrReplace                     // CREATE OR REPLACE TABLE (MariaDB only)
+)
+
+// CreateStatementWithMode returns the output of GeneratedCreateStatement, with
+// its opening keywords adjusted according to mode. An error is returned if mode
+// is CreateModeOrReplace but the flavor is not MariaDB, since MySQL does not
+// support CREATE OR REPLACE TABLE.
+func (t *Table) CreateStatementWithMode(flavor Flavor, mode CreateMode) (string, error) {
+	stmt := t.GeneratedCreateStatement(flavor)
+	switch mode {
+	case CreateModeIfNotExists:
+		return strings.Replace(stmt, "CREATE TABLE ", "CREATE TABLE IF NOT EXISTS ", 1), nil
+	case CreateModeOrReplace:
+		if !flavor.IsMariaDB() {
+			return "", fmt.Errorf("CREATE OR REPLACE TABLE is not supported in %s", flavor)
+		}
+		return strings.Replace(stmt, "CREATE TABLE ", "CREATE OR REPLACE TABLE ", 1), nil
+	}
+	return stmt, nil
+}
+
 // GeneratedCreateStatement generates a CREATE TABLE statement based on the
 // Table's Go field values. If t.UnsupportedDDL is false, this will match
 // the output of MySQL's SHOW CREATE TABLE statement. But if t.UnsupportedDDL
@@ -69,6 +129,12 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 	for _, idx := range t.SecondaryIndexes {
 		defs = append(defs, idx.Definition(flavor))
 	}
+	if rowStart, rowEnd := t.SystemTimePeriod(); rowStart != nil && rowEnd != nil {
+		defs = append(defs, fmt.Sprintf("PERIOD FOR SYSTEM_TIME (%s, %s)", EscapeIdentifier(rowStart.Name), EscapeIdentifier(rowEnd.Name)))
+	}
+	if t.ApplicationPeriod != nil {
+		defs = append(defs, t.ApplicationPeriod.Definition())
+	}
 	for _, fk := range t.ForeignKeys {
 		defs = append(defs, fk.Definition(flavor))
 	}
@@ -95,13 +161,30 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 	}
 	var createOptions string
 	if t.CreateOptions != "" {
-		createOptions = " " + t.CreateOptions
+		createOptions = " " + orderedCreateOptions(t.CreateOptions, flavor)
+	}
+	var mergeOptions string
+	if t.MergeOptions != nil && t.IsMerge() {
+		mergeOptions = t.MergeOptions.Definition()
 	}
 	var comment string
 	if t.Comment != "" {
 		comment = fmt.Sprintf(" COMMENT='%s'", EscapeValueForCreateTable(t.Comment))
 	}
-	result := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s ENGINE=%s%s DEFAULT CHARSET=%s%s%s%s%s",
+	var engineAttributes string
+	if flavor.MinMySQL(8, 0, 21) {
+		if t.EngineAttribute != "" {
+			engineAttributes += " " + engineAttributeClause("ENGINE_ATTRIBUTE", t.EngineAttribute, true)
+		}
+		if t.SecondaryEngineAttribute != "" {
+			engineAttributes += " " + engineAttributeClause("SECONDARY_ENGINE_ATTRIBUTE", t.SecondaryEngineAttribute, true)
+		}
+	}
+	var versioning string
+	if t.SystemVersioned {
+		versioning = " WITH SYSTEM VERSIONING"
+	}
+	result := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s ENGINE=%s%s DEFAULT CHARSET=%s%s%s%s%s%s%s%s",
 		EscapeIdentifier(t.Name),
 		strings.Join(defs, ",\n  "),
 		tablespaceClause,
@@ -110,12 +193,107 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 		charSet,
 		collate,
 		createOptions,
+		mergeOptions,
 		comment,
-		t.Partitioning.Definition(flavor),
+		engineAttributes,
+		versioning,
+		t.Partitioning.withTableEngine(t.Engine).Definition(flavor),
 	)
 	return result
 }
 
+// createOptionsOrder lists the table options which SHOW CREATE TABLE may
+// include between the table's default charset/collation and its comment, in
+// the order that each vendor emits them.
+var createOptionsOrder = map[Vendor][]string{
+	VendorMySQL: {
+		"MIN_ROWS", "MAX_ROWS", "AVG_ROW_LENGTH", "PACK_KEYS", "STATS_PERSISTENT",
+		"STATS_AUTO_RECALC", "STATS_SAMPLE_PAGES", "CHECKSUM", "DELAY_KEY_WRITE",
+		"ROW_FORMAT", "KEY_BLOCK_SIZE", "COMPRESSION", "ENCRYPTION",
+	},
+	VendorMariaDB: {
+		"MIN_ROWS", "MAX_ROWS", "AVG_ROW_LENGTH", "PACK_KEYS", "STATS_PERSISTENT",
+		"STATS_AUTO_RECALC", "STATS_SAMPLE_PAGES", "CHECKSUM", "PAGE_CHECKSUM",
+		"DELAY_KEY_WRITE", "ROW_FORMAT", "TRANSACTIONAL", "SEQUENCE", "KEY_BLOCK_SIZE",
+	},
+}
+
+// orderedCreateOptions returns createOptions reordered to match SHOW CREATE
+// TABLE in the supplied flavor. If the flavor's vendor is unknown, or any
+// option isn't in the vendor's createOptionsOrder (for example MariaDB
+// engine-defined options), createOptions is returned unchanged.
+func orderedCreateOptions(createOptions string, flavor Flavor) string {
+	order := createOptionsOrder[flavor.Vendor]
+	if order == nil || !strings.Contains(createOptions, " ") {
+		return createOptions
+	}
+	opts := strings.Split(createOptions, " ")
+	positions := make(map[string]int, len(opts))
+	for _, opt := range opts {
+		name, _, _ := strings.Cut(opt, "=")
+		pos := slices.Index(order, strings.ToUpper(name))
+		if pos == -1 {
+			return createOptions
+		}
+		positions[opt] = pos
+	}
+	slices.SortStableFunc(opts, func(a, b string) int {
+		return positions[a] - positions[b]
+	})
+	return strings.Join(opts, " ")
+}
+
+// CanonicalCreateStatement returns a flavor-neutral form of the table's CREATE
+// statement, suitable for determining whether two tables introspected from
+// different database server versions or vendors are logically equal. The
+// supplied defaultRowFormat should be that of the server the table was
+// introspected from; see Instance.DefaultRowFormat. This strips integer display
+// widths (aside from tinyint(1) and zerofill types), uses utf8mb3 rather than
+// its utf8 alias, explicitly includes all column character sets and collations,
+// and omits an InnoDB table's ROW_FORMAT if it matches defaultRowFormat. The
+// result is not necessarily valid DDL in any specific flavor, and should only
+// be used for comparison purposes.
+func (t *Table) CanonicalCreateStatement(defaultRowFormat string) string {
+	canon := *t
+	canon.CharSet, canon.Collation = canonicalCharSet(t.CharSet), canonicalCollation(t.Collation)
+	canon.ShowCollation = true
+	canon.Columns = make([]*Column, len(t.Columns))
+	for n, col := range t.Columns {
+		colCopy := *col
+		colCopy.Type.StripDisplayWidth()
+		if colCopy.CharSet != "" {
+			colCopy.CharSet, colCopy.Collation = canonicalCharSet(col.CharSet), canonicalCollation(col.Collation)
+			colCopy.ShowCharSet, colCopy.ShowCollation = true, true
+		}
+		canon.Columns[n] = &colCopy
+	}
+	var opts []string
+	for _, opt := range strings.Fields(t.CreateOptions) {
+		if rowFormat, isRowFormat := strings.CutPrefix(opt, "ROW_FORMAT="); !isRowFormat || t.Engine != "InnoDB" || rowFormat != defaultRowFormat {
+			opts = append(opts, opt)
+		}
+	}
+	canon.CreateOptions = strings.Join(opts, " ")
+	return canon.GeneratedCreateStatement(FlavorUnknown)
+}
+
+// canonicalCharSet returns "utf8mb3" if cs is "utf8", or cs unchanged otherwise.
+func canonicalCharSet(cs string) string {
+	if cs == "utf8" {
+		return "utf8mb3"
+	}
+	return cs
+}
+
+// canonicalCollation returns collation with any "utf8_" prefix converted to
+// "utf8mb3_", or collation unchanged otherwise.
+func canonicalCollation(collation string) string {
+	if rest, ok := strings.CutPrefix(collation, "utf8_"); ok {
+		return "utf8mb3_" + rest
+	}
+	return collation
+}
+
 // UnpartitionedCreateStatement returns the table's CREATE statement without
 // its PARTITION BY clause. Supplying an accurate flavor improves performance,
 // but is not required; FlavorUnknown still works correctly.
@@ -123,7 +301,7 @@ func (t *Table) UnpartitionedCreateStatement(flavor Flavor) string {
 	if t.Partitioning == nil {
 		return t.CreateStatement
 	}
-	if partClause := t.Partitioning.Definition(flavor); strings.HasSuffix(t.CreateStatement, partClause) {
+	if partClause := t.Partitioning.withTableEngine(t.Engine).Definition(flavor); strings.HasSuffix(t.CreateStatement, partClause) {
 		return t.CreateStatement[0 : len(t.CreateStatement)-len(partClause)]
 	}
 	base, _ := ParseCreatePartitioning(t.CreateStatement)
@@ -160,6 +338,24 @@ func (t *Table) foreignKeysByName() map[string]*ForeignKey {
 	return result
 }
 
+// ReferencedTables returns the distinct tables referenced by t's foreign keys,
+// in order of first appearance. TableReference.SchemaName will be blank for any
+// table in the same schema as t. A self-referential foreign key results in t
+// itself being included. This is useful for ordering CREATE TABLE statements,
+// so that referenced tables are created first.
+func (t *Table) ReferencedTables() []TableReference {
+	var result []TableReference
+	seen := make(map[TableReference]bool, len(t.ForeignKeys))
+	for _, fk := range t.ForeignKeys {
+		ref := TableReference{SchemaName: fk.ReferencedSchemaName, TableName: fk.ReferencedTableName}
+		if !seen[ref] {
+			seen[ref] = true
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
 // checksByName returns a mapping of check constraint names to Check value
 // pointers, for all check constraints in the table.
 func (t *Table) checksByName() map[string]*Check {
@@ -193,6 +389,13 @@ func (t *Table) ClusteredIndexKey() *Index {
 	if t.PrimaryKey != nil {
 		return t.PrimaryKey
 	}
+	return t.promotedPrimaryKey()
+}
+
+// promotedPrimaryKey returns the first unique index made of only non-nullable,
+// non-expression columns, which the server treats as the primary key if the
+// table lacks an explicit one. Returns nil if there is no such index.
+func (t *Table) promotedPrimaryKey() *Index {
 	cols := t.ColumnsByName()
 	nullable := func(index *Index) bool {
 		for _, part := range index.Parts {
@@ -210,6 +413,17 @@ func (t *Table) ClusteredIndexKey() *Index {
 	return nil
 }
 
+// HasPrimaryKey returns true if the table has a user-defined primary key:
+// either an explicit PRIMARY KEY, or a unique index made of only non-nullable,
+// non-expression columns, which the server promotes to act as the primary key.
+// If false, InnoDB will instead cluster the table using a hidden internally-
+// generated row ID. Note that servers enforcing sql_require_primary_key (MySQL
+// 8.0.13+) additionally require the primary key to be explicit; see
+// Schema.TablesWithoutPrimaryKey.
+func (t *Table) HasPrimaryKey() bool {
+	return t.PrimaryKey != nil || t.promotedPrimaryKey() != nil
+}
+
 // RowFormat returns the table's ROW_FORMAT, if one was specified in the table's
 // creation options. If no ROW_FORMAT clause was specified, but a KEY_BLOCK_SIZE
 // was, "COMPRESSED" will be returned since MySQL applies this automatically. If
@@ -230,6 +444,243 @@ func (t *Table) RowFormat() string {
 	return ""
 }
 
+// RawCreate returns the unmodified SHOW CREATE TABLE output which the table
+// was introspected from. Unlike CreateStatement, this has not been adjusted to
+// strip attributes that are ignored by InnoDB or to correct any other
+// introspection edge cases. A blank string is returned unless
+// Instance.SetRetainRawCreate was enabled prior to introspection.
+func (t *Table) RawCreate() string {
+	return t.rawCreate
+}
+
+// TableEncryption represents a table's encryption-related creation options.
+// MySQL and MariaDB use different syntax for table encryption: MySQL uses
+// ENCRYPTION='Y', whereas MariaDB uses ENCRYPTED=YES along with an optional
+// ENCRYPTION_KEY_ID=N. These are tracked separately so that the two are never
+// conflated. In both cases, an explicit "N" or "NO" value is distinguished
+// from an omitted option, since the latter may inherit a schema-level or
+// server-level default.
+type TableEncryption struct {
+	MySQLEncryption  string // value of MySQL ENCRYPTION option: "Y", "N", or "" if omitted
+	MariaDBEncrypted string // value of MariaDB ENCRYPTED option: "YES", "NO", or "" if omitted
+	MariaDBKeyID     uint32 // value of MariaDB ENCRYPTION_KEY_ID option, or 0 if omitted
+}
+
+// Encryption returns the table's encryption settings, as specified in its
+// creation options. This method does not query an instance to determine
+// whether the table is actually encrypted due to server-level defaults.
+func (t *Table) Encryption() (te TableEncryption) {
+	for _, kv := range splitAttributes(t.CreateOptions) {
+		k, v, _ := strings.Cut(kv, "=")
+		k, v = strings.ToUpper(stripAnyQuote(k)), strings.ToUpper(stripAnyQuote(v))
+		switch k {
+		case "ENCRYPTION":
+			te.MySQLEncryption = v
+		case "ENCRYPTED":
+			te.MariaDBEncrypted = v
+		case "ENCRYPTION_KEY_ID":
+			keyID, _ := strconv.ParseUint(v, 10, 32)
+			te.MariaDBKeyID = uint32(keyID)
+		}
+	}
+	return te
+}
+
+// Encrypted returns true if the encryption options explicitly enable
+// encryption, using either MySQL or MariaDB syntax.
+func (te TableEncryption) Encrypted() bool {
+	return te.MySQLEncryption == "Y" || te.MariaDBEncrypted == "YES"
+}
+
+// TableStatistics represents a table's options relating to persistent
+// optimizer statistics. Each field is blank if the corresponding option was
+// omitted or explicitly set to DEFAULT, either of which causes the table to use
+// the server's global setting.
+type TableStatistics struct {
+	Persistent  string // value of STATS_PERSISTENT option: "0", "1", or ""
+	AutoRecalc  string // value of STATS_AUTO_RECALC option: "0", "1", or ""
+	SamplePages string // value of STATS_SAMPLE_PAGES option, or ""
+}
+
+// Statistics returns the table's persistent optimizer statistics settings, as
+// specified in its creation options. SHOW CREATE TABLE only includes these
+// options when they have a non-default value.
+func (t *Table) Statistics() (ts TableStatistics) {
+	for _, kv := range splitAttributes(t.CreateOptions) {
+		k, v, _ := strings.Cut(kv, "=")
+		if strings.EqualFold(v, "DEFAULT") {
+			v = ""
+		}
+		switch strings.ToUpper(k) {
+		case "STATS_PERSISTENT":
+			ts.Persistent = v
+		case "STATS_AUTO_RECALC":
+			ts.AutoRecalc = v
+		case "STATS_SAMPLE_PAGES":
+			ts.SamplePages = v
+		}
+	}
+	return ts
+}
+
+// TablePageCompression represents a table's InnoDB transparent page
+// compression settings. MySQL uses COMPRESSION='algorithm', whereas MariaDB
+// uses PAGE_COMPRESSED=1 along with an optional PAGE_COMPRESSION_LEVEL=N.
+type TablePageCompression struct {
+	MySQLAlgorithm string // value of MySQL COMPRESSION option, lowercased, or "" if omitted
+	MariaDBEnabled bool   // true if MariaDB PAGE_COMPRESSED is set to 1 or ON
+	MariaDBLevel   uint8  // value of MariaDB PAGE_COMPRESSION_LEVEL option, or 0 if omitted
+}
+
+// PageCompression returns the table's page compression settings, as specified
+// in its creation options. This method does not query an instance to determine
+// whether the table is actually compressed due to server-level defaults.
+func (t *Table) PageCompression() (tpc TablePageCompression) {
+	for _, kv := range splitAttributes(t.CreateOptions) {
+		k, v, _ := strings.Cut(kv, "=")
+		k, v = strings.ToUpper(stripAnyQuote(k)), stripAnyQuote(v)
+		switch k {
+		case "COMPRESSION":
+			tpc.MySQLAlgorithm = strings.ToLower(v)
+		case "PAGE_COMPRESSED":
+			tpc.MariaDBEnabled = (v == "1" || strings.EqualFold(v, "ON"))
+		case "PAGE_COMPRESSION_LEVEL":
+			level, _ := strconv.ParseUint(v, 10, 8)
+			tpc.MariaDBLevel = uint8(level)
+		}
+	}
+	return tpc
+}
+
+// AvgRowLength returns the value of the AVG_ROW_LENGTH table option, or 0 if
+// not set. Along with MAX_ROWS, this option influences the data pointer size of
+// engines such as MyISAM and Aria; other engines, including InnoDB, retain the
+// option in SHOW CREATE TABLE but otherwise ignore it. The second return value
+// indicates whether the option is meaningful for the table's engine.
+func (t *Table) AvgRowLength() (avgRowLength uint64, meaningful bool) {
+	for _, kv := range splitAttributes(t.CreateOptions) {
+		if k, v, _ := strings.Cut(kv, "="); k == "AVG_ROW_LENGTH" {
+			avgRowLength, _ = strconv.ParseUint(v, 10, 64)
+		}
+	}
+	meaningful = (t.Engine == "MyISAM" || t.Engine == "Aria")
+	return avgRowLength, meaningful
+}
+
+// RowLimits returns the values of the table-level MIN_ROWS and MAX_ROWS table
+// options, or 0 for either if not set. Engines such as MEMORY use MAX_ROWS to
+// size their storage. Per-partition MIN_ROWS or MAX_ROWS values in a partitioned
+// table's partition definitions are not reflected here.
+func (t *Table) RowLimits() (minRows, maxRows uint64) {
+	for _, kv := range splitAttributes(t.CreateOptions) {
+		k, v, _ := strings.Cut(kv, "=")
+		switch k {
+		case "MIN_ROWS":
+			minRows, _ = strconv.ParseUint(v, 10, 64)
+		case "MAX_ROWS":
+			maxRows, _ = strconv.ParseUint(v, 10, 64)
+		}
+	}
+	return minRows, maxRows
+}
+
+// SystemTimePeriod returns the columns used as the start and end of a MariaDB
+// system-versioned table's PERIOD FOR SYSTEM_TIME. If the table isn't system-
+// versioned, or uses implicit period columns (which are hidden entirely from
+// SHOW CREATE TABLE and information_schema), nil values are returned.
+func (t *Table) SystemTimePeriod() (rowStart, rowEnd *Column) {
+	if !t.SystemVersioned {
+		return nil, nil
+	}
+	for _, col := range t.Columns {
+		if col.SystemTime == "ROW START" {
+			rowStart = col
+		} else if col.SystemTime == "ROW END" {
+			rowEnd = col
+		}
+	}
+	return rowStart, rowEnd
+}
+
+// ApplicationPeriod represents a MariaDB 10.4+ application-time period, which
+// is defined using a pair of existing temporal columns. MariaDB permits at most
+// one application-time period per table.
+type ApplicationPeriod struct {
+	Name        string `json:"name"`
+	StartColumn string `json:"startColumn"`
+	EndColumn   string `json:"endColumn"`
+}
+
+// Definition returns the period's definition clause, for use as part of a DDL
+// statement.
+func (ap *ApplicationPeriod) Definition() string {
+	return fmt.Sprintf("PERIOD FOR %s (%s, %s)", EscapeIdentifier(ap.Name), EscapeIdentifier(ap.StartColumn), EscapeIdentifier(ap.EndColumn))
+}
+
+// Equals returns true if two application-time periods are identical, false
+// otherwise.
+func (ap *ApplicationPeriod) Equals(other *ApplicationPeriod) bool {
+	if ap == nil || other == nil {
+		return ap == other // only equal if BOTH are nil
+	}
+	return *ap == *other
+}
+
+// IsMerge returns true if the table uses the MERGE storage engine, which is
+// reported as MRG_MyISAM or MRG_MYISAM depending on flavor.
+func (t *Table) IsMerge() bool {
+	return strings.EqualFold(t.Engine, "MRG_MyISAM") || strings.EqualFold(t.Engine, "MERGE")
+}
+
+// MergeOptions represents the INSERT_METHOD and UNION table options of a
+// MyISAM MERGE table.
+type MergeOptions struct {
+	InsertMethod string   `json:"insertMethod,omitempty"` // "FIRST" or "LAST", or blank if inserts are disabled
+	Union        []string `json:"union,omitempty"`        // names of underlying MyISAM tables, in order
+}
+
+// Definition returns the MERGE table options, for use in a CREATE TABLE
+// statement. The return value will have a leading space if non-empty.
+func (mo *MergeOptions) Definition() string {
+	if mo == nil {
+		return ""
+	}
+	var b strings.Builder
+	if mo.InsertMethod != "" {
+		b.WriteString(" INSERT_METHOD=" + mo.InsertMethod)
+	}
+	if len(mo.Union) > 0 {
+		b.WriteString(" " + mo.unionClause())
+	}
+	return b.String()
+}
+
+// unionClause returns a UNION table option listing the underlying tables.
+func (mo *MergeOptions) unionClause() string {
+	var members []string
+	if mo != nil {
+		members = make([]string, len(mo.Union))
+		for n, name := range mo.Union {
+			members[n] = EscapeIdentifier(name)
+		}
+	}
+	return "UNION=(" + strings.Join(members, ",") + ")"
+}
+
+// Equals returns true if two sets of MERGE table options are identical, false
+// otherwise. A nil value is equivalent to an empty MergeOptions.
+func (mo *MergeOptions) Equals(other *MergeOptions) bool {
+	var insertMethod, otherInsertMethod string
+	var union, otherUnion []string
+	if mo != nil {
+		insertMethod, union = mo.InsertMethod, mo.Union
+	}
+	if other != nil {
+		otherInsertMethod, otherUnion = other.InsertMethod, other.Union
+	}
+	return insertMethod == otherInsertMethod && slices.Equal(union, otherUnion)
+}
+
 // VirtualColumns returns a slice of virtual generated columns in the table.
 func (t *Table) VirtualColumns() (result []*Column) {
 	for _, col := range t.Columns {
@@ -289,10 +740,10 @@ func (t *Table) HasFulltextIndex() bool {
 }
 
 // Diff returns a set of differences between this table and another table. Some
-// edge cases are not supported, such as sub-partitioning, spatial indexes,
-// MariaDB application time periods, or various non-InnoDB table features; in
-// this case, supported will be false and clauses MAY OR MAY NOT be empty. Any
-// returned clauses in that case must be carefully verified for correctness.
+// edge cases are not supported, such as sub-partitioning, spatial indexes, or
+// various non-InnoDB table features; in this case, supported will be false and
+// clauses MAY OR MAY NOT be empty. Any returned clauses in that case must be
+// carefully verified for correctness.
 func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 	return diffTables(t, to)
 }
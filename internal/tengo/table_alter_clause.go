@@ -2,37 +2,98 @@ package tengo
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
 // TableAlterClause interface represents a specific single-element difference
 // between two tables. Structs satisfying this interface can generate an ALTER
-// TABLE clause, such as ADD COLUMN, MODIFY COLUMN, ADD KEY, etc.
+// TABLE clause, such as ADD COLUMN, MODIFY COLUMN, ADD KEY, etc. They can also
+// indicate whether or not the clause is unsafe, via the Unsafer interface.
 type TableAlterClause interface {
+	Unsafer
 	Clause(StatementModifiers) string
+	Summary(StatementModifiers) ClauseSummary
+	Affects() AffectedNames
 }
 
 // Unsafer interface represents a type of clause that may have the ability to
 // destroy data. Structs satisfying this interface can indicate whether or not
-// this particular clause is unsafe, and if so, the reason why.
-// If a TableAlterClause struct does NOT implement this interface, it is
-// considered to always be safe.
+// this particular clause is unsafe, and if so, the reason why. All
+// TableAlterClause implementations satisfy this interface; clauses which can
+// never destroy data always return false.
 type Unsafer interface {
 	Unsafe(StatementModifiers) (unsafe bool, reason string)
 }
 
+// alwaysSafe may be embedded in TableAlterClause implementations which can
+// never destroy data, to satisfy the Unsafer interface.
+type alwaysSafe struct{}
+
+// Unsafe always returns false.
+func (alwaysSafe) Unsafe(_ StatementModifiers) (unsafe bool, reason string) {
+	return false, ""
+}
+
+// ClauseSummary is a structured, read-only representation of a single
+// TableAlterClause, suitable for serialization to JSON for use in external
+// tooling.
+type ClauseSummary struct {
+	Type         string `json:"type"`                   // name of the clause type, e.g. "AddColumn"
+	Name         string `json:"name,omitempty"`         // name of the affected column, index, etc; blank for table-level clauses
+	Unsafe       bool   `json:"unsafe"`                 // true if the clause is unsafe with the supplied StatementModifiers
+	UnsafeReason string `json:"unsafeReason,omitempty"` // reason the clause is unsafe, if applicable
+	SQL          string `json:"sql"`                    // rendered clause, with secrets redacted; blank if the clause is a no-op with the supplied StatementModifiers
+}
+
+// AffectedNames lists the names of columns, indexes, and constraints which are
+// touched by a TableAlterClause. Table-level clauses, such as those changing
+// the table's storage engine or partitioning, return an empty AffectedNames.
+type AffectedNames struct {
+	Columns     []string `json:"columns,omitempty"`
+	Indexes     []string `json:"indexes,omitempty"`
+	Constraints []string `json:"constraints,omitempty"` // foreign keys and check constraints
+}
+
+// summarizeClause returns a ClauseSummary for the supplied clause, which
+// affects an object with the supplied name.
+func summarizeClause(clause TableAlterClause, name string, mods StatementModifiers) ClauseSummary {
+	summary := ClauseSummary{
+		Type: strings.TrimPrefix(fmt.Sprintf("%T", clause), "tengo."),
+		Name: name,
+		SQL:  RedactSecrets(clause.Clause(mods)),
+	}
+	summary.Unsafe, summary.UnsafeReason = clause.Unsafe(mods)
+	return summary
+}
+
+// existenceGuard returns " IF NOT EXISTS" (if notExists is true) or
+// " IF EXISTS" (if notExists is false) when mods.ExistsGuards is enabled
+// and mods.Flavor is MariaDB. Otherwise, a blank string is returned.
+func existenceGuard(mods StatementModifiers, notExists bool) string {
+	if mods.ExistsGuards == ExistsGuardsNone || !mods.Flavor.IsMariaDB() {
+		return ""
+	} else if notExists {
+		return " IF NOT EXISTS"
+	}
+	return " IF EXISTS"
+}
+
 ///// AddColumn ////////////////////////////////////////////////////////////////
 
 // AddColumn represents a new column that is present on the right-side ("to")
 // schema version of the table, but not the left-side ("from") version. It
 // satisfies the TableAlterClause interface.
 type AddColumn struct {
+	alwaysSafe
 	Column        *Column
 	PositionFirst bool
 	PositionAfter *Column
 }
 
-// Clause returns an ADD COLUMN clause of an ALTER TABLE statement.
+// Clause returns an ADD COLUMN clause of an ALTER TABLE statement. If
+// mods.NullableAddColumns is enabled, a column which is NOT NULL without a
+// default is instead added as nullable.
 func (ac AddColumn) Clause(mods StatementModifiers) string {
 	var positionClause string
 	if ac.PositionFirst {
@@ -40,7 +101,46 @@ func (ac AddColumn) Clause(mods StatementModifiers) string {
 	} else if ac.PositionAfter != nil {
 		positionClause = " AFTER " + EscapeIdentifier(ac.PositionAfter.Name)
 	}
-	return "ADD COLUMN " + ac.Column.Definition(mods.Flavor) + positionClause
+	col := ac.Column
+	if mods.NullableAddColumns && ac.notNullWithoutDefault() {
+		colCopy := *col
+		colCopy.Nullable = true
+		col = &colCopy
+	}
+	return "ADD COLUMN" + existenceGuard(mods, true) + " " + col.Definition(mods.Flavor) + positionClause
+}
+
+// notNullWithoutDefault returns true if the column is NOT NULL and lacks a
+// default value, and is not auto-incrementing or generated.
+func (ac AddColumn) notNullWithoutDefault() bool {
+	col := ac.Column
+	return !col.Nullable && col.Default == "" && !col.AutoIncrement && col.GenerationExpr == "" && col.SystemTime == ""
+}
+
+// Warning returns a human-readable note if the column is NOT NULL without a
+// default value. Adding such a column to a table with existing rows either
+// fails (for example with temporal or spatial types in strict sql_mode) or
+// populates each row with an implicit default value, so callers should surface
+// this warning to the user. If mods.NullableAddColumns is enabled, the note
+// instead describes how the column was rewritten. If the column does not have
+// this problem, a blank string is returned.
+func (ac AddColumn) Warning(mods StatementModifiers) string {
+	if !ac.notNullWithoutDefault() {
+		return ""
+	} else if mods.NullableAddColumns {
+		return "column " + EscapeIdentifier(ac.Column.Name) + " is NOT NULL without a default, so it will be added as nullable instead; once it has been populated, a subsequent ALTER TABLE is needed to make it NOT NULL"
+	}
+	return "column " + EscapeIdentifier(ac.Column.Name) + " is NOT NULL without a default, which may fail or populate implicit values on a table with existing rows; consider adding it as nullable or with a default"
+}
+
+// Summary returns a structured representation of this clause.
+func (ac AddColumn) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ac, ac.Column.Name, mods)
+}
+
+// Affects returns the name of the new column.
+func (ac AddColumn) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{ac.Column.Name}}
 }
 
 ///// DropColumn ///////////////////////////////////////////////////////////////
@@ -53,8 +153,18 @@ type DropColumn struct {
 }
 
 // Clause returns a DROP COLUMN clause of an ALTER TABLE statement.
-func (dc DropColumn) Clause(_ StatementModifiers) string {
-	return fmt.Sprintf("DROP COLUMN %s", EscapeIdentifier(dc.Column.Name))
+func (dc DropColumn) Clause(mods StatementModifiers) string {
+	return fmt.Sprintf("DROP COLUMN%s %s", existenceGuard(mods, false), EscapeIdentifier(dc.Column.Name))
+}
+
+// Summary returns a structured representation of this clause.
+func (dc DropColumn) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(dc, dc.Column.Name, mods)
+}
+
+// Affects returns the name of the dropped column.
+func (dc DropColumn) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{dc.Column.Name}}
 }
 
 // Unsafe returns true if this clause is potentially destructive of data.
@@ -72,12 +182,29 @@ func (dc DropColumn) Unsafe(_ StatementModifiers) (unsafe bool, reason string) {
 // AddIndex represents an index that is only present on the right-side ("to")
 // schema version of the table.
 type AddIndex struct {
+	alwaysSafe
 	Index *Index
 }
 
 // Clause returns an ADD KEY clause of an ALTER TABLE statement.
 func (ai AddIndex) Clause(mods StatementModifiers) string {
-	return "ADD " + ai.Index.Definition(mods.Flavor)
+	def := ai.Index.Definition(mods.Flavor)
+	if guard := existenceGuard(mods, true); guard != "" && !ai.Index.PrimaryKey {
+		// MariaDB expects the guard between the KEY keyword and the index name
+		def = strings.Replace(def, "KEY ", "KEY"+guard+" ", 1)
+	}
+	return "ADD " + def
+}
+
+// Summary returns a structured representation of this clause.
+func (ai AddIndex) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ai, ai.Index.Name, mods)
+}
+
+// Affects returns the name of the new index, along with the names of the
+// columns it indexes.
+func (ai AddIndex) Affects() AffectedNames {
+	return AffectedNames{Columns: ai.Index.ColumnNames(), Indexes: []string{ai.Index.Name}}
 }
 
 ///// DropIndex ////////////////////////////////////////////////////////////////
@@ -85,15 +212,27 @@ func (ai AddIndex) Clause(mods StatementModifiers) string {
 // DropIndex represents an index that was only present on the left-side ("from")
 // schema version of the table.
 type DropIndex struct {
+	alwaysSafe
 	Index *Index
 }
 
 // Clause returns a DROP KEY clause of an ALTER TABLE statement.
-func (di DropIndex) Clause(_ StatementModifiers) string {
+func (di DropIndex) Clause(mods StatementModifiers) string {
 	if di.Index.PrimaryKey {
 		return "DROP PRIMARY KEY"
 	}
-	return "DROP KEY " + EscapeIdentifier(di.Index.Name)
+	return "DROP KEY" + existenceGuard(mods, false) + " " + EscapeIdentifier(di.Index.Name)
+}
+
+// Summary returns a structured representation of this clause.
+func (di DropIndex) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(di, di.Index.Name, mods)
+}
+
+// Affects returns the name of the dropped index, along with the names of the
+// columns it indexed.
+func (di DropIndex) Affects() AffectedNames {
+	return AffectedNames{Columns: di.Index.ColumnNames(), Indexes: []string{di.Index.Name}}
 }
 
 ///// ModifyIndex and AlterIndex ///////////////////////////////////////////////
@@ -103,6 +242,7 @@ func (di DropIndex) Clause(_ StatementModifiers) string {
 // to 2 underlying SQL syntax clauses if the index needs to be dropped
 // and recreated to perform the requested change.
 type ModifyIndex struct {
+	alwaysSafe
 	FromIndex          *Index
 	ToIndex            *Index
 	reorderDueToClause []TableAlterClause
@@ -114,10 +254,16 @@ type ModifyIndex struct {
 // but in some situations it can leverage other syntax depending on the flavor
 // and the nature of the changes.
 func (mi ModifyIndex) Clause(mods StatementModifiers) string {
-	rebuild := DropIndex{mi.FromIndex}.Clause(mods) + ", " + AddIndex{mi.ToIndex}.Clause(mods)
-	if !mi.FromIndex.Equivalent(mi.ToIndex) {
+	rebuild := DropIndex{Index: mi.FromIndex}.Clause(mods) + ", " + AddIndex{Index: mi.ToIndex}.Clause(mods)
+	if mi.ParserChanged() {
+		// No flavor currently supports changing a FULLTEXT index's parser in-place
+		return rebuild
+	} else if !mi.FromIndex.Equivalent(mi.ToIndex) {
 		return rebuild
-	} else if mi.FromIndex.Comment != mi.ToIndex.Comment && !mods.LaxComments {
+	} else if mi.FromIndex.Comment != mi.ToIndex.Comment && (!mods.LaxComments || mi.MergeThresholdChanged()) {
+		// No flavor supports changing an index comment in-place. LaxComments cannot
+		// suppress this if the comment's InnoDB MERGE_THRESHOLD is changing, since
+		// that is a functional setting rather than a mere comment.
 		return rebuild
 	}
 
@@ -158,7 +304,7 @@ func (mi ModifyIndex) Clause(mods StatementModifiers) string {
 	}
 
 	// Changing index visibility: delegate to AlterIndex
-	if mi.FromIndex.Invisible != mi.ToIndex.Invisible {
+	if mi.VisibilityChanged() {
 		ai := AlterIndex{
 			Name:      mi.ToIndex.Name,
 			Invisible: mi.ToIndex.Invisible,
@@ -169,12 +315,56 @@ func (mi ModifyIndex) Clause(mods StatementModifiers) string {
 	return "" // Unsupported request for this Flavor, excluded by above conditionals
 }
 
+// Summary returns a structured representation of this clause.
+func (mi ModifyIndex) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(mi, mi.ToIndex.Name, mods)
+}
+
+// Affects returns the name of the modified index, along with the names of the
+// columns indexed by either version of it.
+func (mi ModifyIndex) Affects() AffectedNames {
+	affected := AffectedNames{Indexes: []string{mi.FromIndex.Name}}
+	if mi.ToIndex.Name != mi.FromIndex.Name {
+		affected.Indexes = append(affected.Indexes, mi.ToIndex.Name)
+	}
+	affected.Columns = mi.FromIndex.ColumnNames()
+	for _, name := range mi.ToIndex.ColumnNames() {
+		if !slices.Contains(affected.Columns, name) {
+			affected.Columns = append(affected.Columns, name)
+		}
+	}
+	return affected
+}
+
+// ParserChanged returns true if the index is a FULLTEXT index whose parser is
+// being changed, for example from ngram to the built-in default parser. This
+// always requires the index to be dropped and re-added.
+func (mi ModifyIndex) ParserChanged() bool {
+	return mi.FromIndex.Type == "FULLTEXT" && mi.ToIndex.Type == "FULLTEXT" && mi.FromIndex.FullTextParser != mi.ToIndex.FullTextParser
+}
+
+// MergeThresholdChanged returns true if the InnoDB MERGE_THRESHOLD specified
+// in the index comment is being added, removed, or changed.
+func (mi ModifyIndex) MergeThresholdChanged() bool {
+	fromThreshold, fromOK := mi.FromIndex.MergeThreshold()
+	toThreshold, toOK := mi.ToIndex.MergeThreshold()
+	return fromThreshold != toThreshold || fromOK != toOK
+}
+
+// VisibilityChanged returns true if the index is being made visible or
+// invisible. If the index is otherwise equivalent, this can be handled in-place
+// via AlterIndex in flavors supporting invisible/ignored indexes.
+func (mi ModifyIndex) VisibilityChanged() bool {
+	return mi.FromIndex.Invisible != mi.ToIndex.Invisible
+}
+
 // AlterIndex represents a change to an index's visibility. Usually this is only
 // used internally by ModifyIndex.Clause(), except in one edge-case where it
 // appears on its own: when attempting to change visibility as well as rename an
 // index, TableDiff.SplitConflicts() needs to separate the two operations into
 // distinct ALTER TABLEs to form legal DDL.
 type AlterIndex struct {
+	alwaysSafe
 	Name         string
 	Invisible    bool
 	linkedRename *ModifyIndex
@@ -210,12 +400,23 @@ func (ai AlterIndex) Clause(mods StatementModifiers) string {
 	return "" // Flavor without invisible/ignored index support
 }
 
+// Summary returns a structured representation of this clause.
+func (ai AlterIndex) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ai, ai.Name, mods)
+}
+
+// Affects returns the name of the index whose visibility is changed.
+func (ai AlterIndex) Affects() AffectedNames {
+	return AffectedNames{Indexes: []string{ai.Name}}
+}
+
 ///// AddForeignKey ////////////////////////////////////////////////////////////
 
 // AddForeignKey represents a new foreign key that is present on the right-side
 // ("to") schema version of the table, but not the left-side ("from") version.
 // It satisfies the TableAlterClause interface.
 type AddForeignKey struct {
+	alwaysSafe
 	ForeignKey   *ForeignKey
 	cosmeticOnly bool // true if this FK is being dropped and re-added just to change name or other cosmetic aspect
 }
@@ -226,7 +427,23 @@ func (afk AddForeignKey) Clause(mods StatementModifiers) string {
 	if !mods.StrictForeignKeyNaming && afk.cosmeticOnly {
 		return ""
 	}
-	return fmt.Sprintf("ADD %s", afk.ForeignKey.Definition(mods.Flavor))
+	def := afk.ForeignKey.Definition(mods.Flavor)
+	if guard := existenceGuard(mods, true); guard != "" {
+		// MariaDB expects the guard immediately after the FOREIGN KEY keywords
+		def = strings.Replace(def, " FOREIGN KEY ", " FOREIGN KEY"+guard+" ", 1)
+	}
+	return "ADD " + def
+}
+
+// Summary returns a structured representation of this clause.
+func (afk AddForeignKey) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(afk, afk.ForeignKey.Name, mods)
+}
+
+// Affects returns the name of the new foreign key, along with the names of
+// its columns in this table.
+func (afk AddForeignKey) Affects() AffectedNames {
+	return AffectedNames{Columns: afk.ForeignKey.ColumnNames, Constraints: []string{afk.ForeignKey.Name}}
 }
 
 ///// DropForeignKey ///////////////////////////////////////////////////////////
@@ -235,6 +452,7 @@ func (afk AddForeignKey) Clause(mods StatementModifiers) string {
 // ("from") schema version of the table, but not the right-side ("to") version.
 // It satisfies the TableAlterClause interface.
 type DropForeignKey struct {
+	alwaysSafe
 	ForeignKey   *ForeignKey
 	cosmeticOnly bool // true if this FK is being dropped and re-added just to change name or other cosmetic aspect
 }
@@ -244,7 +462,18 @@ func (dfk DropForeignKey) Clause(mods StatementModifiers) string {
 	if !mods.StrictForeignKeyNaming && dfk.cosmeticOnly {
 		return ""
 	}
-	return fmt.Sprintf("DROP FOREIGN KEY %s", EscapeIdentifier(dfk.ForeignKey.Name))
+	return "DROP FOREIGN KEY" + existenceGuard(mods, false) + " " + EscapeIdentifier(dfk.ForeignKey.Name)
+}
+
+// Summary returns a structured representation of this clause.
+func (dfk DropForeignKey) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(dfk, dfk.ForeignKey.Name, mods)
+}
+
+// Affects returns the name of the dropped foreign key, along with the names of
+// its columns in this table.
+func (dfk DropForeignKey) Affects() AffectedNames {
+	return AffectedNames{Columns: dfk.ForeignKey.ColumnNames, Constraints: []string{dfk.ForeignKey.Name}}
 }
 
 ///// AddCheck /////////////////////////////////////////////////////////////////
@@ -253,6 +482,7 @@ func (dfk DropForeignKey) Clause(mods StatementModifiers) string {
 // ("to") schema version of the table, but not the left-side ("from") version.
 // It satisfies the TableAlterClause interface.
 type AddCheck struct {
+	alwaysSafe
 	Check       *Check
 	reorderOnly bool // true if check is being dropped and re-added just to re-order (only relevant in MariaDB)
 	renameOnly  bool // true if check is being dropped and re-added just to change name
@@ -278,12 +508,24 @@ func (acc AddCheck) Clause(mods StatementModifiers) string {
 	return "ADD " + acc.Check.Definition(mods.Flavor)
 }
 
+// Summary returns a structured representation of this clause.
+func (acc AddCheck) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(acc, acc.Check.Name, mods)
+}
+
+// Affects returns the name of the new check constraint. Columns referenced by
+// the check expression are not included.
+func (acc AddCheck) Affects() AffectedNames {
+	return AffectedNames{Constraints: []string{acc.Check.Name}}
+}
+
 ///// DropCheck ////////////////////////////////////////////////////////////////
 
 // DropCheck represents a check constraint that was present on the left-side
 // ("from") schema version of the table, but not the right-side ("to") version.
 // It satisfies the TableAlterClause interface.
 type DropCheck struct {
+	alwaysSafe
 	Check       *Check
 	reorderOnly bool // true if index is being dropped and re-added just to re-order (only relevant in MariaDB)
 	renameOnly  bool // true if check is being dropped and re-added just to change name
@@ -313,20 +555,38 @@ func (dcc DropCheck) Clause(mods StatementModifiers) string {
 	}
 }
 
+// Summary returns a structured representation of this clause.
+func (dcc DropCheck) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(dcc, dcc.Check.Name, mods)
+}
+
+// Affects returns the name of the dropped check constraint. Columns referenced
+// by the check expression are not included.
+func (dcc DropCheck) Affects() AffectedNames {
+	return AffectedNames{Constraints: []string{dcc.Check.Name}}
+}
+
 ///// AlterCheck ///////////////////////////////////////////////////////////////
 
 // AlterCheck represents a change in a check's enforcement status in MySQL 8+.
 // It satisfies the TableAlterClause interface.
 type AlterCheck struct {
+	alwaysSafe
 	Check          *Check
 	NewEnforcement bool
 }
 
-// Clause returns an ALTER CHECK clause of an ALTER TABLE statement.
+// Clause returns an ALTER CHECK clause of an ALTER TABLE statement. If the
+// flavor is known to lack support for NOT ENFORCED checks (MariaDB, or MySQL
+// prior to 8.0.16), a blank string is returned, since these flavors ignore the
+// version-gated NOT ENFORCED in check definitions anyway.
 func (alcc AlterCheck) Clause(mods StatementModifiers) string {
 	// Note: if MariaDB ever supports NOT ENFORCED, this will need extra logic to
 	// handle the situation where the same check is being reordered and altered
 	// and strict mods are in-use.
+	if mods.Flavor.IsMariaDB() || (mods.Flavor.IsMySQL() && !mods.Flavor.MinMySQL(8, 0, 16)) {
+		return ""
+	}
 	var status string
 	if alcc.NewEnforcement {
 		status = "ENFORCED"
@@ -336,6 +596,52 @@ func (alcc AlterCheck) Clause(mods StatementModifiers) string {
 	return fmt.Sprintf("ALTER CHECK %s %s", EscapeIdentifier(alcc.Check.Name), status)
 }
 
+// Summary returns a structured representation of this clause.
+func (alcc AlterCheck) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(alcc, alcc.Check.Name, mods)
+}
+
+// Affects returns the name of the check constraint being altered.
+func (alcc AlterCheck) Affects() AffectedNames {
+	return AffectedNames{Constraints: []string{alcc.Check.Name}}
+}
+
+///// RenameTable //////////////////////////////////////////////////////////////
+
+// RenameTable represents a change to the name of a table, within the same
+// schema. It satisfies the TableAlterClause interface.
+type RenameTable struct {
+	NewName string
+}
+
+// Clause returns a RENAME TO clause of an ALTER TABLE statement. If
+// mods.QualifySchema is set, the new name is qualified as well, since an
+// unqualified new name would move the table into the session's default
+// database.
+func (rt RenameTable) Clause(mods StatementModifiers) string {
+	if mods.QualifySchema != "" {
+		return "RENAME TO " + EscapeQualifiedIdentifier(mods.QualifySchema, rt.NewName)
+	}
+	return "RENAME TO " + EscapeIdentifier(rt.NewName)
+}
+
+// Summary returns a structured representation of this clause.
+func (rt RenameTable) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(rt, rt.NewName, mods)
+}
+
+// Affects always returns an empty AffectedNames for RenameTable.
+func (rt RenameTable) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// Unsafe returns true if this clause is potentially destructive of data.
+// RenameTable is always considered unsafe, for the same reasons as
+// RenameColumn.
+func (rt RenameTable) Unsafe(_ StatementModifiers) (unsafe bool, reason string) {
+	return true, "table would be renamed to " + EscapeIdentifier(rt.NewName) + ", and there is no way to deploy application code for this change at the same moment as the schema change"
+}
+
 ///// RenameColumn /////////////////////////////////////////////////////////////
 
 // RenameColumn represents a column that exists in both versions of the table,
@@ -350,6 +656,23 @@ func (rc RenameColumn) Clause(_ StatementModifiers) string {
 	panic(fmt.Errorf("Rename Column not yet supported"))
 }
 
+// Summary returns a structured representation of this clause. Since
+// RenameColumn is not yet supported, the summary's SQL is always blank.
+func (rc RenameColumn) Summary(mods StatementModifiers) ClauseSummary {
+	unsafe, reason := rc.Unsafe(mods)
+	return ClauseSummary{
+		Type:         "RenameColumn",
+		Name:         rc.OldColumn.Name,
+		Unsafe:       unsafe,
+		UnsafeReason: reason,
+	}
+}
+
+// Affects returns both the old and new names of the renamed column.
+func (rc RenameColumn) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{rc.OldColumn.Name, rc.NewName}}
+}
+
 // Unsafe returns true if this clause is potentially destructive of data.
 // RenameColumn is always considered unsafe, despite it not directly destroying
 // data, because it is high-risk for interfering with application logic that may
@@ -369,6 +692,7 @@ type ModifyColumn struct {
 	PositionFirst      bool
 	PositionAfter      *Column
 	InUniqueConstraint bool // true if column is part of a unique index (or PK) in both old and new version of table
+	convertedCharSet   bool // true if the only change is one which a ConvertCharSet in the same diff also performs
 }
 
 // Clause returns a MODIFY COLUMN clause of an ALTER TABLE statement.
@@ -380,6 +704,12 @@ func (mc ModifyColumn) Clause(mods StatementModifiers) string {
 		positionClause = " AFTER " + EscapeIdentifier(mc.PositionAfter.Name)
 	}
 
+	// If the table's CONVERT TO CHARACTER SET clause already handles this
+	// column's change, there's nothing more to do here.
+	if mods.PreferConvertCharSet && mc.convertedCharSet && positionClause == "" {
+		return ""
+	}
+
 	// LaxComments means we only emit a MODIFY COLUMN if something OTHER than the
 	// comment differs; but if we do emit a MODIFY COLUMN we still want to use the
 	// new comment value.
@@ -396,6 +726,17 @@ func (mc ModifyColumn) Clause(mods StatementModifiers) string {
 		mc.OldColumn = &oldColumnCopy
 	}
 
+	// LaxZeroTimestamps similarly means we only emit a MODIFY COLUMN if
+	// something OTHER than presence of a zero-date default differs.
+	if mods.LaxZeroTimestamps && mc.OldColumn.zeroTimestampDefaultChange(mc.NewColumn) {
+		if positionClause == "" {
+			return ""
+		}
+		oldColumnCopy := *mc.OldColumn
+		oldColumnCopy.Default = mc.NewColumn.Default
+		mc.OldColumn = &oldColumnCopy
+	}
+
 	// If the only difference is a position difference, and LaxColumnOrder is
 	// enabled, emit a no-op.
 	if positionClause != "" && mods.LaxColumnOrder && mc.OldColumn.Equals(mc.NewColumn) {
@@ -406,11 +747,91 @@ func (mc ModifyColumn) Clause(mods StatementModifiers) string {
 	// differences, such as presence/lack of int display width, or presence/lack
 	// of charset/collation clauses that are equal to the table's defaults anyway.
 	// (These situations only come up in MySQL 8, under various edge cases.)
-	if !mods.StrictColumnDefinition && (positionClause == "" || mods.LaxColumnOrder) && mc.OldColumn.Equivalent(mc.NewColumn) {
+	if !mods.StrictColumnDefinition && (positionClause == "" || mods.LaxColumnOrder) && mc.OldColumn.EquivalentForFlavor(mc.NewColumn, mods.Flavor) {
 		return ""
 	}
 
-	return "MODIFY COLUMN " + mc.NewColumn.Definition(mods.Flavor) + positionClause
+	// If only the collation is changing, omit any CHARACTER SET clause from the
+	// new definition, since the server infers it from the collation anyway
+	newCol := mc.NewColumn
+	if !mods.StrictColumnDefinition && newCol.ShowCharSet && mc.OldColumn.collationOnlyChange(newCol) {
+		colCopy := *newCol
+		colCopy.ShowCharSet, colCopy.ShowCollation = false, true
+		newCol = &colCopy
+	}
+
+	return "MODIFY COLUMN" + existenceGuard(mods, false) + " " + newCol.Definition(mods.Flavor) + positionClause
+}
+
+// Summary returns a structured representation of this clause.
+func (mc ModifyColumn) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(mc, mc.NewColumn.Name, mods)
+}
+
+// Affects returns the name of the modified column.
+func (mc ModifyColumn) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{mc.OldColumn.Name}}
+}
+
+// RebuildsTable returns true if this clause is known to always require a full
+// copy of the table's data, regardless of the ALTER TABLE algorithm. This is
+// the case when converting a generated column between VIRTUAL and STORED, since
+// the column's values must be materialized or discarded for every row. It is
+// also the case when adding, changing, or removing a spatial column's SRID
+// attribute, since the server must revalidate the column's existing values.
+// Changing only a column's collation is treated as a rebuild as well, since
+// the change affects how existing values compare and sort; although some
+// MariaDB versions can change the collation of a non-indexed column in-place,
+// MySQL always copies the table for this.
+func (mc ModifyColumn) RebuildsTable() bool {
+	return mc.OldColumn.generatedStorageChange(mc.NewColumn) || mc.OldColumn.spatialReferenceChange(mc.NewColumn) || mc.OldColumn.collationOnlyChange(mc.NewColumn)
+}
+
+// DataLossRisk returns a non-empty reason if this clause may lose or truncate
+// existing values of the column. Unlike Unsafe, this ignores changes which are
+// risky for other reasons: for example re-ordering an enum's value list, or
+// changing the collation of a column in a unique index. Changing the character
+// set is only a risk if the new character set cannot represent all characters
+// of the old one; changing a textual column to a non-textual type is instead
+// always reported as a type change. Changing from NULL to NOT NULL is always a
+// risk, since the server converts existing NULL values to the type's zero value
+// if strict sql_mode is disabled.
+func (mc ModifyColumn) DataLossRisk(mods StatementModifiers) string {
+	if mc.OldColumn.Virtual {
+		return ""
+	}
+	// Changing a string column to a non-string type is a risk regardless of
+	// character set, and must be checked first since the new column lacks one
+	if mc.OldColumn.CharSet != "" && mc.NewColumn.CharSet == "" {
+		return fmt.Sprintf("changing column %s from %s to %s may truncate or lose existing values", mc.OldColumn.Name, mc.OldColumn.Type, mc.NewColumn.Type)
+	}
+	if !charSetCanRepresent(mc.OldColumn.CharSet, mc.NewColumn.CharSet) {
+		return fmt.Sprintf("converting column %s from character set %s to %s may lose characters which cannot be represented", mc.OldColumn.Name, mc.OldColumn.CharSet, mc.NewColumn.CharSet)
+	}
+	if mc.OldColumn.Nullable && !mc.NewColumn.Nullable {
+		return "changing column " + mc.OldColumn.Name + " to NOT NULL would convert any existing NULL values to the type's zero value if strict sql_mode is disabled"
+	}
+	oldType, newType := mc.OldColumn.Type, mc.NewColumn.Type
+	if oldType.Base == newType.Base && (oldType.Base == "enum" || oldType.Base == "set") {
+		for _, value := range oldType.Values() {
+			if !slices.Contains(newType.Values(), value) {
+				return fmt.Sprintf("removing value '%s' from column %s's %s value list would lose any existing occurrences of it", value, mc.OldColumn.Name, oldType.Base)
+			}
+		}
+		return ""
+	}
+
+	// Evaluate any remaining type change as if the character set and nullability
+	// were unchanged, so that Unsafe only considers the type itself
+	mcCopy, newCol := mc, *mc.NewColumn
+	newCol.CharSet, newCol.Collation = mc.OldColumn.CharSet, mc.OldColumn.Collation
+	newCol.Nullable = mc.OldColumn.Nullable
+	mcCopy.NewColumn = &newCol
+	mcCopy.InUniqueConstraint = false
+	if unsafe, _ := mcCopy.Unsafe(mods); unsafe && !mc.OldColumn.spatialReferenceChange(mc.NewColumn) {
+		return "modification to column " + mc.OldColumn.Name + " may truncate or lose existing values"
+	}
+	return ""
 }
 
 // Unsafe returns true if this clause is potentially destroys/corrupts existing
@@ -436,6 +857,9 @@ func (mc ModifyColumn) Unsafe(mods StatementModifiers) (unsafe bool, reason stri
 	// * changing, adding, or removing SRID is unsafe: changing or adding it
 	//   restricts what data can be in the column; removing it would prevent
 	//   a usable spatial index from being added to the column
+	// * changing from NULL to NOT NULL is unsafe: it fails if any existing rows
+	//   have a NULL value, or converts them to the type's zero value if strict
+	//   sql_mode is disabled
 	// * otherwise, leaving column type as-is is safe
 	if mc.OldColumn.Virtual {
 		return false, ""
@@ -446,8 +870,14 @@ func (mc ModifyColumn) Unsafe(mods StatementModifiers) (unsafe bool, reason stri
 	if !collationsEquivalent(mc.OldColumn.Collation, mc.NewColumn.Collation) && mc.InUniqueConstraint {
 		return true, "collation change for column " + mc.OldColumn.Name + " affects equality comparisons in unique index"
 	}
-	if mc.OldColumn.SpatialReferenceID != mc.NewColumn.SpatialReferenceID || mc.OldColumn.HasSpatialReference != mc.NewColumn.HasSpatialReference {
-		return true, genericReason
+	if mc.OldColumn.spatialReferenceChange(mc.NewColumn) {
+		if !mc.NewColumn.HasSpatialReference {
+			return true, "removing SRID from column " + mc.OldColumn.Name + " prevents its use in a spatial index"
+		}
+		return true, fmt.Sprintf("adding SRID %d constraint to column %s will fail if any existing values use a different SRID", mc.NewColumn.SpatialReferenceID, mc.OldColumn.Name)
+	}
+	if mc.OldColumn.Nullable && !mc.NewColumn.Nullable {
+		return true, "changing column " + mc.OldColumn.Name + " to NOT NULL will fail if any existing rows have a NULL value"
 	}
 	oldType := mc.OldColumn.Type
 	newType := mc.NewColumn.Type
@@ -590,6 +1020,7 @@ func (mc ModifyColumn) Unsafe(mods StatementModifiers) (unsafe bool, reason stri
 // ChangeAutoIncrement represents a difference in next-auto-increment value
 // between two versions of a table. It satisfies the TableAlterClause interface.
 type ChangeAutoIncrement struct {
+	alwaysSafe
 	OldNextAutoIncrement uint64
 	NewNextAutoIncrement uint64
 }
@@ -606,12 +1037,23 @@ func (cai ChangeAutoIncrement) Clause(mods StatementModifiers) string {
 	return fmt.Sprintf("AUTO_INCREMENT = %d", cai.NewNextAutoIncrement)
 }
 
+// Summary returns a structured representation of this clause.
+func (cai ChangeAutoIncrement) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(cai, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeAutoIncrement.
+func (cai ChangeAutoIncrement) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
 ///// ChangeCharSet ////////////////////////////////////////////////////////////
 
 // ChangeCharSet represents a difference in default character set and/or
 // collation between two versions of a table. It satisfies the TableAlterClause
 // interface.
 type ChangeCharSet struct {
+	alwaysSafe
 	FromCharSet   string
 	FromCollation string
 	ToCharSet     string
@@ -619,38 +1061,115 @@ type ChangeCharSet struct {
 }
 
 // Clause returns a DEFAULT CHARACTER SET clause of an ALTER TABLE statement.
+// If only the default collation is changing, a DEFAULT COLLATE clause is
+// returned instead. Either way, this only affects the defaults inherited by
+// columns added subsequently, not the definitions of existing columns.
 func (ccs ChangeCharSet) Clause(_ StatementModifiers) string {
 	// Each collation belongs to exactly one character set. However, the canonical
 	// name of a character set can change across flavors/versions (currently just
 	// in terms of "utf8" becoming "utf8mb3"). To permit comparing tables
 	// introspected from different flavors/versions, emit a blank (no-op) clause
 	// in this situation.
-	if ccs.FromCollation == ccs.ToCollation {
+	if collationsEquivalent(ccs.FromCollation, ccs.ToCollation) {
 		return ""
 	}
-	if strings.HasPrefix(ccs.FromCollation, "utf8mb3_") || strings.HasPrefix(ccs.ToCollation, "utf8mb3_") {
-		fromNormalized := strings.Replace(ccs.FromCollation, "utf8_", "utf8mb3_", 1)
-		toNormalized := strings.Replace(ccs.ToCollation, "utf8_", "utf8mb3_", 1)
-		if fromNormalized == toNormalized {
-			return ""
-		}
+	if charsetsEquivalent(ccs.FromCharSet, ccs.ToCharSet) {
+		return "DEFAULT COLLATE = " + ccs.ToCollation
 	}
 	return fmt.Sprintf("DEFAULT CHARACTER SET = %s COLLATE = %s", ccs.ToCharSet, ccs.ToCollation)
 }
 
+// Summary returns a structured representation of this clause.
+func (ccs ChangeCharSet) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ccs, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeCharSet.
+func (ccs ChangeCharSet) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+///// ConvertCharSet ///////////////////////////////////////////////////////////
+
+// ConvertCharSet represents a difference in default character set and/or
+// collation between two versions of a table, in a situation where every text
+// column inherits the table's default on both sides. This means the change
+// can optionally be expressed as a single CONVERT TO CHARACTER SET clause,
+// instead of a combination of ChangeCharSet and per-column modifications. It
+// satisfies the TableAlterClause interface.
+type ConvertCharSet struct {
+	FromCharSet   string
+	FromCollation string
+	ToCharSet     string
+	ToCollation   string
+}
+
+// Clause returns a CONVERT TO CHARACTER SET clause of an ALTER TABLE statement
+// if mods.PreferConvertCharSet is enabled. Otherwise, it returns the same
+// clause as an equivalent ChangeCharSet, in which case the diff's ModifyColumn
+// clauses handle the individual column changes.
+func (cvcs ConvertCharSet) Clause(mods StatementModifiers) string {
+	if !mods.PreferConvertCharSet {
+		return cvcs.changeCharSet().Clause(mods)
+	}
+	return fmt.Sprintf("CONVERT TO CHARACTER SET %s COLLATE %s", cvcs.ToCharSet, cvcs.ToCollation)
+}
+
+// changeCharSet returns the ChangeCharSet which is equivalent to cvcs for
+// purposes of only changing the table's default.
+func (cvcs ConvertCharSet) changeCharSet() ChangeCharSet {
+	return ChangeCharSet{
+		FromCharSet:   cvcs.FromCharSet,
+		FromCollation: cvcs.FromCollation,
+		ToCharSet:     cvcs.ToCharSet,
+		ToCollation:   cvcs.ToCollation,
+	}
+}
+
+// Summary returns a structured representation of this clause. If
+// mods.PreferConvertCharSet is not enabled, the summary is that of the
+// equivalent ChangeCharSet, since that is what Clause emits in this situation.
+func (cvcs ConvertCharSet) Summary(mods StatementModifiers) ClauseSummary {
+	if !mods.PreferConvertCharSet {
+		return cvcs.changeCharSet().Summary(mods)
+	}
+	return summarizeClause(cvcs, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ConvertCharSet. The
+// affected columns are reflected by the diff's ModifyColumn clauses instead.
+func (cvcs ConvertCharSet) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// Unsafe returns true if mods.PreferConvertCharSet is enabled, since CONVERT
+// TO CHARACTER SET rewrites the data in every text column of the table.
+func (cvcs ConvertCharSet) Unsafe(mods StatementModifiers) (unsafe bool, reason string) {
+	if !mods.PreferConvertCharSet {
+		return false, ""
+	}
+	return true, "CONVERT TO CHARACTER SET " + cvcs.ToCharSet + " rewrites all text columns"
+}
+
 ///// ChangeCreateOptions //////////////////////////////////////////////////////
 
 // ChangeCreateOptions represents a difference in the create options
 // (row_format, stats_persistent, stats_auto_recalc, etc) between two versions
 // of a table. It satisfies the TableAlterClause interface.
 type ChangeCreateOptions struct {
+	alwaysSafe
 	OldCreateOptions string
 	NewCreateOptions string
+	innoDB           bool // true if the table uses InnoDB in both versions
 }
 
 // Clause returns a clause of an ALTER TABLE statement that sets one or more
-// create options.
-func (cco ChangeCreateOptions) Clause(_ StatementModifiers) string {
+// create options. Any options named in mods.IgnoreTableOptions are omitted.
+// For InnoDB tables, if one side omits ROW_FORMAT and the other side explicitly
+// specifies mods.Flavor's default row format, ROW_FORMAT is not considered to
+// differ. Similarly, an explicit ROW_FORMAT=COMPRESSED on one side does not
+// differ from an implied one on the other side, if both have a KEY_BLOCK_SIZE.
+func (cco ChangeCreateOptions) Clause(mods StatementModifiers) string {
 	// Map of known defaults that make options no longer show up in create_options
 	// or SHOW CREATE TABLE.
 	knownDefaults := map[string]string{
@@ -666,13 +1185,26 @@ func (cco ChangeCreateOptions) Clause(_ StatementModifiers) string {
 		"ROW_FORMAT":         "DEFAULT",
 		"KEY_BLOCK_SIZE":     "0",
 		"COMPRESSION":        "''", // Undocumented way of removing clause entirely (vs "None" which sticks around)
+		"ENCRYPTION":         "'N'",
 	}
 
+	// MariaDB engine-defined options (e.g. ENCRYPTED, ENCRYPTION_KEY_ID,
+	// PAGE_COMPRESSED) are backtick-wrapped and retain their original casing in
+	// SHOW CREATE TABLE, so their names are upper-cased here to avoid emitting
+	// spurious clauses for casing-only differences.
 	splitOpts := func(full string) map[string]string {
 		result := make(map[string]string)
 		for _, kv := range strings.Split(full, " ") {
 			tokens := strings.Split(kv, "=")
 			if len(tokens) == 2 {
+				if k := tokens[0]; k != "" && k[0] == '`' {
+					tokens[0] = "`" + strings.ToUpper(stripBackticks(k)) + "`"
+				}
+				// Options explicitly set to their default (e.g. AVG_ROW_LENGTH=0 or
+				// STATS_PERSISTENT=DEFAULT) are equivalent to not being set at all.
+				if def := knownDefaults[tokens[0]]; (def == "0" || def == "DEFAULT") && strings.EqualFold(tokens[1], def) {
+					continue
+				}
 				result[tokens[0]] = tokens[1]
 			}
 		}
@@ -681,6 +1213,51 @@ func (cco ChangeCreateOptions) Clause(_ StatementModifiers) string {
 
 	oldOpts := splitOpts(cco.OldCreateOptions)
 	newOpts := splitOpts(cco.NewCreateOptions)
+	for _, opts := range []map[string]string{oldOpts, newOpts} {
+		for k := range opts {
+			if ignoreTableOption(k, mods) {
+				delete(opts, k)
+			}
+		}
+	}
+	// MySQL may continue to show ENCRYPTION='N' in SHOW CREATE TABLE after it is
+	// used to remove encryption. This is only equivalent to omitting the option
+	// if the server doesn't encrypt tables by default; otherwise, an explicit 'N'
+	// is meaningful, since a table without it may inherit encryption.
+	if mods.Flavor.MinMySQL(5, 7) && !mods.DefaultTableEncryption {
+		oldEncryption, oldHas := oldOpts["ENCRYPTION"]
+		newEncryption, newHas := newOpts["ENCRYPTION"]
+		if !newHas && strings.EqualFold(oldEncryption, "'N'") {
+			delete(oldOpts, "ENCRYPTION")
+		} else if !oldHas && strings.EqualFold(newEncryption, "'N'") {
+			delete(newOpts, "ENCRYPTION")
+		}
+	}
+	defaultRowFormat := mods.DefaultRowFormat
+	if defaultRowFormat == "" {
+		defaultRowFormat = mods.Flavor.DefaultRowFormat()
+	}
+	if cco.innoDB && defaultRowFormat != "" {
+		oldRowFormat, oldHas := oldOpts["ROW_FORMAT"]
+		newRowFormat, newHas := newOpts["ROW_FORMAT"]
+		if !newHas && strings.EqualFold(oldRowFormat, defaultRowFormat) {
+			delete(oldOpts, "ROW_FORMAT")
+		} else if !oldHas && strings.EqualFold(newRowFormat, defaultRowFormat) {
+			delete(newOpts, "ROW_FORMAT")
+		}
+	}
+	// InnoDB implicitly uses ROW_FORMAT=COMPRESSED for any table with a nonzero
+	// KEY_BLOCK_SIZE. So if both sides have a KEY_BLOCK_SIZE, an explicit
+	// ROW_FORMAT=COMPRESSED on just one side is not a difference.
+	if cco.innoDB && oldOpts["KEY_BLOCK_SIZE"] != "" && newOpts["KEY_BLOCK_SIZE"] != "" {
+		oldRowFormat, oldHas := oldOpts["ROW_FORMAT"]
+		newRowFormat, newHas := newOpts["ROW_FORMAT"]
+		if !newHas && strings.EqualFold(oldRowFormat, "COMPRESSED") {
+			delete(oldOpts, "ROW_FORMAT")
+		} else if !oldHas && strings.EqualFold(newRowFormat, "COMPRESSED") {
+			delete(newOpts, "ROW_FORMAT")
+		}
+	}
 	subclauses := make([]string, 0, len(knownDefaults))
 
 	// Determine which oldOpts changed in newOpts or are no longer present
@@ -706,44 +1283,194 @@ func (cco ChangeCreateOptions) Clause(_ StatementModifiers) string {
 	return strings.Join(subclauses, " ")
 }
 
+// ignoreTableOption returns true if the table-level create option name is
+// listed in mods.IgnoreTableOptions. Comparison is case-insensitive, and
+// ignores any backticks wrapping MariaDB engine-defined option names. This only
+// affects table-level options; column-level and index-level clauses which
+// happen to use the same names (e.g. a column COMMENT, or an index
+// KEY_BLOCK_SIZE) are unaffected.
+func ignoreTableOption(name string, mods StatementModifiers) bool {
+	name = stripBackticks(name)
+	for _, ignored := range mods.IgnoreTableOptions {
+		if strings.EqualFold(name, stripBackticks(ignored)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary returns a structured representation of this clause.
+func (cco ChangeCreateOptions) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(cco, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeCreateOptions.
+func (cco ChangeCreateOptions) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
 ///// ChangeComment ////////////////////////////////////////////////////////////
 
 // ChangeComment represents a difference in the table-level comment between two
 // versions of a table. It satisfies the TableAlterClause interface.
 type ChangeComment struct {
+	alwaysSafe
 	NewComment string
 }
 
 // Clause returns a clause of an ALTER TABLE statement that changes a table's
 // comment.
-func (cc ChangeComment) Clause(_ StatementModifiers) string {
+func (cc ChangeComment) Clause(mods StatementModifiers) string {
 	// Note: mods.LaxComments is handled in TableDiff.alterStatement() rather than
 	// here, since that modifier's effect depends on whether anything else besides
 	// the comment is also changing
+	if ignoreTableOption("COMMENT", mods) {
+		return ""
+	}
 	return fmt.Sprintf("COMMENT '%s'", EscapeValueForCreateTable(cc.NewComment))
 }
 
+// Summary returns a structured representation of this clause.
+func (cc ChangeComment) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(cc, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeComment.
+func (cc ChangeComment) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+///// ChangeEngineAttribute ////////////////////////////////////////////////////
+
+// ChangeEngineAttribute represents a difference in a table's ENGINE_ATTRIBUTE
+// or SECONDARY_ENGINE_ATTRIBUTE option, available in MySQL 8.0.21+. It
+// satisfies the TableAlterClause interface.
+type ChangeEngineAttribute struct {
+	alwaysSafe
+	Secondary bool // true for SECONDARY_ENGINE_ATTRIBUTE, false for ENGINE_ATTRIBUTE
+	OldValue  string
+	NewValue  string
+}
+
+// optionName returns the name of the table option being changed.
+func (cea ChangeEngineAttribute) optionName() string {
+	if cea.Secondary {
+		return "SECONDARY_ENGINE_ATTRIBUTE"
+	}
+	return "ENGINE_ATTRIBUTE"
+}
+
+// Clause returns a clause of an ALTER TABLE statement that changes a table's
+// ENGINE_ATTRIBUTE or SECONDARY_ENGINE_ATTRIBUTE. Since the values are JSON
+// documents, an empty string is returned if the old and new values only differ
+// in formatting or object key order. An empty string is also returned if
+// mods.Flavor is MariaDB or a MySQL version prior to 8.0.21, since these lack
+// support for the option.
+func (cea ChangeEngineAttribute) Clause(mods StatementModifiers) string {
+	name := cea.optionName()
+	if engineAttributesEquivalent(cea.OldValue, cea.NewValue) || ignoreTableOption(name, mods) {
+		return ""
+	} else if mods.Flavor.IsMariaDB() || (mods.Flavor.IsMySQL() && !mods.Flavor.MinMySQL(8, 0, 21)) {
+		return ""
+	}
+	return fmt.Sprintf("%s='%s'", name, EscapeValueForCreateTable(cea.NewValue))
+}
+
+// Summary returns a structured representation of this clause.
+func (cea ChangeEngineAttribute) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(cea, cea.optionName(), mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeEngineAttribute.
+func (cea ChangeEngineAttribute) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
 ///// ChangeTablespace /////////////////////////////////////////////////////////
 
 // ChangeTablespace represents a difference in the table's TABLESPACE clause
 // between two versions of a table. It satisfies the TableAlterClause interface.
 type ChangeTablespace struct {
+	alwaysSafe
 	NewTablespace string
 }
 
 // Clause returns a clause of an ALTER TABLE statement that changes a table's
-// tablespace.
-func (ct ChangeTablespace) Clause(_ StatementModifiers) string {
+// tablespace. General tablespaces require MySQL 5.7+, so an empty string is
+// returned if mods.Flavor is an older MySQL or any MariaDB, since these ignore
+// or reject the clause.
+func (ct ChangeTablespace) Clause(mods StatementModifiers) string {
 	// Once an explicit tablespace name has been specified, there's no way to
 	// hide it again. Table.Diff will still generate a ChangeTablespace value,
 	// which avoids the "unsupported diff due to no clauses generated" check,
 	// but there's nothing to actually run.
-	if ct.NewTablespace == "" {
+	if ct.NewTablespace == "" || mods.Flavor.IsMariaDB() || mods.Flavor.IsMySQL(5, 5) || mods.Flavor.IsMySQL(5, 6) {
 		return ""
 	}
 	return "TABLESPACE " + EscapeIdentifier(ct.NewTablespace)
 }
 
+// RebuildsTable returns true if this clause moves the table to a different
+// tablespace, which always requires copying all of the table's data.
+func (ct ChangeTablespace) RebuildsTable() bool {
+	return ct.NewTablespace != ""
+}
+
+// Summary returns a structured representation of this clause.
+func (ct ChangeTablespace) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ct, ct.NewTablespace, mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeTablespace.
+func (ct ChangeTablespace) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+///// ChangeMergeOptions ///////////////////////////////////////////////////////
+
+// ChangeMergeOptions represents a difference in the INSERT_METHOD and/or UNION
+// table options of a MERGE table. It satisfies the TableAlterClause interface.
+// It is always safe, since it only affects which underlying tables are
+// referenced.
+type ChangeMergeOptions struct {
+	alwaysSafe
+	OldMergeOptions *MergeOptions
+	NewMergeOptions *MergeOptions
+}
+
+// Clause returns a clause of an ALTER TABLE statement that sets the MERGE
+// table options which differ.
+func (cmo ChangeMergeOptions) Clause(_ StatementModifiers) string {
+	var oldMethod, newMethod string
+	if cmo.OldMergeOptions != nil {
+		oldMethod = cmo.OldMergeOptions.InsertMethod
+	}
+	if cmo.NewMergeOptions != nil {
+		newMethod = cmo.NewMergeOptions.InsertMethod
+	}
+	var subclauses []string
+	if oldMethod != newMethod {
+		if newMethod == "" {
+			newMethod = "NO"
+		}
+		subclauses = append(subclauses, "INSERT_METHOD="+newMethod)
+	}
+	if newUnion := cmo.NewMergeOptions.unionClause(); newUnion != cmo.OldMergeOptions.unionClause() {
+		subclauses = append(subclauses, newUnion)
+	}
+	return strings.Join(subclauses, " ")
+}
+
+// Summary returns a structured representation of this clause.
+func (cmo ChangeMergeOptions) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(cmo, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeMergeOptions.
+func (cmo ChangeMergeOptions) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
 ///// ChangeStorageEngine //////////////////////////////////////////////////////
 
 // ChangeStorageEngine represents a difference in the table's storage engine.
@@ -761,6 +1488,16 @@ func (cse ChangeStorageEngine) Clause(_ StatementModifiers) string {
 	return fmt.Sprintf("ENGINE=%s", cse.NewStorageEngine)
 }
 
+// Summary returns a structured representation of this clause.
+func (cse ChangeStorageEngine) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(cse, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for ChangeStorageEngine.
+func (cse ChangeStorageEngine) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
 // Unsafe returns true if this clause is potentially destructive of data.
 // ChangeStorageEngine is always considered unsafe, due to the potential
 // complexity in converting a table's data to the new storage engine.
@@ -768,6 +1505,125 @@ func (cse ChangeStorageEngine) Unsafe(_ StatementModifiers) (unsafe bool, reason
 	return true, "storage engine changes have significant operational implications"
 }
 
+///// ChangeSystemVersioning ///////////////////////////////////////////////////
+
+// ChangeSystemVersioning represents adding or removing MariaDB system
+// versioning on a table. It satisfies the TableAlterClause interface.
+// If the table uses explicit period columns, these must be added by separate
+// AddColumn clauses, followed by an AddSystemTimePeriod clause, in the same
+// ALTER TABLE. Removing system versioning is only supported for tables using
+// implicit period columns.
+type ChangeSystemVersioning struct {
+	Enabled bool
+}
+
+// Clause returns a clause of an ALTER TABLE statement that adds or drops
+// system versioning.
+func (csv ChangeSystemVersioning) Clause(_ StatementModifiers) string {
+	if !csv.Enabled {
+		return "DROP SYSTEM VERSIONING"
+	}
+	return "ADD SYSTEM VERSIONING"
+}
+
+// Summary returns a structured representation of this clause.
+func (csv ChangeSystemVersioning) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(csv, "", mods)
+}
+
+// Affects returns an empty AffectedNames, since system versioning does not
+// affect any specific named columns, indexes, or constraints.
+func (csv ChangeSystemVersioning) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// Unsafe returns true if this clause is potentially destructive of data.
+// Dropping system versioning is always considered unsafe, since it permanently
+// discards all historical row versions.
+func (csv ChangeSystemVersioning) Unsafe(_ StatementModifiers) (unsafe bool, reason string) {
+	if unsafe = !csv.Enabled; unsafe {
+		reason = "system versioning would be dropped, permanently removing all historical row versions"
+	}
+	return
+}
+
+///// AddSystemTimePeriod //////////////////////////////////////////////////////
+
+// AddSystemTimePeriod represents adding a MariaDB PERIOD FOR SYSTEM_TIME using
+// explicit row start and row end columns. It must be followed by a
+// ChangeSystemVersioning clause in the same ALTER TABLE. It satisfies the
+// TableAlterClause interface.
+type AddSystemTimePeriod struct {
+	alwaysSafe
+	RowStart string
+	RowEnd   string
+}
+
+// Clause returns an ADD PERIOD FOR SYSTEM_TIME clause of an ALTER TABLE
+// statement.
+func (astp AddSystemTimePeriod) Clause(_ StatementModifiers) string {
+	return fmt.Sprintf("ADD PERIOD FOR SYSTEM_TIME(%s, %s)", EscapeIdentifier(astp.RowStart), EscapeIdentifier(astp.RowEnd))
+}
+
+// Summary returns a structured representation of this clause.
+func (astp AddSystemTimePeriod) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(astp, "", mods)
+}
+
+// Affects returns the names of the row start and row end columns.
+func (astp AddSystemTimePeriod) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{astp.RowStart, astp.RowEnd}}
+}
+
+///// AddApplicationPeriod /////////////////////////////////////////////////////
+
+// AddApplicationPeriod represents adding a MariaDB application-time period to
+// a table. It satisfies the TableAlterClause interface.
+type AddApplicationPeriod struct {
+	alwaysSafe
+	Period *ApplicationPeriod
+}
+
+// Clause returns an ADD PERIOD clause of an ALTER TABLE statement.
+func (aap AddApplicationPeriod) Clause(_ StatementModifiers) string {
+	return "ADD " + aap.Period.Definition()
+}
+
+// Summary returns a structured representation of this clause.
+func (aap AddApplicationPeriod) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(aap, aap.Period.Name, mods)
+}
+
+// Affects returns the names of the period's start and end columns.
+func (aap AddApplicationPeriod) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{aap.Period.StartColumn, aap.Period.EndColumn}}
+}
+
+///// DropApplicationPeriod ////////////////////////////////////////////////////
+
+// DropApplicationPeriod represents removing a MariaDB application-time period
+// from a table. The period's start and end columns are not affected. It
+// satisfies the TableAlterClause interface.
+type DropApplicationPeriod struct {
+	alwaysSafe
+	Period *ApplicationPeriod
+}
+
+// Clause returns a DROP PERIOD clause of an ALTER TABLE statement.
+func (dap DropApplicationPeriod) Clause(_ StatementModifiers) string {
+	return "DROP PERIOD FOR " + EscapeIdentifier(dap.Period.Name)
+}
+
+// Summary returns a structured representation of this clause.
+func (dap DropApplicationPeriod) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(dap, dap.Period.Name, mods)
+}
+
+// Affects returns the names of the period's start and end columns.
+func (dap DropApplicationPeriod) Affects() AffectedNames {
+	return AffectedNames{Columns: []string{dap.Period.StartColumn, dap.Period.EndColumn}}
+}
+
 ///// PartitionBy //////////////////////////////////////////////////////////////
 
 // PartitionBy represents initially partitioning a previously-unpartitioned
@@ -787,11 +1643,39 @@ func (pb PartitionBy) Clause(mods StatementModifiers) string {
 	return strings.TrimSpace(pb.Partitioning.Definition(mods.Flavor))
 }
 
+// Summary returns a structured representation of this clause.
+func (pb PartitionBy) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(pb, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for PartitionBy.
+func (pb PartitionBy) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// Unsafe returns true if this clause re-partitions an already-partitioned
+// table. Re-partitioning rebuilds the table, and the ALTER fails if any
+// existing rows do not fit the new partition definitions. Partitioning a
+// previously unpartitioned table is considered safe.
+func (pb PartitionBy) Unsafe(mods StatementModifiers) (unsafe bool, reason string) {
+	if pb.RePartition && pb.Clause(mods) != "" {
+		return true, "re-partitioning by " + pb.Partitioning.FullMethod() + " would rebuild the table, and will fail if any existing rows do not fit the new partition definitions"
+	}
+	return false, ""
+}
+
 ///// RemovePartitioning ///////////////////////////////////////////////////////
 
 // RemovePartitioning represents de-partitioning a previously-partitioned table.
 // It satisfies the TableAlterClause interface.
-type RemovePartitioning struct{}
+// Warning is non-empty if the desired table still has partitioning, but its
+// partition list was reduced to zero partitions. RANGE and LIST partitioned
+// tables cannot drop their last partition, so REMOVE PARTITIONING is used
+// instead; callers should surface this warning to the user.
+type RemovePartitioning struct {
+	alwaysSafe
+	Warning string
+}
 
 // Clause returns a clause of an ALTER TABLE statement that partitions a
 // previously-unpartitioned table.
@@ -802,6 +1686,16 @@ func (rp RemovePartitioning) Clause(mods StatementModifiers) string {
 	return "REMOVE PARTITIONING"
 }
 
+// Summary returns a structured representation of this clause.
+func (rp RemovePartitioning) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(rp, "", mods)
+}
+
+// Affects always returns an empty AffectedNames for RemovePartitioning.
+func (rp RemovePartitioning) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
 ///// ModifyPartitions /////////////////////////////////////////////////////////
 
 // ModifyPartitions represents a change to the partition list for a table using
@@ -836,7 +1730,17 @@ func (mp ModifyPartitions) Clause(mods StatementModifiers) string {
 	// valid syntax because DROP PARTITION cannot occur alongside other alter
 	// clauses. TODO: TableDiff.SplitConflicts() must handle that if/when
 	// partition list modifications are supported in more cases.
-	return "DROP PARTITION " + strings.Join(names, ", ")
+	return "DROP PARTITION" + existenceGuard(mods, false) + " " + strings.Join(names, ", ")
+}
+
+// Summary returns a structured representation of this clause.
+func (mp ModifyPartitions) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(mp, mp.partitionNames(), mods)
+}
+
+// Affects always returns an empty AffectedNames for ModifyPartitions.
+func (mp ModifyPartitions) Affects() AffectedNames {
+	return AffectedNames{}
 }
 
 // Unsafe returns true if this clause is potentially destructive of data.
@@ -850,3 +1754,201 @@ func (mp ModifyPartitions) Unsafe(_ StatementModifiers) (unsafe bool, reason str
 	}
 	return
 }
+
+// partitionNames returns a comma-separated list of the names of all partitions
+// being added or dropped.
+func (mp ModifyPartitions) partitionNames() string {
+	names := make([]string, 0, len(mp.Add)+len(mp.Drop))
+	for _, p := range append(mp.Add, mp.Drop...) {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+///// ExchangePartition ////////////////////////////////////////////////////////
+
+// ExchangePartition represents swapping the data of a partition with that of a
+// separate unpartitioned table, typically a staging table used for bulk loads.
+// This clause is never generated by diff logic, but may be constructed directly
+// by callers. It satisfies the TableAlterClause interface.
+type ExchangePartition struct {
+	PartitionName     string
+	StagingTable      string
+	WithoutValidation bool // if true, skip verifying that rows in StagingTable match the partition definition
+}
+
+// Clause returns an EXCHANGE PARTITION clause of an ALTER TABLE statement. The
+// WITHOUT VALIDATION modifier is omitted in flavors which don't support it;
+// this results in the default behavior of validating the rows.
+func (ep ExchangePartition) Clause(mods StatementModifiers) string {
+	clause := "EXCHANGE PARTITION " + EscapeIdentifier(ep.PartitionName) + " WITH TABLE " + EscapeIdentifier(ep.StagingTable)
+	if ep.WithoutValidation && (mods.Flavor.MinMySQL(5, 7, 5) || mods.Flavor.MinMariaDB(11, 4)) {
+		clause += " WITHOUT VALIDATION"
+	}
+	return clause
+}
+
+// Summary returns a structured representation of this clause.
+func (ep ExchangePartition) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ep, ep.PartitionName, mods)
+}
+
+// Affects always returns an empty AffectedNames for ExchangePartition.
+func (ep ExchangePartition) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// Unsafe returns true if this clause is potentially destructive of data.
+// ExchangePartition is always considered unsafe, since the partition's existing
+// rows are moved out of the table.
+func (ep ExchangePartition) Unsafe(_ StatementModifiers) (unsafe bool, reason string) {
+	return true, "partition " + EscapeIdentifier(ep.PartitionName) + " would have its data exchanged with table " + EscapeIdentifier(ep.StagingTable)
+}
+
+///// AddPartitions ////////////////////////////////////////////////////////////
+
+// AddPartitions represents appending new partitions to the end of the partition
+// list of a table using RANGE, RANGE COLUMNS, LIST, or LIST COLUMNS
+// partitioning. It is only generated by TablePartitioning.DecomposedDiff, and
+// must be run in its own ALTER TABLE.
+type AddPartitions struct {
+	alwaysSafe
+	Method     string
+	Partitions []*Partition
+}
+
+// Clause returns an ADD PARTITION clause of an ALTER TABLE statement.
+func (ap AddPartitions) Clause(mods StatementModifiers) string {
+	if mods.Partitioning == PartitioningRemove {
+		return ""
+	}
+	return "ADD PARTITION" + existenceGuard(mods, true) + " (" + partitionDefinitions(ap.Partitions, mods.Flavor, ap.Method) + ")"
+}
+
+// Summary returns a structured representation of this clause.
+func (ap AddPartitions) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(ap, partitionNames(ap.Partitions), mods)
+}
+
+// Affects always returns an empty AffectedNames for AddPartitions.
+func (ap AddPartitions) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+///// DropPartitions ///////////////////////////////////////////////////////////
+
+// DropPartitions represents removing partitions, along with all of their rows,
+// from a table using RANGE, RANGE COLUMNS, LIST, or LIST COLUMNS partitioning.
+// It is only generated by TablePartitioning.DecomposedDiff, and must be run in
+// its own ALTER TABLE.
+type DropPartitions struct {
+	Partitions []*Partition
+}
+
+// Clause returns a DROP PARTITION clause of an ALTER TABLE statement.
+func (dp DropPartitions) Clause(mods StatementModifiers) string {
+	if mods.Partitioning == PartitioningRemove {
+		return ""
+	}
+	names := make([]string, len(dp.Partitions))
+	for n, p := range dp.Partitions {
+		names[n] = EscapeIdentifier(p.Name)
+	}
+	return "DROP PARTITION" + existenceGuard(mods, false) + " " + strings.Join(names, ", ")
+}
+
+// Summary returns a structured representation of this clause.
+func (dp DropPartitions) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(dp, partitionNames(dp.Partitions), mods)
+}
+
+// Affects always returns an empty AffectedNames for DropPartitions.
+func (dp DropPartitions) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// Unsafe always returns true, since dropping partitions destroys their rows.
+func (dp DropPartitions) Unsafe(_ StatementModifiers) (unsafe bool, reason string) {
+	noun := fmt.Sprintf("%d partitions", len(dp.Partitions))
+	if len(dp.Partitions) == 1 {
+		noun = "a partition"
+	}
+	return true, noun + " would be dropped"
+}
+
+///// ReorganizePartitions /////////////////////////////////////////////////////
+
+// ReorganizePartitions represents replacing one or more consecutive partitions
+// with a new set of partitions, for example to split a partition in two or to
+// change a partition's comment. Rows are redistributed into the new partitions.
+// It is only generated by TablePartitioning.DecomposedDiff, and must be run in
+// its own ALTER TABLE. It is always safe, since the server rejects a
+// reorganization in which existing rows would not fit into the new partitions,
+// rather than losing them.
+type ReorganizePartitions struct {
+	alwaysSafe
+	Method string
+	From   []*Partition
+	To     []*Partition
+}
+
+// Clause returns a REORGANIZE PARTITION clause of an ALTER TABLE statement.
+// If mods.LaxComments is true and the only difference is partition comments, an
+// empty string is returned, since rebuilding partitions just to change their
+// comments is rarely worthwhile.
+func (rp ReorganizePartitions) Clause(mods StatementModifiers) string {
+	if mods.Partitioning == PartitioningRemove || (mods.LaxComments && rp.onlyCommentsDiffer()) {
+		return ""
+	}
+	names := make([]string, len(rp.From))
+	for n, p := range rp.From {
+		names[n] = EscapeIdentifier(p.Name)
+	}
+	return "REORGANIZE PARTITION " + strings.Join(names, ", ") + " INTO (" + partitionDefinitions(rp.To, mods.Flavor, rp.Method) + ")"
+}
+
+// Summary returns a structured representation of this clause.
+func (rp ReorganizePartitions) Summary(mods StatementModifiers) ClauseSummary {
+	return summarizeClause(rp, partitionNames(rp.From), mods)
+}
+
+// Affects always returns an empty AffectedNames for ReorganizePartitions.
+func (rp ReorganizePartitions) Affects() AffectedNames {
+	return AffectedNames{}
+}
+
+// onlyCommentsDiffer returns true if each partition in rp.From is identical to
+// the corresponding partition in rp.To aside from its comment.
+func (rp ReorganizePartitions) onlyCommentsDiffer() bool {
+	if len(rp.From) != len(rp.To) {
+		return false
+	}
+	for n, from := range rp.From {
+		fromCopy, toCopy := *from, *rp.To[n]
+		fromCopy.Comment, toCopy.Comment = "", ""
+		if !fromCopy.equals(&toCopy, rp.Method) {
+			return false
+		}
+	}
+	return true
+}
+
+// partitionDefinitions returns a comma-separated list of the definitions of
+// the supplied partitions.
+func partitionDefinitions(partitions []*Partition, flavor Flavor, method string) string {
+	defs := make([]string, len(partitions))
+	for n, p := range partitions {
+		defs[n] = p.Definition(flavor, method)
+	}
+	return strings.Join(defs, ", ")
+}
+
+// partitionNames returns a comma-separated list of the names of the supplied
+// partitions.
+func partitionNames(partitions []*Partition) string {
+	names := make([]string, len(partitions))
+	for n, p := range partitions {
+		names[n] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -3,6 +3,7 @@ package tengo
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -50,6 +51,12 @@ func NewCreateTable(table *Table) *TableDiff {
 // i.e. a table that exists in the "from" and "to" side schemas but with one
 // or more differences. If the supplied tables are identical, nil will be
 // returned instead of a TableDiff.
+// The two tables may originate from different schemas, for example to detect
+// drift between environments. Generated DDL refers to the table by its bare
+// name, so it may be run against either schema, unless the caller requests
+// otherwise using StatementModifiers.QualifySchema. Foreign keys referencing a
+// table in the same schema are compared by relative name; only foreign keys
+// which explicitly reference another schema are compared by schema name.
 func NewAlterTable(from, to *Table) *TableDiff {
 	clauses, supported := from.Diff(to)
 	if supported && len(clauses) == 0 {
@@ -64,6 +71,28 @@ func NewAlterTable(from, to *Table) *TableDiff {
 	}
 }
 
+// NewRenameTable returns a *TableDiff representing an ALTER TABLE ... RENAME TO
+// statement, i.e. a table that exists with the same definition but a different
+// name in the same schema. This is intended for use when a rename has already
+// been identified by the caller; it is never generated automatically by diff
+// logic. Note that this differs from a RENAME TABLE statement, which can rename
+// several tables atomically, as well as move tables between schemas.
+// The returned TableDiff's To field is a copy of table, with the new name.
+// Callers may use Schema.RenameTableWarnings to identify foreign keys affected
+// by the rename.
+func NewRenameTable(table *Table, newName string) *TableDiff {
+	to := *table
+	to.Name = newName
+	to.CreateStatement = strings.Replace(table.CreateStatement, "CREATE TABLE "+EscapeIdentifier(table.Name), "CREATE TABLE "+EscapeIdentifier(newName), 1)
+	return &TableDiff{
+		Type:         DiffTypeAlter,
+		From:         table,
+		To:           &to,
+		alterClauses: []TableAlterClause{RenameTable{NewName: newName}},
+		supported:    true,
+	}
+}
+
 // NewDropTable returns a *TableDiff representing a DROP TABLE statement,
 // i.e. a table that only exists in the "from" side schema in a diff.
 func NewDropTable(table *Table) *TableDiff {
@@ -107,6 +136,28 @@ func PreDropAlters(table *Table) []*TableDiff {
 	return result
 }
 
+// PartitionAlters returns a slice of *TableDiff which, if run in order, would
+// transform the partitioning of from into that of to. For tables using RANGE or
+// LIST partitioning, changes to the partition list are split into a separate
+// ALTER TABLE for each granular partition operation; see
+// TablePartitioning.DecomposedDiff. If this is not possible, the result is a
+// single re-partitioning ALTER TABLE, and fallbackReason explains why. Only the
+// partitioning of the two tables is compared by this function.
+func PartitionAlters(from, to *Table) (result []*TableDiff, fallbackReason string) {
+	fromPartitioning, toPartitioning := from.Partitioning.withTableEngine(from.Engine), to.Partitioning.withTableEngine(to.Engine)
+	clauses, fallbackReason := fromPartitioning.DecomposedDiff(toPartitioning)
+	for _, clause := range clauses {
+		result = append(result, &TableDiff{
+			Type:         DiffTypeAlter,
+			From:         from,
+			To:           to,
+			alterClauses: []TableAlterClause{clause},
+			supported:    true,
+		})
+	}
+	return result, fallbackReason
+}
+
 // SplitAddForeignKeys looks through a TableDiff's alterClauses and pulls out
 // any AddForeignKey clauses into a separate TableDiff. The first returned
 // TableDiff is guaranteed to contain no AddForeignKey clauses, and the second
@@ -159,8 +210,9 @@ func (td *TableDiff) SplitAddForeignKeys() (*TableDiff, *TableDiff) {
 // SplitConflicts looks through a TableDiff's alterClauses and pulls out any
 // clauses that need to be placed into a separate TableDiff in order to yield
 // legal or error-free DDL, due to DDL edge-cases. This includes attempts to add
-// multiple FULLTEXT indexes in a single ALTER, and attempts to rename an index
-// while also changing its visibility/ignored status.
+// multiple FULLTEXT indexes in a single ALTER (including re-adding a FULLTEXT
+// index to change its parser), and attempts to rename an index while also
+// changing its visibility/ignored status.
 // This method returns a slice of TableDiffs. The first element will be
 // equivalent to the receiver (td) with any conflicting clauses removed;
 // subsequent slice elements, if any, will be separate TableDiffs each
@@ -184,7 +236,15 @@ func (td *TableDiff) SplitConflicts() (result []*TableDiff) {
 				continue
 			}
 			seenAddFulltext = true
-		} else if mi, ok := clause.(ModifyIndex); ok && mi.FromIndex.Equivalent(mi.ToIndex) && mi.FromIndex.Name != mi.ToIndex.Name && mi.FromIndex.Invisible != mi.ToIndex.Invisible {
+		} else if mi, ok := clause.(ModifyIndex); ok && mi.ParserChanged() {
+			// Changing parser requires a DROP and re-ADD of the FULLTEXT index, which
+			// conflicts with any other FULLTEXT index addition
+			if seenAddFulltext {
+				separateClauses = append(separateClauses, clause)
+				continue
+			}
+			seenAddFulltext = true
+		} else if mi, ok := clause.(ModifyIndex); ok && mi.FromIndex.Equivalent(mi.ToIndex) && mi.FromIndex.Name != mi.ToIndex.Name && mi.VisibilityChanged() {
 			// Put an AlterIndex into separateClauses so that we run that clause in its
 			// own separate ALTER TABLE, or skipped if StatementModifiers cause the
 			// original ModifyIndex to be handled as a DROP/re-ADD.
@@ -216,12 +276,353 @@ func (td *TableDiff) SplitConflicts() (result []*TableDiff) {
 	return result
 }
 
+// SplitClauses returns a slice of TableDiffs, each consisting of a single
+// clause of the receiver, in the same order as the receiver's clauses. This
+// permits generating one ALTER TABLE statement per operation, for use with
+// external online schema change tools or for retrying individual operations.
+// Each resulting TableDiff's Statement independently applies any LOCK and
+// ALGORITHM clauses from the supplied StatementModifiers. A ChangeComment
+// clause is kept in the same TableDiff as the preceding clause (if any), so
+// that StatementModifiers.LaxComments behaves the same as in the receiver.
+// Note that some combinations of clauses are only valid within a single ALTER
+// TABLE, such as adding an auto-increment column along with its index; callers
+// should fall back to the receiver if a split statement fails.
+// If the receiver is not a supported ALTER, the result consists only of the
+// receiver.
+func (td *TableDiff) SplitClauses() (result []*TableDiff) {
+	if td == nil {
+		return nil
+	} else if td.Type != DiffTypeAlter || !td.supported || len(td.alterClauses) < 2 {
+		return []*TableDiff{td}
+	}
+	for _, clause := range td.alterClauses {
+		if _, ok := clause.(ChangeComment); ok && len(result) > 0 {
+			prev := result[len(result)-1]
+			prev.alterClauses = append(prev.alterClauses, clause)
+			continue
+		}
+		result = append(result, &TableDiff{
+			Type:         DiffTypeAlter,
+			From:         td.From,
+			To:           td.To,
+			alterClauses: []TableAlterClause{clause},
+			supported:    true,
+		})
+	}
+	return result
+}
+
+// AlterClauses returns a copy of the TableDiff's ALTER TABLE clauses, in the
+// order they would appear in Statement. The result is nil for a TableDiff with
+// a Type other than DiffTypeAlter.
+func (td *TableDiff) AlterClauses() []TableAlterClause {
+	if td == nil || td.Type != DiffTypeAlter {
+		return nil
+	}
+	return slices.Clone(td.alterClauses)
+}
+
+// ClauseSummaries returns a structured representation of each of the
+// TableDiff's clauses, in the order they would appear in Statement, suitable
+// for serialization to JSON. For a TableDiff of DiffTypeCreate or DiffTypeDrop,
+// the result consists of a single summary of the entire statement.
+func (td *TableDiff) ClauseSummaries(mods StatementModifiers) []ClauseSummary {
+	if td == nil {
+		return nil
+	}
+	switch td.Type {
+	case DiffTypeCreate, DiffTypeDrop:
+		summary := ClauseSummary{Name: td.ObjectKey().Name}
+		if td.Type == DiffTypeCreate {
+			summary.Type = "CreateTable"
+		} else {
+			summary.Type = "DropTable"
+		}
+		var err error
+		mods.AllowUnsafe = false // so that err indicates whether the statement is unsafe
+		summary.SQL, err = td.Statement(mods)
+		summary.SQL = RedactSecrets(summary.SQL)
+		var unsafeErr *UnsafeDiffError
+		if errors.As(err, &unsafeErr) {
+			summary.Unsafe, summary.UnsafeReason = true, unsafeErr.Reason
+		}
+		return []ClauseSummary{summary}
+	}
+	result := make([]ClauseSummary, 0, len(td.alterClauses))
+	for _, clause := range td.alterClauses {
+		result = append(result, clause.Summary(mods))
+	}
+	return result
+}
+
+// Warnings returns human-readable warnings about the TableDiff's ALTER TABLE
+// clauses, in the order they would appear in Statement. These describe
+// clauses which are permitted, but which callers should surface to the user:
+// adding a NOT NULL column without a default, or removing partitioning in
+// place of dropping all partitions. The result is nil if there are no warnings.
+func (td *TableDiff) Warnings(mods StatementModifiers) (warnings []string) {
+	for _, clause := range td.AlterClauses() {
+		switch clause := clause.(type) {
+		case AddColumn:
+			if warning := clause.Warning(mods); warning != "" {
+				warnings = append(warnings, warning)
+			}
+		case RemovePartitioning:
+			if clause.Warning != "" && clause.Clause(mods) != "" {
+				warnings = append(warnings, clause.Warning)
+			}
+		}
+	}
+	return warnings
+}
+
+// DataLossRisk describes a part of a TableDiff which could destroy or truncate
+// existing data if applied.
+type DataLossRisk struct {
+	Type   string `json:"type"`           // name of the clause type, e.g. "DropColumn", or "DropTable" for an entire DROP TABLE
+	Name   string `json:"name,omitempty"` // name of the affected column, partition, etc; blank for table-level clauses
+	Reason string `json:"reason"`         // human-readable description of the risk
+}
+
+// DataLossRisks returns a description of each part of td which could lose
+// existing data, in the order the clauses would appear in Statement. This
+// differs from the clauses' Unsafe methods: some unsafe operations cannot lose
+// data (such as renaming a table or re-ordering an enum's values), whereas
+// some data-destroying operations are permitted by Unsafe. The result is nil
+// if td has no risk of data loss.
+func (td *TableDiff) DataLossRisks(mods StatementModifiers) (risks []DataLossRisk) {
+	if td == nil || td.Type == DiffTypeCreate {
+		return nil
+	} else if td.Type == DiffTypeDrop {
+		return []DataLossRisk{{Type: "DropTable", Name: td.From.Name, Reason: "table " + EscapeIdentifier(td.From.Name) + " and all of its data would be dropped"}}
+	}
+	for _, clause := range td.alterClauses {
+		var reason string
+		switch clause := clause.(type) {
+		case ModifyColumn:
+			reason = clause.DataLossRisk(mods)
+		case DropColumn, DropPartitions, ModifyPartitions, ChangeSystemVersioning:
+			// For these clause types, unsafe always means data may be lost
+			_, reason = clause.Unsafe(mods)
+		case ConvertCharSet:
+			// Without PreferConvertCharSet, the diff's ModifyColumn clauses handle
+			// the conversion instead, and are evaluated individually
+			if mods.PreferConvertCharSet && !charSetCanRepresent(clause.FromCharSet, clause.ToCharSet) {
+				reason = fmt.Sprintf("converting table %s from character set %s to %s may lose characters which cannot be represented", td.From.Name, clause.FromCharSet, clause.ToCharSet)
+			}
+		case ChangeStorageEngine:
+			if engine := strings.ToUpper(clause.NewStorageEngine); engine == "BLACKHOLE" || engine == "MEMORY" {
+				reason = "storage engine " + clause.NewStorageEngine + " does not durably retain data"
+			}
+		}
+		if reason != "" && clause.Clause(mods) != "" {
+			summary := clause.Summary(mods)
+			risks = append(risks, DataLossRisk{Type: summary.Type, Name: summary.Name, Reason: reason})
+		}
+	}
+	return risks
+}
+
+// Inverse returns a TableDiff which reverses td, transforming td.To back into
+// td.From. The inverse of a CREATE TABLE is a DROP TABLE and vice versa. The
+// inverse of an ALTER TABLE is computed by diffing the two tables in the
+// opposite direction, so for example an AddColumn is reversed by a DropColumn
+// using the original column definition, and a PartitionBy is reversed by a
+// RemovePartitioning or by re-partitioning back to the original definition.
+// The inverse is nil if td is nil, or if reversing it requires no changes.
+//
+// Since the inverse is computed from the full tables, an error is returned if
+// td's clauses (as rendered with mods) do not cover the entire difference
+// between td.From and td.To, for example a partial diff obtained via Subset,
+// SplitClauses, or PartitionAlters. Otherwise, the inverse would revert changes
+// which td never applied.
+//
+// Reversing a diff only restores the table definition, not any data destroyed
+// by td. Summaries of any unsafe parts of td (with respect to mods) are
+// returned as nonInvertible, so that callers can flag them instead of treating
+// the inverse as a complete rollback.
+func (td *TableDiff) Inverse(mods StatementModifiers) (inverse *TableDiff, nonInvertible []ClauseSummary, err error) {
+	if td == nil {
+		return nil, nil, nil
+	}
+	for _, summary := range td.ClauseSummaries(mods) {
+		// RenameTable is considered unsafe for deployment reasons, but loses no data
+		if summary.Unsafe && summary.Type != "RenameTable" {
+			nonInvertible = append(nonInvertible, summary)
+		}
+	}
+	switch td.Type {
+	case DiffTypeCreate:
+		return NewDropTable(td.To), nonInvertible, nil
+	case DiffTypeDrop:
+		return NewCreateTable(td.From), nonInvertible, nil
+	}
+	if td.From.Name != td.To.Name {
+		return NewRenameTable(td.To, td.From.Name), nonInvertible, nil
+	}
+	if td.supported {
+		emitted := make(map[string]bool, len(td.alterClauses))
+		for _, clause := range td.alterClauses {
+			emitted[clause.Clause(mods)] = true
+		}
+		for _, clause := range NewAlterTable(td.From, td.To).AlterClauses() {
+			if str := clause.Clause(mods); str != "" && !emitted[str] {
+				return nil, nil, fmt.Errorf("cannot invert partial diff of table %s: clause %q is not part of the diff", EscapeIdentifier(td.From.Name), str)
+			}
+		}
+	}
+	return NewAlterTable(td.To, td.From), nonInvertible, nil
+}
+
+// Subset returns a new TableDiff consisting only of the receiver's ALTER TABLE
+// clauses for which keep returns true. The relative order of the kept clauses
+// is preserved. This permits applying a diff incrementally, for example adding
+// new columns and indexes first, and then running destructive clauses later.
+// An error is returned if the receiver is not a supported ALTER, if no clauses
+// are kept, or if the kept clauses would not be valid on their own: for
+// example, adding an index on a column whose ADD COLUMN was not kept, or
+// dropping a column which is still part of an index that isn't being dropped.
+func (td *TableDiff) Subset(keep func(TableAlterClause) bool) (*TableDiff, error) {
+	if td == nil || td.Type != DiffTypeAlter {
+		return nil, errors.New("cannot obtain subset of clauses: only supported for ALTER TABLE")
+	} else if !td.supported {
+		return nil, errors.New("cannot obtain subset of clauses: diff is not supported")
+	}
+	clauses := make([]TableAlterClause, 0, len(td.alterClauses))
+	for _, clause := range td.alterClauses {
+		if keep(clause) {
+			clauses = append(clauses, clause)
+		}
+	}
+	if len(clauses) == 0 {
+		return nil, errors.New("cannot obtain subset of clauses: no clauses were kept")
+	}
+
+	// Determine which columns and indexes will exist after the subset of clauses
+	// is run
+	colExists := make(map[string]bool, len(td.From.Columns))
+	for _, col := range td.From.Columns {
+		colExists[col.Name] = true
+	}
+	droppedIndexes := make(map[string]bool)
+	for _, clause := range clauses {
+		switch clause := clause.(type) {
+		case AddColumn:
+			colExists[clause.Column.Name] = true
+		case DropColumn:
+			delete(colExists, clause.Column.Name)
+		case DropIndex:
+			droppedIndexes[clause.Index.Name] = true
+		case ModifyIndex:
+			droppedIndexes[clause.FromIndex.Name] = true
+		}
+	}
+
+	indexColsExist := func(idx *Index) error {
+		for _, part := range idx.Parts {
+			if part.ColumnName != "" && !colExists[part.ColumnName] {
+				return fmt.Errorf("invalid subset of clauses: index %s depends on column %s, which would not exist", EscapeIdentifier(idx.Name), EscapeIdentifier(part.ColumnName))
+			}
+		}
+		return nil
+	}
+	for _, clause := range clauses {
+		var err error
+		switch clause := clause.(type) {
+		case AddColumn:
+			if clause.PositionAfter != nil && !colExists[clause.PositionAfter.Name] {
+				err = fmt.Errorf("invalid subset of clauses: column %s is positioned after column %s, which would not exist", EscapeIdentifier(clause.Column.Name), EscapeIdentifier(clause.PositionAfter.Name))
+			}
+		case ModifyColumn:
+			if clause.PositionAfter != nil && !colExists[clause.PositionAfter.Name] {
+				err = fmt.Errorf("invalid subset of clauses: column %s is positioned after column %s, which would not exist", EscapeIdentifier(clause.NewColumn.Name), EscapeIdentifier(clause.PositionAfter.Name))
+			}
+		case AddIndex:
+			err = indexColsExist(clause.Index)
+		case ModifyIndex:
+			err = indexColsExist(clause.ToIndex)
+		case AddForeignKey:
+			for _, colName := range clause.ForeignKey.ColumnNames {
+				if !colExists[colName] {
+					err = fmt.Errorf("invalid subset of clauses: foreign key %s depends on column %s, which would not exist", EscapeIdentifier(clause.ForeignKey.Name), EscapeIdentifier(colName))
+					break
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Dropping a column which is still part of an existing index would silently
+	// modify or drop that index, which is not permitted
+	existingIndexes := td.From.SecondaryIndexes
+	if td.From.PrimaryKey != nil {
+		existingIndexes = append([]*Index{td.From.PrimaryKey}, existingIndexes...)
+	}
+	for _, idx := range existingIndexes {
+		if !droppedIndexes[idx.Name] {
+			if err := indexColsExist(idx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &TableDiff{
+		Type:         DiffTypeAlter,
+		From:         td.From,
+		To:           td.To,
+		alterClauses: clauses,
+		supported:    true,
+	}, nil
+}
+
 // Statement returns the full DDL statement corresponding to the TableDiff. A
 // blank string may be returned if the mods indicate the statement should be
 // skipped. If the mods indicate the statement should be disallowed, it will
 // still be returned as-is, but the error will be non-nil. Be sure not to
 // ignore the error value of this method.
+// An ALTER TABLE which changes the structure of a MariaDB system-versioned
+// table is prefixed with SET STATEMENT system_versioning_alter_history=KEEP FOR,
+// since the server otherwise rejects it.
 func (td *TableDiff) Statement(mods StatementModifiers) (string, error) {
+	stmt, err := td.statement(mods)
+	if mods.QualifySchema != "" {
+		stmt = td.qualifyNames(stmt, mods.QualifySchema)
+	}
+	if mods.LowerCaseKeywords {
+		stmt = LowerCaseKeywords(stmt)
+	}
+	if mods.ANSIQuotes {
+		stmt = ANSIQuoteIdentifiers(stmt)
+	}
+	if stmt != "" && td.altersVersionedTable(mods) {
+		stmt = keepAlterHistoryPrefix + stmt
+	}
+	return stmt, err
+}
+
+// keepAlterHistoryPrefix is prepended to ALTER TABLE statements which modify
+// the structure of a MariaDB system-versioned table. Without this, MariaDB
+// rejects such ALTERs under the default system_versioning_alter_history=ERROR.
+const keepAlterHistoryPrefix = "SET STATEMENT system_versioning_alter_history=KEEP FOR "
+
+// altersVersionedTable returns true if td is an ALTER TABLE of a MariaDB
+// system-versioned table, consisting of at least one clause (as rendered with
+// mods) other than adding or dropping system versioning itself.
+func (td *TableDiff) altersVersionedTable(mods StatementModifiers) bool {
+	if td == nil || td.Type != DiffTypeAlter || !td.From.SystemVersioned {
+		return false
+	}
+	for _, clause := range td.alterClauses {
+		if _, ok := clause.(ChangeSystemVersioning); !ok && clause.Clause(mods) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (td *TableDiff) statement(mods StatementModifiers) (string, error) {
 	if td == nil {
 		return "", nil
 	}
@@ -255,27 +656,80 @@ func (td *TableDiff) Statement(mods StatementModifiers) (string, error) {
 // Clauses returns the body of the statement represented by the table diff.
 // For DROP statements, this will be an empty string. For CREATE statements,
 // it will be everything after "CREATE TABLE [name] ". For ALTER statements,
-// it will be everything after "ALTER TABLE [name] ".
+// it will be everything after "ALTER TABLE [name] ". This form is suitable for
+// passing verbatim to external online schema change tools such as gh-ost or
+// pt-online-schema-change. Note that any partitioning clause is not preceded by
+// a comma, and may be wrapped in a version-gated comment. The
+// system_versioning_alter_history prefix used for system-versioned tables (see
+// Statement) is not included.
 func (td *TableDiff) Clauses(mods StatementModifiers) (string, error) {
 	stmt, err := td.Statement(mods)
 	if stmt == "" {
 		return stmt, err
 	}
+	var prefix string
 	switch td.Type {
 	case DiffTypeCreate:
-		prefix := fmt.Sprintf("CREATE TABLE %s ", EscapeIdentifier(td.To.Name))
-		return strings.Replace(stmt, prefix, "", 1), err
+		prefix = fmt.Sprintf("CREATE TABLE %s ", EscapeIdentifier(td.To.Name))
 	case DiffTypeAlter:
-		prefix := fmt.Sprintf("%s ", td.From.AlterStatement())
-		return strings.Replace(stmt, prefix, "", 1), err
+		prefix = fmt.Sprintf("%s ", td.From.AlterStatement())
 	case DiffTypeDrop:
 		return "", err
 	default: // DiffTypeRename not supported yet
 		panic(fmt.Errorf("Unsupported diff type %d", td.Type))
 	}
+	if mods.QualifySchema != "" {
+		prefix = td.qualifyNames(prefix, mods.QualifySchema)
+	}
+	if mods.LowerCaseKeywords {
+		prefix = LowerCaseKeywords(prefix)
+	}
+	if mods.ANSIQuotes {
+		prefix = ANSIQuoteIdentifiers(prefix)
+	}
+	stmt = strings.TrimPrefix(stmt, keepAlterHistoryPrefix)
+	return strings.Replace(stmt, prefix, "", 1), err
+}
+
+// qualifyNames returns a copy of stmt, which must have been generated by
+// td.statement, in which the table name is qualified with the supplied schema
+// name. Any foreign key references to tables in the same schema are qualified
+// as well, since these would otherwise be resolved relative to the session's
+// default database.
+func (td *TableDiff) qualifyNames(stmt, schema string) string {
+	var verb string
+	table := td.From
+	switch td.Type {
+	case DiffTypeCreate:
+		verb, table = "CREATE TABLE ", td.To
+	case DiffTypeAlter:
+		verb = "ALTER TABLE "
+	case DiffTypeDrop:
+		verb = "DROP TABLE "
+	default: // DiffTypeRename not supported yet
+		panic(fmt.Errorf("Unsupported diff type %d", td.Type))
+	}
+	if !strings.HasPrefix(stmt, verb+EscapeIdentifier(table.Name)) {
+		return stmt
+	}
+	stmt = verb + EscapeQualifiedIdentifier(schema, table.Name) + strings.TrimPrefix(stmt, verb+EscapeIdentifier(table.Name))
+	if td.Type == DiffTypeDrop {
+		return stmt
+	}
+	for _, fk := range td.To.ForeignKeys {
+		if fk.ReferencedSchemaName == "" {
+			ref := " REFERENCES " + EscapeIdentifier(fk.ReferencedTableName) + " ("
+			stmt = strings.ReplaceAll(stmt, ref, " REFERENCES "+EscapeQualifiedIdentifier(schema, fk.ReferencedTableName)+" (")
+		}
+	}
+	return stmt
 }
 
 func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
+	if mods.ExistsGuards == ExistsGuardsRequire && !mods.Flavor.IsMariaDB() {
+		return "", fmt.Errorf("IF [NOT] EXISTS guards in ALTER TABLE are only supported in MariaDB, but flavor is %s", mods.Flavor)
+	}
+
 	// Force StrictIndexOrder to be enabled for InnoDB tables that have no primary
 	// key and at least one unique index with non-nullable columns
 	if !mods.StrictIndexOrder && td.To.Engine == "InnoDB" && td.To.ClusteredIndexKey() != td.To.PrimaryKey {
@@ -286,12 +740,13 @@ func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 	var unsafeReasons []string
 	var partitionClauseString string
 	var changingComment bool
+	var storageChangeCols []string // generated cols changing between VIRTUAL and STORED, which MySQL does not permit
+	var addingForeignKey bool
+	var emittedClauses []TableAlterClause
 	for _, clause := range td.alterClauses {
 		if !mods.AllowUnsafe {
-			if clause, ok := clause.(Unsafer); ok {
-				if unsafe, reason := clause.Unsafe(mods); unsafe {
-					unsafeReasons = append(unsafeReasons, reason)
-				}
+			if unsafe, reason := clause.Unsafe(mods); unsafe {
+				unsafeReasons = append(unsafeReasons, reason)
 			}
 		}
 		if clauseString := clause.Clause(mods); clauseString != "" {
@@ -301,16 +756,24 @@ func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 				// TABLE, and oddly *without* a preceeding comma
 				partitionClauseString = clauseString
 				continue // do NOT append to clauseStrings
-			case ModifyPartitions:
+			case ModifyPartitions, ExchangePartition, AddPartitions, DropPartitions, ReorganizePartitions:
 				// Other partitioning-related clauses cannot appear alongside any other
 				// clauses, including ALGORITHM or LOCK clauses
 				mods.LockClause = ""
 				mods.AlgorithmClause = ""
+			case AddForeignKey:
+				// Track this for SkipFKValidation modifier
+				addingForeignKey = true
 			case ChangeComment:
 				// Track this for LaxComments modifier
 				changingComment = true
+			case ModifyColumn:
+				if mc := clause.(ModifyColumn); mc.OldColumn.generatedStorageChange(mc.NewColumn) && mods.Flavor.IsMySQL() {
+					storageChangeCols = append(storageChangeCols, EscapeIdentifier(mc.NewColumn.Name))
+				}
 			}
 			clauseStrings = append(clauseStrings, clauseString)
+			emittedClauses = append(emittedClauses, clause)
 		}
 	}
 
@@ -361,6 +824,31 @@ func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 		}
 	}
 
+	if len(storageChangeCols) > 0 && td.supported {
+		err = &UnsupportedDiffError{
+			Reason:         "MySQL does not permit changing a generated column between VIRTUAL and STORED, affecting " + strings.Join(storageChangeCols, ", ") + ". The column must be dropped and re-added instead.",
+			ExpectedCreate: td.From.CreateStatement,
+			ExpectedDesc:   "original state actual SHOW CREATE",
+			ActualCreate:   td.To.CreateStatement,
+			ActualDesc:     "desired state actual SHOW CREATE",
+			WrappedErr:     err,
+		}
+	}
+
+	// Neither MySQL nor MariaDB offer clause-level syntax for adding a foreign
+	// key without validating existing rows. This can only be done by executing
+	// the ALTER in a session with foreign_key_checks=0.
+	if addingForeignKey && mods.SkipFKValidation {
+		return "", &UnsupportedDiffError{
+			Reason:         "Neither MySQL nor MariaDB support ALTER TABLE syntax for adding a foreign key without validating existing rows. Instead, execute the ALTER TABLE in a session with foreign_key_checks=0.",
+			ExpectedCreate: td.From.CreateStatement,
+			ExpectedDesc:   "original state actual SHOW CREATE",
+			ActualCreate:   td.To.CreateStatement,
+			ActualDesc:     "desired state actual SHOW CREATE",
+			WrappedErr:     err,
+		}
+	}
+
 	if len(clauseStrings) == 0 && partitionClauseString == "" {
 		return "", err
 	}
@@ -371,7 +859,7 @@ func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 		return "", err
 	}
 
-	if mods.LockClause != "" {
+	if mods.LockClause != "" && (!mods.LaxLockClause || lockClauseSupported(emittedClauses, mods)) {
 		lockClause := fmt.Sprintf("LOCK=%s", strings.ToUpper(mods.LockClause))
 		clauseStrings = append([]string{lockClause}, clauseStrings...)
 	}
@@ -401,6 +889,113 @@ func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 	return td.From.AlterStatement() + " " + strings.Join(clauseStrings, ", ") + spacer + partitionClauseString, err
 }
 
+// lockClauseSupported returns false if mods.Flavor is known to reject an ALTER
+// TABLE consisting of the supplied clauses when combined with the LOCK level in
+// mods.LockClause, taking mods.AlgorithmClause into account as well. In this
+// situation, the DDL would fail at runtime, so the LOCK clause is omitted if
+// mods.LaxLockClause is enabled. If mods.Flavor is not known, this always
+// returns true.
+func lockClauseSupported(clauses []TableAlterClause, mods StatementModifiers) bool {
+	if !mods.Flavor.Known() {
+		return true
+	} else if mods.Flavor.IsMySQL() && !mods.Flavor.MinMySQL(5, 6) {
+		return false // LOCK clause introduced in MySQL 5.6
+	}
+	lock, algorithm := strings.ToLower(mods.LockClause), strings.ToLower(mods.AlgorithmClause)
+	if algorithm == "instant" && lock != "default" && mods.Flavor.IsMySQL() {
+		return false // MySQL only permits LOCK=DEFAULT with ALGORITHM=INSTANT
+	} else if lock != "none" {
+		return true
+	}
+
+	// MariaDB 11.2+ supports LOCK=NONE with the COPY algorithm, which the server
+	// may also fall back to if no algorithm was requested. Otherwise, LOCK=NONE
+	// is not permitted for operations that must copy the table, nor for adding
+	// FULLTEXT or SPATIAL indexes.
+	copyOK := mods.Flavor.MinMariaDB(11, 2) && (algorithm == "" || algorithm == "default" || algorithm == "copy")
+	if algorithm == "copy" {
+		return copyOK
+	}
+	var addingPK bool
+	for _, clause := range clauses {
+		if ai, ok := clause.(AddIndex); ok && ai.Index.PrimaryKey {
+			addingPK = true
+		}
+	}
+	for _, clause := range clauses {
+		if !copyOK && (clauseRequiresCopy(clause, addingPK) || clauseAddsSharedLockIndex(clause)) {
+			return false
+		}
+	}
+	return true
+}
+
+// clauseRequiresCopy returns true if clause can only be performed using the
+// COPY algorithm, in all flavors. addingPK indicates whether the same ALTER
+// TABLE also adds a primary key; dropping a primary key without replacing it
+// requires a copy.
+func clauseRequiresCopy(clause TableAlterClause, addingPK bool) bool {
+	switch clause := clause.(type) {
+	case ChangeStorageEngine, PartitionBy, RemovePartitioning:
+		return true
+	case DropIndex:
+		return clause.Index.PrimaryKey && !addingPK
+	case ChangeTablespace:
+		return clause.RebuildsTable()
+	case ModifyColumn:
+		if clause.RebuildsTable() || !charsetsEquivalent(clause.OldColumn.CharSet, clause.NewColumn.CharSet) {
+			return true
+		}
+		return clause.OldColumn.Type.Base != clause.NewColumn.Type.Base
+	}
+	return false
+}
+
+// clauseAddsSharedLockIndex returns true if clause adds a FULLTEXT or SPATIAL
+// index, which requires at least a shared lock.
+func clauseAddsSharedLockIndex(clause TableAlterClause) bool {
+	var idx *Index
+	switch clause := clause.(type) {
+	case AddIndex:
+		idx = clause.Index
+	case ModifyIndex:
+		idx = clause.ToIndex
+	}
+	return idx != nil && (idx.Type == "FULLTEXT" || idx.Type == "SPATIAL")
+}
+
+// UnsupportedReasons returns a slice of human-readable reasons explaining why
+// the TableDiff is not fully supported. If the TableDiff is supported, the
+// result is nil. The returned reasons are in a stable order, so that callers
+// can display them (or make assertions on them) without needing to resort.
+func (td *TableDiff) UnsupportedReasons() (reasons []string) {
+	if td == nil || td.supported {
+		return nil
+	}
+	if td.Type == DiffTypeAlter {
+		if td.From.UnsupportedDDL {
+			reasons = append(reasons, "original state (\"from\" side of diff) contains unexpected or unsupported clauses in SHOW CREATE TABLE")
+		}
+		if td.To.UnsupportedDDL {
+			reasons = append(reasons, "desired state (\"to\" side of diff) contains unexpected or unsupported clauses in SHOW CREATE TABLE")
+		}
+		_, shrinkProblems := orderIndexRebuildsForColumnShrink(td.From, td.alterClauses)
+		reasons = append(reasons, shrinkProblems...)
+		if fromPeriod, toPeriod := td.From.ApplicationPeriod, td.To.ApplicationPeriod; fromPeriod != nil && toPeriod != nil && !fromPeriod.Equals(toPeriod) {
+			reasons = append(reasons, fmt.Sprintf("modifying application-time period %s in-place is not supported", EscapeIdentifier(fromPeriod.Name)))
+		}
+		if _, versioningProblem := compareSystemVersioning(td.From, td.To); versioningProblem != "" {
+			reasons = append(reasons, versioningProblem)
+		}
+		fromPartitioning, toPartitioning := td.From.Partitioning.withTableEngine(td.From.Engine), td.To.Partitioning.withTableEngine(td.To.Engine)
+		reasons = append(reasons, fromPartitioning.UnsupportedReasons(toPartitioning)...)
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "generation of the necessary DDL to convert the original table definition to the desired state is not supported")
+	}
+	return reasons
+}
+
 // MarkSupported provides a mechanism for callers to vouch for the correctness
 // of a TableDiff that was automatically marked as unsupported. This should only
 // be used in cases where a table with UnsupportedDDL is being altered in a way
@@ -445,8 +1040,20 @@ func diffTables(from, to *Table) (clauses []TableAlterClause, supported bool) {
 
 	// Check for default charset or collation changes first, prior to looking at
 	// column adds, to ensure the default change affects any new columns that don't
-	// explicitly override the table default
-	if from.CharSet != to.CharSet || from.Collation != to.Collation {
+	// explicitly override the table default. Existing columns which inherit the
+	// table default are handled by column modification logic below, since each
+	// Column's CharSet and Collation always reflect its effective values.
+	// If every text column inherits the default on both sides, a ConvertCharSet
+	// is used instead, which can optionally be expressed as CONVERT TO.
+	convertCharSet := canConvertCharSet(from, to)
+	if convertCharSet {
+		clauses = append(clauses, ConvertCharSet{
+			FromCharSet:   from.CharSet,
+			FromCollation: from.Collation,
+			ToCharSet:     to.CharSet,
+			ToCollation:   to.Collation,
+		})
+	} else if from.CharSet != to.CharSet || from.Collation != to.Collation {
 		clauses = append(clauses, ChangeCharSet{
 			FromCharSet:   from.CharSet,
 			FromCollation: from.Collation,
@@ -459,9 +1066,30 @@ func diffTables(from, to *Table) (clauses []TableAlterClause, supported bool) {
 	// so that column reordering works properly.
 	cc := compareColumnExistence(from, to)
 	clauses = append(clauses, cc.columnDrops()...)
-	clauses = append(clauses, cc.columnModifications()...)
+	for _, clause := range cc.columnModifications() {
+		if mc, ok := clause.(ModifyColumn); ok && convertCharSet {
+			mc.convertedCharSet = mc.OldColumn.charSetOnlyChange(mc.NewColumn)
+			clause = mc
+		}
+		clauses = append(clauses, clause)
+	}
 	clauses = append(clauses, cc.columnAdds()...)
 
+	// Compare MariaDB application-time periods. A new period is added prior to
+	// index changes, since a new WITHOUT OVERLAPS index may refer to it; a dropped
+	// period is handled after index changes, for the opposite reason. Modifying
+	// an existing period in-place is not supported yet.
+	var dropPeriod *ApplicationPeriod
+	if from.ApplicationPeriod != nil && to.ApplicationPeriod != nil {
+		if !from.ApplicationPeriod.Equals(to.ApplicationPeriod) {
+			supported = false
+		}
+	} else if from.ApplicationPeriod != nil {
+		dropPeriod = from.ApplicationPeriod
+	} else if to.ApplicationPeriod != nil {
+		clauses = append(clauses, AddApplicationPeriod{Period: to.ApplicationPeriod})
+	}
+
 	// Compare PK
 	if !from.PrimaryKey.Equals(to.PrimaryKey) {
 		if from.PrimaryKey != nil {
@@ -475,6 +1103,17 @@ func diffTables(from, to *Table) (clauses []TableAlterClause, supported bool) {
 	// Compare secondary indexes
 	clauses = append(clauses, compareSecondaryIndexes(from, to)...)
 
+	// If any column is being shortened below the prefix length of an index on it,
+	// ensure the index is being rebuilt, and move the rebuild ahead of the column
+	// modification
+	var shrinkProblems []string
+	if clauses, shrinkProblems = orderIndexRebuildsForColumnShrink(from, clauses); len(shrinkProblems) > 0 {
+		supported = false
+	}
+	if dropPeriod != nil {
+		clauses = append(clauses, DropApplicationPeriod{Period: dropPeriod})
+	}
+
 	// Compare foreign keys. If only the name of an FK changes, we consider this
 	// difference to be cosmetic, and suppress it at clause generation time unless
 	// requested. (This is important for pt-osc support, since it renames FKs due
@@ -589,26 +1228,51 @@ func diffTables(from, to *Table) (clauses []TableAlterClause, supported bool) {
 		cco := ChangeCreateOptions{
 			OldCreateOptions: from.CreateOptions,
 			NewCreateOptions: to.CreateOptions,
+			innoDB:           from.Engine == "InnoDB" && to.Engine == "InnoDB",
 		}
 		clauses = append(clauses, cco)
 	}
 
+	// Compare MERGE table options
+	if to.IsMerge() && !from.MergeOptions.Equals(to.MergeOptions) {
+		clauses = append(clauses, ChangeMergeOptions{
+			OldMergeOptions: from.MergeOptions,
+			NewMergeOptions: to.MergeOptions,
+		})
+	}
+
 	// Compare comment
 	if from.Comment != to.Comment {
 		clauses = append(clauses, ChangeComment{NewComment: to.Comment})
 	}
 
+	// Compare MySQL 8.0.21+ engine attributes. A clause is generated even if the
+	// values only differ cosmetically, but it emits no DDL in that situation.
+	if from.EngineAttribute != to.EngineAttribute {
+		clauses = append(clauses, ChangeEngineAttribute{OldValue: from.EngineAttribute, NewValue: to.EngineAttribute})
+	}
+	if from.SecondaryEngineAttribute != to.SecondaryEngineAttribute {
+		clauses = append(clauses, ChangeEngineAttribute{Secondary: true, OldValue: from.SecondaryEngineAttribute, NewValue: to.SecondaryEngineAttribute})
+	}
+
 	// Compare tablespace
 	if from.Tablespace != to.Tablespace {
 		clauses = append(clauses, ChangeTablespace{NewTablespace: to.Tablespace})
 	}
 
+	// Compare MariaDB system versioning
+	versioningClauses, versioningProblem := compareSystemVersioning(from, to)
+	clauses = append(clauses, versioningClauses...)
+	if versioningProblem != "" {
+		supported = false
+	}
+
 	// Compare partitioning. This must be performed last due to a MySQL requirement
 	// of PARTITION BY / REMOVE PARTITIONING occurring last in a multi-clause ALTER
 	// TABLE.
 	// Note that some partitioning differences aren't supported yet, and others are
 	// intentionally ignored.
-	partClauses, partSupported := from.Partitioning.Diff(to.Partitioning)
+	partClauses, partSupported := from.Partitioning.withTableEngine(from.Engine).Diff(to.Partitioning.withTableEngine(to.Engine))
 	clauses = append(clauses, partClauses...)
 	if !partSupported {
 		supported = false
@@ -626,6 +1290,110 @@ func diffTables(from, to *Table) (clauses []TableAlterClause, supported bool) {
 	return
 }
 
+// compareSystemVersioning returns clauses for adding or removing MariaDB system
+// versioning. Explicit period columns are only supported when adding system
+// versioning along with brand new period columns; removing versioning from a
+// table with explicit period columns, or modifying the explicit period columns
+// of an already-versioned table, is not supported yet. In these cases, a
+// non-empty problem is returned, describing the unsupported change.
+func compareSystemVersioning(from, to *Table) (clauses []TableAlterClause, problem string) {
+	fromStart, fromEnd := from.SystemTimePeriod()
+	toStart, toEnd := to.SystemTimePeriod()
+	if from.SystemVersioned && to.SystemVersioned {
+		if (fromStart == nil) != (toStart == nil) || (fromEnd == nil) != (toEnd == nil) {
+			return nil, "adding or removing explicit row start/end columns of a system-versioned table is not supported"
+		} else if fromStart != nil && (*fromStart != *toStart || *fromEnd != *toEnd) {
+			return nil, fmt.Sprintf("modifying explicit row start/end columns %s and %s of a system-versioned table is not supported", EscapeIdentifier(fromStart.Name), EscapeIdentifier(fromEnd.Name))
+		}
+		return nil, ""
+	} else if from.SystemVersioned {
+		if fromStart != nil || fromEnd != nil {
+			return nil, "removing system versioning from a table with explicit row start/end columns is not supported"
+		}
+		return []TableAlterClause{ChangeSystemVersioning{Enabled: false}}, ""
+	} else if !to.SystemVersioned {
+		return nil, ""
+	}
+	if toStart != nil || toEnd != nil {
+		fromCols := from.ColumnsByName()
+		if toStart == nil || toEnd == nil || fromCols[toStart.Name] != nil || fromCols[toEnd.Name] != nil {
+			return nil, "adding system versioning using pre-existing or incomplete explicit row start/end columns is not supported"
+		}
+		clauses = append(clauses, AddSystemTimePeriod{RowStart: toStart.Name, RowEnd: toEnd.Name})
+	}
+	return append(clauses, ChangeSystemVersioning{Enabled: true}), ""
+}
+
+// orderIndexRebuildsForColumnShrink examines ModifyColumn clauses which shorten
+// a column below the prefix length of an existing index on that column. Any
+// DropIndex or ModifyIndex clause for such an index is moved to appear before
+// the first ModifyColumn clause, so that the DDL reads in a valid logical order.
+// If an affected index is not being dropped or modified at all, the desired
+// state has an invalid prefix length, and a problem describing each such index
+// is returned.
+func orderIndexRebuildsForColumnShrink(from *Table, clauses []TableAlterClause) (result []TableAlterClause, problems []string) {
+	newSize := make(map[string]uint16)
+	firstModify := -1
+	for n, clause := range clauses {
+		if mc, isModify := clause.(ModifyColumn); isModify {
+			if firstModify < 0 {
+				firstModify = n
+			}
+			if mc.NewColumn.Type.Size > 0 && mc.NewColumn.Type.Size < mc.OldColumn.Type.Size {
+				newSize[mc.OldColumn.Name] = mc.NewColumn.Type.Size
+			}
+		}
+	}
+	if len(newSize) == 0 {
+		return clauses, nil
+	}
+
+	// Find the existing indexes which have a prefix that won't be valid anymore,
+	// tracking the first such column of each
+	affected := make(map[*Index]string)
+	indexes := from.SecondaryIndexes
+	if from.PrimaryKey != nil {
+		indexes = append([]*Index{from.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		for _, part := range idx.Parts {
+			if size, shrunk := newSize[part.ColumnName]; shrunk && part.PrefixLength > size && affected[idx] == "" {
+				affected[idx] = part.ColumnName
+			}
+		}
+	}
+	if len(affected) == 0 {
+		return clauses, nil
+	}
+
+	// Move the clauses rebuilding affected indexes ahead of the first ModifyColumn
+	var moved, others []TableAlterClause
+	for n, clause := range clauses {
+		var idx *Index
+		switch clause := clause.(type) {
+		case DropIndex:
+			idx = clause.Index
+		case ModifyIndex:
+			idx = clause.FromIndex
+		}
+		if idx != nil && affected[idx] != "" {
+			delete(affected, idx)
+			if n > firstModify {
+				moved = append(moved, clause)
+				continue
+			}
+		}
+		others = append(others, clause)
+	}
+	result = slices.Concat(others[:firstModify], moved, others[firstModify:])
+	for _, idx := range indexes {
+		if colName := affected[idx]; colName != "" {
+			problems = append(problems, fmt.Sprintf("shrinking column %s below the prefix length of index %s is not supported unless the index is also modified", EscapeIdentifier(colName), EscapeIdentifier(idx.Name)))
+		}
+	}
+	return result, problems
+}
+
 // Secondary indexes may be added, dropped, renamed, or have visibility changes.
 // Although relative order of indexes is usually irrelevant, we still support
 // dropping/re-adding indexes to result in a desired ordering, requiring extra
@@ -709,6 +1477,42 @@ func compareSecondaryIndexes(from, to *Table) (clauses []TableAlterClause) {
 	return clauses
 }
 
+// canConvertCharSet returns true if the table's default character set or
+// collation is changing, and a CONVERT TO CHARACTER SET clause would yield the
+// desired column definitions. This requires at least one text column, and all
+// text columns on both sides must use their table's default character set and
+// collation. Additionally, if the new character set uses more bytes per
+// character, the conversion would change TINYTEXT, TEXT, and MEDIUMTEXT
+// columns to a larger type, so those are not permitted in this situation.
+func canConvertCharSet(from, to *Table) bool {
+	if collationsEquivalent(from.Collation, to.Collation) {
+		return false
+	}
+	inheritsDefault := func(t *Table, col *Column) bool {
+		return col.CharSet == "" || (charsetsEquivalent(col.CharSet, t.CharSet) && collationsEquivalent(col.Collation, t.Collation))
+	}
+	for _, col := range from.Columns {
+		if !inheritsDefault(from, col) {
+			return false
+		}
+	}
+	widening := characterMaxBytes(canonicalCharSet(to.CharSet)) > characterMaxBytes(canonicalCharSet(from.CharSet))
+	var hasTextColumn bool
+	for _, col := range to.Columns {
+		if !inheritsDefault(to, col) {
+			return false
+		}
+		switch col.Type.Base {
+		case "tinytext", "text", "mediumtext":
+			if widening {
+				return false
+			}
+		}
+		hasTextColumn = hasTextColumn || col.CharSet != ""
+	}
+	return hasTextColumn
+}
+
 func compareColumnExistence(self, other *Table) columnsComparison {
 	cc := columnsComparison{
 		fromTable:           self,
@@ -805,7 +1609,8 @@ func (cc *columnsComparison) columnModifications() []TableAlterClause {
 	} else if !cc.commonColumnsMoved {
 		// If all common cols are at same position, efficient comparison is simpler
 		for toPos, toCol := range cc.toOrderCommonCols {
-			if fromCol := cc.fromOrderCommonCols[toPos]; !fromCol.Equals(toCol) {
+			fromCol, toCol := cc.withInheritedCharSets(cc.fromOrderCommonCols[toPos], toCol)
+			if !fromCol.Equals(toCol) {
 				clauses = append(clauses, ModifyColumn{
 					OldColumn:          fromCol,
 					NewColumn:          toCol,
@@ -835,7 +1640,7 @@ func (cc *columnsComparison) columnModifications() []TableAlterClause {
 	// For each common column (relative to the "to" order), emit a MODIFY COLUMN
 	// clause if the col was reordered or modified.
 	for toPos, toCol := range cc.toOrderCommonCols {
-		fromCol := cc.fromColumnsByName[toCol.Name]
+		fromCol, toCol := cc.withInheritedCharSets(cc.fromColumnsByName[toCol.Name], toCol)
 		if moved := !stayPut[toPos]; moved || !fromCol.Equals(toCol) {
 			modify := ModifyColumn{
 				OldColumn:          fromCol,
@@ -852,6 +1657,25 @@ func (cc *columnsComparison) columnModifications() []TableAlterClause {
 	return clauses
 }
 
+// withInheritedCharSets returns fromCol and toCol, substituting a copy of
+// either one if it lacks a charset and collation while the other column has
+// them. The copy uses its table's default charset and collation, so that a
+// column which implicitly inherits the table default compares equal to one
+// which explicitly specifies the same charset and collation. Columns of
+// non-textual types are never substituted.
+func (cc *columnsComparison) withInheritedCharSets(fromCol, toCol *Column) (*Column, *Column) {
+	if fromCol.CharSet == "" && fromCol.Collation == "" && toCol.CharSet != "" && fromCol.Type.hasCharSet() {
+		colCopy := *fromCol
+		colCopy.CharSet, colCopy.Collation = cc.fromTable.CharSet, cc.fromTable.Collation
+		fromCol = &colCopy
+	} else if toCol.CharSet == "" && toCol.Collation == "" && fromCol.CharSet != "" && toCol.Type.hasCharSet() {
+		colCopy := *toCol
+		colCopy.CharSet, colCopy.Collation = cc.toTable.CharSet, cc.toTable.Collation
+		toCol = &colCopy
+	}
+	return fromCol, toCol
+}
+
 // colInUniqueConstraint returns true if the old and new versions of the column
 // are in at least one unique constraint that existed in both old and new
 // versions of the table. This information is useful for determining if a
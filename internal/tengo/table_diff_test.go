@@ -1,7 +1,9 @@
 package tengo
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -140,6 +142,9 @@ func TestSchemaDiffAlterTable(t *testing.T) {
 	assertAutoIncAlter(4, 1, NextAutoIncIgnore, false)
 	assertAutoIncAlter(1, 4, NextAutoIncIfIncreased, true)
 	assertAutoIncAlter(4, 1, NextAutoIncIfIncreased, false)
+	assertAutoIncAlter(1000, 1001, NextAutoIncIfIncreased, true)
+	assertAutoIncAlter(1001, 1000, NextAutoIncIfIncreased, false) // InnoDB cannot lower next auto-inc below existing values
+	assertAutoIncAlter(1001, 1000, NextAutoIncIgnore, false)
 	assertAutoIncAlter(1, 4, NextAutoIncIfAlready, false)
 	assertAutoIncAlter(2, 4, NextAutoIncIfAlready, true)
 	assertAutoIncAlter(4, 2, NextAutoIncIfAlready, true)
@@ -350,6 +355,32 @@ func TestSchemaDiffForeignKeys(t *testing.T) {
 	}
 }
 
+func TestAlterTableStatementSkipFKValidation(t *testing.T) {
+	from, to := foreignKeyTable(), foreignKeyTable()
+	from.ForeignKeys = from.ForeignKeys[1:]
+	from.CreateStatement = from.GeneratedCreateStatement(FlavorUnknown)
+	addFK := NewAlterTable(&from, &to)
+	dropFK := NewAlterTable(&to, &from)
+
+	// Default behavior is unchanged
+	mods := StatementModifiers{AllowUnsafe: true}
+	if stmt, err := addFK.Statement(mods); err != nil || !strings.Contains(stmt, "ADD CONSTRAINT") {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+
+	// No flavor supports non-validating ADD FOREIGN KEY syntax, so this should
+	// be refused, while other clauses remain unaffected
+	for _, flavor := range []Flavor{ParseFlavor("mysql:8.0"), ParseFlavor("mariadb:11.4")} {
+		mods := StatementModifiers{AllowUnsafe: true, SkipFKValidation: true, Flavor: flavor}
+		if stmt, err := addFK.Statement(mods); stmt != "" || !IsUnsupportedDiff(err) || !strings.Contains(err.Error(), "foreign_key_checks=0") {
+			t.Errorf("Unexpected return from Statement in %s: %q, %v", flavor, stmt, err)
+		}
+		if stmt, err := dropFK.Statement(mods); err != nil || !strings.Contains(stmt, "DROP FOREIGN KEY") {
+			t.Errorf("Unexpected return from Statement in %s: %q, %v", flavor, stmt, err)
+		}
+	}
+}
+
 func TestSchemaDiffMultiFulltext(t *testing.T) {
 	t1 := aTable(0)
 	t2 := aTable(0)
@@ -409,6 +440,58 @@ func TestSchemaDiffMultiFulltext(t *testing.T) {
 	}
 }
 
+func TestTableDiffFullTextParser(t *testing.T) {
+	from := aTable(1)
+	from.SecondaryIndexes = append(from.SecondaryIndexes, &Index{
+		Name:           "ft_last",
+		Parts:          []IndexPart{{ColumnName: "last_name"}},
+		Type:           "FULLTEXT",
+		FullTextParser: "ngram",
+	})
+	from.CreateStatement = from.GeneratedCreateStatement(FlavorUnknown)
+	to := aTable(1)
+	ftIdx := *from.SecondaryIndexes[len(from.SecondaryIndexes)-1]
+	ftIdx.FullTextParser = ""
+	to.SecondaryIndexes = append(to.SecondaryIndexes, &ftIdx)
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+
+	// Changing ngram to the default parser requires a drop and re-add
+	clauses, supported := from.Diff(&to)
+	if !supported || len(clauses) != 1 {
+		t.Fatalf("Unexpected return from Diff: %d clauses, supported=%t", len(clauses), supported)
+	}
+	mi, ok := clauses[0].(ModifyIndex)
+	if !ok {
+		t.Fatalf("Expected clause to be ModifyIndex, instead found %T", clauses[0])
+	} else if !mi.ParserChanged() {
+		t.Error("Expected ParserChanged() to return true, but it returned false")
+	}
+	expected := "DROP KEY `ft_last`, ADD FULLTEXT KEY `ft_last` (`last_name`)"
+	if actual := mi.Clause(StatementModifiers{Flavor: ParseFlavor("mysql:8.0")}); actual != expected {
+		t.Errorf("Unexpected clause: expected %q, found %q", expected, actual)
+	}
+
+	// A parser change conflicts with adding another FULLTEXT index in the same
+	// ALTER, so SplitConflicts must separate them
+	ftFirst := &Index{
+		Name:  "ft_first",
+		Parts: []IndexPart{{ColumnName: "first_name"}},
+		Type:  "FULLTEXT",
+	}
+	to.SecondaryIndexes = append(to.SecondaryIndexes, ftFirst)
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	if tds := td.SplitConflicts(); len(tds) != 2 {
+		t.Errorf("Expected SplitConflicts to return 2 TableDiffs, instead found %d", len(tds))
+	}
+
+	// Other index modifications must not be classified as parser changes
+	mi = ModifyIndex{FromIndex: to.SecondaryIndexes[0], ToIndex: to.SecondaryIndexes[1]}
+	if mi.ParserChanged() {
+		t.Error("Expected ParserChanged() to return false for non-FULLTEXT indexes, but it returned true")
+	}
+}
+
 func TestTableDiffUnsupportedAlter(t *testing.T) {
 	t1 := supportedTable()
 	t2 := unsupportedTable()
@@ -463,6 +546,11 @@ func TestTableDiffUnsupportedAlter(t *testing.T) {
 		t.Errorf("Output of Error() did not match expectation. Returned value:\n%s", actual)
 	}
 
+	expectedReasons := []string{`original state ("from" side of diff) contains unexpected or unsupported clauses in SHOW CREATE TABLE`}
+	if reasons := td.UnsupportedReasons(); !slices.Equal(reasons, expectedReasons) {
+		t.Errorf("Unexpected return from UnsupportedReasons: expected %q, found %q", expectedReasons, reasons)
+	}
+
 	// Test error-handling for when a diff is both unsupported AND unsafe
 	t2.Columns = append(t2.Columns, &Column{
 		Name: "foo_id",
@@ -490,12 +578,65 @@ func TestTableDiffUnsupportedAlter(t *testing.T) {
 	if td.MarkSupported() == nil {
 		t.Error("Expected repeated call to MarkSupported to return an error, but error was nil")
 	}
+	if reasons := td.UnsupportedReasons(); reasons != nil {
+		t.Errorf("Expected nil reasons for supported diff, instead found %q", reasons)
+	}
 	td = NewAlterTable(&t2, &t2)
 	if td.MarkSupported() == nil {
 		t.Error("Expected error return from MarkSupported on an empty diff, but error was nil")
 	}
 }
 
+func TestTableDiffSplitClauses(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	to.Columns = append(to.Columns, &Column{
+		Name:     "age",
+		Type:     ParseColumnType("int unsigned"),
+		Nullable: true,
+		Default:  "NULL",
+	})
+	to.SecondaryIndexes = []*Index{
+		to.SecondaryIndexes[0],
+		{Name: "idx_actor_name", Parts: []IndexPart{{ColumnName: "last_name"}}, Type: "BTREE"},
+	}
+	to.Comment = "hello world"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+
+	mods := StatementModifiers{LockClause: "none", AlgorithmClause: "inplace"}
+	expected := []string{
+		"ALTER TABLE `actor` ALGORITHM=INPLACE, LOCK=NONE, ADD COLUMN `age` int unsigned DEFAULT NULL",
+		"ALTER TABLE `actor` ALGORITHM=INPLACE, LOCK=NONE, DROP KEY `idx_actor_name`, ADD KEY `idx_actor_name` (`last_name`), COMMENT 'hello world'",
+	}
+	tds := td.SplitClauses()
+	if len(tds) != len(expected) {
+		t.Fatalf("Expected SplitClauses to return %d TableDiffs, instead found %d", len(expected), len(tds))
+	}
+	for n, splitTD := range tds {
+		if stmt, err := splitTD.Statement(mods); stmt != expected[n] || err != nil {
+			t.Errorf("Unexpected return from Statement on split TableDiff %d:\nexpected %s\nfound    %s (err=%v)", n, expected[n], stmt, err)
+		}
+	}
+
+	// With LaxComments, the comment change is still emitted alongside the clause
+	// preceding it
+	mods.LaxComments = true
+	if stmt, _ := tds[1].Statement(mods); stmt != expected[1] {
+		t.Errorf("Unexpected return from Statement with LaxComments: %s", stmt)
+	}
+
+	// Non-ALTERs and single-clause ALTERs are returned as-is
+	for _, td := range []*TableDiff{NewCreateTable(&to), NewDropTable(&to), NewRenameTable(&to, "actor2")} {
+		if tds := td.SplitClauses(); len(tds) != 1 || tds[0] != td {
+			t.Errorf("Unexpected return from SplitClauses: %+v", tds)
+		}
+	}
+	var nilDiff *TableDiff
+	if tds := nilDiff.SplitClauses(); tds != nil {
+		t.Errorf("Expected nil result from SplitClauses on nil TableDiff, instead found %+v", tds)
+	}
+}
+
 func TestTableDiffClauses(t *testing.T) {
 	mods := StatementModifiers{
 		AllowUnsafe: true,
@@ -522,6 +663,420 @@ func TestTableDiffClauses(t *testing.T) {
 	if err != nil || clauses != "" {
 		t.Errorf("Unexpected result for Clauses on drop table: err=%v, output=%s", err, clauses)
 	}
+
+	// Clauses is used for passing the ALTER body verbatim to external OSC tools,
+	// so confirm multi-clause alters and partitioning clauses render correctly
+	// with all statement modifiers that affect the statement prefix
+	flavor := ParseFlavor("mysql:8.0")
+	unpart, part := unpartitionedTable(flavor), partitionedTable(flavor)
+	part.Columns = append(part.Columns, &Column{Name: "note", Type: ParseColumnType("varchar(20)"), Nullable: true, Default: "NULL"})
+	part.Comment = "hello world"
+	part.CreateStatement = part.GeneratedCreateStatement(flavor)
+	mods.Flavor = flavor
+	expected := "ADD COLUMN `note` varchar(20) DEFAULT NULL, COMMENT 'hello world' /*!50100 PARTITION BY RANGE (`customer_id`)\n"
+	if clauses, err := NewAlterTable(&unpart, &part).Clauses(mods); err != nil || !strings.HasPrefix(clauses, expected) || !strings.HasSuffix(clauses, " */") {
+		t.Errorf("Unexpected result for Clauses on multi-clause alter with partitioning: err=%v, output=%s", err, clauses)
+	}
+	variations := []StatementModifiers{
+		{Flavor: flavor, AllowUnsafe: true, QualifySchema: "foo"},
+		{Flavor: flavor, AllowUnsafe: true, LowerCaseKeywords: true},
+		{Flavor: flavor, AllowUnsafe: true, ANSIQuotes: true},
+	}
+	for _, mods := range variations {
+		if clauses, err := NewAlterTable(&part, &unpart).Clauses(mods); err != nil || !strings.HasPrefix(strings.ToUpper(clauses), "DROP COLUMN ") || !strings.HasSuffix(strings.ToUpper(clauses), " REMOVE PARTITIONING") {
+			t.Errorf("Unexpected result for Clauses on multi-clause alter removing partitioning with %+v: err=%v, output=%s", mods, err, clauses)
+		}
+	}
+	partOnly := partitionedTable(flavor)
+	mods = StatementModifiers{Flavor: flavor, Partitioning: PartitioningRemove}
+	if clauses, err := NewAlterTable(&partOnly, &unpart).Clauses(mods); err != nil || clauses != "REMOVE PARTITIONING" {
+		t.Errorf("Unexpected result for Clauses on partitioning-only alter: err=%v, output=%s", err, clauses)
+	}
+}
+
+func TestTableDiffSubset(t *testing.T) {
+	from := aTable(1)
+	to := aTable(1)
+	to.Columns = append(to.Columns[0:4], to.Columns[5:]...) // drop col ssn
+	to.SecondaryIndexes = to.SecondaryIndexes[1:]           // drop idx_ssn
+	nickname := &Column{
+		Name:     "nickname",
+		Nullable: true,
+		Type:     ParseColumnType("varchar(30)"),
+		Default:  "NULL",
+	}
+	to.Columns = append(to.Columns, nickname)
+	to.SecondaryIndexes = append(to.SecondaryIndexes, &Index{
+		Name:  "idx_nickname",
+		Parts: []IndexPart{{ColumnName: "nickname"}},
+		Type:  "BTREE",
+	})
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	if clauses := td.AlterClauses(); len(clauses) != 4 {
+		t.Fatalf("Expected 4 clauses, instead found %d", len(clauses))
+	}
+
+	isAdditive := func(clause TableAlterClause) bool {
+		switch clause.(type) {
+		case AddColumn, AddIndex:
+			return true
+		}
+		return false
+	}
+	additive, err := td.Subset(isAdditive)
+	if err != nil {
+		t.Fatalf("Unexpected error from Subset: %v", err)
+	}
+	expected := "ALTER TABLE `actor` ADD COLUMN `nickname` varchar(30) DEFAULT NULL, ADD KEY `idx_nickname` (`nickname`)"
+	if stmt, err := additive.Statement(StatementModifiers{}); stmt != expected || err != nil {
+		t.Errorf("Unexpected return from Statement(): %q / %v", stmt, err)
+	}
+	destructive, err := td.Subset(func(clause TableAlterClause) bool { return !isAdditive(clause) })
+	if err != nil {
+		t.Fatalf("Unexpected error from Subset: %v", err)
+	}
+	expected = "ALTER TABLE `actor` DROP COLUMN `ssn`, DROP KEY `idx_ssn`"
+	if stmt, _ := destructive.Statement(StatementModifiers{AllowUnsafe: true}); stmt != expected {
+		t.Errorf("Unexpected return from Statement(): %q", stmt)
+	}
+	if len(td.AlterClauses()) != 4 {
+		t.Error("Subset unexpectedly modified the original TableDiff")
+	}
+
+	// Invalid subsets should return errors
+	invalid := map[string]func(TableAlterClause) bool{
+		"no clauses":               func(TableAlterClause) bool { return false },
+		"index without its column": func(clause TableAlterClause) bool { _, ok := clause.(AddIndex); return ok },
+		"drop indexed column": func(clause TableAlterClause) bool {
+			_, ok := clause.(DropColumn)
+			return ok || isAdditive(clause)
+		},
+	}
+	for desc, keep := range invalid {
+		if _, err := td.Subset(keep); err == nil {
+			t.Errorf("Expected Subset to return an error for %s, but it did not", desc)
+		}
+	}
+	if _, err := NewCreateTable(&to).Subset(isAdditive); err == nil {
+		t.Error("Expected Subset to return an error for a CREATE TABLE, but it did not")
+	}
+}
+
+func TestNewRenameTable(t *testing.T) {
+	products := aTable(1)
+	products.Name = "products"
+	products.CreateStatement = products.GeneratedCreateStatement(FlavorUnknown)
+	fkTable := foreignKeyTable()
+	schema := aSchema("s1", &products, &fkTable)
+
+	td := NewRenameTable(&products, "items")
+	if td.ObjectKey().Name != "products" || td.To.Name != "items" {
+		t.Errorf("Unexpected names in TableDiff: ObjectKey=%s, To.Name=%s", td.ObjectKey(), td.To.Name)
+	}
+	if td.To.CreateStatement != td.To.GeneratedCreateStatement(FlavorUnknown) {
+		t.Errorf("Unexpected To.CreateStatement: %s", td.To.CreateStatement)
+	}
+	if products.Name != "products" {
+		t.Error("NewRenameTable unexpectedly modified the original table")
+	}
+	expected := "ALTER TABLE `products` RENAME TO `items`"
+	if stmt, err := td.Statement(StatementModifiers{}); stmt != expected || !IsUnsafeDiff(err) {
+		t.Errorf("Unexpected return from Statement(): %q / %v", stmt, err)
+	}
+	if stmt, err := td.Statement(StatementModifiers{AllowUnsafe: true}); stmt != expected || err != nil {
+		t.Errorf("Unexpected return from Statement(): %q / %v", stmt, err)
+	}
+
+	warnings := schema.RenameTableWarnings("products", "items")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "`product_fk`") || !strings.Contains(warnings[0], "`warranties`") {
+		t.Errorf("Unexpected return from RenameTableWarnings: %v", warnings)
+	}
+	// customer_fk references a table in a different schema
+	if warnings := schema.RenameTableWarnings("customers", "clients"); len(warnings) != 0 {
+		t.Errorf("Unexpected return from RenameTableWarnings: %v", warnings)
+	}
+	schema.Name = "purchasing"
+	if warnings := schema.RenameTableWarnings("customers", "clients"); len(warnings) != 1 {
+		t.Errorf("Unexpected return from RenameTableWarnings: %v", warnings)
+	}
+}
+
+func TestTableDiffColumnShrinkBelowPrefix(t *testing.T) {
+	from := aTable(1)
+	to := aTable(1)
+	lastName := *to.Columns[2]
+	lastName.Type = ParseColumnType("varchar(5)")
+	to.Columns[2] = &lastName
+	if to.SecondaryIndexes[1].Parts[0].ColumnName != "last_name" || to.SecondaryIndexes[1].Parts[0].PrefixLength != 10 {
+		t.Fatal("Test fixture has changed without corresponding update to this test's logic")
+	}
+
+	// Desired state retains an index prefix longer than the column: unsupported
+	to.CreateStatement = ""
+	if _, supported := from.Diff(&to); supported {
+		t.Error("Expected diff with invalid index prefix to be unsupported, but it was supported")
+	}
+	expectedReasons := []string{"shrinking column `last_name` below the prefix length of index `idx_actor_name` is not supported unless the index is also modified"}
+	if reasons := NewAlterTable(&from, &to).UnsupportedReasons(); !slices.Equal(reasons, expectedReasons) {
+		t.Errorf("Unexpected return from UnsupportedReasons: %q", reasons)
+	}
+
+	// Desired state indexes the full column: index rebuild must precede the
+	// column modification
+	idx := *to.SecondaryIndexes[1]
+	idx.Parts = []IndexPart{{ColumnName: "last_name"}, idx.Parts[1]}
+	to.SecondaryIndexes = []*Index{to.SecondaryIndexes[0], &idx}
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	clauses, supported := from.Diff(&to)
+	if !supported || len(clauses) != 2 {
+		t.Fatalf("Unexpected return from Diff: %d clauses, supported=%t", len(clauses), supported)
+	}
+	if _, ok := clauses[0].(ModifyIndex); !ok {
+		t.Errorf("Expected first clause to be ModifyIndex, instead found %T", clauses[0])
+	}
+	if _, ok := clauses[1].(ModifyColumn); !ok {
+		t.Errorf("Expected second clause to be ModifyColumn, instead found %T", clauses[1])
+	}
+	stmt, _ := NewAlterTable(&from, &to).Statement(StatementModifiers{AllowUnsafe: true})
+	if dropPos, modifyPos := strings.Index(stmt, "DROP KEY"), strings.Index(stmt, "MODIFY COLUMN"); dropPos < 0 || dropPos > modifyPos {
+		t.Errorf("Unexpected statement: %s", stmt)
+	}
+}
+
+func TestTableDiffClauseSummaries(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	to.Columns = append(to.Columns, &Column{
+		Name:     "age",
+		Type:     ParseColumnType("int unsigned"),
+		Nullable: true,
+		Default:  "NULL",
+	})
+	to.SecondaryIndexes = to.SecondaryIndexes[0:1]
+	to.Comment = "hello world"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	actual := td.ClauseSummaries(StatementModifiers{})
+	expected := []ClauseSummary{
+		{Type: "AddColumn", Name: "age", SQL: "ADD COLUMN `age` int unsigned DEFAULT NULL"},
+		{Type: "DropIndex", Name: "idx_actor_name", SQL: "DROP KEY `idx_actor_name`"},
+		{Type: "ChangeComment", SQL: "COMMENT 'hello world'"},
+	}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Unexpected result from ClauseSummaries:\nexpected %+v\nfound    %+v", expected, actual)
+	}
+	if b, err := json.Marshal(actual[0]); err != nil {
+		t.Errorf("Unexpected error from json.Marshal: %v", err)
+	} else if expectJSON := `{"type":"AddColumn","name":"age","unsafe":false,"sql":"ADD COLUMN ` + "`age`" + ` int unsigned DEFAULT NULL"}`; string(b) != expectJSON {
+		t.Errorf("Unexpected JSON: expected %s, found %s", expectJSON, b)
+	}
+
+	// Confirm unsafe classification for dropping a column
+	td = NewAlterTable(&to, &from)
+	actual = td.ClauseSummaries(StatementModifiers{})
+	if actual[0].Type != "DropColumn" || actual[0].Name != "age" || !actual[0].Unsafe || actual[0].UnsafeReason == "" {
+		t.Errorf("Unexpected summary for dropping column: %+v", actual[0])
+	}
+
+	// Confirm summaries for CREATE and DROP
+	if actual := NewCreateTable(&to).ClauseSummaries(StatementModifiers{}); len(actual) != 1 || actual[0].Type != "CreateTable" || actual[0].SQL != to.CreateStatement || actual[0].Unsafe {
+		t.Errorf("Unexpected summaries for CREATE: %+v", actual)
+	}
+	if actual := NewDropTable(&to).ClauseSummaries(StatementModifiers{AllowUnsafe: true}); len(actual) != 1 || actual[0].Type != "DropTable" || actual[0].Name != to.Name || !actual[0].Unsafe {
+		t.Errorf("Unexpected summaries for DROP: %+v", actual)
+	}
+	var nilDiff *TableDiff
+	if actual := nilDiff.ClauseSummaries(StatementModifiers{}); actual != nil {
+		t.Errorf("Expected nil summaries for nil TableDiff, instead found %+v", actual)
+	}
+}
+
+func TestTableAlterClauseAffects(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	to.Columns = to.Columns[0 : len(to.Columns)-1]
+	dropped := from.Columns[len(from.Columns)-1]
+	to.SecondaryIndexes = append(to.SecondaryIndexes, &Index{
+		Name: "idx_name_update",
+		Parts: []IndexPart{
+			{ColumnName: "first_name"},
+			{Expression: "(`last_name` || `first_name`)"},
+			{ColumnName: "last_update"},
+		},
+		Type: "BTREE",
+	})
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	var foundAdd, foundDrop bool
+	for _, clause := range td.alterClauses {
+		affected := clause.Affects()
+		switch clause := clause.(type) {
+		case AddIndex:
+			foundAdd = true
+			if !slices.Equal(affected.Indexes, []string{"idx_name_update"}) || !slices.Equal(affected.Columns, []string{"first_name", "last_update"}) || affected.Constraints != nil {
+				t.Errorf("Unexpected result from Affects for %s: %+v", clause.Clause(StatementModifiers{}), affected)
+			}
+		case DropColumn:
+			foundDrop = true
+			if !slices.Equal(affected.Columns, []string{dropped.Name}) || affected.Indexes != nil || affected.Constraints != nil {
+				t.Errorf("Unexpected result from Affects for %s: %+v", clause.Clause(StatementModifiers{}), affected)
+			}
+		}
+	}
+	if !foundAdd || !foundDrop {
+		t.Fatalf("Test setup problem: expected AddIndex and DropColumn clauses, instead found %+v", td.alterClauses)
+	}
+
+	// Table-level clauses don't affect any specific column, index, or constraint
+	if affected := (ChangeComment{NewComment: "hello world"}).Affects(); affected.Columns != nil || affected.Indexes != nil || affected.Constraints != nil {
+		t.Errorf("Unexpected result from Affects for ChangeComment: %+v", affected)
+	}
+}
+
+func TestTableDiffInverse(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	to.Columns = append(to.Columns, &Column{
+		Name:     "age",
+		Type:     ParseColumnType("int unsigned"),
+		Nullable: true,
+		Default:  "NULL",
+	})
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	mods := StatementModifiers{}
+
+	// Adding a column is reversed by dropping it; this is invertible since the
+	// original diff is safe, but the inverse itself is not
+	td := NewAlterTable(&from, &to)
+	inverse, nonInvertible, err := td.Inverse(mods)
+	if err != nil {
+		t.Fatalf("Unexpected error from Inverse: %v", err)
+	} else if len(nonInvertible) != 0 {
+		t.Errorf("Expected no non-invertible clauses, instead found %+v", nonInvertible)
+	}
+	clauses := inverse.AlterClauses()
+	if len(clauses) != 1 {
+		t.Fatalf("Expected inverse to have 1 clause, instead found %d", len(clauses))
+	} else if dc, ok := clauses[0].(DropColumn); !ok || dc.Column.Name != "age" || inverse.From != &to || inverse.To != &from {
+		t.Errorf("Unexpected inverse: %+v", clauses[0])
+	}
+
+	// Inverting the inverse restores the column definition, but the dropped data
+	// is lost
+	inverse2, nonInvertible, _ := inverse.Inverse(mods)
+	if len(nonInvertible) != 1 || nonInvertible[0].Type != "DropColumn" || nonInvertible[0].Name != "age" {
+		t.Errorf("Unexpected non-invertible clauses: %+v", nonInvertible)
+	}
+	if stmt, err := inverse2.Statement(mods); err != nil || stmt != "ALTER TABLE `actor` ADD COLUMN `age` int unsigned DEFAULT NULL" {
+		t.Errorf("Unexpected inverse statement: %q / %v", stmt, err)
+	}
+
+	// Partitioning a table is reversed by removing partitioning, and vice versa
+	partitioned := partitionedTable(FlavorUnknown)
+	unpartitioned := partitionedTable(FlavorUnknown)
+	unpartitioned.Partitioning = nil
+	unpartitioned.CreateStatement = unpartitioned.GeneratedCreateStatement(FlavorUnknown)
+	td = NewAlterTable(&unpartitioned, &partitioned)
+	inverse, _, _ = td.Inverse(mods)
+	if clauses := inverse.AlterClauses(); len(clauses) != 1 {
+		t.Errorf("Expected inverse to have 1 clause, instead found %+v", clauses)
+	} else if _, ok := clauses[0].(RemovePartitioning); !ok {
+		t.Errorf("Expected inverse to be RemovePartitioning, instead found %T", clauses[0])
+	}
+	inverse2, _, _ = inverse.Inverse(mods)
+	if clauses := inverse2.AlterClauses(); len(clauses) != 1 {
+		t.Errorf("Expected inverse to have 1 clause, instead found %+v", clauses)
+	} else if pb, ok := clauses[0].(PartitionBy); !ok || pb.RePartition || pb.Partitioning != partitioned.Partitioning {
+		t.Errorf("Expected inverse to be PartitionBy, instead found %T %+v", clauses[0], clauses[0])
+	}
+
+	// CREATE and DROP are reversed by each other, but DROP is non-invertible
+	inverse, nonInvertible, _ = NewCreateTable(&to).Inverse(mods)
+	if inverse.Type != DiffTypeDrop || inverse.From != &to || len(nonInvertible) != 0 {
+		t.Errorf("Unexpected inverse of CREATE: %+v / %+v", inverse, nonInvertible)
+	}
+	inverse, nonInvertible, _ = NewDropTable(&to).Inverse(mods)
+	if inverse.Type != DiffTypeCreate || inverse.To != &to || len(nonInvertible) != 1 || nonInvertible[0].Type != "DropTable" {
+		t.Errorf("Unexpected inverse of DROP: %+v / %+v", inverse, nonInvertible)
+	}
+
+	// Renames are reversed by renaming back
+	inverse, nonInvertible, _ = NewRenameTable(&from, "actor2").Inverse(mods)
+	if len(nonInvertible) != 0 {
+		t.Errorf("Expected rename to be invertible, instead found %+v", nonInvertible)
+	}
+	if stmt, err := inverse.Statement(StatementModifiers{AllowUnsafe: true}); err != nil || stmt != "ALTER TABLE `actor2` RENAME TO `actor`" {
+		t.Errorf("Unexpected inverse statement for rename: %q / %v", stmt, err)
+	}
+
+	var nilDiff *TableDiff
+	if inverse, nonInvertible, err := nilDiff.Inverse(mods); inverse != nil || nonInvertible != nil || err != nil {
+		t.Errorf("Expected nil inverse for nil TableDiff, instead found %+v / %+v / %v", inverse, nonInvertible, err)
+	}
+
+	// Partial diffs cannot be inverted, since the inverse would revert clauses
+	// which were never applied
+	to.Columns = append(to.Columns, &Column{
+		Name:     "height",
+		Type:     ParseColumnType("int unsigned"),
+		Nullable: true,
+		Default:  "NULL",
+	})
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	td = NewAlterTable(&from, &to)
+	if _, _, err := td.Inverse(mods); err != nil {
+		t.Errorf("Unexpected error inverting full diff: %v", err)
+	}
+	subset, err := td.Subset(func(clause TableAlterClause) bool {
+		ac, ok := clause.(AddColumn)
+		return ok && ac.Column.Name == "age"
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error from Subset: %v", err)
+	}
+	if inverse, _, err := subset.Inverse(mods); err == nil || inverse != nil {
+		t.Errorf("Expected error inverting subset, instead found %+v / %v", inverse, err)
+	}
+	for _, split := range td.SplitClauses() {
+		if _, _, err := split.Inverse(mods); err == nil {
+			t.Errorf("Expected error inverting split diff %+v, but err was nil", split.AlterClauses())
+		}
+	}
+}
+
+func TestTableDiffRedactSecrets(t *testing.T) {
+	const secret = "s3cr3t"
+	table := anotherTable()
+	table.Engine = "FEDERATED"
+	table.CreateStatement = table.GeneratedCreateStatement(FlavorUnknown) + " CONNECTION='mysql://app:" + secret + "@remote:3306/db/t'"
+	table.UnsupportedDDL = true
+	td := NewCreateTable(&table)
+
+	// Emitted SQL retains the real value
+	if stmt, err := td.Statement(StatementModifiers{}); err != nil || !strings.Contains(stmt, secret) {
+		t.Errorf("Expected statement to contain credentials, instead found %q (err=%v)", stmt, err)
+	}
+
+	// Summary and JSON output are redacted
+	summaries := td.ClauseSummaries(StatementModifiers{})
+	if len(summaries) != 1 || strings.Contains(summaries[0].SQL, secret) || !strings.Contains(summaries[0].SQL, "CONNECTION='<redacted>'") {
+		t.Errorf("Unexpected summaries: %+v", summaries)
+	}
+	if b, err := json.Marshal(summaries); err != nil || strings.Contains(string(b), secret) {
+		t.Errorf("Unexpected JSON output: %s (err=%v)", b, err)
+	}
+	if b, err := json.Marshal(&table); err != nil || strings.Contains(string(b), secret) || !strings.Contains(string(b), "redacted") {
+		t.Errorf("Unexpected table JSON output: %s (err=%v)", b, err)
+	}
+
+	// Error messages, which are logged, are redacted
+	other := table
+	other.Comment = "changed"
+	other.CreateStatement = strings.Replace(table.CreateStatement, " CONNECTION=", " COMMENT='changed' CONNECTION=", 1)
+	_, err := NewAlterTable(&table, &other).Statement(StatementModifiers{})
+	if !IsUnsupportedDiff(err) {
+		t.Fatalf("Expected unsupported diff error, instead found %v", err)
+	} else if msg := err.Error(); strings.Contains(msg, secret) || !strings.Contains(msg, "<redacted>") {
+		t.Errorf("Expected error message to be redacted, instead found: %s", msg)
+	}
 }
 
 func TestAlterTableStatementAllowUnsafeMods(t *testing.T) {
@@ -581,6 +1136,70 @@ func TestAlterTableStatementAllowUnsafeMods(t *testing.T) {
 	assertUnsafe(&s1, &s2)
 }
 
+func TestAlterTableStatementExistsGuards(t *testing.T) {
+	maria, mysql := ParseFlavor("mariadb:10.11"), ParseFlavor("mysql:8.0")
+	from, to := aTableForFlavor(maria, 1), aTableForFlavor(maria, 1)
+	age := &Column{Name: "age", Type: ParseColumnType("int unsigned"), Nullable: true, Default: "NULL"}
+	to.Columns = append(to.Columns[:len(to.Columns)-1], age)
+	to.Columns[3].Comment = "hello"
+	to.SecondaryIndexes = []*Index{
+		to.SecondaryIndexes[0],
+		{Name: "idx_age", Parts: []IndexPart{{ColumnName: "age"}}, Unique: true, Type: "BTREE"},
+	}
+	to.CreateStatement = to.GeneratedCreateStatement(maria)
+	td := NewAlterTable(&from, &to)
+
+	mods := StatementModifiers{AllowUnsafe: true, Flavor: maria, ExistsGuards: ExistsGuardsMariaDB}
+	expected := "ALTER TABLE `actor` DROP COLUMN IF EXISTS `alive_bit`, MODIFY COLUMN IF EXISTS " + to.Columns[3].Definition(maria) + ", ADD COLUMN IF NOT EXISTS `age` int unsigned DEFAULT NULL, DROP KEY IF EXISTS `idx_actor_name`, ADD UNIQUE KEY IF NOT EXISTS `idx_age` (`age`)"
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected result from Statement:\nexpected %s\nfound    %s (err=%v)", expected, stmt, err)
+	}
+	mods.ExistsGuards = ExistsGuardsRequire
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected result from Statement:\nexpected %s\nfound    %s (err=%v)", expected, stmt, err)
+	}
+
+	// Other flavors either ignore the guards, or return an error, depending on
+	// the mode
+	mods.Flavor = mysql
+	if stmt, err := td.Statement(mods); stmt != "" || err == nil {
+		t.Errorf("Expected error from Statement with ExistsGuardsRequire in %s, instead found %q, %v", mysql, stmt, err)
+	}
+	mods.ExistsGuards = ExistsGuardsMariaDB
+	if stmt, err := td.Statement(mods); strings.Contains(stmt, "EXISTS") || err != nil {
+		t.Errorf("Expected no guards in %s, instead found %q, %v", mysql, stmt, err)
+	}
+
+	// Primary keys cannot be guarded
+	if clause := (AddIndex{Index: to.PrimaryKey}).Clause(StatementModifiers{Flavor: maria, ExistsGuards: ExistsGuardsMariaDB}); strings.Contains(clause, "EXISTS") {
+		t.Errorf("Unexpected guard in clause %q", clause)
+	}
+
+	// Foreign key additions and removals
+	fk := foreignKeyTable().ForeignKeys[0]
+	fkmods := StatementModifiers{Flavor: maria, ExistsGuards: ExistsGuardsMariaDB}
+	if clause := (AddForeignKey{ForeignKey: fk}).Clause(fkmods); !strings.HasPrefix(clause, "ADD CONSTRAINT "+EscapeIdentifier(fk.Name)+" FOREIGN KEY IF NOT EXISTS (") {
+		t.Errorf("Unexpected clause for AddForeignKey: %q", clause)
+	}
+	if clause := (DropForeignKey{ForeignKey: fk}).Clause(fkmods); clause != "DROP FOREIGN KEY IF EXISTS "+EscapeIdentifier(fk.Name) {
+		t.Errorf("Unexpected clause for DropForeignKey: %q", clause)
+	}
+	fkmods.Flavor = mysql
+	if clause := (AddForeignKey{ForeignKey: fk}).Clause(fkmods); strings.Contains(clause, "EXISTS") {
+		t.Errorf("Unexpected guard in clause %q", clause)
+	}
+
+	// Partition list modifications
+	pmods := StatementModifiers{Flavor: maria, ExistsGuards: ExistsGuardsMariaDB}
+	parts := []*Partition{{Name: "p9", Values: "MAXVALUE", Engine: "InnoDB"}}
+	if clause := (AddPartitions{Method: "RANGE", Partitions: parts}).Clause(pmods); clause != "ADD PARTITION IF NOT EXISTS (PARTITION `p9` VALUES LESS THAN MAXVALUE ENGINE = InnoDB)" {
+		t.Errorf("Unexpected clause for AddPartitions: %q", clause)
+	}
+	if clause := (DropPartitions{Partitions: parts}).Clause(pmods); clause != "DROP PARTITION IF EXISTS `p9`" {
+		t.Errorf("Unexpected clause for DropPartitions: %q", clause)
+	}
+}
+
 func TestAlterTableStatementOnlineMods(t *testing.T) {
 	from := anotherTable()
 	to := anotherTable()
@@ -623,6 +1242,175 @@ func TestAlterTableStatementOnlineMods(t *testing.T) {
 	}
 }
 
+func TestAlterTableStatementIgnoreTableOptions(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	from.CreateOptions = "ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 STATS_PERSISTENT=1"
+	from.Comment = "owned by team A"
+	from.CreateStatement = from.GeneratedCreateStatement(FlavorUnknown)
+	to.CreateOptions = "ROW_FORMAT=DYNAMIC"
+	to.Comment = "owned by team B"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableDiff := NewAlterTable(&from, &to)
+
+	cases := []struct {
+		ignore   []string
+		expected string
+	}{
+		{nil, ""}, // not checked exactly, since create option order isn't predictable
+		{[]string{"ROW_FORMAT", "KEY_BLOCK_SIZE"}, "ALTER TABLE `actor` STATS_PERSISTENT=DEFAULT, COMMENT 'owned by team B'"},
+		{[]string{"row_format", "key_block_size", "stats_persistent"}, "ALTER TABLE `actor` COMMENT 'owned by team B'"},
+		{[]string{"ROW_FORMAT", "KEY_BLOCK_SIZE", "STATS_PERSISTENT", "COMMENT"}, ""},
+	}
+	for n, c := range cases {
+		mods := StatementModifiers{IgnoreTableOptions: c.ignore}
+		stmt, err := tableDiff.Statement(mods)
+		if err != nil {
+			t.Errorf("Unexpected error in case %d: %v", n, err)
+		} else if c.ignore == nil {
+			if !strings.Contains(stmt, "ROW_FORMAT=DYNAMIC") || !strings.Contains(stmt, "KEY_BLOCK_SIZE=0") || !strings.Contains(stmt, "COMMENT 'owned by team B'") {
+				t.Errorf("Unexpected statement in case %d: %q", n, stmt)
+			}
+		} else if stmt != c.expected {
+			t.Errorf("Unexpected statement in case %d: expected %q, found %q", n, c.expected, stmt)
+		}
+	}
+
+	// Ignoring an option must not suppress structural clauses, including ones
+	// that render a same-named attribute on a column or index
+	to.Columns[2].Comment = "surname"
+	to.SecondaryIndexes[1].Attributes = "KEY_BLOCK_SIZE=4"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableDiff = NewAlterTable(&from, &to)
+	mods := StatementModifiers{IgnoreTableOptions: []string{"ROW_FORMAT", "KEY_BLOCK_SIZE", "STATS_PERSISTENT", "COMMENT"}}
+	stmt, err := tableDiff.Statement(mods)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(stmt, "COMMENT 'surname'") || !strings.Contains(stmt, "KEY_BLOCK_SIZE=4") || strings.Contains(stmt, "team B") || strings.Contains(stmt, "ROW_FORMAT") {
+		t.Errorf("Unexpected statement: %q", stmt)
+	}
+}
+
+func TestTableDiffDefaultRowFormat(t *testing.T) {
+	getTable := func(createOptions string) *Table {
+		table := aTable(1)
+		table.CreateOptions = createOptions
+		table.CreateStatement = table.GeneratedCreateStatement(FlavorUnknown)
+		return &table
+	}
+	modern := StatementModifiers{Flavor: ParseFlavor("mysql:8.0")}
+	old := StatementModifiers{Flavor: ParseFlavor("mysql:5.6")}
+	configuredCompact := StatementModifiers{Flavor: ParseFlavor("mysql:8.0"), DefaultRowFormat: "COMPACT"}
+	cases := []struct {
+		from, to string
+		mods     StatementModifiers
+		expected string
+	}{
+		{"ROW_FORMAT=DYNAMIC", "", modern, ""},
+		{"", "ROW_FORMAT=DYNAMIC", modern, ""},
+		{"ROW_FORMAT=COMPACT", "", modern, "ROW_FORMAT=DEFAULT"},
+		{"", "ROW_FORMAT=COMPACT", modern, "ROW_FORMAT=COMPACT"},
+		{"ROW_FORMAT=COMPACT", "", old, ""},
+		{"", "ROW_FORMAT=DYNAMIC", old, "ROW_FORMAT=DYNAMIC"},
+		{"ROW_FORMAT=COMPACT", "ROW_FORMAT=DYNAMIC", modern, "ROW_FORMAT=DYNAMIC"},
+		{"ROW_FORMAT=DYNAMIC", "", StatementModifiers{}, "ROW_FORMAT=DEFAULT"},
+		{"ROW_FORMAT=DYNAMIC STATS_PERSISTENT=1", "STATS_PERSISTENT=1", modern, ""},
+		{"ROW_FORMAT=DYNAMIC", "", configuredCompact, "ROW_FORMAT=DEFAULT"},
+		{"", "ROW_FORMAT=DYNAMIC", configuredCompact, "ROW_FORMAT=DYNAMIC"},
+		{"ROW_FORMAT=COMPACT", "", configuredCompact, ""},
+	}
+	for _, c := range cases {
+		td := NewAlterTable(getTable(c.from), getTable(c.to))
+		expected := c.expected
+		if expected != "" {
+			expected = "ALTER TABLE `actor` " + expected
+		}
+		if stmt, err := td.Statement(c.mods); stmt != expected || err != nil {
+			t.Errorf("Diff from %q to %q in %s: expected %q, instead found %q (err=%v)", c.from, c.to, c.mods.Flavor, expected, stmt, err)
+		}
+	}
+
+	// Non-InnoDB tables are not affected
+	from, to := getTable("ROW_FORMAT=DYNAMIC"), getTable("")
+	from.Engine, to.Engine = "MyISAM", "MyISAM"
+	if stmt, _ := NewAlterTable(from, to).Statement(modern); stmt != "ALTER TABLE `actor` ROW_FORMAT=DEFAULT" {
+		t.Errorf("Unexpected statement for MyISAM table: %q", stmt)
+	}
+}
+
+func TestAlterTableStatementLockClauseSupport(t *testing.T) {
+	table := aTable(1)
+	col := table.Columns[2]
+	newCol := *col
+	newCol.Type = ParseColumnType("text")
+	ftIndex := &Index{Name: "ft", Parts: []IndexPart{{ColumnName: col.Name}}, Type: "FULLTEXT"}
+	mysql56, mysql55, mysql80 := ParseFlavor("mysql:5.6"), ParseFlavor("mysql:5.5"), ParseFlavor("mysql:8.0")
+	maria106, maria112 := ParseFlavor("mariadb:10.6"), ParseFlavor("mariadb:11.2")
+
+	cases := []struct {
+		clause    TableAlterClause
+		flavor    Flavor
+		lock      string
+		algorithm string
+		expected  bool
+	}{
+		{AddColumn{Column: &newCol}, FlavorUnknown, "none", "copy", true},
+		{AddColumn{Column: &newCol}, mysql80, "none", "", true},
+		{AddColumn{Column: &newCol}, mysql55, "none", "", false},
+		{AddColumn{Column: &newCol}, mysql56, "shared", "", true},
+		{AddColumn{Column: &newCol}, mysql80, "none", "copy", false},
+		{AddColumn{Column: &newCol}, maria112, "none", "copy", true},
+		{AddColumn{Column: &newCol}, mysql80, "shared", "instant", false},
+		{AddColumn{Column: &newCol}, mysql80, "default", "instant", true},
+		{AddColumn{Column: &newCol}, maria106, "none", "instant", true},
+		{ModifyColumn{OldColumn: col, NewColumn: &newCol}, mysql80, "none", "", false},
+		{ModifyColumn{OldColumn: col, NewColumn: &newCol}, mysql80, "shared", "", true},
+		{ModifyColumn{OldColumn: col, NewColumn: &newCol}, maria106, "none", "", false},
+		{ModifyColumn{OldColumn: col, NewColumn: &newCol}, maria112, "none", "", true},
+		{ModifyColumn{OldColumn: col, NewColumn: &newCol}, maria112, "none", "inplace", false},
+		{ChangeStorageEngine{NewStorageEngine: "MyISAM"}, mysql80, "none", "", false},
+		{ChangeTablespace{NewTablespace: "ts1"}, mysql80, "none", "", false},
+		{ChangeTablespace{NewTablespace: "ts1"}, mysql80, "shared", "", true},
+		{ChangeTablespace{}, mysql80, "none", "", true},
+		{DropIndex{Index: table.PrimaryKey}, mysql80, "none", "", false},
+		{DropIndex{Index: table.SecondaryIndexes[0]}, mysql80, "none", "", true},
+		{AddIndex{Index: ftIndex}, mysql80, "none", "", false},
+		{AddIndex{Index: ftIndex}, mysql80, "shared", "", true},
+	}
+	for n, c := range cases {
+		mods := StatementModifiers{Flavor: c.flavor, LockClause: c.lock, AlgorithmClause: c.algorithm}
+		if actual := lockClauseSupported([]TableAlterClause{c.clause}, mods); actual != c.expected {
+			t.Errorf("cases[%d]: Unexpected return from lockClauseSupported for %T with %s: expected %t, found %t", n, c.clause, c.flavor, c.expected, actual)
+		}
+	}
+
+	// Dropping the primary key is fine if a new one is added in the same ALTER
+	clauses := []TableAlterClause{DropIndex{Index: table.PrimaryKey}, AddIndex{Index: primaryKey(col)}}
+	if !lockClauseSupported(clauses, StatementModifiers{Flavor: mysql80, LockClause: "none"}) {
+		t.Error("Expected LOCK=NONE to be supported when replacing primary key, but it was not")
+	}
+
+	// Confirm the LOCK clause is retained by default even when unsupported, so
+	// that the ALTER fails instead of running with a different lock level; and
+	// confirm it is omitted when unsupported if LaxLockClause is enabled
+	from, to := aTable(1), aTable(1)
+	to.Columns[2] = &newCol
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	alter := NewAlterTable(&from, &to)
+	mods := StatementModifiers{Flavor: mysql80, LockClause: "none", AlgorithmClause: "copy", AllowUnsafe: true}
+	if stmt, err := alter.Statement(mods); err != nil || !strings.HasPrefix(stmt, "ALTER TABLE `actor` ALGORITHM=COPY, LOCK=NONE, MODIFY COLUMN") {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	mods.LaxLockClause = true
+	if stmt, err := alter.Statement(mods); err != nil || !strings.HasPrefix(stmt, "ALTER TABLE `actor` ALGORITHM=COPY, MODIFY COLUMN") {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	mods.LockClause = "shared"
+	if stmt, err := alter.Statement(mods); err != nil || !strings.HasPrefix(stmt, "ALTER TABLE `actor` ALGORITHM=COPY, LOCK=SHARED, MODIFY COLUMN") {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+}
+
 func TestAlterTableStatementVirtualColValidation(t *testing.T) {
 	from, to := aTable(1), aTable(1)
 
@@ -679,6 +1467,304 @@ func TestAlterTableStatementVirtualColValidation(t *testing.T) {
 	assertWithValidation(false)
 }
 
+func TestTableAlterGeneratedStorage(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	virtualCol := &Column{
+		Name:           "id_doubled",
+		Type:           ParseColumnType("int(11)"),
+		Nullable:       true,
+		GenerationExpr: "`actor_id` * 2",
+		Virtual:        true,
+	}
+	storedCol := *virtualCol
+	storedCol.Virtual = false
+	from.Columns = append(from.Columns, virtualCol)
+	to.Columns = append(to.Columns, &storedCol)
+	from.CreateStatement = from.GeneratedCreateStatement(FlavorUnknown)
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+
+	// Toggle in both directions
+	for _, pair := range [][2]*Table{{&from, &to}, {&to, &from}} {
+		tableDiff := NewAlterTable(pair[0], pair[1])
+		if len(tableDiff.alterClauses) != 1 {
+			t.Fatalf("Expected 1 clause, instead found %d", len(tableDiff.alterClauses))
+		}
+		mc, ok := tableDiff.alterClauses[0].(ModifyColumn)
+		if !ok || !mc.RebuildsTable() {
+			t.Fatalf("Expected ModifyColumn which rebuilds the table, instead found %+v", tableDiff.alterClauses[0])
+		}
+		if unsafe, reason := mc.Unsafe(StatementModifiers{}); unsafe {
+			t.Errorf("Expected storage kind change to be safe, but Unsafe returned %q", reason)
+		}
+		newKind := "STORED"
+		if mc.NewColumn.Virtual {
+			newKind = "VIRTUAL"
+		}
+		mods := StatementModifiers{Flavor: ParseFlavor("mariadb:10.11")}
+		if stmt, err := tableDiff.Statement(mods); err != nil || !strings.Contains(stmt, "MODIFY COLUMN `id_doubled` int(11) GENERATED ALWAYS AS (`actor_id` * 2) "+newKind) {
+			t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+		}
+
+		// MySQL does not permit this change via MODIFY COLUMN
+		mods.Flavor = ParseFlavor("mysql:8.0")
+		if _, err := tableDiff.Statement(mods); !IsUnsupportedDiff(err) {
+			t.Errorf("Expected unsupported diff error for MySQL, instead found %v", err)
+		}
+	}
+
+	// Other modifications to a generated column do not rebuild the table
+	storedCol.Virtual = true
+	storedCol.GenerationExpr = "`actor_id` * 3"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableDiff := NewAlterTable(&from, &to)
+	if mc := tableDiff.alterClauses[0].(ModifyColumn); mc.RebuildsTable() {
+		t.Error("Expected expression-only change not to rebuild the table, but RebuildsTable returned true")
+	}
+	if _, err := tableDiff.Statement(StatementModifiers{Flavor: ParseFlavor("mysql:8.0")}); err != nil {
+		t.Errorf("Unexpected error from Statement: %v", err)
+	}
+}
+
+func TestTableAlterSpatialReference(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	from.Columns = append(from.Columns, &Column{
+		Name: "location",
+		Type: ParseColumnType("point"),
+	})
+	sridCol := *from.Columns[len(from.Columns)-1]
+	sridCol.HasSpatialReference = true
+	sridCol.SpatialReferenceID = 4326
+	to.Columns = append(to.Columns, &sridCol)
+	flavor := ParseFlavor("mysql:8.0")
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+
+	cases := []struct {
+		tableDiff      *TableDiff
+		expectedClause string
+		expectedReason string
+	}{
+		{NewAlterTable(&from, &to), "MODIFY COLUMN `location` point NOT NULL /*!80003 SRID 4326 */", "adding SRID 4326 constraint to column location will fail if any existing values use a different SRID"},
+		{NewAlterTable(&to, &from), "MODIFY COLUMN `location` point NOT NULL", "removing SRID from column location prevents its use in a spatial index"},
+	}
+	for _, c := range cases {
+		if len(c.tableDiff.alterClauses) != 1 {
+			t.Fatalf("Expected 1 clause, instead found %d", len(c.tableDiff.alterClauses))
+		}
+		mc, ok := c.tableDiff.alterClauses[0].(ModifyColumn)
+		if !ok || !mc.RebuildsTable() {
+			t.Fatalf("Expected ModifyColumn which rebuilds the table, instead found %+v", c.tableDiff.alterClauses[0])
+		}
+		mods := StatementModifiers{Flavor: flavor, LockClause: "none", LaxLockClause: true}
+		if unsafe, reason := mc.Unsafe(mods); !unsafe || reason != c.expectedReason {
+			t.Errorf("Unexpected return from Unsafe: %t, %q", unsafe, reason)
+		}
+		stmt, err := c.tableDiff.Statement(mods)
+		if !IsUnsafeDiff(err) {
+			t.Errorf("Expected unsafe diff error, instead found %v", err)
+		}
+		// LOCK=NONE is omitted with LaxLockClause since the rebuild requires the
+		// COPY algorithm
+		if expected := "ALTER TABLE `actor` " + c.expectedClause; stmt != expected {
+			t.Errorf("Unexpected statement: expected %q, found %q", expected, stmt)
+		}
+		mods.AllowUnsafe = true
+		if _, err := c.tableDiff.Statement(mods); err != nil {
+			t.Errorf("Unexpected error with AllowUnsafe: %v", err)
+		}
+	}
+}
+
+func TestTableAlterCollationOnly(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0")
+	from, to := aTableForFlavor(flavor, 1), aTableForFlavor(flavor, 1)
+	from.Columns = append(from.Columns, &Column{
+		Name:        "nickname",
+		Type:        ParseColumnType("varchar(60)"),
+		Nullable:    true,
+		Default:     "NULL",
+		CharSet:     "utf8mb4",
+		Collation:   "utf8mb4_general_ci",
+		ShowCharSet: true,
+	})
+	newCol := *from.Columns[len(from.Columns)-1]
+	newCol.Collation = "utf8mb4_unicode_ci"
+	newCol.ShowCollation = true
+	to.Columns = append(to.Columns, &newCol)
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+
+	td := NewAlterTable(&from, &to)
+	if td == nil || len(td.alterClauses) != 1 {
+		t.Fatalf("Expected 1 clause, instead found %+v", td)
+	}
+	mc, ok := td.alterClauses[0].(ModifyColumn)
+	if !ok || !mc.RebuildsTable() {
+		t.Fatalf("Expected ModifyColumn which rebuilds the table, instead found %+v", td.alterClauses[0])
+	}
+
+	// Column is not in a unique index, so the collation change is safe. The
+	// CHARACTER SET clause is omitted since the server infers it from the
+	// collation. With LaxLockClause, LOCK=NONE is omitted since the rebuild
+	// requires a copy.
+	mods := StatementModifiers{Flavor: flavor, LockClause: "none", LaxLockClause: true}
+	if unsafe, reason := mc.Unsafe(mods); unsafe {
+		t.Errorf("Expected collation change on non-unique column to be safe, instead found unsafe with reason %q", reason)
+	}
+	expected := "ALTER TABLE `actor` MODIFY COLUMN `nickname` varchar(60) COLLATE utf8mb4_unicode_ci DEFAULT NULL"
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected return from Statement:\nexpected %s\nfound    %s (err=%v)", expected, stmt, err)
+	}
+
+	// Reverse direction is also detected
+	if td := NewAlterTable(&to, &from); td == nil || len(td.alterClauses) != 1 {
+		t.Errorf("Expected 1 clause in reverse direction, instead found %+v", td)
+	} else if mc := td.alterClauses[0].(ModifyColumn); !mc.RebuildsTable() || mc.NewColumn.Collation != "utf8mb4_general_ci" {
+		t.Errorf("Unexpected reverse clause: %+v", mc)
+	}
+}
+
+func TestTableAlterAddNotNullColumn(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	notNullCol := &Column{
+		Name:     "signup_date",
+		Type:     ParseColumnType("date"),
+		Nullable: false,
+	}
+	to.Columns = append(to.Columns, notNullCol)
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableDiff := NewAlterTable(&from, &to)
+
+	// By default, column is added as-is, but with a warning
+	var mods StatementModifiers
+	if stmt, err := tableDiff.Statement(mods); err != nil || !strings.Contains(stmt, "ADD COLUMN `signup_date` date NOT NULL") {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if warnings := tableDiff.Warnings(mods); len(warnings) != 1 || !strings.Contains(warnings[0], "consider adding it as nullable") {
+		t.Errorf("Unexpected return from Warnings: %v", warnings)
+	}
+
+	// With NullableAddColumns, the column is rewritten as nullable
+	mods.NullableAddColumns = true
+	if stmt, err := tableDiff.Statement(mods); err != nil || !strings.HasSuffix(stmt, "ADD COLUMN `signup_date` date") {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if warnings := tableDiff.Warnings(mods); len(warnings) != 1 || !strings.Contains(warnings[0], "added as nullable instead") {
+		t.Errorf("Unexpected return from Warnings: %v", warnings)
+	}
+	if notNullCol.Nullable {
+		t.Error("Clause unexpectedly modified the original Column")
+	}
+
+	// Columns with a default, or which are nullable, are not affected
+	for _, col := range []*Column{
+		{Name: "signup_date", Type: ParseColumnType("date"), Default: "'2000-01-01'"},
+		{Name: "signup_date", Type: ParseColumnType("date"), Nullable: true, Default: "NULL"},
+	} {
+		to.Columns[len(to.Columns)-1] = col
+		to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+		tableDiff = NewAlterTable(&from, &to)
+		if warnings := tableDiff.Warnings(mods); len(warnings) != 0 {
+			t.Errorf("Expected no warnings for column %s, instead found %v", col.Definition(FlavorUnknown), warnings)
+		}
+		if stmt, _ := tableDiff.Statement(mods); !strings.Contains(stmt, col.Definition(FlavorUnknown)) {
+			t.Errorf("Expected column %s to be added unchanged, instead found %q", col.Definition(FlavorUnknown), stmt)
+		}
+	}
+}
+
+func TestTableAlterIndexVisibilityOnly(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	to.SecondaryIndexes[1].Invisible = true
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableAlters, supported := from.Diff(&to)
+	if len(tableAlters) != 1 || !supported {
+		t.Fatalf("Incorrect number of table alters: expected 1, found %d", len(tableAlters))
+	}
+	mi, ok := tableAlters[0].(ModifyIndex)
+	if !ok {
+		t.Fatalf("Expected ModifyIndex, instead found %T", tableAlters[0])
+	} else if mi.FromIndex != from.SecondaryIndexes[1] || mi.ToIndex != to.SecondaryIndexes[1] {
+		t.Errorf("ModifyIndex paired the wrong indexes: %s to %s", mi.FromIndex.Name, mi.ToIndex.Name)
+	} else if !mi.VisibilityChanged() || mi.ParserChanged() {
+		t.Errorf("Unexpected results from ModifyIndex: VisibilityChanged=%t ParserChanged=%t", mi.VisibilityChanged(), mi.ParserChanged())
+	}
+
+	name := to.SecondaryIndexes[1].Name
+	cases := map[Flavor]string{
+		ParseFlavor("mysql:8.0"):    "ALTER INDEX `" + name + "` INVISIBLE",
+		ParseFlavor("mariadb:10.6"): "ALTER INDEX `" + name + "` IGNORED",
+		ParseFlavor("mysql:5.7"):    "",
+	}
+	for flavor, expected := range cases {
+		mods := StatementModifiers{Flavor: flavor}
+		if actual := mi.Clause(mods); actual != expected {
+			t.Errorf("Unexpected clause with flavor %s: expected %q, found %q", flavor, expected, actual)
+		}
+		mods.StrictIndexOrder = true
+		if actual := mi.Clause(mods); actual != expected {
+			t.Errorf("Unexpected clause with flavor %s and StrictIndexOrder: expected %q, found %q", flavor, expected, actual)
+		}
+	}
+
+	// Reverse direction should make the index visible again
+	tableAlters, _ = to.Diff(&from)
+	if len(tableAlters) != 1 {
+		t.Fatalf("Incorrect number of table alters: expected 1, found %d", len(tableAlters))
+	}
+	if actual, expected := tableAlters[0].Clause(StatementModifiers{Flavor: ParseFlavor("mysql:8.0")}), "ALTER INDEX `"+name+"` VISIBLE"; actual != expected {
+		t.Errorf("Expected %q, found %q", expected, actual)
+	}
+
+	// Changing anything else in the index still requires a drop and re-add
+	to.SecondaryIndexes[1].Unique = true
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableAlters, _ = from.Diff(&to)
+	if len(tableAlters) != 1 {
+		t.Fatalf("Incorrect number of table alters: expected 1, found %d", len(tableAlters))
+	}
+	if clause := tableAlters[0].Clause(StatementModifiers{Flavor: ParseFlavor("mysql:8.0")}); !strings.HasPrefix(clause, "DROP KEY ") {
+		t.Errorf("Unexpected clause: %s", clause)
+	}
+}
+
+func TestTableAlterClauseUnsafe(t *testing.T) {
+	table, other := partitionedTable(FlavorUnknown), aTable(1)
+	col, idx := other.Columns[0], other.SecondaryIndexes[0]
+	cases := []struct {
+		clause TableAlterClause
+		unsafe bool
+	}{
+		{AddColumn{Column: col}, false},
+		{DropColumn{Column: col}, true},
+		{AddIndex{Index: idx}, false},
+		{DropIndex{Index: idx}, false},
+		{ModifyIndex{FromIndex: idx, ToIndex: idx}, false},
+		{AlterIndex{Name: idx.Name, Invisible: true}, false},
+		{ChangeComment{NewComment: "hello"}, false},
+		{ChangeCharSet{FromCharSet: "latin1", FromCollation: "latin1_swedish_ci", ToCharSet: "utf8mb4", ToCollation: "utf8mb4_general_ci"}, false},
+		{ConvertCharSet{FromCharSet: "latin1", FromCollation: "latin1_swedish_ci", ToCharSet: "utf8mb4", ToCollation: "utf8mb4_general_ci"}, false},
+		{ChangeStorageEngine{NewStorageEngine: "MyISAM"}, true},
+		{RenameTable{NewName: "foo"}, true},
+		{PartitionBy{Partitioning: table.Partitioning}, false},
+		{PartitionBy{Partitioning: table.Partitioning, RePartition: true}, true},
+		{RemovePartitioning{}, false},
+		{ModifyPartitions{}, false},
+		{ModifyPartitions{Drop: table.Partitioning.Partitions[0:1]}, true},
+	}
+	for _, c := range cases {
+		if unsafe, reason := c.clause.Unsafe(StatementModifiers{}); unsafe != c.unsafe || (unsafe && reason == "") || (!unsafe && reason != "") {
+			t.Errorf("Unexpected return from %T.Unsafe(): %t, %q", c.clause, unsafe, reason)
+		}
+	}
+
+	// Re-partitioning is not unsafe if the clause is negated by mods
+	pb := PartitionBy{Partitioning: table.Partitioning, RePartition: true}
+	if unsafe, _ := pb.Unsafe(StatementModifiers{Partitioning: PartitioningKeep}); unsafe {
+		t.Error("Expected re-partitioning to be safe with PartitioningKeep, but it was not")
+	}
+}
+
 func TestModifyColumnUnsafe(t *testing.T) {
 	assertUnsafeWithMods := func(type1, type2 string, mods StatementModifiers, expected bool) {
 		t.Helper()
@@ -922,6 +2008,9 @@ func (s TengoIntegrationSuite) TestAlterPageCompression(t *testing.T) {
 		t.Fatal("Table with page compression is unexpectedly unsupported for diff")
 	}
 	compTable.Name = uncompTable.Name
+	if tpc := compTable.PageCompression(); (flavor.IsMariaDB() && !tpc.MariaDBEnabled) || (!flavor.IsMariaDB() && tpc.MySQLAlgorithm != "zlib") {
+		t.Errorf("Unexpected return from PageCompression(): %+v", tpc)
+	}
 
 	// Test diff generation for uncompressed -> compressed
 	clauses, supported := uncompTable.Diff(compTable)
@@ -948,6 +2037,134 @@ func (s TengoIntegrationSuite) TestAlterPageCompression(t *testing.T) {
 	}
 }
 
+func (s TengoIntegrationSuite) TestAlterMariaDBEncryption(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMariaDB(10, 2) {
+		t.Skipf("MariaDB-style table encryption not supported in flavor %s", flavor)
+	}
+	db, err := s.d.CachedConnectionPool("", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	var pluginCount int
+	query := "SELECT COUNT(*) FROM information_schema.plugins WHERE plugin_type = 'ENCRYPTION' AND plugin_status = 'ACTIVE'"
+	if err := db.QueryRow(query).Scan(&pluginCount); err != nil {
+		t.Fatalf("Unexpected error from query %q: %v", query, err)
+	} else if pluginCount == 0 {
+		t.Skip("No key management plugin is active in this image")
+	}
+
+	s.SourceTestSQL(t, "encryption-maria.sql")
+	schema := s.GetSchema(t, "testing")
+	plainTable := getTable(t, schema, "actor_in_film")
+	encTable := getTable(t, schema, "actor_in_film_enc")
+	if encTable.UnsupportedDDL {
+		t.Fatal("Table with encryption is unexpectedly unsupported for diff")
+	}
+	expected := TableEncryption{MariaDBEncrypted: "YES", MariaDBKeyID: 2}
+	if actual := encTable.Encryption(); actual != expected {
+		t.Errorf("Unexpected return from Encryption(): expected %+v, found %+v", expected, actual)
+	}
+	if actual := plainTable.Encryption(); actual != (TableEncryption{}) {
+		t.Errorf("Unexpected return from Encryption(): expected zero value, found %+v", actual)
+	}
+
+	// Test diff generation and execution for unencrypted -> encrypted
+	encTable.Name = plainTable.Name
+	clauses, supported := plainTable.Diff(encTable)
+	if len(clauses) != 1 || !supported {
+		t.Fatalf("Unexpected return from diff: %d clauses, supported=%t", len(clauses), supported)
+	}
+	query = fmt.Sprintf("ALTER TABLE %s %s", EscapeIdentifier(plainTable.Name), clauses[0].Clause(StatementModifiers{Flavor: flavor}))
+	pool, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	if _, err := pool.Exec(query); err != nil {
+		t.Fatalf("Unexpected error from query %q: %v", query, err)
+	}
+	schema = s.GetSchema(t, "testing")
+	if actual := getTable(t, schema, "actor_in_film").Encryption(); actual != expected {
+		t.Errorf("Unexpected encryption after ALTER: expected %+v, found %+v", expected, actual)
+	}
+
+	// Test rekey operation
+	rekeyed := getTable(t, schema, "actor_in_film")
+	rekeyed.CreateOptions = strings.Replace(rekeyed.CreateOptions, "=2", "=1", 1)
+	rekeyed.CreateStatement = ""
+	clauses, supported = getTable(t, schema, "actor_in_film").Diff(rekeyed)
+	if len(clauses) != 1 || !supported {
+		t.Fatalf("Unexpected return from diff: %d clauses, supported=%t", len(clauses), supported)
+	}
+	if clause := clauses[0].Clause(StatementModifiers{Flavor: flavor}); clause != "`ENCRYPTION_KEY_ID`=1" {
+		t.Errorf("Unexpected clause for rekey: %q", clause)
+	}
+}
+
+func (s TengoIntegrationSuite) TestAlterMySQLEncryption(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMySQL(8, 0, 16) {
+		t.Skipf("MySQL-style table encryption not tested in flavor %s", flavor)
+	}
+	db, err := s.d.CachedConnectionPool("", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	var keyringCount int
+	query := "SELECT COUNT(*) FROM performance_schema.keyring_component_status WHERE status_key = 'Component_status' AND status_value = 'Active'"
+	if err := db.QueryRow(query).Scan(&keyringCount); err != nil || keyringCount == 0 {
+		query = "SELECT COUNT(*) FROM information_schema.plugins WHERE plugin_name LIKE 'keyring%' AND plugin_status = 'ACTIVE'"
+		if err := db.QueryRow(query).Scan(&keyringCount); err != nil {
+			t.Fatalf("Unexpected error from query %q: %v", query, err)
+		} else if keyringCount == 0 {
+			t.Skip("No keyring component or plugin is active in this image")
+		}
+	}
+
+	s.SourceTestSQL(t, "encryption.sql")
+	schema := s.GetSchema(t, "testing")
+	plainTable := getTable(t, schema, "actor_in_film")
+	encTable := getTable(t, schema, "actor_in_film_enc")
+	noEncTable := getTable(t, schema, "actor_in_film_noenc")
+	if actual := encTable.Encryption(); actual != (TableEncryption{MySQLEncryption: "Y"}) || !actual.Encrypted() {
+		t.Errorf("Unexpected return from Encryption(): %+v", actual)
+	}
+	if actual := plainTable.Encryption(); actual != (TableEncryption{}) {
+		t.Errorf("Unexpected return from Encryption(): expected zero value, found %+v", actual)
+	}
+	if actual := noEncTable.Encryption(); actual.MySQLEncryption == "Y" || actual.Encrypted() {
+		t.Errorf("Unexpected return from Encryption(): %+v", actual)
+	}
+
+	pool, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	runAlter := func(from, to *Table) {
+		t.Helper()
+		to.Name = from.Name
+		clauses, supported := from.Diff(to)
+		if len(clauses) != 1 || !supported {
+			t.Fatalf("Unexpected return from diff: %d clauses, supported=%t", len(clauses), supported)
+		}
+		query := fmt.Sprintf("ALTER TABLE %s %s", EscapeIdentifier(from.Name), clauses[0].Clause(StatementModifiers{Flavor: flavor}))
+		if _, err := pool.Exec(query); err != nil {
+			t.Fatalf("Unexpected error from query %q: %v", query, err)
+		}
+		schema = s.GetSchema(t, "testing")
+	}
+
+	// Test diff generation and execution for unencrypted -> encrypted -> unencrypted
+	runAlter(plainTable, encTable)
+	if actual := getTable(t, schema, "actor_in_film").Encryption(); !actual.Encrypted() {
+		t.Errorf("Expected table to be encrypted after ALTER, but Encryption() returned %+v", actual)
+	}
+	runAlter(getTable(t, schema, "actor_in_film"), getTable(t, schema, "actor_in_film_noenc"))
+	if actual := getTable(t, schema, "actor_in_film").Encryption(); actual.Encrypted() {
+		t.Errorf("Expected table to be unencrypted after ALTER, but Encryption() returned %+v", actual)
+	}
+}
+
 // TestAlterCheckConstraints provides unit test coverage relating to diffs of
 // check constraints.
 func TestAlterCheckConstraints(t *testing.T) {
@@ -1150,6 +2367,49 @@ func TestAlterCheckConstraints(t *testing.T) {
 	}
 }
 
+// TestAlterCheckEnforcement confirms that a change in only a check's enforcement
+// status results in an ALTER CHECK clause, rather than a drop and re-add.
+func TestAlterCheckEnforcement(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	from, to := aTableForFlavor(flavor, 1), aTableForFlavor(flavor, 1)
+	from.Checks = []*Check{
+		{Name: "alivecheck", Clause: "(`alive` <> 0)", Enforced: true},
+		{Name: "ssncheck", Clause: "(`ssn` <> _utf8mb3'000000000')", Enforced: true},
+	}
+	to.Checks = []*Check{
+		{Name: "alivecheck", Clause: "(`alive` <> 0)", Enforced: true},
+		{Name: "ssncheck", Clause: "(`ssn` <> _utf8mb3'000000000')", Enforced: false},
+	}
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+
+	cases := []struct {
+		from, to *Table
+		expected string
+	}{
+		{&from, &to, "ALTER TABLE `actor` ALTER CHECK `ssncheck` NOT ENFORCED"},
+		{&to, &from, "ALTER TABLE `actor` ALTER CHECK `ssncheck` ENFORCED"},
+	}
+	for _, c := range cases {
+		td := NewAlterTable(c.from, c.to)
+		if td == nil || len(td.alterClauses) != 1 {
+			t.Fatalf("Expected 1 clause, instead found %+v", td)
+		} else if _, ok := td.alterClauses[0].(AlterCheck); !ok {
+			t.Fatalf("Expected AlterCheck clause, instead found %T", td.alterClauses[0])
+		}
+		if stmt, err := td.Statement(StatementModifiers{Flavor: flavor}); stmt != c.expected || err != nil {
+			t.Errorf("Unexpected return from Statement: %q / %v", stmt, err)
+		}
+
+		// Flavors which don't support NOT ENFORCED should emit nothing
+		for _, other := range []Flavor{ParseFlavor("mysql:8.0.15"), ParseFlavor("mariadb:10.6")} {
+			if stmt, err := td.Statement(StatementModifiers{Flavor: other}); stmt != "" || err != nil {
+				t.Errorf("Unexpected return from Statement with flavor %s: %q / %v", other, stmt, err)
+			}
+		}
+	}
+}
+
 // TestAlterCheckConstraints provides integration test coverage relating to
 // diffs of check constraints. It is similar to the above function, but actually
 // executes the generated ALTERs to confirm validity.
@@ -1272,3 +2532,289 @@ func (s TengoIntegrationSuite) TestAlterCheckConstraints(t *testing.T) {
 		}
 	}
 }
+
+// TestAlterCheckEnforcementFixture confirms that a check constraint changing
+// from enforced to not enforced, as introspected from real tables, generates an
+// ALTER CHECK clause which the server accepts and which has the expected result.
+func (s TengoIntegrationSuite) TestAlterCheckEnforcementFixture(t *testing.T) {
+	flavor := s.d.Flavor()
+	if flavor.IsMariaDB() || !flavor.HasCheckConstraints() {
+		t.Skipf("NOT ENFORCED check constraints not supported in flavor %s", flavor)
+	}
+	s.SourceTestSQL(t, "check-enforcement.sql")
+	enforced := getTable(t, s.GetSchema(t, "checkenforced"), "check_enforcement")
+	notEnforced := getTable(t, s.GetSchema(t, "checknotenforced"), "check_enforcement")
+	if enforced.UnsupportedDDL || notEnforced.UnsupportedDDL {
+		t.Fatal("Test fixture tables are unexpectedly unsupported for diffs")
+	}
+	db, err := s.d.ConnectionPool("checkenforced", "")
+	if err != nil {
+		t.Fatalf("Unable to establish connection pool: %v", err)
+	}
+	defer db.Close()
+
+	assertAlter := func(from, to *Table, expected string) {
+		t.Helper()
+		stmt, err := NewAlterTable(from, to).Statement(StatementModifiers{Flavor: flavor})
+		if stmt != expected || err != nil {
+			t.Fatalf("Unexpected return from Statement: %q / %v", stmt, err)
+		} else if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Unexpected error executing statement %q: %v", stmt, err)
+		}
+		altered := getTable(t, s.GetSchema(t, "checkenforced"), "check_enforcement")
+		if altered.CreateStatement != to.CreateStatement {
+			t.Errorf("Unexpected CREATE after executing %q.\nExpected:\n%s\nFound:\n%s", stmt, to.CreateStatement, altered.CreateStatement)
+		}
+	}
+	assertAlter(enforced, notEnforced, "ALTER TABLE `check_enforcement` ALTER CHECK `qty_limit` NOT ENFORCED")
+	assertAlter(notEnforced, enforced, "ALTER TABLE `check_enforcement` ALTER CHECK `qty_limit` ENFORCED")
+}
+
+// TestAlterTableAcrossSchemas confirms that identically-structured tables in
+// two different schemas do not generate any differences, even if they have
+// foreign keys referencing tables within their own schema.
+func (s TengoIntegrationSuite) TestAlterTableAcrossSchemas(t *testing.T) {
+	prod := s.GetSchema(t, "testing")
+	if _, err := s.d.CreateSchema("staging", SchemaCreationOptions{DefaultCharSet: prod.CharSet, DefaultCollation: prod.Collation}); err != nil {
+		t.Fatalf("Unable to create schema: %v", err)
+	}
+	db, err := s.d.CachedConnectionPool("staging", "foreign_key_checks=0")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	for _, table := range prod.Tables {
+		if _, err := db.Exec(table.CreateStatement); err != nil {
+			t.Fatalf("Unexpected error executing %q: %v", table.CreateStatement, err)
+		}
+	}
+	staging := s.GetSchema(t, "staging")
+	var sawForeignKey bool
+	for _, table := range prod.Tables {
+		other := getTable(t, staging, table.Name)
+		sawForeignKey = sawForeignKey || len(table.ForeignKeys) > 0
+		if td := NewAlterTable(table, other); td != nil {
+			stmt, _ := td.Statement(StatementModifiers{AllowUnsafe: true, NextAutoInc: NextAutoIncIgnore})
+			if stmt != "" {
+				t.Errorf("Expected no differences for table %s across schemas, instead found %s", table.Name, stmt)
+			}
+		}
+	}
+	if !sawForeignKey {
+		t.Error("Test setup problem: expected at least one table with foreign keys")
+	}
+}
+
+func (s TengoIntegrationSuite) TestAlterExistsGuards(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.IsMariaDB() {
+		t.Skipf("IF [NOT] EXISTS guards in ALTER TABLE not supported in flavor %s", flavor)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	from := getTable(t, s.GetSchema(t, "testing"), "actor")
+	to := *from
+	to.Columns = append(slices.Clone(from.Columns), &Column{Name: "age", Type: ParseColumnType("int unsigned"), Nullable: true, Default: "NULL"})
+	to.SecondaryIndexes = append(slices.Clone(from.SecondaryIndexes), &Index{Name: "idx_age", Parts: []IndexPart{{ColumnName: "age"}}, Type: "BTREE"})
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+
+	// Each guarded statement should be re-runnable without error
+	mods := StatementModifiers{Flavor: flavor, ExistsGuards: ExistsGuardsRequire, AllowUnsafe: true}
+	for _, td := range []*TableDiff{NewAlterTable(from, &to), NewAlterTable(&to, from)} {
+		stmt, err := td.Statement(mods)
+		if err != nil || !strings.Contains(stmt, " EXISTS ") {
+			t.Fatalf("Unexpected return from Statement: %q, %v", stmt, err)
+		}
+		for range 2 {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+			}
+		}
+		if actual := getTable(t, s.GetSchema(t, "testing"), "actor"); actual.CreateStatement != td.To.CreateStatement {
+			t.Errorf("Unexpected table after executing %q:\n%s", stmt, actual.CreateStatement)
+		}
+	}
+}
+
+func TestTableDiffQualifySchema(t *testing.T) {
+	fkTable := foreignKeyTable()
+	mods := StatementModifiers{QualifySchema: "my`db", AllowUnsafe: true}
+
+	// CREATE: table name and same-schema FK references are qualified, while
+	// cross-schema FK references are left as-is
+	stmt, _ := NewCreateTable(&fkTable).Statement(mods)
+	if !strings.HasPrefix(stmt, "CREATE TABLE `my``db`.`warranties` (") {
+		t.Errorf("Unexpected CREATE prefix: %s", stmt)
+	}
+	if !strings.Contains(stmt, "REFERENCES `my``db`.`products` (`line`, `model`)") || !strings.Contains(stmt, "REFERENCES `purchasing`.`customers` (`id`)") {
+		t.Errorf("Unexpected FK references in CREATE: %s", stmt)
+	}
+	if clauses, _ := NewCreateTable(&fkTable).Clauses(mods); !strings.HasPrefix(clauses, "(\n") {
+		t.Errorf("Unexpected CREATE clauses: %s", clauses)
+	}
+
+	// DROP
+	if stmt, _ := NewDropTable(&fkTable).Statement(mods); stmt != "DROP TABLE `my``db`.`warranties`" {
+		t.Errorf("Unexpected DROP statement: %s", stmt)
+	}
+
+	// ALTER: column clauses and partitioning clauses
+	from, to := unpartitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	td := NewAlterTable(&from, &to)
+	expected := "ALTER TABLE `my``db`.`prange` " + strings.TrimSpace(to.Partitioning.Definition(FlavorUnknown))
+	if stmt, _ := td.Statement(mods); stmt != expected {
+		t.Errorf("Unexpected ALTER statement:\nexpected %s\nfound    %s", expected, stmt)
+	}
+	td = NewAlterTable(&to, &from)
+	if stmt, _ := td.Statement(mods); stmt != "ALTER TABLE `my``db`.`prange` REMOVE PARTITIONING" {
+		t.Errorf("Unexpected ALTER statement: %s", stmt)
+	}
+	to2 := aTable(1)
+	from2 := aTable(1)
+	to2.Columns = append(to2.Columns, &Column{Name: "nickname", Type: ParseColumnType("varchar(20)"), Nullable: true})
+	to2.CreateStatement = to2.GeneratedCreateStatement(FlavorUnknown)
+	td = NewAlterTable(&from2, &to2)
+	if stmt, _ := td.Statement(mods); stmt != "ALTER TABLE `my``db`.`actor` ADD COLUMN `nickname` varchar(20)" {
+		t.Errorf("Unexpected ALTER statement: %s", stmt)
+	}
+	if clauses, _ := td.Clauses(mods); clauses != "ADD COLUMN `nickname` varchar(20)" {
+		t.Errorf("Unexpected ALTER clauses: %s", clauses)
+	}
+
+	// Default behavior is unqualified
+	if stmt, _ := td.Statement(StatementModifiers{}); stmt != "ALTER TABLE `actor` ADD COLUMN `nickname` varchar(20)" {
+		t.Errorf("Unexpected ALTER statement: %s", stmt)
+	}
+
+	// RENAME: the new name must be qualified too, to keep the table in the same
+	// schema regardless of the session's default database
+	td = NewRenameTable(&from2, "actors")
+	if stmt, _ := td.Statement(mods); stmt != "ALTER TABLE `my``db`.`actor` RENAME TO `my``db`.`actors`" {
+		t.Errorf("Unexpected RENAME statement: %s", stmt)
+	}
+	if clauses, _ := td.Clauses(mods); clauses != "RENAME TO `my``db`.`actors`" {
+		t.Errorf("Unexpected RENAME clauses: %s", clauses)
+	}
+	if stmt, _ := td.Statement(StatementModifiers{AllowUnsafe: true}); stmt != "ALTER TABLE `actor` RENAME TO `actors`" {
+		t.Errorf("Unexpected RENAME statement: %s", stmt)
+	}
+}
+
+func TestTableDiffDataLossRisks(t *testing.T) {
+	assertRisks := func(td *TableDiff, expected ...string) {
+		t.Helper()
+		var actual []string
+		for _, risk := range td.DataLossRisks(StatementModifiers{}) {
+			actual = append(actual, strings.TrimSpace(risk.Type+" "+risk.Name))
+		}
+		if !slices.Equal(actual, expected) {
+			t.Errorf("Unexpected return from DataLossRisks: expected %q, found %q", expected, actual)
+		}
+	}
+	from := aTable(1)
+	assertRisks(NewCreateTable(&from))
+	assertRisks(NewDropTable(&from), "DropTable actor")
+
+	// Modify a column in the copy of from, returning a fresh TableDiff
+	alterColumn := func(name string, modify func(*Column)) *TableDiff {
+		to := aTable(1)
+		col := *to.ColumnsByName()[name]
+		modify(&col)
+		to.Columns[slices.IndexFunc(to.Columns, func(c *Column) bool { return c.Name == name })] = &col
+		to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+		return NewAlterTable(&from, &to)
+	}
+
+	// Type narrowing is a risk, but widening is not
+	assertRisks(alterColumn("first_name", func(c *Column) { c.Type = ParseColumnType("varchar(20)") }), "ModifyColumn first_name")
+	assertRisks(alterColumn("first_name", func(c *Column) { c.Type = ParseColumnType("varchar(255)") }))
+	assertRisks(alterColumn("actor_id", func(c *Column) { c.Type = ParseColumnType("tinyint(3) unsigned") }), "ModifyColumn actor_id")
+
+	// Changing a string column to a non-string type is a risk, and the reason
+	// describes the type change rather than a character set conversion
+	td := alterColumn("first_name", func(c *Column) { c.Type, c.CharSet, c.Collation = ParseColumnType("int(11)"), "", "" })
+	if risks := td.DataLossRisks(StatementModifiers{}); len(risks) != 1 || risks[0].Reason != "changing column first_name from varchar(45) to int(11) may truncate or lose existing values" {
+		t.Errorf("Unexpected return from DataLossRisks: %+v", risks)
+	}
+
+	// Tightening a column to NOT NULL is a risk, but relaxing it is not
+	assertRisks(alterColumn("last_name", func(c *Column) { c.Nullable, c.Default = false, "" }), "ModifyColumn last_name")
+	assertRisks(alterColumn("alive", func(c *Column) { c.Nullable = true }))
+
+	// Converting to a character set which is a superset is not a risk, even
+	// though it is unsafe; converting to a narrower character set is a risk
+	td = alterColumn("first_name", func(c *Column) { c.CharSet, c.Collation, c.ShowCharSet = "utf8mb4", "utf8mb4_general_ci", true })
+	if summaries := td.ClauseSummaries(StatementModifiers{}); len(summaries) != 1 || !summaries[0].Unsafe {
+		t.Errorf("Expected charset change to be unsafe, instead found %+v", summaries)
+	}
+	assertRisks(td)
+	assertRisks(alterColumn("first_name", func(c *Column) { c.CharSet, c.Collation, c.ShowCharSet = "latin1", "latin1_swedish_ci", true }), "ModifyColumn first_name")
+
+	// Likewise for converting the entire table with PreferConvertCharSet, in
+	// which case the ModifyColumn clauses are not emitted
+	utf8Table := aTable(1)
+	utf8Table.CharSet, utf8Table.Collation = "utf8mb4", "utf8mb4_general_ci"
+	for _, col := range utf8Table.Columns {
+		if col.CharSet != "" {
+			col.CharSet, col.Collation = utf8Table.CharSet, utf8Table.Collation
+		}
+	}
+	utf8Table.CreateStatement = utf8Table.GeneratedCreateStatement(FlavorUnknown)
+	convertMods := StatementModifiers{PreferConvertCharSet: true}
+	if risks := NewAlterTable(&from, &utf8Table).DataLossRisks(convertMods); len(risks) != 0 {
+		t.Errorf("Expected no risks from converting to utf8mb4, instead found %+v", risks)
+	}
+	if risks := NewAlterTable(&utf8Table, &from).DataLossRisks(convertMods); len(risks) != 1 || risks[0].Type != "ConvertCharSet" {
+		t.Errorf("Expected 1 ConvertCharSet risk from converting to latin1, instead found %+v", risks)
+	}
+
+	// Enum value list: re-ordering is not a risk, but removing a value is
+	enumFrom := aTable(1)
+	enumFrom.Columns = append(enumFrom.Columns, &Column{Name: "status", Type: ParseColumnType("enum('a','b','c')"), CharSet: "utf8mb4", Collation: "utf8mb4_general_ci", Nullable: true})
+	enumFrom.CreateStatement = enumFrom.GeneratedCreateStatement(FlavorUnknown)
+	for newType, expectRisk := range map[string]bool{"enum('c','b','a')": false, "enum('a','c')": true, "enum('a','b','c','d')": false} {
+		enumTo := aTable(1)
+		enumTo.Columns = append(enumTo.Columns, &Column{Name: "status", Type: ParseColumnType(newType), CharSet: "utf8mb4", Collation: "utf8mb4_general_ci", Nullable: true})
+		enumTo.CreateStatement = enumTo.GeneratedCreateStatement(FlavorUnknown)
+		if expectRisk {
+			assertRisks(NewAlterTable(&enumFrom, &enumTo), "ModifyColumn status")
+		} else {
+			assertRisks(NewAlterTable(&enumFrom, &enumTo))
+		}
+	}
+
+	// Dropping a column is a risk; renaming the table is unsafe but not a risk
+	to := aTable(1)
+	to.Columns = slices.DeleteFunc(slices.Clone(to.Columns), func(c *Column) bool { return c.Name == "alive_bit" })
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	assertRisks(NewAlterTable(&from, &to), "DropColumn alive_bit")
+	assertRisks(NewRenameTable(&from, "new_actor"))
+
+	// Dropping partitions and switching to a non-durable engine are risks
+	p1, p2 := partitionedTable(FlavorUnknown), partitionedTable(FlavorUnknown)
+	p2.Partitioning.Partitions = p2.Partitioning.Partitions[1:]
+	diffs, _ := PartitionAlters(&p1, &p2)
+	if len(diffs) != 1 {
+		t.Fatalf("Expected 1 TableDiff from PartitionAlters, instead found %d", len(diffs))
+	}
+	assertRisks(diffs[0], "DropPartitions p0")
+
+	// Re-partitioning is unsafe, but not a risk, since the server rejects the
+	// ALTER rather than dropping any rows which don't fit
+	rePartition := &TableDiff{
+		Type:         DiffTypeAlter,
+		From:         &p1,
+		To:           &p1,
+		alterClauses: []TableAlterClause{PartitionBy{Partitioning: p1.Partitioning, RePartition: true}},
+		supported:    true,
+	}
+	if summaries := rePartition.ClauseSummaries(StatementModifiers{}); len(summaries) != 1 || !summaries[0].Unsafe {
+		t.Errorf("Expected re-partitioning to be unsafe, instead found %+v", summaries)
+	}
+	assertRisks(rePartition)
+	to = aTable(1)
+	to.Engine = "MEMORY"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	assertRisks(NewAlterTable(&from, &to), "ChangeStorageEngine")
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
@@ -20,7 +21,19 @@ import (
 
 var reExtraOnUpdate = regexp.MustCompile(`(?i)\bon update (current_timestamp(?:\(\d*\))?)`)
 
-func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, error) {
+// introspectionOptions controls optional behaviors of querySchemaTables.
+type introspectionOptions struct {
+	bulk            bool // only run SHOW CREATE TABLE for tables which cannot be reconstructed
+	retainRawCreate bool // retain unmodified SHOW CREATE TABLE output in each Table
+}
+
+// querySchemaTables introspects all tables in the schema. If opts.bulk is true,
+// SHOW CREATE TABLE is only executed for tables which cannot be fully
+// reconstructed from information_schema; see canReconstructTable. Otherwise,
+// SHOW CREATE TABLE is run for every table, concurrently with the
+// information_schema queries. Tables which fail SHOW CREATE TABLE are recorded
+// in failures and omitted from the result.
+func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor, opts introspectionOptions, failures *objectFailures) ([]*Table, error) {
 	tables, havePartitions, err := queryTablesInSchema(ctx, db, schema, flavor)
 	if err != nil {
 		return nil, err
@@ -28,14 +41,15 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 
 	g, subCtx := errgroup.WithContext(ctx)
 
-	for _, t := range tables {
-		g.Go(func() (err error) {
-			t.CreateStatement, err = showCreateTable(subCtx, db, t.Name)
-			if err != nil {
-				err = fmt.Errorf("Error executing SHOW CREATE TABLE for %s.%s: %w", EscapeIdentifier(schema), EscapeIdentifier(t.Name), err)
-			}
-			return err
-		})
+	if !opts.bulk {
+		for _, t := range tables {
+			g.Go(func() error {
+				if err := showCreateForTable(subCtx, db, schema, t, opts.retainRawCreate); err != nil {
+					failures.add(t.ObjectKey(), err)
+				}
+				return nil
+			})
+		}
 	}
 
 	var columnsByTableName map[string][]*Column
@@ -78,8 +92,7 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		return nil, err
 	}
 
-	// Assemble all the data, fix edge cases, and determine if SHOW CREATE TABLE
-	// matches expectation
+	// Assemble all the data
 	for _, t := range tables {
 		t.Columns = columnsByTableName[t.Name]
 		for _, col := range t.Columns {
@@ -112,6 +125,44 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 				part.Engine = t.Engine
 			}
 			t.Partitioning = p
+		}
+	}
+
+	// In bulk mode, tables which can be fully reconstructed from information_schema
+	// use their generated CREATE statement; SHOW CREATE TABLE is only run for the
+	// remaining tables.
+	var reconstructed map[string]bool
+	if opts.bulk {
+		reconstructed = make(map[string]bool)
+		g, subCtx = errgroup.WithContext(ctx)
+		for _, t := range tables {
+			if canReconstructTable(t, flavor) {
+				reconstructed[t.Name] = true
+				if t.HasAutoIncrement() && t.NextAutoIncrement == 0 {
+					t.NextAutoIncrement = 1
+				}
+				t.CreateStatement = t.GeneratedCreateStatement(flavor)
+			} else {
+				g.Go(func() error {
+					if err := showCreateForTable(subCtx, db, schema, t, opts.retainRawCreate); err != nil {
+						failures.add(t.ObjectKey(), err)
+					}
+					return nil
+				})
+			}
+		}
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
+	}
+	tables = withoutFailures(tables, failures)
+
+	// Fix edge cases, and determine if SHOW CREATE TABLE matches expectation
+	for _, t := range tables {
+		if reconstructed[t.Name] {
+			continue
+		}
+		if t.Partitioning != nil {
 			fixPartitioningEdgeCases(t, flavor)
 		}
 
@@ -159,6 +210,10 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		if flavor.IsPercona() && flavor.MinMySQL(5, 6, 33) && strings.Contains(t.CreateStatement, "COLUMN_FORMAT COMPRESSED") {
 			fixPerconaColCompression(t)
 		}
+		// Non-InnoDB indexes may have a KEY_BLOCK_SIZE, which isn't exposed in I_S
+		if strings.Contains(t.CreateStatement, ") KEY_BLOCK_SIZE=") {
+			fixIndexKeyBlockSizes(t, flavor)
+		}
 		// FULLTEXT indexes may have a PARSER clause, which isn't exposed in I_S
 		if strings.Contains(t.CreateStatement, "WITH PARSER") {
 			fixFulltextIndexParsers(t, flavor)
@@ -173,12 +228,32 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		if len(t.Checks) > 0 {
 			fixChecks(t, flavor)
 		}
+		// MariaDB system-versioned tables may have explicit period columns, which
+		// must be identified by parsing SHOW CREATE TABLE
+		if t.SystemVersioned && strings.Contains(t.CreateStatement, "GENERATED ALWAYS AS ROW ") {
+			fixSystemTimeColumns(t)
+		}
+		// MariaDB application-time periods, and unique indexes using WITHOUT
+		// OVERLAPS, aren't exposed in I_S prior to MariaDB 11.4
+		if flavor.MinMariaDB(10, 4) && strings.Contains(t.CreateStatement, "\n  PERIOD FOR `") {
+			fixApplicationPeriod(t, flavor)
+		}
 		// MariaDB's VECTOR indexes can have M and/or DISTANCE attributes, which
 		// aren't exposed anywhere in I_S
 		if flavor.MinMariaDB(11, 7) && strings.Contains(t.CreateStatement, "VECTOR KEY") {
 			fixVectorIndexes(t, flavor)
 		}
 
+		// MERGE table options aren't exposed in I_S
+		if t.IsMerge() {
+			fixMergeOptions(t)
+		}
+		// MySQL 8.0.21+ engine attributes are only exposed in separate I_S extension
+		// tables, so it's simpler to obtain them from SHOW CREATE TABLE
+		if flavor.MinMySQL(8, 0, 21) && strings.Contains(t.CreateStatement, "ENGINE_ATTRIBUTE") {
+			fixEngineAttributes(t, flavor)
+		}
+
 		// Compare what we expect the create DDL to be, to determine if we support
 		// diffing for the table. (No need to remove next AUTO_INCREMENT from this
 		// comparison since the value was parsed from t.CreateStatement earlier.)
@@ -189,6 +264,99 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 	return tables, nil
 }
 
+// showCreateForTable sets t.CreateStatement using SHOW CREATE TABLE. If
+// retainRaw is true, the unmodified output is also stored in t.rawCreate.
+func showCreateForTable(ctx context.Context, db *sqlx.DB, schema string, t *Table, retainRaw bool) (err error) {
+	t.CreateStatement, err = showCreateTable(ctx, db, t.Name)
+	if err != nil {
+		err = fmt.Errorf("Error executing SHOW CREATE TABLE for %s.%s: %w", EscapeIdentifier(schema), EscapeIdentifier(t.Name), err)
+	} else if retainRaw {
+		t.rawCreate = t.CreateStatement
+	}
+	return err
+}
+
+// reconstructableColumnTypes and reconstructableCreateOptions are allowlists
+// of the column types and table options which are fully represented in
+// information_schema in MariaDB, without requiring any parsing of SHOW CREATE
+// TABLE. Anything not listed here prevents bulk reconstruction of the table.
+var (
+	reconstructableColumnTypes = map[string]bool{
+		"tinyint": true, "smallint": true, "mediumint": true, "int": true, "bigint": true,
+		"decimal": true, "float": true, "double": true, "bit": true,
+		"char": true, "varchar": true, "binary": true, "varbinary": true,
+		"tinytext": true, "text": true, "mediumtext": true, "longtext": true,
+		"tinyblob": true, "blob": true, "mediumblob": true, "longblob": true,
+		"enum": true, "set": true,
+		"date": true, "time": true, "datetime": true, "timestamp": true, "year": true,
+	}
+	reconstructableCreateOptions = map[string]bool{
+		"ROW_FORMAT": true, "STATS_PERSISTENT": true, "STATS_AUTO_RECALC": true, "STATS_SAMPLE_PAGES": true,
+	}
+)
+
+// canReconstructTable returns true if the supplied table, as populated from
+// information_schema queries, only uses features which are known to be fully
+// captured by information_schema, in which case SHOW CREATE TABLE may be
+// skipped. This is an allowlist: only InnoDB tables in MariaDB are eligible,
+// and only if every column type, table option, and index type is listed as
+// reconstructable. The table's NextAutoIncrement must already be populated
+// from information_schema.
+func canReconstructTable(t *Table, flavor Flavor) bool {
+	if !flavor.IsMariaDB() || t.Engine != "InnoDB" || t.Partitioning != nil || len(t.Checks) > 0 || t.SystemVersioned {
+		return false
+	}
+	if !flavor.SortedForeignKeys() && len(t.ForeignKeys) > 1 {
+		return false
+	}
+	for _, opt := range splitAttributes(t.CreateOptions) {
+		name, _, _ := strings.Cut(opt, "=")
+		if !reconstructableCreateOptions[name] {
+			return false
+		}
+	}
+
+	// Application-time periods aren't visible in information_schema, but require
+	// two non-nullable temporal columns, so at most one is permitted here
+	var temporalCols int
+	for _, col := range t.Columns {
+		if !reconstructableColumnTypes[col.Type.Base] || col.GenerationExpr != "" || col.Compression != "" || col.CheckClause != "" || col.SystemTime != "" {
+			return false
+		}
+		if base := col.Type.Base; !col.Nullable && (base == "date" || base == "datetime" || base == "timestamp") {
+			temporalCols++
+		}
+	}
+	if temporalCols > 1 && flavor.MinMariaDB(10, 4) {
+		return false
+	}
+
+	// information_schema.tables.auto_increment can be non-NULL for tables lacking
+	// an auto_increment column; SHOW CREATE TABLE is needed to handle this properly
+	if t.NextAutoIncrement > 0 && !t.HasAutoIncrement() {
+		return false
+	}
+
+	// Only plain BTREE indexes on columns are permitted. Index attributes which
+	// aren't in information_schema, such as KEY_BLOCK_SIZE, would otherwise be
+	// lost.
+	indexes := t.SecondaryIndexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*Index{t.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		if idx.Type != "BTREE" || idx.FullTextParser != "" || idx.Attributes != "" || idx.WithoutOverlaps != "" || idx.KeyBlockSize != 0 {
+			return false
+		}
+		for _, part := range idx.Parts {
+			if part.Expression != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, bool, error) {
 	var rawTables []struct {
 		Name           string         `db:"table_name"`
@@ -197,15 +365,24 @@ func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 		TableCollation sql.NullString `db:"table_collation"`
 		CreateOptions  sql.NullString `db:"create_options"`
 		Comment        string         `db:"table_comment"`
+		AutoIncrement  sql.NullInt64  `db:"auto_increment"`
 	}
+	// Views (table_type 'VIEW') are introspected separately by querySchemaViews,
+	// and must never be passed to SHOW CREATE TABLE or treated as tables. Note
+	// that other information_schema queries in this file may still return rows
+	// for views, e.g. information_schema.columns; callers must only look up those
+	// results by the names of tables returned here.
+	// MariaDB system-versioned tables use a distinct table_type, but otherwise
+	// behave as base tables for purposes of introspection.
 	query := `
 		SELECT SQL_BUFFER_RESULT
 		       table_name AS table_name, table_type AS table_type,
 		       engine AS engine, table_collation AS table_collation,
-		       create_options AS create_options, table_comment AS table_comment
+		       create_options AS create_options, table_comment AS table_comment,
+		       auto_increment AS auto_increment
 		FROM   information_schema.tables
 		WHERE  table_schema = ?
-		AND    table_type = 'BASE TABLE'`
+		AND    table_type IN ('BASE TABLE', 'SYSTEM VERSIONED')`
 	if err := db.SelectContext(ctx, &rawTables, query, schema); err != nil {
 		return nil, false, fmt.Errorf("Error querying information_schema.tables for schema %s: %s", schema, err)
 	}
@@ -215,15 +392,19 @@ func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 	tables := make([]*Table, len(rawTables))
 	var havePartitions bool
 	for n, rawTable := range rawTables {
-		// Note that we no longer set Table.NextAutoIncrement here. information_schema
-		// potentially has bad data, e.g. a table without an auto-inc col can still
-		// have a non-NULL tables.auto_increment if the original CREATE specified one.
-		// Instead the value is parsed from SHOW CREATE TABLE in querySchemaTables().
+		// Note that Table.NextAutoIncrement set here is only a preliminary value.
+		// information_schema potentially has bad data, e.g. a table without an
+		// auto-inc col can still have a non-NULL tables.auto_increment if the
+		// original CREATE specified one. Unless the table is reconstructed without
+		// SHOW CREATE TABLE in bulk mode, the value is overwritten by parsing SHOW
+		// CREATE TABLE in querySchemaTables().
 		tables[n] = &Table{
-			Name:      rawTable.Name,
-			Engine:    rawTable.Engine.String,
-			Collation: rawTable.TableCollation.String,
-			Comment:   rawTable.Comment,
+			Name:              rawTable.Name,
+			Engine:            rawTable.Engine.String,
+			Collation:         rawTable.TableCollation.String,
+			Comment:           rawTable.Comment,
+			NextAutoIncrement: uint64(rawTable.AutoIncrement.Int64),
+			SystemVersioned:   (rawTable.Type == "SYSTEM VERSIONED"),
 		}
 		if underscore := strings.IndexByte(tables[n].Collation, '_'); underscore > 0 {
 			tables[n].CharSet = tables[n].Collation[0:underscore]
@@ -322,6 +503,11 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 		if stripDisplayWidth {
 			col.Type.StripDisplayWidth() // safe/no-op if already no int display width
 		}
+		// MariaDB's PERSISTENT keyword is a synonym for STORED. Depending on version,
+		// MariaDB may report these as either "STORED GENERATED" or "PERSISTENT
+		// GENERATED" in I_S, but SHOW CREATE TABLE always uses STORED. So anything
+		// other than VIRTUAL is treated as STORED here, ensuring a column authored
+		// as PERSISTENT is identical to one authored as STORED.
 		if rawColumn.GenerationExpr.Valid {
 			col.GenerationExpr = rawColumn.GenerationExpr.String
 			col.Virtual = strings.Contains(rawColumn.Extra, "VIRTUAL GENERATED")
@@ -331,13 +517,15 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 			// Only MariaDB 10.2+ allows blob/text default literals, including explicit
 			// DEFAULT NULL clause.
 			// Recent versions of MySQL do allow default *expressions* for these col
-			// types, but 8.0.13-8.0.22 erroneously omit them from I_S, so we need to
-			// catch this situation and parse from SHOW CREATE later.
+			// types (as well as JSON and spatial types), but 8.0.13-8.0.22 erroneously
+			// omit them from I_S, so we need to catch this situation and parse from
+			// SHOW CREATE later.
 			if !flavor.MinMariaDB(10, 2) && (strings.HasSuffix(col.Type.Base, "blob") || strings.HasSuffix(col.Type.Base, "text")) {
 				allowNullDefault = false
-				if strings.Contains(rawColumn.Extra, "DEFAULT_GENERATED") {
-					col.Default = "(!!!BLOBDEFAULT!!!)"
-				}
+			}
+			if col.Type.lobOrSpatial() && col.Type.DefaultPermitted(flavor) && !flavor.IsMariaDB() && strings.Contains(rawColumn.Extra, "DEFAULT_GENERATED") {
+				allowNullDefault = false
+				col.Default = "(!!!BLOBDEFAULT!!!)"
 			}
 			if allowNullDefault {
 				col.Default = "NULL"
@@ -465,8 +653,15 @@ func queryIndexesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 			index.Parts = append(index.Parts, IndexPart{})
 		}
 		part := &index.Parts[rawIndex.SeqInIndex-1]
-		part.ColumnName = rawIndex.ColumnName.String
 		part.Expression = rawIndex.Expression.String
+		// A functional index part is backed by a hidden virtual column, which should
+		// not be tracked as a column name, regardless of whether I_S exposes it
+		if part.Expression == "" {
+			part.ColumnName = rawIndex.ColumnName.String
+		}
+		// STATISTICS.COLLATION is authoritative for index part direction. If SHOW
+		// CREATE TABLE omits DESC for a descending part, the table will not match
+		// its GeneratedCreateStatement and is marked as UnsupportedDDL later.
 		part.Descending = (rawIndex.Collation.String == "D")
 		if rawIndex.Type != "SPATIAL" { // Sub-part value only used for non-SPATIAL indexes
 			part.PrefixLength = uint16(rawIndex.SubPart.Int64)
@@ -577,6 +772,10 @@ func queryChecksInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 	return checksByTableName, nil
 }
 
+// queryPartitionsInSchema returns the partitioning of each partitioned table in
+// the schema. The ORDER BY in the query is essential: each table's Partitions
+// must be in the server's definition order, since RANGE partition boundaries
+// are only meaningful in that order, and partition diffs rely on it as well.
 func queryPartitionsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string]*TablePartitioning, error) {
 	var rawPartitioning []struct {
 		TableName     string         `db:"table_name"`
@@ -612,8 +811,10 @@ func queryPartitionsInSchema(ctx context.Context, db *sqlx.DB, schema string, fl
 	for _, rawPart := range rawPartitioning {
 		p, ok := partitioningByTableName[rawPart.TableName]
 		if !ok {
+			method, linear := strings.CutPrefix(rawPart.Method, "LINEAR ")
 			p = &TablePartitioning{
-				Method:        rawPart.Method,
+				Method:        method,
+				Linear:        linear,
 				SubMethod:     rawPart.SubMethod.String,
 				Expression:    rawPart.Expression.String,
 				SubExpression: rawPart.SubExpression.String,
@@ -689,7 +890,7 @@ func fixCreateOptionsOrder(t *Table, flavor Flavor) {
 			break
 		}
 	}
-	template = strings.Replace(template, t.CreateOptions, "!!!CREATEOPTS!!!", 1)
+	template = strings.Replace(template, orderedCreateOptions(t.CreateOptions, flavor), "!!!CREATEOPTS!!!", 1)
 	template = regexp.QuoteMeta(template)
 	template = strings.Replace(template, "!!!CREATEOPTS!!!", "(.+)", 1)
 	re := regexp.MustCompile("^" + template + "$")
@@ -773,7 +974,7 @@ func fixPartitioningEdgeCases(t *Table, flavor Flavor) {
 	// typically this will just be a PARTITIONS N clause, but it could also be
 	// nothing at all, or an explicit list of partitions, depending on how the
 	// partitioning was originally created.
-	if strings.HasSuffix(t.Partitioning.Method, "HASH") || strings.HasSuffix(t.Partitioning.Method, "KEY") {
+	if t.Partitioning.Method == "HASH" || t.Partitioning.Method == "KEY" {
 		countClause := fmt.Sprintf("\nPARTITIONS %d", len(t.Partitioning.Partitions))
 		if strings.Contains(t.CreateStatement, countClause) {
 			t.Partitioning.ForcePartitionList = PartitionListCount
@@ -786,17 +987,17 @@ func fixPartitioningEdgeCases(t *Table, flavor Flavor) {
 
 	// KEY methods support an optional ALGORITHM clause, which is present in SHOW
 	// CREATE TABLE but not anywhere in information_schema
-	if strings.HasSuffix(t.Partitioning.Method, "KEY") && strings.Contains(t.CreateStatement, "ALGORITHM") {
-		re := regexp.MustCompile(fmt.Sprintf(`PARTITION BY %s ([^(]*)\(`, t.Partitioning.Method))
+	if t.Partitioning.Method == "KEY" && strings.Contains(t.CreateStatement, "ALGORITHM") {
+		re := regexp.MustCompile(fmt.Sprintf(`PARTITION BY %s (ALGORITHM\s*=\s*\d+)\s*\(`, t.Partitioning.FullMethod()))
 		if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
 			t.Partitioning.AlgoClause = matches[1]
 		}
 	}
 
-	// Process DATA DIRECTORY clauses, which are easier to parse from SHOW CREATE
-	// TABLE instead of information_schema.innodb_sys_tablespaces.
+	// Process DATA DIRECTORY, INDEX DIRECTORY, and TABLESPACE clauses, which are
+	// easier to parse from SHOW CREATE TABLE instead of information_schema.
 	if (t.Partitioning.ForcePartitionList == PartitionListDefault || t.Partitioning.ForcePartitionList == PartitionListExplicit) &&
-		strings.Contains(t.CreateStatement, " DATA DIRECTORY = ") {
+		(strings.Contains(t.CreateStatement, " DIRECTORY = ") || strings.Contains(t.CreateStatement, " TABLESPACE = ")) {
 		for _, p := range t.Partitioning.Partitions {
 			name := p.Name
 			if flavor.MinMariaDB(10, 2) {
@@ -807,12 +1008,173 @@ func fixPartitioningEdgeCases(t *Table, flavor Flavor) {
 			if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
 				p.DataDir = matches[1]
 			}
+			re = regexp.MustCompile(fmt.Sprintf(`PARTITION %s .*INDEX DIRECTORY = '((?:\\\\|\\'|''|[^'])*)'`, name))
+			if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
+				p.IndexDir = matches[1]
+			}
+			re = regexp.MustCompile(fmt.Sprintf("PARTITION %s .*TABLESPACE = (?:`((?:[^`]|``)+)`|(\\w+))", name))
+			if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
+				p.Tablespace = strings.ReplaceAll(matches[1], "``", "`") + matches[2]
+			}
+		}
+	}
+}
+
+var reSystemTimeColumnLine = regexp.MustCompile("^\\s+`((?:[^`]|``)+)` .* GENERATED ALWAYS AS (ROW START|ROW END)")
+
+// fixSystemTimeColumns parses the table's CREATE string in order to populate
+// Column.SystemTime for explicit period columns of MariaDB system-versioned
+// tables. These columns are implicitly NOT NULL and cannot have a default, so
+// any other information_schema attributes for them are cleared.
+func fixSystemTimeColumns(t *Table) {
+	colsByName := t.ColumnsByName()
+	for _, line := range strings.Split(t.CreateStatement, "\n") {
+		matches := reSystemTimeColumnLine.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		if col := colsByName[strings.ReplaceAll(matches[1], "``", "`")]; col != nil {
+			col.SystemTime = matches[2]
+			col.Nullable = false
+			col.Default = ""
+			col.OnUpdate = ""
+			col.GenerationExpr = ""
+			col.Virtual = false
+		}
+	}
+}
+
+var reApplicationPeriodLine = regexp.MustCompile("(?m)^  PERIOD FOR `((?:[^`]|``)+)` \\(`((?:[^`]|``)+)`, `((?:[^`]|``)+)`\\),?$")
+
+// fixApplicationPeriod parses the table's CREATE string in order to populate
+// Table.ApplicationPeriod for MariaDB 10.4+, as well as Index.WithoutOverlaps
+// for any unique indexes using the period in MariaDB 10.5+. Information_schema
+// may list the period's end and start columns as the final parts of a WITHOUT
+// OVERLAPS index, in which case these parts are removed.
+func fixApplicationPeriod(t *Table, flavor Flavor) {
+	matches := reApplicationPeriodLine.FindStringSubmatch(t.CreateStatement)
+	if matches == nil {
+		return
+	}
+	unescape := func(name string) string { return strings.ReplaceAll(name, "``", "`") }
+	t.ApplicationPeriod = &ApplicationPeriod{
+		Name:        unescape(matches[1]),
+		StartColumn: unescape(matches[2]),
+		EndColumn:   unescape(matches[3]),
+	}
+	if !flavor.MinMariaDB(10, 5) || !strings.Contains(t.CreateStatement, " WITHOUT OVERLAPS)") {
+		return
+	}
+	indexes := t.SecondaryIndexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*Index{t.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		if !idx.Unique {
+			continue
+		}
+		candidate := *idx
+		candidate.WithoutOverlaps = t.ApplicationPeriod.Name
+		if n := len(candidate.Parts); n > 2 && candidate.Parts[n-2].ColumnName == t.ApplicationPeriod.EndColumn && candidate.Parts[n-1].ColumnName == t.ApplicationPeriod.StartColumn {
+			trimmed := candidate
+			trimmed.Parts = candidate.Parts[:n-2]
+			if strings.Contains(t.CreateStatement, trimmed.Definition(flavor)) {
+				*idx = trimmed
+				continue
+			}
+		}
+		if strings.Contains(t.CreateStatement, candidate.Definition(flavor)) {
+			*idx = candidate
 		}
 	}
 }
 
 var rePerconaColCompressionLine = regexp.MustCompile("^\\s+`((?:[^`]|``)+)` .* /\\*!50633 COLUMN_FORMAT (COMPRESSED[^*]*) \\*/")
 
+var reMergeEngineLine = regexp.MustCompile("(?m)^\\) ENGINE=(\\w+) .*$")
+
+// fixMergeOptions parses the table's CREATE string in order to populate
+// Table.MergeOptions for a MERGE table, since the INSERT_METHOD and UNION
+// table options are not exposed in information_schema. It also obtains the
+// engine name's casing from SHOW CREATE TABLE, which may differ from
+// information_schema. Schema-qualified UNION members are not supported; in
+// this case, Table.MergeOptions.Union is left empty, causing the table to be
+// considered unsupported for diff operations.
+func fixMergeOptions(t *Table) {
+	matches := reMergeEngineLine.FindStringSubmatch(t.CreateStatement)
+	if matches == nil {
+		return
+	}
+	line := matches[0]
+	t.Engine = matches[1]
+	var mo MergeOptions
+	if _, after, ok := strings.Cut(line, " INSERT_METHOD="); ok {
+		mo.InsertMethod, _, _ = strings.Cut(after, " ")
+	}
+	if _, after, ok := strings.Cut(line, " UNION=("); ok {
+		var union []string
+		for _, token := range TokenizeString(after) {
+			if token == ")" {
+				mo.Union = union
+				break
+			} else if token == "." {
+				break
+			} else if token != "," {
+				union = append(union, stripBackticks(token))
+			}
+		}
+	}
+	if mo.InsertMethod != "" || len(mo.Union) > 0 {
+		t.MergeOptions = &mo
+	}
+}
+
+// engineAttributesPattern matches optional ENGINE_ATTRIBUTE and
+// SECONDARY_ENGINE_ATTRIBUTE clauses, capturing their quoted values. It must be
+// appended to a prefix pattern matching everything prior to these clauses.
+const engineAttributesPattern = `(?: (?:/\*!80021 )?ENGINE_ATTRIBUTE\s*=?\s*('(?:[^'\\]|\\.|'')*')(?: \*/)?)?` +
+	`(?: (?:/\*!80021 )?SECONDARY_ENGINE_ATTRIBUTE\s*=?\s*('(?:[^'\\]|\\.|'')*')(?: \*/)?)?`
+
+// fixEngineAttributes parses the table's CREATE string in order to populate
+// the EngineAttribute and SecondaryEngineAttribute fields of the table, its
+// columns, and its indexes. In information_schema, these values are only
+// exposed in separate extension tables in MySQL 8.0.21+. Each column and index
+// definition is used as a template, so that a string literal in a comment or
+// default can't be mistaken for an engine attribute.
+func fixEngineAttributes(t *Table, flavor Flavor) {
+	parse := func(prefix string) (engineAttr, secondaryAttr string) {
+		re := regexp.MustCompile(regexp.QuoteMeta(prefix) + engineAttributesPattern + `(?:,?\n| /\*|$)`)
+		if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
+			engineAttr, secondaryAttr = stripAnyQuote(matches[1]), stripAnyQuote(matches[2])
+		}
+		return engineAttr, secondaryAttr
+	}
+	for _, col := range t.Columns {
+		colCopy := *col
+		colCopy.EngineAttribute, colCopy.SecondaryEngineAttribute = "", ""
+		col.EngineAttribute, col.SecondaryEngineAttribute = parse("\n  " + colCopy.Definition(flavor))
+	}
+	indexes := t.SecondaryIndexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*Index{t.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		idxCopy := *idx
+		idxCopy.EngineAttribute, idxCopy.SecondaryEngineAttribute = "", ""
+		idx.EngineAttribute, idx.SecondaryEngineAttribute = parse("\n  " + idxCopy.Definition(flavor))
+	}
+
+	// Table-level options: use the portion of the generated table options up to
+	// and including any table comment as the template
+	tableCopy := *t
+	tableCopy.EngineAttribute, tableCopy.SecondaryEngineAttribute = "", ""
+	tableCopy.SystemVersioned, tableCopy.Partitioning = false, nil
+	generated := tableCopy.GeneratedCreateStatement(flavor)
+	if pos := strings.LastIndex(generated, "\n) ENGINE="); pos > -1 {
+		t.EngineAttribute, t.SecondaryEngineAttribute = parse(generated[pos:])
+	}
+}
+
 // fixPerconaColCompression parses the table's CREATE string in order to
 // populate Column.Compression for columns that are using Percona Server's
 // column compression feature, which isn't reflected in information_schema.
@@ -851,6 +1213,26 @@ func fixFulltextIndexParsers(t *Table, flavor Flavor) {
 	}
 }
 
+// fixIndexKeyBlockSizes parses the table's CREATE string in order to populate
+// Index.KeyBlockSize for any indexes that specify a KEY_BLOCK_SIZE differing
+// from the table's.
+func fixIndexKeyBlockSizes(t *Table, flavor Flavor) {
+	indexes := t.SecondaryIndexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*Index{t.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		// Obtain properly-formatted index definition prefix, without any trailing
+		// clauses, and then build a regex from this which captures the size.
+		idxCopy := Index{Name: idx.Name, Parts: idx.Parts, PrimaryKey: idx.PrimaryKey, Unique: idx.Unique, Type: idx.Type, WithoutOverlaps: idx.WithoutOverlaps}
+		re := regexp.MustCompile(regexp.QuoteMeta(idxCopy.Definition(flavor)) + ` KEY_BLOCK_SIZE=(\d+)`)
+		if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
+			size, _ := strconv.ParseUint(matches[1], 10, 32)
+			idx.KeyBlockSize = uint32(size)
+		}
+	}
+}
+
 // fixDefaultExpression parses the table's CREATE string in order to correct
 // problems in Column.Default for columns using a default expression in MySQL 8:
 //   - In MySQL 8.0.13-8.0.22, blob/text cols may have default expressions but
@@ -1,6 +1,8 @@
 package tengo
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -102,6 +104,13 @@ func (s TengoIntegrationSuite) TestInstanceSchemaIntrospection(t *testing.T) {
 		}
 	}
 
+	// Test introspection of index comments specifying an InnoDB MERGE_THRESHOLD
+	if idx := getTable(t, schema, "merge_threshold").SecondaryIndexes[0]; idx.Comment != "MERGE_THRESHOLD=40" {
+		t.Errorf("Unexpected index comment %q", idx.Comment)
+	} else if threshold, ok := idx.MergeThreshold(); threshold != 40 || !ok {
+		t.Errorf("Unexpected return from MergeThreshold: %d, %t", threshold, ok)
+	}
+
 	// Test introspection of default expressions, if flavor supports them
 	if flavor.MinMariaDB(10, 2) || flavor.MinMySQL(8, 0, 13) {
 		table := getTable(t, schema, "testdefaults")
@@ -116,6 +125,25 @@ func (s TengoIntegrationSuite) TestInstanceSchemaIntrospection(t *testing.T) {
 		if flavor.Vendor != VendorMariaDB && !strings.Contains(table.CreateStatement, "\U0001F4A9") {
 			t.Errorf("Expected default expression to contain 4-byte char \U0001F4A9, but it did not. CREATE statement:\n%s", table.CreateStatement)
 		}
+
+		// In MySQL, ensure a default expression with a CAST to the column's own
+		// type is equivalent to the same expression without the CAST
+		if flavor.Vendor != VendorMariaDB {
+			col := table.Columns[15]
+			other := *col
+			other.Default = "(now())"
+			if !col.Equivalent(&other) {
+				t.Errorf("Expected column %s with default %s to be equivalent to default %s", col.Name, col.Default, other.Default)
+			}
+
+			// JSON and TEXT default expressions must be introspected, even in versions
+			// which omit them from information_schema
+			for _, col := range table.Columns[16:] {
+				if _, isExpr := col.DefaultExpression(flavor); !isExpr {
+					t.Errorf("Expected column %s to have a default expression, instead found default %q", col.Name, col.Default)
+				}
+			}
+		}
 	}
 
 	// Test introspection of generated columns, if flavor supports them
@@ -134,6 +162,30 @@ func (s TengoIntegrationSuite) TestInstanceSchemaIntrospection(t *testing.T) {
 			t.Errorf("Expected generation expression to contain 4-byte char \U0001F4A9, but it did not. CREATE statement:\n%s", table.CreateStatement)
 		}
 
+		// In MariaDB, PERSISTENT is introspected as STORED
+		if flavor.IsMariaDB() {
+			if col := table.ColumnsByName()["last_name_upper"]; col == nil || col.Virtual || col.GenerationExpr == "" {
+				t.Errorf("Unexpected introspection of PERSISTENT generated column: %+v", col)
+			} else if !strings.Contains(table.CreateStatement, col.Definition(flavor)) || !strings.HasSuffix(col.Definition(flavor), " STORED") {
+				t.Errorf("Unexpected definition of PERSISTENT generated column: %s", col.Definition(flavor))
+			}
+		}
+
+		// Ensure secondary indexes on virtual columns are introspected properly, and
+		// don't result in spurious diffs against an otherwise-identical table
+		vtable := getTable(t, schema, "staff_virtidx")
+		if vtable.UnsupportedDDL {
+			t.Errorf("Table %s unexpectedly unsupported for diffs. Expected:\n%s\nActual:\n%s", vtable.Name, vtable.GeneratedCreateStatement(flavor), vtable.CreateStatement)
+		}
+		if idx := vtable.SecondaryIndexes[2]; len(idx.Parts) != 2 || idx.Parts[0].ColumnName != "email_domain" || idx.Parts[0].PrefixLength != 20 || idx.Parts[0].Expression != "" {
+			t.Errorf("Unexpected introspection of index %s: %+v", idx.Name, idx.Parts)
+		}
+		vcopy := *vtable
+		vcopy.CreateStatement = "" // prevent diff from short-circuiting on identical CREATE
+		if clauses, supported := vtable.Diff(&vcopy); len(clauses) != 0 || !supported {
+			t.Errorf("Unexpected diff of table %s against itself: %+v / %t", vtable.Name, clauses, supported)
+		}
+
 		// Test generation expression fix, even if test image isn't MySQL 5.7+
 		for _, col := range table.Columns {
 			if col.GenerationExpr != "" {
@@ -272,6 +324,490 @@ func TestColumnCompression(t *testing.T) {
 	}
 }
 
+// TestColumnNullabilityIntrospection confirms that nullability of JSON and
+// spatial columns is introspected correctly, and that changing it results in
+// a correct diff.
+func (s TengoIntegrationSuite) TestColumnNullabilityIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMySQL(5, 7) && !flavor.MinMariaDB(10, 2) {
+		t.Skipf("Test not relevant for flavor %s", flavor)
+	}
+	files := []string{"spatial.sql"}
+	if flavor.MinMySQL(5, 7) {
+		files = append(files, "json.sql")
+	}
+	s.SourceTestSQL(t, files...)
+	schema := s.GetSchema(t, "testing")
+	expectNullable := map[string]map[string]bool{
+		"has_geo":  {"id": false, "geo1": true, "geo2": false, "geo3": true, "geo4": false, "geo5": true, "geo6": false},
+		"has_json": {"id": false, "j1": true, "j2": false, "j3": true, "pt": true, "pt2": false},
+	}
+	for tableName, expectCols := range expectNullable {
+		table := schema.Table(tableName)
+		if table == nil {
+			continue // has_json only exists in MySQL
+		}
+		if table.UnsupportedDDL {
+			t.Errorf("Table %s unexpectedly unsupported for diff", tableName)
+		}
+		for colName, nullable := range expectCols {
+			if col := table.ColumnsByName()[colName]; col == nil {
+				t.Errorf("Column %s.%s not found", tableName, colName)
+			} else if col.Nullable != nullable {
+				t.Errorf("Column %s.%s: expected Nullable=%t, found %t", tableName, colName, nullable, col.Nullable)
+			}
+		}
+
+		// Flip nullability of all non-indexed columns, and confirm the diff applies
+		// cleanly and yields the expected result
+		from := getTable(t, schema, tableName)
+		to := *getTable(t, schema, tableName)
+		to.Columns = make([]*Column, len(from.Columns))
+		for n, col := range from.Columns {
+			colCopy := *col
+			if col.Name != "id" && len(from.IndexesWithColumn(col)) == 0 {
+				colCopy.Nullable = !col.Nullable
+				if colCopy.Nullable {
+					colCopy.Default = "NULL"
+				} else {
+					colCopy.Default = ""
+				}
+			}
+			to.Columns[n] = &colCopy
+		}
+		to.CreateStatement = to.GeneratedCreateStatement(flavor)
+		stmt, err := NewAlterTable(from, &to).Statement(StatementModifiers{AllowUnsafe: true, Flavor: flavor})
+		if err != nil {
+			t.Fatalf("Unexpected error from Statement: %v", err)
+		}
+		db, err := s.d.CachedConnectionPool("testing", "")
+		if err != nil {
+			t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Unexpected error from query %q: %v", stmt, err)
+		}
+		if createStatement, err := s.d.ShowCreateTable("testing", tableName); err != nil {
+			t.Fatalf("Unexpected error from ShowCreateTable: %v", err)
+		} else if createStatement != to.CreateStatement {
+			t.Errorf("Unexpected CREATE after altering nullability of %s:\nexpected %s\nfound    %s", tableName, to.CreateStatement, createStatement)
+		}
+	}
+}
+
+// TestIntrospectionWithViews confirms that views are excluded from table
+// introspection, in a schema containing a mix of tables and views.
+func (s TengoIntegrationSuite) TestIntrospectionWithViews(t *testing.T) {
+	s.SourceTestSQL(t, "views.sql")
+	assertNoViews := func(schema *Schema) {
+		t.Helper()
+		if len(schema.Tables) == 0 {
+			t.Fatal("Expected schema to contain tables, but it did not")
+		}
+		for _, name := range []string{"view1", "view2"} {
+			if schema.HasTable(name) {
+				t.Errorf("View %s unexpectedly introspected as a table", name)
+			}
+			if _, ok := schema.Objects()[ObjectKey{Type: ObjectTypeTable, Name: name}]; ok {
+				t.Errorf("View %s unexpectedly present in Objects()", name)
+			}
+		}
+		for _, table := range schema.Tables {
+			if table.UnsupportedDDL {
+				t.Errorf("Table %s unexpectedly unsupported for diff", table.Name)
+			}
+		}
+		if actor := getTable(t, schema, "actor"); len(actor.Columns) == 0 {
+			t.Error("Table actor unexpectedly has no columns")
+		}
+	}
+	assertNoViews(s.GetSchema(t, "testing"))
+	s.d.SetBulkIntrospection(true)
+	defer s.d.SetBulkIntrospection(false)
+	assertNoViews(s.GetSchema(t, "testing"))
+}
+
+func (s TengoIntegrationSuite) TestSystemVersioningIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMariaDB(10, 3) {
+		t.Skipf("System-versioned tables not supported in flavor %s", flavor)
+	}
+	s.SourceTestSQL(t, "sysversion-maria.sql")
+	schema := s.GetSchema(t, "testing")
+	implicit := getTable(t, schema, "versioned_implicit")
+	explicit := getTable(t, schema, "versioned_explicit")
+	unversioned := getTable(t, schema, "unversioned")
+	for _, table := range []*Table{implicit, explicit, unversioned} {
+		if table.UnsupportedDDL {
+			t.Errorf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", table.Name, table.GeneratedCreateStatement(flavor), table.CreateStatement)
+		}
+	}
+	if !implicit.SystemVersioned || !explicit.SystemVersioned || unversioned.SystemVersioned {
+		t.Errorf("Unexpected SystemVersioned values: %t, %t, %t", implicit.SystemVersioned, explicit.SystemVersioned, unversioned.SystemVersioned)
+	}
+	if rowStart, rowEnd := explicit.SystemTimePeriod(); rowStart == nil || rowEnd == nil || rowStart.Name != "row_start" || rowEnd.Name != "row_end" {
+		t.Errorf("Unexpected return from SystemTimePeriod: %+v, %+v", rowStart, rowEnd)
+	}
+
+	// Confirm ADD SYSTEM VERSIONING executes and yields the expected table
+	implicit.Name = unversioned.Name
+	clauses, supported := unversioned.Diff(implicit)
+	if len(clauses) != 1 || !supported {
+		t.Fatalf("Unexpected return from Diff: %d clauses, supported=%t", len(clauses), supported)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	query := fmt.Sprintf("ALTER TABLE %s %s", EscapeIdentifier(unversioned.Name), clauses[0].Clause(StatementModifiers{Flavor: flavor}))
+	if _, err := db.Exec(query); err != nil {
+		t.Fatalf("Unexpected error from query %q: %v", query, err)
+	}
+	refetched := getTable(t, s.GetSchema(t, "testing"), "unversioned")
+	if !refetched.SystemVersioned || refetched.UnsupportedDDL {
+		t.Errorf("Unexpected result after ALTER: SystemVersioned=%t UnsupportedDDL=%t", refetched.SystemVersioned, refetched.UnsupportedDDL)
+	}
+}
+
+func (s TengoIntegrationSuite) TestApplicationPeriodIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMariaDB(10, 5) {
+		t.Skipf("Application-time periods with WITHOUT OVERLAPS not supported in flavor %s", flavor)
+	}
+	s.SourceTestSQL(t, "period-maria.sql")
+	schema := s.GetSchema(t, "testing")
+	rooms := getTable(t, schema, "rooms")
+	noPeriod := getTable(t, schema, "rooms_noperiod")
+	for _, table := range []*Table{rooms, noPeriod} {
+		if table.UnsupportedDDL {
+			t.Errorf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", table.Name, table.GeneratedCreateStatement(flavor), table.CreateStatement)
+		}
+	}
+	expectPeriod := ApplicationPeriod{Name: "stay", StartColumn: "checkin", EndColumn: "checkout"}
+	if rooms.ApplicationPeriod == nil || *rooms.ApplicationPeriod != expectPeriod || noPeriod.ApplicationPeriod != nil {
+		t.Errorf("Unexpected ApplicationPeriod values: %+v, %+v", rooms.ApplicationPeriod, noPeriod.ApplicationPeriod)
+	}
+	if rooms.PrimaryKey.WithoutOverlaps != "stay" || len(rooms.PrimaryKey.Parts) != 1 || rooms.SecondaryIndexes[0].WithoutOverlaps != "stay" || rooms.SecondaryIndexes[1].WithoutOverlaps != "" {
+		t.Errorf("Unexpected index introspection: %+v, %+v", rooms.PrimaryKey, rooms.SecondaryIndexes)
+	}
+
+	// Confirm bulk introspection yields the same result
+	s.d.SetBulkIntrospection(true)
+	defer s.d.SetBulkIntrospection(false)
+	if bulkRooms := getTable(t, s.GetSchema(t, "testing"), "rooms"); bulkRooms.CreateStatement != rooms.CreateStatement || !bulkRooms.ApplicationPeriod.Equals(rooms.ApplicationPeriod) {
+		t.Errorf("Unexpected bulk introspection result:\n%s", bulkRooms.CreateStatement)
+	}
+
+	// Confirm the generated DDL executes in both directions and yields the
+	// expected table
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	mods := StatementModifiers{Flavor: flavor, StrictIndexOrder: true}
+	for _, direction := range [][2]*Table{{noPeriod, rooms}, {rooms, noPeriod}} {
+		from, to := direction[0], *direction[1]
+		to.Name = from.Name
+		tableDiff := NewAlterTable(from, &to)
+		stmt, err := tableDiff.Statement(mods)
+		if err != nil {
+			t.Fatalf("Unexpected error from Statement: %v", err)
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Unexpected error from query %q: %v", stmt, err)
+		}
+		if createStatement, err := s.d.ShowCreateTable("testing", from.Name); err != nil {
+			t.Fatalf("Unexpected error from ShowCreateTable: %v", err)
+		} else if createStatement != to.GeneratedCreateStatement(flavor) {
+			t.Errorf("Unexpected CREATE after running %q:\nexpected %s\nfound    %s", stmt, to.GeneratedCreateStatement(flavor), createStatement)
+		}
+	}
+}
+
+func (s TengoIntegrationSuite) TestRowLimitsIntrospection(t *testing.T) {
+	s.SourceTestSQL(t, "memory.sql")
+	schema := s.GetSchema(t, "testing")
+	withLimits := getTable(t, schema, "sessions_mem")
+	noLimits := getTable(t, schema, "sessions_mem_nolimit")
+	for _, table := range []*Table{withLimits, noLimits} {
+		if table.UnsupportedDDL {
+			t.Errorf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", table.Name, table.GeneratedCreateStatement(s.d.Flavor()), table.CreateStatement)
+		}
+		if table.Engine != "MEMORY" {
+			t.Errorf("Expected table %s to have engine MEMORY, instead found %s", table.Name, table.Engine)
+		}
+	}
+	if minRows, maxRows := withLimits.RowLimits(); minRows != 100 || maxRows != 50000 {
+		t.Errorf("Unexpected return from RowLimits() for %s: %d, %d", withLimits.Name, minRows, maxRows)
+	}
+	if minRows, maxRows := noLimits.RowLimits(); minRows != 0 || maxRows != 0 {
+		t.Errorf("Unexpected return from RowLimits() for %s: %d, %d", noLimits.Name, minRows, maxRows)
+	}
+
+	// Confirm that removing the options yields a clause which actually resets
+	// them to their defaults
+	renamed := *noLimits
+	renamed.Name = withLimits.Name
+	renamed.CreateStatement = renamed.GeneratedCreateStatement(s.d.Flavor())
+	tableDiff := NewAlterTable(withLimits, &renamed)
+	stmt, err := tableDiff.Statement(StatementModifiers{Flavor: s.d.Flavor()})
+	if err != nil {
+		t.Fatalf("Unexpected error from Statement: %v", err)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+	}
+	table := getTable(t, s.GetSchema(t, "testing"), withLimits.Name)
+	if minRows, maxRows := table.RowLimits(); minRows != 0 || maxRows != 0 {
+		t.Errorf("Unexpected return from RowLimits() after %q: %d, %d", stmt, minRows, maxRows)
+	}
+
+	// Confirm all three sizing options are introspected for a MyISAM table, and
+	// that removing them resets each one, since absence means unset
+	legacy := getTable(t, schema, "legacy_sized")
+	if legacy.UnsupportedDDL {
+		t.Errorf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", legacy.Name, legacy.GeneratedCreateStatement(s.d.Flavor()), legacy.CreateStatement)
+	}
+	if minRows, maxRows := legacy.RowLimits(); minRows != 10 || maxRows != 4000000000 {
+		t.Errorf("Unexpected return from RowLimits() for %s: %d, %d", legacy.Name, minRows, maxRows)
+	}
+	if avgRowLength, meaningful := legacy.AvgRowLength(); avgRowLength != 2048 || !meaningful {
+		t.Errorf("Unexpected return from AvgRowLength() for %s: %d, %t", legacy.Name, avgRowLength, meaningful)
+	}
+	unsized := *legacy
+	unsized.CreateOptions = ""
+	unsized.CreateStatement = unsized.GeneratedCreateStatement(s.d.Flavor())
+	if stmt, err = NewAlterTable(legacy, &unsized).Statement(StatementModifiers{Flavor: s.d.Flavor()}); err != nil {
+		t.Fatalf("Unexpected error from Statement: %v", err)
+	} else if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+	}
+	if table := getTable(t, s.GetSchema(t, "testing"), legacy.Name); table.CreateOptions != "" {
+		t.Errorf("Expected no create options after %q, instead found %q", stmt, table.CreateOptions)
+	}
+}
+
+// TestCreateLikeIntrospection confirms that a table created using the output
+// of Table.CreateLikeStatement is introspected with an identical definition,
+// aside from its name and next auto-increment value.
+func (s TengoIntegrationSuite) TestCreateLikeIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	actor := getTable(t, s.GetSchema(t, "testing"), "actor")
+	stmt, warnings := actor.CreateLikeStatement("actor_like")
+	if len(warnings) > 0 {
+		t.Errorf("Unexpected warnings from CreateLikeStatement: %v", warnings)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("Unexpected error from query %q: %v", stmt, err)
+	}
+	like := getTable(t, s.GetSchema(t, "testing"), "actor_like")
+	if like.UnsupportedDDL {
+		t.Errorf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", like.Name, like.GeneratedCreateStatement(flavor), like.CreateStatement)
+	}
+	expected, _ := ParseCreateAutoInc(strings.Replace(actor.CreateStatement, EscapeIdentifier(actor.Name), EscapeIdentifier(like.Name), 1))
+	if actual, _ := ParseCreateAutoInc(like.CreateStatement); actual != expected {
+		t.Errorf("Unexpected CREATE for table created via LIKE.\nExpected:\n%s\nFound:\n%s", expected, actual)
+	}
+}
+
+func (s TengoIntegrationSuite) TestKeyBlockSizeIntrospection(t *testing.T) {
+	s.SourceTestSQL(t, "keyblocksize.sql")
+	schema := s.GetSchema(t, "testing")
+	flavor := s.d.Flavor()
+	implicit := getTable(t, schema, "kbs_implicit")
+	explicit := getTable(t, schema, "kbs_explicit")
+	myisam := getTable(t, schema, "kbs_myisam")
+	for _, table := range []*Table{implicit, explicit, myisam} {
+		if table.UnsupportedDDL {
+			t.Errorf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", table.Name, table.GeneratedCreateStatement(flavor), table.CreateStatement)
+		}
+	}
+	if idx := myisam.SecondaryIndexes[0]; idx.KeyBlockSize != 4096 {
+		t.Errorf("Expected index %s to have KeyBlockSize 4096, instead found %d", idx.Name, idx.KeyBlockSize)
+	}
+
+	// Implied vs explicit ROW_FORMAT=COMPRESSED should not generate any DDL
+	mods := StatementModifiers{Flavor: flavor}
+	renamed := *implicit
+	renamed.Name = explicit.Name
+	renamed.CreateStatement = renamed.GeneratedCreateStatement(flavor)
+	for _, td := range []*TableDiff{NewAlterTable(explicit, &renamed), NewAlterTable(&renamed, explicit)} {
+		if stmt, err := td.Statement(mods); stmt != "" || err != nil {
+			t.Errorf("Expected no DDL between implied and explicit ROW_FORMAT=COMPRESSED, instead found %q / %v", stmt, err)
+		}
+	}
+
+	// Changing an index's KEY_BLOCK_SIZE should work properly
+	to := *myisam
+	to.SecondaryIndexes = []*Index{{Name: "idx_name", Parts: myisam.SecondaryIndexes[0].Parts, Type: "BTREE", KeyBlockSize: 2048}}
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	stmt, err := NewAlterTable(myisam, &to).Statement(mods)
+	if err != nil {
+		t.Fatalf("Unexpected error from Statement: %v", err)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+	}
+	altered := getTable(t, s.GetSchema(t, "testing"), myisam.Name)
+	if altered.CreateStatement != to.CreateStatement {
+		t.Errorf("Unexpected CREATE after %q:\n%s", stmt, altered.CreateStatement)
+	}
+}
+
+// TestFixApplicationPeriod confirms CREATE TABLE parsing for MariaDB
+// application-time periods and WITHOUT OVERLAPS indexes works properly.
+func TestFixApplicationPeriod(t *testing.T) {
+	flavor := ParseFlavor("mariadb:10.6")
+	table := periodTable(flavor)
+	expected := *table.ApplicationPeriod
+	expectedPK := *table.PrimaryKey
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+
+	// Simulate information_schema: period unknown, and period end/start columns
+	// listed as additional parts of the WITHOUT OVERLAPS index
+	table.ApplicationPeriod = nil
+	table.PrimaryKey.WithoutOverlaps = ""
+	table.PrimaryKey.Parts = append(table.PrimaryKey.Parts, IndexPart{ColumnName: "checkout"}, IndexPart{ColumnName: "checkin"})
+	fixApplicationPeriod(&table, flavor)
+	if table.ApplicationPeriod == nil || *table.ApplicationPeriod != expected {
+		t.Errorf("Expected ApplicationPeriod %+v, instead found %+v", expected, table.ApplicationPeriod)
+	}
+	if !table.PrimaryKey.Equals(&expectedPK) {
+		t.Errorf("Expected primary key %+v, instead found %+v", expectedPK, *table.PrimaryKey)
+	}
+	if table.SecondaryIndexes[0].WithoutOverlaps != "" {
+		t.Errorf("Expected non-unique index to be unaffected, instead found %+v", *table.SecondaryIndexes[0])
+	}
+	if table.GeneratedCreateStatement(flavor) != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match original after fix:\n%s\n%s", table.GeneratedCreateStatement(flavor), table.CreateStatement)
+	}
+
+	// Confirm I_S without the extra index parts is handled too
+	table.PrimaryKey.WithoutOverlaps = ""
+	fixApplicationPeriod(&table, flavor)
+	if !table.PrimaryKey.Equals(&expectedPK) {
+		t.Errorf("Expected primary key %+v, instead found %+v", expectedPK, *table.PrimaryKey)
+	}
+
+	// Confirm a table without any application-time period is unaffected
+	table = aTableForFlavor(flavor, 1)
+	fixApplicationPeriod(&table, flavor)
+	if table.ApplicationPeriod != nil || table.PrimaryKey.WithoutOverlaps != "" {
+		t.Errorf("Unexpected changes from fixApplicationPeriod on table without period: %+v, %+v", table.ApplicationPeriod, table.PrimaryKey)
+	}
+}
+
+// TestFixEngineAttributes confirms CREATE TABLE parsing for MySQL 8.0.21+
+// ENGINE_ATTRIBUTE and SECONDARY_ENGINE_ATTRIBUTE clauses works properly.
+func TestFixEngineAttributes(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	table := aTableForFlavor(flavor, 1)
+	table.Comment = "ENGINE_ATTRIBUTE='{}'" // confirm comment content isn't mistaken for the real thing
+	table.EngineAttribute = `{"a": "it's"}`
+	table.SecondaryEngineAttribute = `{"b": 2}`
+	table.Columns[1].Comment = "not an ENGINE_ATTRIBUTE '{}'"
+	table.Columns[1].EngineAttribute = `{"c": "back\\slash"}`
+	table.Columns[2].SecondaryEngineAttribute = `{"d": []}`
+	table.PrimaryKey.EngineAttribute = `{"e": {"f": 1}}`
+	table.SecondaryIndexes[0].EngineAttribute = `{}`
+	table.SecondaryIndexes[0].SecondaryEngineAttribute = `{"g": false}`
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	expected := aTableForFlavor(flavor, 1)
+	expected.Columns, expected.PrimaryKey, expected.SecondaryIndexes = nil, nil, nil
+	for _, col := range table.Columns {
+		colCopy := *col
+		expected.Columns = append(expected.Columns, &colCopy)
+	}
+	pkCopy, idxCopy := *table.PrimaryKey, *table.SecondaryIndexes[0]
+	expected.PrimaryKey, expected.SecondaryIndexes = &pkCopy, []*Index{&idxCopy}
+
+	// Simulate information_schema, which doesn't expose any of these values
+	table.EngineAttribute, table.SecondaryEngineAttribute = "", ""
+	for _, col := range table.Columns {
+		col.EngineAttribute, col.SecondaryEngineAttribute = "", ""
+	}
+	for _, idx := range append(table.SecondaryIndexes, table.PrimaryKey) {
+		idx.EngineAttribute, idx.SecondaryEngineAttribute = "", ""
+	}
+	fixEngineAttributes(&table, flavor)
+	if table.EngineAttribute != `{"a": "it's"}` || table.SecondaryEngineAttribute != `{"b": 2}` {
+		t.Errorf("Unexpected table-level values after parsing: %q, %q", table.EngineAttribute, table.SecondaryEngineAttribute)
+	}
+	for n, col := range table.Columns {
+		if *col != *expected.Columns[n] {
+			t.Errorf("Unexpected column after parsing: expected %+v, found %+v", *expected.Columns[n], *col)
+		}
+	}
+	if !table.PrimaryKey.Equals(expected.PrimaryKey) || !table.SecondaryIndexes[0].Equals(expected.SecondaryIndexes[0]) {
+		t.Errorf("Unexpected indexes after parsing: %+v, %+v", *table.PrimaryKey, *table.SecondaryIndexes[0])
+	}
+	if table.GeneratedCreateStatement(flavor) != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match original after fix:\n%s\n%s", table.GeneratedCreateStatement(flavor), table.CreateStatement)
+	}
+
+	// Confirm these clauses are omitted for flavors lacking support
+	if old := ParseFlavor("mysql:8.0.20"); strings.Contains(table.GeneratedCreateStatement(old), "/*!80021") {
+		t.Errorf("Expected no ENGINE_ATTRIBUTE clauses for %s, but found:\n%s", old, table.GeneratedCreateStatement(old))
+	}
+}
+
+func TestCanReconstructTable(t *testing.T) {
+	mysql := ParseFlavor("mysql:8.0")
+	maria := ParseFlavor("mariadb:10.11")
+	table := aTableForFlavor(maria, 1)
+	if canReconstructTable(&table, mysql) {
+		t.Error("Expected canReconstructTable to return false for MySQL, but it returned true")
+	}
+	if !canReconstructTable(&table, maria) {
+		t.Error("Expected canReconstructTable to return true for simple MariaDB table, but it returned false")
+	}
+	table.CreateOptions = "ROW_FORMAT=DYNAMIC STATS_PERSISTENT=1"
+	if !canReconstructTable(&table, maria) {
+		t.Error("Expected canReconstructTable to return true for MariaDB table with allowlisted create options, but it returned false")
+	}
+
+	// Each of these modifications requires SHOW CREATE TABLE
+	mods := map[string]func(*Table){
+		"MyISAM":       func(t *Table) { t.Engine = "MyISAM" },
+		"partitioning": func(t *Table) { t.Partitioning = partitionedTable(maria).Partitioning },
+		"check":        func(t *Table) { t.Checks = []*Check{{Name: "alivecheck", Clause: "alive != 0"}} },
+		"fulltext":     func(t *Table) { t.SecondaryIndexes[0].Type = "FULLTEXT" },
+		"autoinc":      func(t *Table) { t.Columns[0].AutoIncrement = false; t.NextAutoIncrement = 5 },
+		"vector":       func(t *Table) { t.SecondaryIndexes[0].Type = "VECTOR" },
+		"hash index":   func(t *Table) { t.SecondaryIndexes[0].Type = "HASH" },
+		"key block":    func(t *Table) { t.SecondaryIndexes[0].KeyBlockSize = 8 },
+		"compressed":   func(t *Table) { t.CreateOptions = "ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8" },
+		"page option":  func(t *Table) { t.CreateOptions = "`PAGE_COMPRESSED`='ON'" },
+		"generated":    func(t *Table) { t.Columns[2].GenerationExpr = "`first_name`" },
+		"json type":    func(t *Table) { t.Columns[2].Type = ParseColumnType("json") },
+		"geometry":     func(t *Table) { t.Columns[2].Type = ParseColumnType("point") },
+		"inet6":        func(t *Table) { t.Columns[2].Type = ParseColumnType("inet6") },
+		"compression":  func(t *Table) { t.Columns[2].Compression = "COMPRESSED" },
+		"period cols": func(t *Table) {
+			t.Columns = append(t.Columns, &Column{Name: "valid_from", Type: ParseColumnType("date")}, &Column{Name: "valid_to", Type: ParseColumnType("date")})
+		},
+	}
+	for desc, mod := range mods {
+		table := aTableForFlavor(maria, 1)
+		mod(&table)
+		if canReconstructTable(&table, maria) {
+			t.Errorf("Expected canReconstructTable to return false for table with %s, but it returned true", desc)
+		}
+	}
+}
+
 // TestFixFulltextIndexParsers confirms CREATE TABLE parsing for WITH PARSER
 // clauses works properly.
 func TestFixFulltextIndexParsers(t *testing.T) {
@@ -301,6 +837,51 @@ func TestFixFulltextIndexParsers(t *testing.T) {
 	}
 }
 
+// TestDescendingIndexPartsOmitted confirms that a table is considered
+// unsupported for diff if information_schema.STATISTICS reports a descending
+// index part, but SHOW CREATE TABLE omits DESC.
+func TestDescendingIndexPartsOmitted(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0")
+	table := anotherTableForFlavor(flavor)
+	table.SecondaryIndexes[0].Parts[0].Descending = true
+	expected := table.GeneratedCreateStatement(flavor)
+	if !strings.Contains(expected, " DESC)") {
+		t.Fatal("Test fixture has changed without corresponding update to this test's logic")
+	}
+	table.CreateStatement = strings.Replace(expected, " DESC)", ")", 1)
+	if table.CreateStatement == table.GeneratedCreateStatement(flavor) {
+		t.Error("Expected SHOW CREATE TABLE omitting DESC to mismatch GeneratedCreateStatement, but it did not")
+	}
+	if !table.SecondaryIndexes[0].Parts[0].Descending {
+		t.Error("GeneratedCreateStatement unexpectedly changed IndexPart.Descending")
+	}
+}
+
+func TestFixMergeOptions(t *testing.T) {
+	table := anotherTable()
+	table.Engine = "MRG_MYISAM"
+	table.CreateStatement = strings.Replace(table.GeneratedCreateStatement(FlavorUnknown), ") ENGINE=MRG_MYISAM DEFAULT CHARSET=latin1", ") ENGINE=MRG_MyISAM DEFAULT CHARSET=latin1 INSERT_METHOD=FIRST UNION=(`log_2023`,`odd``name`)", 1)
+	fixMergeOptions(&table)
+	expected := MergeOptions{InsertMethod: "FIRST", Union: []string{"log_2023", "odd`name"}}
+	if table.Engine != "MRG_MyISAM" || !table.MergeOptions.Equals(&expected) {
+		t.Errorf("Unexpected result from fixMergeOptions: engine=%s, options=%+v", table.Engine, table.MergeOptions)
+	}
+	if table.GeneratedCreateStatement(FlavorUnknown) != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match:\n%s", table.GeneratedCreateStatement(FlavorUnknown))
+	}
+
+	// Schema-qualified members are not supported
+	table.MergeOptions = nil
+	table.CreateStatement = strings.Replace(table.CreateStatement, "UNION=(`log_2023`,", "UNION=(`archive`.`log_2023`,", 1)
+	fixMergeOptions(&table)
+	if table.MergeOptions == nil || len(table.MergeOptions.Union) != 0 {
+		t.Errorf("Unexpected result from fixMergeOptions: %+v", table.MergeOptions)
+	}
+	if table.GeneratedCreateStatement(FlavorUnknown) == table.CreateStatement {
+		t.Error("Expected generated CREATE to differ for schema-qualified UNION member, but it did not")
+	}
+}
+
 // TestFixBlobDefaultExpression confirms CREATE TABLE parsing works for blob/
 // text default expressions in versions which omit them from information_schema.
 func TestFixBlobDefaultExpression(t *testing.T) {
@@ -396,3 +977,155 @@ func TestFixShowCharSets(t *testing.T) {
 		t.Errorf("Mismatch between generated CREATE statement and SHOW.\nGenerated:\n%s\n\nSHOW:\n%s\n", gen, table.CreateStatement)
 	}
 }
+
+func (s TengoIntegrationSuite) TestMergeTableIntrospection(t *testing.T) {
+	s.SourceTestSQL(t, "merge.sql")
+	schema := s.GetSchema(t, "testing")
+	merge := getTable(t, schema, "log_all")
+	if merge.UnsupportedDDL {
+		t.Fatalf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", merge.Name, merge.GeneratedCreateStatement(s.d.Flavor()), merge.CreateStatement)
+	}
+	expected := MergeOptions{InsertMethod: "LAST", Union: []string{"log_2023", "log_2024"}}
+	if !merge.IsMerge() || !merge.MergeOptions.Equals(&expected) {
+		t.Errorf("Unexpected introspection of MERGE table: engine=%s, options=%+v", merge.Engine, merge.MergeOptions)
+	}
+	if member := getTable(t, schema, "log_2023"); member.MergeOptions != nil {
+		t.Errorf("Expected member table to lack MergeOptions, instead found %+v", member.MergeOptions)
+	}
+
+	// Confirm the generated ALTER yields the expected table
+	to := *merge
+	to.MergeOptions = &MergeOptions{Union: []string{"log_2024"}}
+	to.CreateStatement = to.GeneratedCreateStatement(s.d.Flavor())
+	stmt, err := NewAlterTable(merge, &to).Statement(StatementModifiers{Flavor: s.d.Flavor()})
+	if err != nil {
+		t.Fatalf("Unexpected error from Statement: %v", err)
+	}
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+	}
+	if altered := getTable(t, s.GetSchema(t, "testing"), "log_all"); altered.CreateStatement != to.CreateStatement {
+		t.Errorf("Unexpected CREATE after %q:\n%s", stmt, altered.CreateStatement)
+	}
+}
+
+func (s TengoIntegrationSuite) TestDefaultRowFormatDiff(t *testing.T) {
+	s.SourceTestSQL(t, "rowformat.sql")
+	assertDefaultRowFormatDiffs(t, s.GetSchema(t, "testing"), s.d.Flavor(), s.d.DefaultRowFormat())
+}
+
+// TestDefaultRowFormatEras confirms that the server's configured default row
+// format is used in place of the flavor's default, for servers which support
+// changing it.
+func (s TengoIntegrationSuite) TestDefaultRowFormatEras(t *testing.T) {
+	flavor := s.d.Flavor()
+	if !flavor.MinMySQL(5, 7, 9) && !flavor.MinMariaDB(10, 2, 2) {
+		t.Skipf("innodb_default_row_format not supported in flavor %s", flavor)
+	}
+	db, err := s.d.CachedConnectionPool("", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	var origRowFormat string
+	if err := db.QueryRow("SELECT @@global.innodb_default_row_format").Scan(&origRowFormat); err != nil {
+		t.Fatalf("Unable to query innodb_default_row_format: %v", err)
+	}
+	defer func() {
+		if _, err := db.Exec("SET GLOBAL innodb_default_row_format=" + origRowFormat); err != nil {
+			t.Errorf("Unable to restore innodb_default_row_format: %v", err)
+		}
+	}()
+
+	for _, era := range []string{"COMPACT", "DYNAMIC"} {
+		if err := s.d.NukeData(); err != nil {
+			t.Fatalf("Unable to clean up data: %v", err)
+		}
+		s.SourceTestSQL(t, "rowformat-"+strings.ToLower(era)+".sql")
+		// A new Instance is required, since server variables are only queried once
+		inst, err := NewInstance("mysql", s.d.BaseDSN)
+		if err != nil {
+			t.Fatalf("Unexpected error from NewInstance: %v", err)
+		}
+		if actual := inst.DefaultRowFormat(); actual != era {
+			t.Fatalf("Expected DefaultRowFormat() to return %q, instead found %q", era, actual)
+		}
+		schema, err := inst.Schema("testing")
+		if err != nil {
+			t.Fatalf("Unable to obtain schema: %v", err)
+		}
+		assertDefaultRowFormatDiffs(t, schema, flavor, era)
+	}
+}
+
+// assertDefaultRowFormatDiffs confirms that diffs between the tables in
+// rowformat*.sql only include a ROW_FORMAT clause if the explicit row format
+// differs from defaultRowFormat.
+func assertDefaultRowFormatDiffs(t *testing.T, schema *Schema, flavor Flavor, defaultRowFormat string) {
+	t.Helper()
+	implicit := getTable(t, schema, "rf_implicit")
+	if implicit.CreateOptions != "" {
+		t.Fatalf("Expected table %s to lack create options, instead found %q", implicit.Name, implicit.CreateOptions)
+	}
+	mods := StatementModifiers{Flavor: flavor, DefaultRowFormat: defaultRowFormat}
+	for _, name := range []string{"rf_compact", "rf_dynamic"} {
+		explicit := getTable(t, schema, name)
+		rowFormat := strings.TrimPrefix(explicit.CreateOptions, "ROW_FORMAT=")
+		renamed := *implicit
+		renamed.Name = explicit.Name
+		renamed.CreateStatement = renamed.GeneratedCreateStatement(flavor)
+		for _, td := range []*TableDiff{NewAlterTable(explicit, &renamed), NewAlterTable(&renamed, explicit)} {
+			stmt, err := td.Statement(mods)
+			if err != nil {
+				t.Errorf("Unexpected error from Statement: %v", err)
+			} else if isDefault := (rowFormat == defaultRowFormat); isDefault != (stmt == "") {
+				t.Errorf("Unexpected statement for ROW_FORMAT=%s with default %s in flavor %s: %q", rowFormat, defaultRowFormat, flavor, stmt)
+			}
+		}
+	}
+}
+
+func (s TengoIntegrationSuite) TestZeroTimestampDefaults(t *testing.T) {
+	s.SourceTestSQL(t, "zerotimestamp.sql")
+	schema := s.GetSchema(t, "testing")
+	legacy := getTable(t, schema, "ts_legacy")
+	if legacy.UnsupportedDDL {
+		t.Fatalf("Table %s unexpectedly unsupported for diff. Expected:\n%s\nFound:\n%s", legacy.Name, legacy.GeneratedCreateStatement(s.d.Flavor()), legacy.CreateStatement)
+	}
+	for _, col := range legacy.Columns[1:] {
+		if !col.HasZeroTimestampDefault() {
+			t.Errorf("Expected column %s to have a zero-date default, instead found %q", col.Name, col.Default)
+		}
+	}
+
+	// The implicit default of ts_implicit.updated_at varies by server setting
+	implicit := getTable(t, schema, "ts_implicit")
+	col := implicit.Columns[2]
+	if explicitDefs := s.d.ExplicitDefaultsForTimestamp(); col.HasZeroTimestampDefault() == explicitDefs {
+		t.Errorf("With explicit_defaults_for_timestamp=%t, unexpected default %q for column %s", explicitDefs, col.Default, col.Name)
+	}
+
+	// Simulate comparing against a server with the opposite setting
+	to := *implicit
+	to.Columns = slices.Clone(implicit.Columns)
+	toCol := *col
+	if col.Default == "" {
+		toCol.Default = "'0000-00-00 00:00:00'"
+	} else {
+		toCol.Default = ""
+	}
+	to.Columns[2] = &toCol
+	to.CreateStatement = to.GeneratedCreateStatement(s.d.Flavor())
+	td := NewAlterTable(implicit, &to)
+	mods := StatementModifiers{Flavor: s.d.Flavor()}
+	if stmt, err := td.Statement(mods); stmt == "" || err != nil {
+		t.Errorf("Expected a MODIFY COLUMN without LaxZeroTimestamps, instead found %q / %v", stmt, err)
+	}
+	mods.LaxZeroTimestamps = true
+	if stmt, err := td.Statement(mods); stmt != "" || err != nil {
+		t.Errorf("Expected no statement with LaxZeroTimestamps, instead found %q / %v", stmt, err)
+	}
+}
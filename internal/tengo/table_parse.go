@@ -0,0 +1,858 @@
+package tengo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var reCreateTableHeader = regexp.MustCompile("^CREATE TABLE (`(?:[^`]|``)+`) \\($")
+
+// ParseCreateTable parses a canonical CREATE TABLE statement into a Table,
+// without requiring a database connection. The resulting Table is populated in
+// the same manner as schema introspection, so it may be supplied to Table.Diff
+// or NewAlterTable.
+//
+// This is not a general-purpose SQL parser. The statement must be formatted in
+// the same manner as SHOW CREATE TABLE output from a server running the
+// supplied flavor, such as a Table's CreateStatement, or a file written by
+// `skeema pull` or `skeema format`: a header line with a backtick-quoted table
+// name, then each column, index, and constraint definition on its own line,
+// then a line with the table options, optionally followed by a partitioning
+// clause. Columns declared using the SERIAL, BOOL, or BOOLEAN pseudo-types are
+// the only exception, and are expanded in the same way as the server, including
+// SERIAL's implicit UNIQUE index. In particular, CREATE TABLE text generated
+// from templates is only supported if the templates render this canonical
+// format, with the same keyword casing, quoting, spacing, and clause order as
+// SHOW CREATE TABLE. Hand-written DDL or other non-canonical text should
+// instead be executed in a workspace and introspected, to obtain the canonical
+// form from the server.
+//
+// If the statement lacks the expected structure, a *MalformedSQLError is
+// returned, which satisfies IsSyntaxError. If the statement is structurally
+// valid but differs from the CREATE TABLE that this package would generate for
+// the resulting Table (for example due to non-canonical whitespace or an
+// unsupported feature), the Table is returned with UnsupportedDDL set to true,
+// just like an introspected table which cannot be diffed.
+func ParseCreateTable(stmt string, flavor Flavor) (*Table, error) {
+	stmt = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt), ";"))
+	t := &Table{CreateStatement: stmt}
+	base, partitionClause := ParseCreatePartitioning(stmt)
+	lines := strings.Split(base, "\n")
+	matches := reCreateTableHeader.FindStringSubmatch(lines[0])
+	if matches == nil {
+		return nil, &MalformedSQLError{str: "Expected CREATE TABLE header formatted like SHOW CREATE TABLE", lineNumber: 1}
+	} else if len(lines) < 3 {
+		return nil, &MalformedSQLError{str: "CREATE TABLE statement lacks any column definitions", lineNumber: len(lines)}
+	}
+	t.Name = stripBackticks(matches[1])
+
+	// Table options must be parsed first, since column character sets and
+	// collations may be inherited from the table's defaults
+	if err := parseTableOptions(t, lines[len(lines)-1], flavor); err != nil {
+		err.lineNumber = len(lines)
+		return nil, err
+	}
+	// Remove column and index attributes which don't affect InnoDB, same as
+	// introspection does
+	if t.Engine == "InnoDB" {
+		t.CreateStatement = StripNonInnoAttributes(t.CreateStatement)
+		base, partitionClause = ParseCreatePartitioning(t.CreateStatement)
+		lines = strings.Split(base, "\n")
+	}
+	var serialCols []*Column
+	for n, line := range lines[1 : len(lines)-1] {
+		def, hasComma := strings.CutSuffix(strings.TrimPrefix(line, "  "), ",")
+		pseudoType, err := parseTableDefinition(t, def, flavor)
+		if err != nil {
+			err.lineNumber = n + 2
+			if err.colNumber > 0 {
+				err.colNumber += len(line) - len(strings.TrimPrefix(line, "  "))
+			}
+			return nil, err
+		}
+		// Columns using pseudo-types are rewritten to their expanded form, so that
+		// they don't cause the table to be considered unsupported
+		if pseudoType != "" {
+			col := t.Columns[len(t.Columns)-1]
+			lines[n+1] = "  " + col.Definition(flavor)
+			if hasComma {
+				lines[n+1] += ","
+			}
+			if pseudoType == "serial" {
+				serialCols = append(serialCols, col)
+			}
+		}
+	}
+	if len(serialCols) > 0 {
+		lines = addSerialIndexes(t, lines, serialCols, flavor)
+	}
+	t.CreateStatement = strings.Join(lines, "\n") + partitionClause
+	if partitionClause != "" {
+		tp, supported, err := parsePartitioning(partitionClause, flavor)
+		if err != nil {
+			err.lineNumber = len(lines) + 1
+			return nil, err
+		}
+		t.Partitioning = tp
+		t.UnsupportedDDL = !supported
+	}
+
+	// Handle everything else which introspection obtains by parsing SHOW CREATE
+	// TABLE, using the same logic
+	t.Tablespace = ParseCreateTablespace(t.CreateStatement)
+	_, t.NextAutoIncrement = ParseCreateAutoInc(t.CreateStatement)
+	if t.NextAutoIncrement == 0 && t.HasAutoIncrement() {
+		t.NextAutoIncrement = 1
+	}
+	if t.Partitioning != nil && !t.UnsupportedDDL {
+		fixPartitioningEdgeCases(t, flavor)
+	}
+	if flavor.IsPercona() && strings.Contains(t.CreateStatement, "COLUMN_FORMAT COMPRESSED") {
+		fixPerconaColCompression(t)
+	}
+	if strings.Contains(t.CreateStatement, ") KEY_BLOCK_SIZE=") {
+		fixIndexKeyBlockSizes(t, flavor)
+	}
+	if strings.Contains(t.CreateStatement, "WITH PARSER") {
+		fixFulltextIndexParsers(t, flavor)
+	}
+	if flavor.MinMariaDB(10, 4) && strings.Contains(t.CreateStatement, "\n  PERIOD FOR `") {
+		fixApplicationPeriod(t, flavor)
+	}
+	if flavor.MinMariaDB(11, 7) && strings.Contains(t.CreateStatement, "VECTOR KEY") {
+		fixVectorIndexes(t, flavor)
+	}
+	if t.IsMerge() {
+		fixMergeOptions(t)
+	}
+	if flavor.MinMySQL(8, 0, 21) && strings.Contains(t.CreateStatement, "ENGINE_ATTRIBUTE") {
+		fixEngineAttributes(t, flavor)
+	}
+	if t.CreateStatement != t.GeneratedCreateStatement(flavor) {
+		t.UnsupportedDDL = true
+	}
+	return t, nil
+}
+
+// parseTableOptions parses the final line of a CREATE TABLE, which contains the
+// table's options. Options which are parsed elsewhere, such as TABLESPACE or
+// the next AUTO_INCREMENT value, are skipped.
+func parseTableOptions(t *Table, line string, flavor Flavor) *MalformedSQLError {
+	dt, err := tokenizeDefinition(line)
+	if err != nil {
+		return err
+	} else if !dt.acceptSymbol(")") {
+		return dt.unexpected()
+	}
+	var createOptions []string
+	for !dt.done() {
+		if dt.peek().typ == TokenExtComment {
+			dt.next() // TABLESPACE and engine attributes are handled separately
+			continue
+		}
+		switch {
+		case dt.acceptWords("ENGINE"):
+			t.Engine, err = dt.optionValue()
+		case dt.acceptWords("AUTO_INCREMENT"):
+			_, err = dt.optionValue()
+		case dt.acceptWords("DEFAULT", "CHARSET"), dt.acceptWords("CHARSET"):
+			t.CharSet, err = dt.optionValue()
+		case dt.acceptWords("COLLATE"):
+			t.Collation, err = dt.optionValue()
+			t.ShowCollation = true
+		case dt.acceptWords("COMMENT"):
+			dt.acceptSymbol("=")
+			t.Comment, err = dt.stringLiteral()
+		case dt.acceptWords("INSERT_METHOD"):
+			_, err = dt.optionValue() // handled by fixMergeOptions
+		case dt.acceptWords("UNION"):
+			dt.acceptSymbol("=")
+			_, err = dt.parens() // handled by fixMergeOptions
+		case dt.acceptWords("WITH", "SYSTEM", "VERSIONING"):
+			t.SystemVersioned = true
+		case dt.peek().typ == TokenWord || dt.peek().typ == TokenIdent:
+			// Any other option is retained as-is in Table.CreateOptions
+			start := dt.next().start()
+			if dt.acceptSymbol("=") {
+				dt.next()
+			}
+			createOptions = append(createOptions, dt.text[start:dt.lastEnd()])
+		default:
+			return dt.unexpected()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if t.Engine == "" {
+		return &MalformedSQLError{str: "CREATE TABLE statement lacks an ENGINE clause"}
+	}
+	// MySQL 8.0.24-8.0.29 displays utf8mb3 in SHOW CREATE TABLE, but still uses
+	// utf8 in information_schema
+	if t.CharSet == "utf8mb3" && flavor.MinMySQL(8, 0, 24) && !flavor.MinMySQL(8, 0, 30) {
+		t.CharSet = "utf8"
+	}
+	if t.Collation == "" {
+		t.Collation = characterSetsForFlavor(flavor)[t.CharSet].DefaultCollation
+	}
+	t.CreateOptions = strings.Join(createOptions, " ")
+	return nil
+}
+
+// parseTableDefinition parses a single column, index, or constraint definition
+// line of a CREATE TABLE, with any leading indentation and trailing comma
+// already removed, and adds the result to t. If the definition is a column
+// using a pseudo-type such as SERIAL or BOOL, the lowercased pseudo-type is
+// returned.
+func parseTableDefinition(t *Table, def string, flavor Flavor) (pseudoType string, err *MalformedSQLError) {
+	dt, err := tokenizeDefinition(def)
+	if err != nil {
+		return "", err
+	} else if dt.done() {
+		return "", &MalformedSQLError{str: "Unexpected blank line"}
+	}
+	switch first := strings.ToUpper(dt.peek().val); {
+	case dt.peek().typ == TokenIdent:
+		var col *Column
+		col, pseudoType, err = parseColumnDefinition(dt, t, flavor)
+		if err != nil {
+			return "", err
+		}
+		t.Columns = append(t.Columns, col)
+	case first == "PRIMARY" || first == "UNIQUE" || first == "KEY" || first == "FULLTEXT" || first == "SPATIAL" || first == "VECTOR":
+		idx, err := parseIndexDefinition(dt)
+		if err != nil {
+			return "", err
+		} else if idx.PrimaryKey {
+			t.PrimaryKey = idx
+		} else {
+			t.SecondaryIndexes = append(t.SecondaryIndexes, idx)
+		}
+	case first == "CONSTRAINT" && len(dt.tokens) > 2 && strings.EqualFold(dt.tokens[2].val, "FOREIGN"):
+		fk, err := parseForeignKeyDefinition(dt, flavor)
+		if err != nil {
+			return "", err
+		}
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+	case first == "CONSTRAINT":
+		cc, err := parseCheckDefinition(dt)
+		if err != nil {
+			return "", err
+		}
+		t.Checks = append(t.Checks, cc)
+	case first == "PERIOD":
+		// Periods are handled by fixApplicationPeriod, or implied by the columns'
+		// SystemTime for system-versioning
+	default:
+		return "", dt.unexpected()
+	}
+	return pseudoType, nil
+}
+
+// addSerialIndexes adds the implicit UNIQUE index of each SERIAL column to t,
+// and returns lines with the corresponding index definitions inserted. The
+// server orders these before all other secondary indexes, since they're
+// declared first and only contain NOT NULL columns.
+func addSerialIndexes(t *Table, lines []string, serialCols []*Column, flavor Flavor) []string {
+	// Definitions are inserted after the last column or PRIMARY KEY line
+	insertAt := 1
+	for n := 1; n < len(lines)-1; n++ {
+		if strings.HasPrefix(lines[n], "  `") || strings.HasPrefix(lines[n], "  PRIMARY KEY ") {
+			insertAt = n + 1
+		}
+	}
+	indexNames := make(map[string]bool, len(t.SecondaryIndexes))
+	for _, idx := range t.SecondaryIndexes {
+		indexNames[strings.ToLower(idx.Name)] = true
+	}
+	newIndexes := make([]*Index, 0, len(serialCols))
+	newLines := make([]string, 0, len(lines)+len(serialCols))
+	newLines = append(newLines, lines[:insertAt]...)
+	for _, col := range serialCols {
+		// Same naming logic as the server: use the column name, adding a numeric
+		// suffix if needed to avoid conflicting with another index name
+		name := col.Name
+		for n := 2; indexNames[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s_%d", col.Name, n)
+		}
+		indexNames[strings.ToLower(name)] = true
+		idx := &Index{
+			Name:   name,
+			Parts:  []IndexPart{{ColumnName: col.Name}},
+			Unique: true,
+			Type:   "BTREE",
+		}
+		newIndexes = append(newIndexes, idx)
+		newLines = append(newLines, "  "+idx.Definition(flavor)+",")
+	}
+	if insertAt == len(lines)-1 { // no definitions follow, so comma placement differs
+		newLines[insertAt-1] += ","
+		newLines[len(newLines)-1] = strings.TrimSuffix(newLines[len(newLines)-1], ",")
+	}
+	t.SecondaryIndexes = append(newIndexes, t.SecondaryIndexes...)
+	return append(newLines, lines[insertAt:]...)
+}
+
+// parseColumnDefinition parses a column definition. Character set and collation
+// are populated with their effective values, inheriting the table's defaults
+// if not specified. If the column's type is a pseudo-type such as SERIAL or BOOL,
+// the column is populated using the expanded type and attributes, and the
+// lowercased pseudo-type is also returned.
+func parseColumnDefinition(dt *defTokens, t *Table, flavor Flavor) (col *Column, pseudoType string, err *MalformedSQLError) {
+	col = &Column{Name: stripBackticks(dt.next().val), Nullable: true}
+
+	// Data type: a bare word, optionally followed by a parenthesized size or list
+	// of values, and then any unsigned or zerofill modifiers
+	if dt.peek().typ != TokenWord {
+		return nil, "", dt.unexpected()
+	}
+	typeStart := dt.next().start()
+	if dt.peek().val == "(" {
+		if _, err := dt.parens(); err != nil {
+			return nil, "", err
+		}
+	}
+	for dt.acceptWords("unsigned") || dt.acceptWords("zerofill") {
+		// modifiers are included in the text passed to ParseColumnType
+	}
+	if ct, serial, ok := ParsePseudoType(dt.text[typeStart:dt.lastEnd()], flavor); ok {
+		col.Type, pseudoType = ct, strings.ToLower(dt.text[typeStart:dt.lastEnd()])
+		if serial {
+			col.Nullable, col.AutoIncrement = false, true
+		}
+	} else {
+		col.Type = ParseColumnType(dt.text[typeStart:dt.lastEnd()])
+	}
+
+	for !dt.done() && err == nil {
+		if tok := dt.peek(); tok.typ == TokenExtComment {
+			dt.next()
+			body := versionCommentBody(tok.val)
+			if srid, ok := strings.CutPrefix(body, "SRID "); ok {
+				id, _ := strconv.ParseUint(srid, 10, 32)
+				col.SpatialReferenceID, col.HasSpatialReference = uint32(id), true
+			} else if body == "INVISIBLE" {
+				col.Invisible = true
+			} else if body == "COMPRESSED" && flavor.IsMariaDB() {
+				col.Compression = body
+			}
+			// Other version-gated clauses are handled by fixPerconaColCompression or
+			// fixEngineAttributes
+			continue
+		}
+		switch {
+		case dt.acceptWords("CHARACTER", "SET"):
+			col.CharSet, col.ShowCharSet = dt.next().val, true
+		case dt.acceptWords("COLLATE"):
+			col.Collation, col.ShowCollation = dt.next().val, true
+		case dt.acceptWords("GENERATED", "ALWAYS", "AS", "ROW"):
+			col.SystemTime = "ROW " + strings.ToUpper(dt.next().val)
+			col.Nullable = false
+		case dt.acceptWords("GENERATED", "ALWAYS", "AS"):
+			col.GenerationExpr, err = dt.parens()
+			col.Virtual = dt.acceptWords("VIRTUAL")
+			if !col.Virtual && !dt.acceptWords("STORED") {
+				err = dt.unexpected()
+			}
+		case dt.acceptWords("NOT", "NULL"):
+			col.Nullable = false
+		case dt.acceptWords("NULL"):
+			col.Nullable = true
+		case dt.acceptWords("DEFAULT"):
+			col.Default = dt.expression()
+		case dt.acceptWords("ON", "UPDATE"):
+			col.OnUpdate = dt.expression()
+		case dt.acceptWords("AUTO_INCREMENT"):
+			col.AutoIncrement = true
+		case dt.acceptWords("INVISIBLE"):
+			col.Invisible = true
+		case dt.acceptWords("COMMENT"):
+			col.Comment, err = dt.stringLiteral()
+		case dt.acceptWords("CHECK"):
+			col.CheckClause, err = dt.parens()
+		default:
+			err = dt.unexpected()
+		}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if col.Type.hasCharSet() {
+		if col.Collation == "" && col.CharSet != "" && !charsetsEquivalent(col.CharSet, t.CharSet) {
+			col.Collation = characterSetsForFlavor(flavor)[col.CharSet].DefaultCollation
+		} else if col.Collation == "" {
+			col.Collation = t.Collation
+		}
+		if col.CharSet == "" {
+			col.CharSet = t.CharSet
+		}
+	}
+	return col, pseudoType, nil
+}
+
+// parseIndexDefinition parses a primary key or secondary index definition.
+// KEY_BLOCK_SIZE, WITH PARSER, vector attributes, and engine attributes are
+// skipped here; these are populated afterwards using the same logic as
+// introspection.
+func parseIndexDefinition(dt *defTokens) (*Index, *MalformedSQLError) {
+	idx := &Index{Type: "BTREE"}
+	switch {
+	case dt.acceptWords("PRIMARY", "KEY"):
+		idx.Name, idx.PrimaryKey, idx.Unique = "PRIMARY", true, true
+	case dt.acceptWords("UNIQUE", "KEY"):
+		idx.Unique = true
+	case dt.acceptWords("KEY"):
+	default:
+		idx.Type = strings.ToUpper(dt.next().val)
+		if !dt.acceptWords("KEY") {
+			return nil, dt.unexpected()
+		}
+	}
+	if !idx.PrimaryKey {
+		if dt.peek().typ != TokenIdent {
+			return nil, dt.unexpected()
+		}
+		idx.Name = stripBackticks(dt.next().val)
+	}
+	if !dt.acceptSymbol("(") {
+		return nil, dt.unexpected()
+	}
+	for {
+		var part IndexPart
+		if tok := dt.peek(); tok.typ == TokenIdent {
+			dt.next()
+			part.ColumnName = stripBackticks(tok.val)
+			if dt.acceptWords("WITHOUT", "OVERLAPS") {
+				idx.WithoutOverlaps, part.ColumnName = part.ColumnName, ""
+			} else if dt.acceptSymbol("(") {
+				length, _ := strconv.ParseUint(dt.next().val, 10, 16)
+				part.PrefixLength = uint16(length)
+				if !dt.acceptSymbol(")") {
+					return nil, dt.unexpected()
+				}
+			}
+		} else if tok.val == "(" {
+			var err *MalformedSQLError
+			if part.Expression, err = dt.parens(); err != nil {
+				return nil, err
+			}
+		} else {
+			return nil, dt.unexpected()
+		}
+		part.Descending = dt.acceptWords("DESC")
+		if !part.Descending {
+			dt.acceptWords("ASC")
+		}
+		if part.ColumnName != "" || part.Expression != "" {
+			idx.Parts = append(idx.Parts, part)
+		}
+		if dt.acceptSymbol(")") {
+			break
+		} else if !dt.acceptSymbol(",") {
+			return nil, dt.unexpected()
+		}
+	}
+
+	var err *MalformedSQLError
+	for !dt.done() && err == nil {
+		if tok := dt.peek(); tok.typ == TokenExtComment {
+			dt.next()
+			if versionCommentBody(tok.val) == "INVISIBLE" {
+				idx.Invisible = true
+			}
+			continue
+		}
+		switch {
+		case dt.acceptWords("KEY_BLOCK_SIZE"), dt.acceptWords("USING"):
+			_, err = dt.optionValue()
+		case dt.acceptWords("COMMENT"):
+			idx.Comment, err = dt.stringLiteral()
+		case dt.acceptWords("INVISIBLE"), dt.acceptWords("IGNORED"):
+			idx.Invisible = true
+		case dt.acceptWords("WITH", "PARSER"):
+			dt.next()
+		case idx.Type == "VECTOR":
+			dt.next() // M and DISTANCE attributes
+		default:
+			err = dt.unexpected()
+		}
+	}
+	return idx, err
+}
+
+// parseForeignKeyDefinition parses a foreign key constraint definition.
+func parseForeignKeyDefinition(dt *defTokens, flavor Flavor) (*ForeignKey, *MalformedSQLError) {
+	dt.acceptWords("CONSTRAINT")
+	fk := &ForeignKey{Name: stripBackticks(dt.next().val)}
+	var err *MalformedSQLError
+	if !dt.acceptWords("FOREIGN", "KEY") {
+		return nil, dt.unexpected()
+	} else if fk.ColumnNames, err = dt.identifierList(); err != nil {
+		return nil, err
+	} else if !dt.acceptWords("REFERENCES") || dt.peek().typ != TokenIdent {
+		return nil, dt.unexpected()
+	}
+	fk.ReferencedTableName = stripBackticks(dt.next().val)
+	if dt.acceptSymbol(".") {
+		fk.ReferencedSchemaName = fk.ReferencedTableName
+		fk.ReferencedTableName = stripBackticks(dt.next().val)
+	}
+	if fk.ReferencedColumnNames, err = dt.identifierList(); err != nil {
+		return nil, err
+	}
+
+	// Rules omitted from SHOW CREATE TABLE vary by flavor; see
+	// ForeignKey.Definition
+	fk.DeleteRule, fk.UpdateRule = "RESTRICT", "RESTRICT"
+	if flavor.MinMySQL(8) {
+		fk.DeleteRule, fk.UpdateRule = "NO ACTION", "NO ACTION"
+	}
+	for !dt.done() {
+		var rule *string
+		if dt.acceptWords("ON", "DELETE") {
+			rule = &fk.DeleteRule
+		} else if dt.acceptWords("ON", "UPDATE") {
+			rule = &fk.UpdateRule
+		} else {
+			return nil, dt.unexpected()
+		}
+		switch {
+		case dt.acceptWords("SET", "NULL"):
+			*rule = "SET NULL"
+		case dt.acceptWords("SET", "DEFAULT"):
+			*rule = "SET DEFAULT"
+		case dt.acceptWords("NO", "ACTION"):
+			*rule = "NO ACTION"
+		case dt.acceptWords("RESTRICT"), dt.acceptWords("CASCADE"):
+			*rule = strings.ToUpper(dt.tokens[dt.pos-1].val)
+		default:
+			return nil, dt.unexpected()
+		}
+	}
+	return fk, nil
+}
+
+// parseCheckDefinition parses a check constraint definition.
+func parseCheckDefinition(dt *defTokens) (*Check, *MalformedSQLError) {
+	dt.acceptWords("CONSTRAINT")
+	cc := &Check{Name: stripBackticks(dt.next().val), Enforced: true}
+	if !dt.acceptWords("CHECK") {
+		return nil, dt.unexpected()
+	}
+	var err *MalformedSQLError
+	if cc.Clause, err = dt.parens(); err != nil {
+		return nil, err
+	}
+	if tok := dt.peek(); tok.typ == TokenExtComment && versionCommentBody(tok.val) == "NOT ENFORCED" {
+		dt.next()
+		cc.Enforced = false
+	}
+	if !dt.done() {
+		return nil, dt.unexpected()
+	}
+	return cc, nil
+}
+
+// parsePartitioning parses a partitioning clause, as split from a CREATE TABLE
+// by ParseCreatePartitioning. DATA DIRECTORY, INDEX DIRECTORY, TABLESPACE, and
+// ALGORITHM clauses are skipped here, since these are handled afterwards by
+// fixPartitioningEdgeCases. If the clause uses sub-partitioning, supported is
+// false, and the returned TablePartitioning is incomplete.
+func parsePartitioning(clause string, flavor Flavor) (tp *TablePartitioning, supported bool, err *MalformedSQLError) {
+	clause = strings.TrimSpace(clause)
+	if strings.HasPrefix(clause, "/*") {
+		clause = versionCommentBody(clause)
+	}
+	dt, err := tokenizeDefinition(clause)
+	if err != nil {
+		return nil, false, err
+	} else if !dt.acceptWords("PARTITION", "BY") {
+		return nil, false, dt.unexpected()
+	}
+	tp = &TablePartitioning{Linear: dt.acceptWords("LINEAR")}
+	tp.Method = strings.ToUpper(dt.next().val)
+	if (tp.Method == "RANGE" || tp.Method == "LIST") && dt.acceptWords("COLUMNS") {
+		tp.Method += " COLUMNS"
+	} else if tp.Method == "KEY" && dt.acceptWords("ALGORITHM") {
+		if _, err = dt.optionValue(); err != nil { // handled by fixPartitioningEdgeCases
+			return nil, false, err
+		}
+	}
+	if tp.Expression, err = dt.parens(); err != nil {
+		return nil, false, err
+	}
+	// SHOW CREATE TABLE omits backticks around column names for some methods in
+	// some flavors, but information_schema always includes them
+	if (strings.HasSuffix(tp.Method, "COLUMNS") || tp.Method == "KEY") && !strings.Contains(tp.Expression, "`") {
+		names := strings.Split(tp.Expression, ",")
+		for n := range names {
+			names[n] = EscapeIdentifier(strings.TrimSpace(names[n]))
+		}
+		tp.Expression = strings.Join(names, ",")
+	}
+	var count int
+	if dt.acceptWords("PARTITIONS") {
+		count, _ = strconv.Atoi(dt.next().val)
+	}
+	if dt.acceptWords("SUBPARTITION") {
+		return tp, false, nil
+	}
+	if !dt.acceptSymbol("(") {
+		for n := 0; n < max(count, 1); n++ {
+			tp.Partitions = append(tp.Partitions, &Partition{Name: fmt.Sprintf("p%d", n)})
+		}
+		if !dt.done() {
+			return nil, false, dt.unexpected()
+		}
+		return tp, true, nil
+	}
+	for {
+		if !dt.acceptWords("PARTITION") {
+			return nil, false, dt.unexpected()
+		}
+		p := &Partition{Name: stripBackticks(dt.next().val)}
+		if dt.acceptWords("VALUES", "LESS", "THAN", "MAXVALUE") {
+			p.Values = "MAXVALUE"
+		} else if dt.acceptWords("VALUES", "LESS", "THAN") || dt.acceptWords("VALUES", "IN") {
+			if p.Values, err = dt.parens(); err != nil {
+				return nil, false, err
+			}
+		}
+		for !dt.done() && dt.peek().val != "," && dt.peek().val != ")" {
+			switch {
+			case dt.acceptWords("COMMENT"):
+				dt.acceptSymbol("=")
+				p.Comment, err = dt.stringLiteral()
+			case dt.acceptWords("ENGINE"), dt.acceptWords("STORAGE", "ENGINE"), dt.acceptWords("TABLESPACE"),
+				dt.acceptWords("DATA", "DIRECTORY"), dt.acceptWords("INDEX", "DIRECTORY"):
+				_, err = dt.optionValue()
+			case dt.acceptWords("SUBPARTITION"), dt.peek().val == "(":
+				return tp, false, nil
+			default:
+				err = dt.unexpected()
+			}
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		tp.Partitions = append(tp.Partitions, p)
+		if dt.acceptSymbol(")") {
+			break
+		} else if !dt.acceptSymbol(",") {
+			return nil, false, dt.unexpected()
+		}
+	}
+	if !dt.done() {
+		return nil, false, dt.unexpected()
+	}
+	return tp, true, nil
+}
+
+// versionCommentBody returns the contents of a version-gated comment such as
+// "/*!80023 INVISIBLE */" or "/*M!100301 COMPRESSED*/", without the comment's
+// opening sequence, version number, closing sequence, or surrounding
+// whitespace.
+func versionCommentBody(comment string) string {
+	comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+	comment = strings.TrimPrefix(strings.TrimPrefix(comment, "M"), "!")
+	return strings.TrimSpace(strings.TrimLeft(comment, "0123456789"))
+}
+
+// defTokens is a cursor over the Lexer's tokens for part of a CREATE TABLE
+// statement. Token offsets permit obtaining the original text of expressions
+// as-is.
+type defTokens struct {
+	text   string
+	tokens []Token
+	pos    int
+}
+
+// tokenizeDefinition returns a cursor over the non-filler tokens of text.
+func tokenizeDefinition(text string) (*defTokens, *MalformedSQLError) {
+	dt := &defTokens{text: text}
+	lexer := NewLexer(strings.NewReader(text), "\000", 1024)
+	var offset int
+	for {
+		val, typ, err := lexer.Scan()
+		if mse, ok := err.(*MalformedSQLError); ok {
+			return nil, mse
+		} else if err != nil {
+			return dt, nil
+		}
+		if typ != TokenFiller {
+			dt.tokens = append(dt.tokens, Token{val: string(val), typ: typ, offset: uint32(offset)})
+		} else {
+			// The lexer returns version-gated comments as part of filler, but these
+			// are meaningful in SHOW CREATE TABLE output
+			filler := string(val)
+			for pos := 0; ; {
+				start := strings.Index(filler[pos:], "/*")
+				if start < 0 {
+					break
+				}
+				start += pos
+				end := strings.Index(filler[start+2:], "*/")
+				if end < 0 {
+					break
+				}
+				end += start + 4
+				if comment := filler[start:end]; strings.HasPrefix(comment, "/*!") || strings.HasPrefix(comment, "/*M!") {
+					dt.tokens = append(dt.tokens, Token{val: comment, typ: TokenExtComment, offset: uint32(offset + start)})
+				}
+				pos = end
+			}
+		}
+		offset += len(val)
+	}
+}
+
+// done returns true if all tokens have been consumed.
+func (dt *defTokens) done() bool {
+	return dt.pos >= len(dt.tokens)
+}
+
+// peek returns the next token without consuming it, or a zero value if all
+// tokens have been consumed.
+func (dt *defTokens) peek() Token {
+	if dt.done() {
+		return Token{}
+	}
+	return dt.tokens[dt.pos]
+}
+
+// next consumes and returns the next token, or a zero value if all tokens have
+// already been consumed.
+func (dt *defTokens) next() Token {
+	tok := dt.peek()
+	if !dt.done() {
+		dt.pos++
+	}
+	return tok
+}
+
+// lastEnd returns the end offset of the most recently consumed token.
+func (dt *defTokens) lastEnd() int {
+	if dt.pos == 0 {
+		return 0
+	}
+	return dt.tokens[dt.pos-1].end()
+}
+
+// acceptWords consumes the supplied sequence of words, returning true, if the
+// next tokens case-insensitively match it. Otherwise nothing is consumed and
+// false is returned.
+func (dt *defTokens) acceptWords(words ...string) bool {
+	matched, count := tokensMatchSequence(dt.tokens[dt.pos:], strings.Join(words, " "))
+	dt.pos += count
+	return matched
+}
+
+// acceptSymbol consumes the next token and returns true if it is the supplied
+// symbol.
+func (dt *defTokens) acceptSymbol(symbol string) bool {
+	if tok := dt.peek(); tok.typ == TokenSymbol && tok.val == symbol {
+		dt.pos++
+		return true
+	}
+	return false
+}
+
+// optionValue consumes an optional equals sign, followed by a single value
+// token, which is returned as-is.
+func (dt *defTokens) optionValue() (string, *MalformedSQLError) {
+	dt.acceptSymbol("=")
+	if dt.done() {
+		return "", dt.unexpected()
+	}
+	return dt.next().val, nil
+}
+
+// stringLiteral consumes a quoted string and returns its unescaped value.
+func (dt *defTokens) stringLiteral() (string, *MalformedSQLError) {
+	if dt.peek().typ != TokenString {
+		return "", dt.unexpected()
+	}
+	return stripAnyQuote(dt.next().val), nil
+}
+
+// parens consumes a balanced parenthesized group, returning the original text
+// between the outermost parens.
+func (dt *defTokens) parens() (string, *MalformedSQLError) {
+	if !dt.acceptSymbol("(") {
+		return "", dt.unexpected()
+	}
+	start, depth := dt.lastEnd(), 1
+	for !dt.done() {
+		tok := dt.next()
+		if tok.typ == TokenSymbol && tok.val == "(" {
+			depth++
+		} else if tok.typ == TokenSymbol && tok.val == ")" {
+			if depth--; depth == 0 {
+				return dt.text[start:tok.start()], nil
+			}
+		}
+	}
+	return "", &MalformedSQLError{str: "Parenthesized expression is never closed"}
+}
+
+// identifierList consumes a parenthesized list of backtick-wrapped identifiers,
+// returning their unescaped names.
+func (dt *defTokens) identifierList() (names []string, err *MalformedSQLError) {
+	if !dt.acceptSymbol("(") {
+		return nil, dt.unexpected()
+	}
+	for dt.peek().typ == TokenIdent {
+		names = append(names, stripBackticks(dt.next().val))
+		if dt.acceptSymbol(")") {
+			return names, nil
+		} else if !dt.acceptSymbol(",") {
+			break
+		}
+	}
+	return nil, dt.unexpected()
+}
+
+// expression consumes a column's default value or ON UPDATE expression, and
+// returns its original text. This continues until the end of the definition,
+// or until reaching a subsequent column attribute outside of any parens.
+func (dt *defTokens) expression() string {
+	start := dt.peek().start()
+	var depth int
+	for n := 0; !dt.done(); n++ {
+		tok := dt.peek()
+		if depth == 0 && n > 0 {
+			if tok.typ == TokenExtComment {
+				break
+			} else if tok.typ == TokenWord {
+				if word := strings.ToUpper(tok.val); word == "AUTO_INCREMENT" || word == "COMMENT" || word == "INVISIBLE" || word == "CHECK" || word == "NOT" || word == "NULL" {
+					break
+				} else if word == "ON" && dt.pos+1 < len(dt.tokens) && strings.EqualFold(dt.tokens[dt.pos+1].val, "UPDATE") {
+					break
+				}
+			}
+		}
+		if tok.typ == TokenSymbol && tok.val == "(" {
+			depth++
+		} else if tok.typ == TokenSymbol && tok.val == ")" {
+			depth--
+		}
+		dt.next()
+	}
+	return dt.text[start:dt.lastEnd()]
+}
+
+// unexpected returns an error describing the next token, or the end of input if
+// all tokens have been consumed.
+func (dt *defTokens) unexpected() *MalformedSQLError {
+	if dt.done() {
+		return &MalformedSQLError{str: "Unexpected end of definition"}
+	}
+	tok := dt.peek()
+	return &MalformedSQLError{
+		str:       fmt.Sprintf("Unexpected %q", tok.val),
+		colNumber: tok.start() + 1,
+	}
+}
@@ -0,0 +1,304 @@
+package tengo
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	flavors := []Flavor{
+		ParseFlavor("mysql:5.7"),
+		ParseFlavor("mysql:8.0.28"),
+		ParseFlavor("mysql:8.4"),
+		ParseFlavor("mariadb:10.6"),
+		ParseFlavor("mariadb:11.8"),
+	}
+	for _, flavor := range flavors {
+		tables := []Table{
+			aTableForFlavor(flavor, 1),
+			aTableForFlavor(flavor, 123),
+			anotherTableForFlavor(flavor),
+			supportedTableForFlavor(flavor),
+			foreignKeyTable(),
+			partitionedTable(flavor),
+		}
+		if flavor.MinMariaDB(10, 5) {
+			tables = append(tables, periodTable(flavor))
+		}
+		for _, table := range tables {
+			table.CreateStatement = table.GeneratedCreateStatement(flavor)
+			parsed, err := ParseCreateTable(table.CreateStatement, flavor)
+			if err != nil {
+				t.Errorf("Unexpected error parsing table %s for %s: %v", table.Name, flavor, err)
+				continue
+			}
+			if parsed.UnsupportedDDL {
+				t.Errorf("Parsed table %s for %s unexpectedly unsupported; generated CREATE:\n%s", table.Name, flavor, parsed.GeneratedCreateStatement(flavor))
+			}
+			for n, col := range parsed.Columns {
+				if !col.Equals(table.Columns[n]) {
+					t.Errorf("Column %d of table %s for %s mismatch: expected %+v, found %+v", n, table.Name, flavor, *table.Columns[n], *col)
+				}
+			}
+			// Confirm the diff engine considers the tables equal, even without the
+			// shortcut of comparing CREATE statements
+			expected, actual := table, *parsed
+			expected.CreateStatement, actual.CreateStatement = "", ""
+			if clauses, supported := expected.Diff(&actual); len(clauses) > 0 || !supported {
+				t.Errorf("Parsed table %s for %s differs from original: %d clauses, supported=%t", table.Name, flavor, len(clauses), supported)
+			}
+		}
+	}
+}
+
+func TestParseCreateTableFeatures(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	stmt := "CREATE TABLE `orders` (\n" +
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `code` varchar(20) CHARACTER SET latin1 COLLATE latin1_bin NOT NULL COMMENT 'it''s a code',\n" +
+		"  `total` decimal(10,2) NOT NULL DEFAULT '0.00',\n" +
+		"  `doubled` decimal(11,2) GENERATED ALWAYS AS ((`total` * 2)) VIRTUAL,\n" +
+		"  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  `uuid` binary(16) NOT NULL DEFAULT (uuid_to_bin(uuid())),\n" +
+		"  `notes` text COLLATE utf8mb4_bin,\n" +
+		"  `loc` point NOT NULL /*!80003 SRID 4326 */,\n" +
+		"  `secret` int DEFAULT NULL /*!80023 INVISIBLE */,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `code` (`code`) COMMENT 'lookup',\n" +
+		"  KEY `created` (`created_at` DESC,`total`) /*!80000 INVISIBLE */,\n" +
+		"  KEY `lower_code` ((lower(`code`))),\n" +
+		"  SPATIAL KEY `loc` (`loc`),\n" +
+		"  FULLTEXT KEY `notes` (`notes`) /*!50100 WITH PARSER `ngram` */ ,\n" +
+		"  CONSTRAINT `orders_fk` FOREIGN KEY (`code`) REFERENCES `other`.`codes` (`code`) ON DELETE CASCADE,\n" +
+		"  CONSTRAINT `total_positive` CHECK ((`total` >= 0)),\n" +
+		"  CONSTRAINT `lax` CHECK ((`id` > 0)) /*!80016 NOT ENFORCED */\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci STATS_PERSISTENT=1 ROW_FORMAT=COMPRESSED COMMENT='orders table'\n" +
+		"/*!50100 PARTITION BY KEY (id)\n" +
+		"PARTITIONS 4 */"
+	table, err := ParseCreateTable(stmt+";\n", flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseCreateTable: %v", err)
+	}
+	if table.UnsupportedDDL {
+		t.Errorf("Parsed table unexpectedly unsupported; generated CREATE:\n%s", table.GeneratedCreateStatement(flavor))
+	}
+	if table.Name != "orders" || table.NextAutoIncrement != 42 || table.Comment != "orders table" || table.CreateOptions != "STATS_PERSISTENT=1 ROW_FORMAT=COMPRESSED" {
+		t.Errorf("Unexpected table-level fields: %+v", *table)
+	}
+	if len(table.Columns) != 9 || len(table.SecondaryIndexes) != 5 || len(table.ForeignKeys) != 1 || len(table.Checks) != 2 {
+		t.Fatalf("Unexpected counts: %d columns, %d secondary indexes, %d foreign keys, %d checks", len(table.Columns), len(table.SecondaryIndexes), len(table.ForeignKeys), len(table.Checks))
+	}
+	if col := table.Columns[1]; col.CharSet != "latin1" || col.Collation != "latin1_bin" || col.Comment != "it's a code" {
+		t.Errorf("Unexpected column %+v", *col)
+	}
+	if col := table.Columns[3]; col.GenerationExpr != "(`total` * 2)" || !col.Virtual {
+		t.Errorf("Unexpected column %+v", *col)
+	}
+	if col := table.Columns[5]; col.Default != "(uuid_to_bin(uuid()))" {
+		t.Errorf("Unexpected column %+v", *col)
+	}
+	if col := table.Columns[6]; col.CharSet != "utf8mb4" || col.Collation != "utf8mb4_bin" || !col.ShowCollation || col.ShowCharSet {
+		t.Errorf("Unexpected column %+v", *col)
+	}
+	if col := table.Columns[7]; !col.HasSpatialReference || col.SpatialReferenceID != 4326 {
+		t.Errorf("Unexpected column %+v", *col)
+	}
+	if idx := table.SecondaryIndexes[1]; !idx.Invisible || !idx.Parts[0].Descending || idx.Parts[1].Descending {
+		t.Errorf("Unexpected index %+v", *idx)
+	}
+	if idx := table.SecondaryIndexes[2]; idx.Parts[0].Expression != "lower(`code`)" {
+		t.Errorf("Unexpected index %+v", *idx)
+	}
+	if idx := table.SecondaryIndexes[4]; idx.Type != "FULLTEXT" || idx.FullTextParser != "ngram" {
+		t.Errorf("Unexpected index %+v", *idx)
+	}
+	if fk := table.ForeignKeys[0]; fk.ReferencedSchemaName != "other" || fk.DeleteRule != "CASCADE" || fk.UpdateRule != "NO ACTION" {
+		t.Errorf("Unexpected foreign key %+v", *fk)
+	}
+	if !table.Checks[0].Enforced || table.Checks[1].Enforced || table.Checks[1].Clause != "(`id` > 0)" {
+		t.Errorf("Unexpected checks %+v, %+v", *table.Checks[0], *table.Checks[1])
+	}
+	if tp := table.Partitioning; tp == nil || tp.Method != "KEY" || tp.Expression != "`id`" || len(tp.Partitions) != 4 {
+		t.Errorf("Unexpected partitioning %+v", tp)
+	}
+
+	// Confirm that two parsed tables can be diffed offline
+	other, err := ParseCreateTable(strings.Replace(stmt, "  `secret` int DEFAULT NULL /*!80023 INVISIBLE */,\n", "", 1), flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseCreateTable: %v", err)
+	}
+	mods := StatementModifiers{Flavor: flavor, AllowUnsafe: true}
+	if ddl, err := NewAlterTable(table, other).Statement(mods); err != nil || ddl != "ALTER TABLE `orders` DROP COLUMN `secret`" {
+		t.Errorf("Unexpected result from diffing parsed tables: %q, %v", ddl, err)
+	}
+
+	// A structurally-valid but non-canonical statement should be parsed, but
+	// flagged as unsupported for diff operations
+	nonCanonical := strings.Replace(stmt, "`total` decimal(10,2) NOT NULL DEFAULT '0.00'", "`total` decimal(10,2) DEFAULT '0.00' NOT NULL", 1)
+	if table, err := ParseCreateTable(nonCanonical, flavor); err != nil {
+		t.Errorf("Unexpected error from ParseCreateTable: %v", err)
+	} else if !table.UnsupportedDDL {
+		t.Error("Expected non-canonical CREATE to be flagged as unsupported, but it was not")
+	}
+}
+
+func TestParseCreateTablePseudoTypes(t *testing.T) {
+	for _, flavor := range []Flavor{ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.32"), ParseFlavor("mariadb:10.6")} {
+		serialType, _, _ := ParsePseudoType("serial", flavor)
+		expanded := "CREATE TABLE `flags` (\n" +
+			"  `id` " + serialType.String() + " NOT NULL AUTO_INCREMENT,\n" +
+			"  `enabled` tinyint(1) NOT NULL DEFAULT '1',\n" +
+			"  `seq` " + serialType.String() + " NOT NULL AUTO_INCREMENT COMMENT 'sequence',\n" +
+			"  PRIMARY KEY (`id`),\n" +
+			"  UNIQUE KEY `id` (`id`),\n" +
+			"  UNIQUE KEY `seq_2` (`seq`),\n" +
+			"  KEY `seq` (`seq`,`enabled`)\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=latin1"
+		pseudo := "CREATE TABLE `flags` (\n" +
+			"  `id` SERIAL,\n" +
+			"  `enabled` BOOL NOT NULL DEFAULT '1',\n" +
+			"  `seq` serial COMMENT 'sequence',\n" +
+			"  PRIMARY KEY (`id`),\n" +
+			"  KEY `seq` (`seq`,`enabled`)\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=latin1"
+		expected, err := ParseCreateTable(expanded, flavor)
+		if err != nil || expected.UnsupportedDDL {
+			t.Fatalf("Unexpected result parsing expanded CREATE for %s: %+v, %v", flavor, expected, err)
+		}
+		actual, err := ParseCreateTable(pseudo, flavor)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing pseudo-type CREATE for %s: %v", flavor, err)
+		} else if actual.UnsupportedDDL {
+			t.Errorf("Parsed table unexpectedly unsupported for %s", flavor)
+		}
+		if actual.CreateStatement != expanded {
+			t.Errorf("Unexpected CreateStatement for %s:\n%s", flavor, actual.CreateStatement)
+		}
+		if alter := NewAlterTable(expected, actual); alter != nil {
+			t.Errorf("Expected no differences for %s, but found %+v", flavor, alter)
+		}
+		expected.CreateStatement, actual.CreateStatement = "", ""
+		if clauses, supported := expected.Diff(actual); len(clauses) > 0 || !supported {
+			t.Errorf("Expected no differences for %s, but found %d clauses, supported=%t", flavor, len(clauses), supported)
+		}
+
+		// When the SERIAL column is the last definition, the implied index must be
+		// placed correctly with respect to commas
+		pseudo = "CREATE TABLE `flags` (\n  `id` serial\n) ENGINE=InnoDB DEFAULT CHARSET=latin1"
+		expanded = "CREATE TABLE `flags` (\n  `id` " + serialType.String() + " NOT NULL AUTO_INCREMENT,\n  UNIQUE KEY `id` (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1"
+		if actual, err := ParseCreateTable(pseudo, flavor); err != nil || actual.UnsupportedDDL || actual.CreateStatement != expanded {
+			t.Errorf("Unexpected result parsing SERIAL-only table for %s: %+v, %v", flavor, actual, err)
+		}
+	}
+}
+
+// TestParseCreateTablePseudoTypesFixture confirms that ParseCreateTable expands
+// the SERIAL and BOOL synonyms in the same way as the server, including the
+// name and position of the UNIQUE index implied by a SERIAL column.
+func (s TengoIntegrationSuite) TestParseCreateTablePseudoTypesFixture(t *testing.T) {
+	flavor := s.d.Flavor()
+	s.SourceTestSQL(t, "pseudotypes.sql")
+	schema := s.GetSchema(t, "testing")
+	statements, err := ParseStatementsInFile(filepath.Join("testdata", "pseudotypes.sql"))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing statements: %v", err)
+	}
+	var tableCount int
+	for _, stmt := range statements {
+		if stmt.Type != StatementTypeCreate || stmt.ObjectType != ObjectTypeTable {
+			continue
+		}
+		tableCount++
+		introspected := getTable(t, schema, stmt.ObjectName)
+		parsed, err := ParseCreateTable(stmt.Body(), flavor)
+		if err != nil {
+			t.Errorf("Unexpected error parsing table %s: %v", stmt.ObjectName, err)
+			continue
+		}
+		indexNames := func(table *Table) (names []string) {
+			for _, idx := range table.SecondaryIndexes {
+				names = append(names, idx.Name)
+			}
+			return names
+		}
+		if expected, actual := indexNames(introspected), indexNames(parsed); !slices.Equal(expected, actual) {
+			t.Errorf("Secondary indexes of table %s mismatch: server has %v, parsed has %v", stmt.ObjectName, expected, actual)
+		}
+		if !introspected.UnsupportedDDL {
+			// Display of the table-level COLLATE varies by flavor, and is unrelated to
+			// the column type synonyms being tested here
+			parsed.ShowCollation = introspected.ShowCollation
+			if actual := parsed.GeneratedCreateStatement(flavor); actual != introspected.CreateStatement {
+				t.Errorf("Parsed table %s does not match server's expansion.\nServer:\n%s\nParsed:\n%s", stmt.ObjectName, introspected.CreateStatement, actual)
+			}
+		}
+		expected, actual := *introspected, *parsed
+		expected.CreateStatement, actual.CreateStatement = "", ""
+		if clauses, supported := expected.Diff(&actual); len(clauses) > 0 || !supported {
+			t.Errorf("Parsed table %s differs from server's version: %d clauses, supported=%t", stmt.ObjectName, len(clauses), supported)
+		}
+	}
+	if tableCount == 0 {
+		t.Fatal("Test setup problem: no CREATE TABLE statements found in fixture")
+	}
+}
+
+func TestParseCreateTableErrors(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	valid := aTableForFlavor(flavor, 1).CreateStatement
+	cases := map[string]int{ // input: expected line number of error
+		"":                                      1,
+		"SELECT 1":                              1,
+		"CREATE TABLE `foo` (\n) ENGINE=InnoDB": 2,
+		strings.Replace(valid, "ENGINE=InnoDB ", "", 1):                                          12,
+		strings.Replace(valid, "PRIMARY KEY", "PRIMARY INDEX", 1):                                9,
+		strings.Replace(valid, "NOT NULL AUTO_INCREMENT", "BOGUS", 1):                            2,
+		strings.Replace(valid, "bit(1) NOT NULL DEFAULT b'1'", "bit(1) NOT NULL DEFAULT b'1", 1): 8,
+		strings.Replace(valid, "(`last_name`(10)", "(`last_name`(10", 1):                         11,
+	}
+	for input, expectedLine := range cases {
+		_, err := ParseCreateTable(input, flavor)
+		if err == nil {
+			t.Errorf("Expected error parsing %q, but err was nil", input)
+			continue
+		} else if !IsSyntaxError(err) {
+			t.Errorf("Expected IsSyntaxError to return true for %v, but it returned false", err)
+		}
+		if mse, ok := err.(*MalformedSQLError); !ok || mse.lineNumber != expectedLine {
+			t.Errorf("Expected error at line %d, instead found %v", expectedLine, err)
+		}
+	}
+}
+
+// TestParseCreateTableNonCanonical confirms that input not formatted like SHOW
+// CREATE TABLE is either rejected as a syntax error, or flagged as unsupported.
+func TestParseCreateTableNonCanonical(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	canonical := "CREATE TABLE `t` (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=latin1"
+	if table, err := ParseCreateTable(canonical, flavor); err != nil || table.UnsupportedDDL {
+		t.Fatalf("Unexpected result parsing canonical input: %+v, %v", table, err)
+	}
+	malformed := []string{
+		"create table `t` (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+		"CREATE TABLE t (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+		"CREATE TABLE `t` (`id` int NOT NULL) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+		"CREATE TABLE `t` (\n  `id` int NOT NULL, `x` int\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+		"CREATE TABLE `t` (\n  id int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+	}
+	for _, input := range malformed {
+		if _, err := ParseCreateTable(input, flavor); !IsSyntaxError(err) {
+			t.Errorf("Expected syntax error parsing %q, instead found %v", input, err)
+		}
+	}
+	unsupported := []string{
+		"CREATE TABLE `t` (\n\t`id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+		"CREATE TABLE `t` (\n  `id` int NOT NULL\n) engine=InnoDB DEFAULT CHARSET=latin1",
+	}
+	for _, input := range unsupported {
+		if table, err := ParseCreateTable(input, flavor); err != nil || !table.UnsupportedDDL {
+			t.Errorf("Expected %q to be parsed but flagged as unsupported, instead found %+v, %v", input, table, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tengo
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -25,6 +26,48 @@ func TestTableGeneratedCreateStatement(t *testing.T) {
 	}
 }
 
+func TestTableCanonicalCreateStatement(t *testing.T) {
+	flavor57, flavor80 := ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.32")
+	mysql57 := aTableForFlavor(flavor57, 1)
+	mysql80 := aTableForFlavor(flavor80, 1)
+	if mysql57.CreateStatement == mysql80.CreateStatement {
+		t.Fatal("Test fixture has changed without corresponding update to this test's logic")
+	}
+	if a, b := mysql57.CanonicalCreateStatement(flavor57.DefaultRowFormat()), mysql80.CanonicalCreateStatement(flavor80.DefaultRowFormat()); a != b {
+		t.Errorf("Expected canonical CREATE statements to be equal across flavors, instead found:\n%s\nvs\n%s", a, b)
+	}
+
+	// Explicit ROW_FORMAT=DYNAMIC is equivalent to omitting it in flavors where
+	// it is the default, but other ROW_FORMAT values are not
+	mysql80.CreateOptions = "ROW_FORMAT=DYNAMIC STATS_PERSISTENT=1"
+	mysql57.CreateOptions = "STATS_PERSISTENT=1"
+	if a, b := mysql57.CanonicalCreateStatement(flavor57.DefaultRowFormat()), mysql80.CanonicalCreateStatement(flavor80.DefaultRowFormat()); a != b {
+		t.Errorf("Expected canonical CREATE statements to be equal, instead found:\n%s\nvs\n%s", a, b)
+	}
+	mysql80.CreateOptions = "ROW_FORMAT=COMPACT STATS_PERSISTENT=1"
+	if a, b := mysql57.CanonicalCreateStatement(flavor57.DefaultRowFormat()), mysql80.CanonicalCreateStatement(flavor80.DefaultRowFormat()); a == b {
+		t.Errorf("Expected canonical CREATE statements to differ, but both were:\n%s", a)
+	}
+
+	// In older flavors, or servers configured with innodb_default_row_format=COMPACT,
+	// COMPACT is the default instead
+	if a, b := mysql57.CanonicalCreateStatement(flavor57.DefaultRowFormat()), mysql80.CanonicalCreateStatement("COMPACT"); a != b {
+		t.Errorf("Expected canonical CREATE statements to be equal, instead found:\n%s\nvs\n%s", a, b)
+	}
+	mysql80.CreateOptions = "ROW_FORMAT=DYNAMIC STATS_PERSISTENT=1"
+	if a, b := mysql57.CanonicalCreateStatement(flavor57.DefaultRowFormat()), mysql80.CanonicalCreateStatement(ParseFlavor("mysql:5.6").DefaultRowFormat()); a == b {
+		t.Errorf("Expected canonical CREATE statements to differ, but both were:\n%s", a)
+	}
+
+	// Canonicalization must not modify the original table
+	table := aTableForFlavor(flavor57, 1)
+	origCreate := table.GeneratedCreateStatement(flavor57)
+	table.CanonicalCreateStatement(flavor57.DefaultRowFormat())
+	if table.GeneratedCreateStatement(flavor57) != origCreate {
+		t.Error("CanonicalCreateStatement unexpectedly modified the original table")
+	}
+}
+
 func TestTableClusteredIndexKey(t *testing.T) {
 	table := aTable(1)
 	if table.ClusteredIndexKey() == nil || table.ClusteredIndexKey() != table.PrimaryKey {
@@ -69,6 +112,35 @@ func TestTableClusteredIndexKey(t *testing.T) {
 	}
 }
 
+func TestTableHasPrimaryKey(t *testing.T) {
+	table := aTable(1)
+	if !table.HasPrimaryKey() {
+		t.Error("Expected HasPrimaryKey() to return true for table with explicit primary key")
+	}
+
+	// A non-nullable unique index is promoted, regardless of storage engine
+	table.PrimaryKey = nil
+	table.Engine = "MyISAM"
+	if !table.HasPrimaryKey() {
+		t.Error("Expected HasPrimaryKey() to return true for table with non-nullable unique index")
+	}
+	table.Engine = "InnoDB"
+
+	// Nullable unique indexes and non-unique indexes are not promoted
+	for _, idx := range table.SecondaryIndexes {
+		if idx.Unique {
+			table.ColumnsByName()[idx.Parts[0].ColumnName].Nullable = true
+		}
+	}
+	if table.HasPrimaryKey() {
+		t.Error("Expected HasPrimaryKey() to return false for table with only nullable unique indexes")
+	}
+	table.SecondaryIndexes = nil
+	if table.HasPrimaryKey() {
+		t.Error("Expected HasPrimaryKey() to return false for table without any indexes")
+	}
+}
+
 func TestTableRowFormat(t *testing.T) {
 	assertRowFormat := func(createOptions, expectRowFormat string) {
 		t.Helper()
@@ -163,6 +235,50 @@ func TestTableHasFulltextIndex(t *testing.T) {
 	}
 }
 
+func TestTableCreateLikeStatement(t *testing.T) {
+	table := aTable(1)
+	stmt, warnings := table.CreateLikeStatement("actor_copy")
+	if expected := "CREATE TABLE `actor_copy` LIKE `actor`"; stmt != expected || len(warnings) != 0 {
+		t.Errorf("Unexpected return from CreateLikeStatement: %q / %v", stmt, warnings)
+	}
+
+	table = foreignKeyTable()
+	stmt, warnings = table.CreateLikeStatement("new`name")
+	if expected := "CREATE TABLE `new``name` LIKE `warranties`"; stmt != expected {
+		t.Errorf("Unexpected statement from CreateLikeStatement: expected %q, found %q", expected, stmt)
+	}
+	if len(warnings) != len(table.ForeignKeys) || len(warnings) == 0 {
+		t.Fatalf("Expected %d warnings, instead found %d: %v", len(table.ForeignKeys), len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], EscapeIdentifier(table.ForeignKeys[0].Name)) {
+		t.Errorf("Expected warning to mention foreign key name, instead found %q", warnings[0])
+	}
+}
+
+func TestTableCreateStatementWithMode(t *testing.T) {
+	mysql, maria := ParseFlavor("mysql:8.0"), ParseFlavor("mariadb:10.11")
+	for _, flavor := range []Flavor{mysql, maria} {
+		table := aTableForFlavor(flavor, 1)
+		if stmt, err := table.CreateStatementWithMode(flavor, CreateModeDefault); err != nil || stmt != table.CreateStatement {
+			t.Errorf("Unexpected return from CreateStatementWithMode for default mode in %s: %q, %v", flavor, stmt, err)
+		}
+		expected := strings.Replace(table.CreateStatement, "CREATE TABLE `actor`", "CREATE TABLE IF NOT EXISTS `actor`", 1)
+		if stmt, err := table.CreateStatementWithMode(flavor, CreateModeIfNotExists); err != nil || stmt != expected {
+			t.Errorf("Unexpected return from CreateStatementWithMode for IF NOT EXISTS in %s: %q, %v", flavor, stmt, err)
+		}
+	}
+
+	table := aTableForFlavor(maria, 1)
+	expected := strings.Replace(table.CreateStatement, "CREATE TABLE `actor`", "CREATE OR REPLACE TABLE `actor`", 1)
+	if stmt, err := table.CreateStatementWithMode(maria, CreateModeOrReplace); err != nil || stmt != expected {
+		t.Errorf("Unexpected return from CreateStatementWithMode for OR REPLACE in %s: %q, %v", maria, stmt, err)
+	}
+	table = aTableForFlavor(mysql, 1)
+	if stmt, err := table.CreateStatementWithMode(mysql, CreateModeOrReplace); err == nil {
+		t.Errorf("Expected error from CreateStatementWithMode for OR REPLACE in %s, instead found %q", mysql, stmt)
+	}
+}
+
 func TestTableAlterAddOrDropColumn(t *testing.T) {
 	from := aTable(1)
 	to := aTable(1)
@@ -331,6 +447,30 @@ func TestTableAlterAddOrDropIndex(t *testing.T) {
 	if tableAlters[0].Clause(StatementModifiers{LaxComments: true}) != "" {
 		t.Error("Clause unexpectedly returns non-blank string")
 	}
+
+	// LaxComments cannot suppress a change to an InnoDB MERGE_THRESHOLD, since
+	// that's functional rather than cosmetic
+	to.SecondaryIndexes[1].Comment = "MERGE_THRESHOLD=40"
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	tableAlters, supported = from.Diff(&to)
+	if len(tableAlters) != 1 || !supported {
+		t.Fatalf("Incorrect number of table alters: expected 1, found %d", len(tableAlters))
+	}
+	if clause := tableAlters[0].Clause(StatementModifiers{LaxComments: true}); !strings.HasPrefix(clause, "DROP KEY ") || !strings.HasSuffix(clause, " COMMENT 'MERGE_THRESHOLD=40'") {
+		t.Errorf("Clause returned unexpected string: %s", clause)
+	}
+	from2 := aTable(1)
+	from2.SecondaryIndexes[1].Comment = "hi MERGE_THRESHOLD=40"
+	from2.CreateStatement = from2.GeneratedCreateStatement(FlavorUnknown)
+	tableAlters, supported = from2.Diff(&to)
+	if len(tableAlters) != 1 || !supported {
+		t.Fatalf("Incorrect number of table alters: expected 1, found %d", len(tableAlters))
+	}
+	if clause := tableAlters[0].Clause(StatementModifiers{LaxComments: true}); clause != "" {
+		t.Errorf("Expected LaxComments to suppress clause when MERGE_THRESHOLD unchanged, instead found %s", clause)
+	}
+	to.SecondaryIndexes[1].Comment = "hello I am an index"
+
 	mysql8 := Flavor{VendorMySQL, Version{8}, VariantNone}
 	to.SecondaryIndexes[1].Invisible = true
 	to.CreateStatement = to.GeneratedCreateStatement(mysql8)
@@ -425,6 +565,133 @@ func TestTableAlterAddOrDropIndex(t *testing.T) {
 	}
 }
 
+func TestForeignKeyDefinitionCrossSchema(t *testing.T) {
+	fk := &ForeignKey{
+		Name:                  "actor_fk",
+		ColumnNames:           []string{"actor_id"},
+		ReferencedSchemaName:  "other`db",
+		ReferencedTableName:   "act`or",
+		ReferencedColumnNames: []string{"actor_id"},
+		DeleteRule:            "RESTRICT",
+		UpdateRule:            "RESTRICT",
+	}
+	expected := "CONSTRAINT `actor_fk` FOREIGN KEY (`actor_id`) REFERENCES `other``db`.`act``or` (`actor_id`)"
+	if actual := fk.Definition(FlavorUnknown); actual != expected {
+		t.Errorf("Unexpected result from Definition():\nexpected %s\nfound    %s", expected, actual)
+	}
+	fk.ReferencedSchemaName = ""
+	expected = "CONSTRAINT `actor_fk` FOREIGN KEY (`actor_id`) REFERENCES `act``or` (`actor_id`)"
+	if actual := fk.Definition(FlavorUnknown); actual != expected {
+		t.Errorf("Unexpected result from Definition():\nexpected %s\nfound    %s", expected, actual)
+	}
+}
+
+func TestTableReferencedTables(t *testing.T) {
+	table := anotherTable()
+	if refs := table.ReferencedTables(); len(refs) != 0 {
+		t.Errorf("Expected table without foreign keys to have no referenced tables, instead found %v", refs)
+	}
+
+	table = foreignKeyTable()
+	// Add a duplicate reference to an already-referenced table, as well as a
+	// self-referential foreign key
+	table.ForeignKeys = append(table.ForeignKeys,
+		&ForeignKey{Name: "product_fk2", ColumnNames: []string{"model"}, ReferencedTableName: "products", ReferencedColumnNames: []string{"model"}},
+		&ForeignKey{Name: "self_fk", ColumnNames: []string{"customer_id"}, ReferencedTableName: "warranties", ReferencedColumnNames: []string{"id"}},
+	)
+	expected := []TableReference{
+		{SchemaName: "purchasing", TableName: "customers"},
+		{TableName: "products"},
+		{TableName: "warranties"},
+	}
+	if refs := table.ReferencedTables(); !slices.Equal(refs, expected) {
+		t.Errorf("Unexpected return from ReferencedTables: expected %v, found %v", expected, refs)
+	}
+	if str := expected[0].String(); str != "`purchasing`.`customers`" {
+		t.Errorf("Unexpected TableReference.String() result: %s", str)
+	}
+}
+
+func TestSortTablesForCreate(t *testing.T) {
+	// makeTable returns a table with a foreign key to each of refs. A ref
+	// containing a dot is treated as schema-qualified.
+	makeTable := func(name string, refs ...string) *Table {
+		table := &Table{Name: name}
+		for _, ref := range refs {
+			fk := &ForeignKey{Name: name + "_" + strings.ReplaceAll(ref, ".", "_"), ColumnNames: []string{"id"}, ReferencedColumnNames: []string{"id"}, ReferencedTableName: ref, UpdateRule: "RESTRICT", DeleteRule: "RESTRICT"}
+			if schema, tableName, ok := strings.Cut(ref, "."); ok {
+				fk.ReferencedSchemaName, fk.ReferencedTableName = schema, tableName
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		return table
+	}
+	names := func(tables []*Table) (result []string) {
+		for _, table := range tables {
+			result = append(result, table.Name)
+		}
+		return result
+	}
+	deferredNames := func(deferred []DeferredForeignKey) (result []string) {
+		for _, dfk := range deferred {
+			result = append(result, dfk.ForeignKey.Name)
+		}
+		return result
+	}
+
+	cases := []struct {
+		tables           []*Table
+		expectedOrder    []string
+		expectedDeferred []string
+	}{
+		// no foreign keys: order is unchanged
+		{[]*Table{makeTable("a"), makeTable("b")}, []string{"a", "b"}, nil},
+		// chain, with a self-reference, a reference to another schema, and a
+		// reference to a table not in the input
+		{
+			[]*Table{makeTable("orders", "customers", "orders", "products"), makeTable("customers", "other.regions"), makeTable("lines", "orders", "missing"), makeTable("products")},
+			[]string{"customers", "products", "orders", "lines"},
+			nil,
+		},
+		// two-table cycle
+		{[]*Table{makeTable("a", "b"), makeTable("b", "a")}, []string{"a", "b"}, []string{"a_b"}},
+		// cycle between b and c, with a depending on the cycle but not part of it
+		{
+			[]*Table{makeTable("a", "b"), makeTable("b", "c"), makeTable("c", "b"), makeTable("d")},
+			[]string{"d", "b", "a", "c"},
+			[]string{"b_c"},
+		},
+	}
+	for n, c := range cases {
+		sorted, deferred := SortTablesForCreate(c.tables)
+		if actual := names(sorted); !slices.Equal(actual, c.expectedOrder) {
+			t.Errorf("Case %d: expected order %v, found %v", n, c.expectedOrder, actual)
+		}
+		if actual := deferredNames(deferred); !slices.Equal(actual, c.expectedDeferred) {
+			t.Errorf("Case %d: expected deferred foreign keys %v, found %v", n, c.expectedDeferred, actual)
+		}
+
+		// Confirm the order is valid, once deferred foreign keys are excluded
+		created := make(map[string]bool)
+		for _, table := range sorted {
+			for _, fk := range table.ForeignKeys {
+				isDeferred := slices.ContainsFunc(deferred, func(dfk DeferredForeignKey) bool { return dfk.ForeignKey == fk })
+				if ref := fk.ReferencedTableName; !isDeferred && fk.ReferencedSchemaName == "" && ref != table.Name && ref != "missing" && !created[ref] {
+					t.Errorf("Case %d: table %s created before referenced table %s", n, table.Name, ref)
+				}
+			}
+			created[table.Name] = true
+		}
+	}
+
+	a, b := makeTable("a", "b"), makeTable("b", "a")
+	_, deferred := SortTablesForCreate([]*Table{a, b})
+	expected := "ALTER TABLE `a` ADD CONSTRAINT `a_b` FOREIGN KEY (`id`) REFERENCES `b` (`id`)"
+	if stmt := deferred[0].Statement(FlavorUnknown); stmt != expected {
+		t.Errorf("Unexpected DeferredForeignKey statement: expected %q, found %q", expected, stmt)
+	}
+}
+
 func TestTableAlterAddOrDropForeignKey(t *testing.T) {
 	from := anotherTable()
 	to := anotherTable()
@@ -1038,6 +1305,89 @@ func TestTableAlterModifyColumn(t *testing.T) {
 	}
 }
 
+func TestTableAlterInvisibleColumnOrder(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0")
+	from, to := aTableForFlavor(flavor, 1), aTableForFlavor(flavor, 1)
+	from.Columns[4].Invisible = true
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+
+	// Move invisible column ssn to be after actor_id, drop alive, and add a new
+	// column age after last_name
+	// FROM: actor_id, first_name, last_name, last_update, ssn, alive, alive_bit
+	// TO:   actor_id, ssn, first_name, last_name, age, last_update, alive_bit
+	ssn := *to.Columns[4]
+	ssn.Invisible = true
+	age := &Column{Name: "age", Type: ParseColumnType("int unsigned"), Nullable: true, Default: "NULL"}
+	to.Columns = []*Column{to.Columns[0], &ssn, to.Columns[1], to.Columns[2], age, to.Columns[3], to.Columns[6]}
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	td := NewAlterTable(&from, &to)
+	mods := StatementModifiers{Flavor: flavor, AllowUnsafe: true}
+	expected := "ALTER TABLE `actor` DROP COLUMN `alive`, MODIFY COLUMN `ssn` char(10) NOT NULL /*!80023 INVISIBLE */ AFTER `actor_id`, ADD COLUMN `age` int unsigned DEFAULT NULL AFTER `last_name`"
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected result from Statement:\nexpected %s\nfound    %s (err=%v)", expected, stmt, err)
+	}
+
+	// With LaxColumnOrder, the move is omitted, but the add and drop are not
+	mods.LaxColumnOrder = true
+	expected = "ALTER TABLE `actor` DROP COLUMN `alive`, ADD COLUMN `age` int unsigned DEFAULT NULL AFTER `last_name`"
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected result from Statement with LaxColumnOrder:\nexpected %s\nfound    %s (err=%v)", expected, stmt, err)
+	}
+
+	// Toggling visibility is still emitted with LaxColumnOrder, retaining the
+	// position clause
+	ssn.Invisible = false
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	td = NewAlterTable(&from, &to)
+	expected = "ALTER TABLE `actor` DROP COLUMN `alive`, MODIFY COLUMN `ssn` char(10) NOT NULL AFTER `actor_id`, ADD COLUMN `age` int unsigned DEFAULT NULL AFTER `last_name`"
+	if stmt, err := td.Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected result from Statement with LaxColumnOrder:\nexpected %s\nfound    %s (err=%v)", expected, stmt, err)
+	}
+}
+
+func TestTableAlterModifyColumnNullability(t *testing.T) {
+	assertNullabilityChange := func(from, to *Table, expectClause string, expectUnsafe bool) {
+		t.Helper()
+		to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+		tableAlters, supported := from.Diff(to)
+		if len(tableAlters) != 1 || !supported {
+			t.Fatalf("Incorrect number of table alters: expected 1, found %d", len(tableAlters))
+		}
+		mc, ok := tableAlters[0].(ModifyColumn)
+		if !ok {
+			t.Fatalf("Incorrect type of table alter returned: expected %T, found %T", mc, tableAlters[0])
+		}
+		mods := StatementModifiers{}
+		if clause := mc.Clause(mods); clause != expectClause {
+			t.Errorf("Unexpected clause:\nexpected %s\nfound    %s", expectClause, clause)
+		}
+		if unsafe, reason := mc.Unsafe(mods); unsafe != expectUnsafe {
+			t.Errorf("Expected Unsafe to return %t, instead found %t (reason %q)", expectUnsafe, unsafe, reason)
+		}
+		// Tightening to NOT NULL is also a data loss risk, since non-strict sql_mode
+		// converts existing NULLs to zero values
+		if risk := mc.DataLossRisk(mods); (risk != "") != expectUnsafe {
+			t.Errorf("Expected DataLossRisk to return a risk=%t, instead found %q", expectUnsafe, risk)
+		}
+	}
+
+	// Relaxing a NOT NULL column with a default to NULL is safe, and the MODIFY
+	// keeps the column's type, attributes, and default as-is
+	from, to := aTable(1), aTable(1)
+	to.Columns[5].Nullable = true
+	assertNullabilityChange(&from, &to, "MODIFY COLUMN `alive` tinyint(1) unsigned DEFAULT '1'", false)
+
+	// Tightening the same column back to NOT NULL is unsafe
+	assertNullabilityChange(&to, &from, "MODIFY COLUMN `alive` tinyint(1) unsigned NOT NULL DEFAULT '1'", true)
+
+	// Same for a column with a NULL default, which is removed when tightening
+	from, to = aTable(1), aTable(1)
+	to.Columns[2].Nullable = false
+	to.Columns[2].Default = ""
+	assertNullabilityChange(&from, &to, "MODIFY COLUMN `last_name` varchar(45) NOT NULL", true)
+	assertNullabilityChange(&to, &from, "MODIFY COLUMN `last_name` varchar(45) DEFAULT NULL", false)
+}
+
 func TestTableAlterNoModify(t *testing.T) {
 	// Compare to a table with no common columns, and confirm no MODIFY clauses
 	// present
@@ -1274,8 +1624,8 @@ func TestTableAlterChangeCharSet(t *testing.T) {
 	assertChangeCharSet(&from, &to, "")
 
 	to = getTableWithCharSet("utf8mb4", "utf8mb4_swedish_ci")
-	assertChangeCharSet(&from, &to, "DEFAULT CHARACTER SET = utf8mb4 COLLATE = utf8mb4_swedish_ci")
-	assertChangeCharSet(&to, &from, "DEFAULT CHARACTER SET = utf8mb4 COLLATE = utf8mb4_general_ci")
+	assertChangeCharSet(&from, &to, "DEFAULT COLLATE = utf8mb4_swedish_ci")
+	assertChangeCharSet(&to, &from, "DEFAULT COLLATE = utf8mb4_general_ci")
 
 	to = getTableWithCharSet("latin1", "latin1_swedish_ci")
 	assertChangeCharSet(&from, &to, "DEFAULT CHARACTER SET = latin1 COLLATE = latin1_swedish_ci")
@@ -1299,6 +1649,224 @@ func TestTableAlterChangeCharSet(t *testing.T) {
 	assertChangeCharSet(&from, &to, "DEFAULT CHARACTER SET = utf8mb4 COLLATE = utf8mb4_general_ci")
 }
 
+// TestTableAlterCharSetInheritance confirms that changing a table's default
+// charset results in modifications to columns which inherit the default, but
+// not to columns which explicitly retain their old charset.
+func TestTableAlterCharSetInheritance(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	from, to := aTableForFlavor(flavor, 1), aTableForFlavor(flavor, 1)
+	to.CharSet, to.Collation = "utf8mb4", "utf8mb4_0900_ai_ci"
+	for _, col := range to.Columns {
+		if col.CharSet == "" {
+			continue
+		}
+		if col.Name == "ssn" { // explicitly retain old charset
+			col.ShowCharSet, col.ShowCollation = true, true
+		} else { // inherit new default
+			col.CharSet, col.Collation = to.CharSet, to.Collation
+		}
+	}
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	if !strings.Contains(to.CreateStatement, "`ssn` char(10) CHARACTER SET utf8mb3") || strings.Contains(to.CreateStatement, "`first_name` varchar(45) CHARACTER SET") {
+		t.Fatalf("Test setup did not generate expected CREATE TABLE; found:\n%s", to.CreateStatement)
+	}
+
+	alter := NewAlterTable(&from, &to)
+	if alter == nil || !alter.supported {
+		t.Fatal("Expected supported diff, but it was not")
+	}
+	stmt, err := alter.Statement(StatementModifiers{Flavor: flavor, AllowUnsafe: true})
+	if err != nil {
+		t.Fatalf("Unexpected error from Statement: %v", err)
+	}
+	expected := "ALTER TABLE `actor` DEFAULT CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci, MODIFY COLUMN `first_name` varchar(45) NOT NULL, MODIFY COLUMN `last_name` varchar(45) DEFAULT NULL"
+	if stmt != expected {
+		t.Errorf("Unexpected statement.\nExpected: %s\nFound:    %s", expected, stmt)
+	}
+
+	// Without AllowUnsafe, the inherited charset change must be flagged unsafe
+	if _, err := alter.Statement(StatementModifiers{Flavor: flavor}); !IsUnsafeDiff(err) {
+		t.Errorf("Expected unsafe error, instead found %v", err)
+	}
+
+	// Reverting back to the original default should likewise modify the
+	// inheriting columns, and the previously-explicit column should no longer
+	// need any change
+	alter = NewAlterTable(&to, &from)
+	stmt, _ = alter.Statement(StatementModifiers{Flavor: flavor, AllowUnsafe: true})
+	if !strings.Contains(stmt, "MODIFY COLUMN `first_name`") || !strings.Contains(stmt, "MODIFY COLUMN `last_name`") || strings.Contains(stmt, "`ssn`") {
+		t.Errorf("Unexpected statement: %s", stmt)
+	}
+}
+
+// TestTableAlterDefaultCollationOnly confirms that changing only a table's default
+// collation emits a DEFAULT COLLATE clause, without modifying columns which
+// retain their existing collation.
+func TestTableAlterDefaultCollationOnly(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	from, to := aTableForFlavor(flavor, 1), aTableForFlavor(flavor, 1)
+	to.Collation, to.ShowCollation = "utf8mb3_unicode_ci", true
+	for _, col := range to.Columns {
+		if col.CharSet != "" { // explicitly retain old collation
+			col.ShowCollation = true
+		}
+	}
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	if !strings.Contains(to.CreateStatement, "`ssn` char(10) COLLATE utf8mb3_general_ci") {
+		t.Fatalf("Test setup did not generate expected CREATE TABLE; found:\n%s", to.CreateStatement)
+	}
+
+	// Running the test in both directions covers columns which switch between
+	// explicitly using a collation and inheriting that same collation
+	for _, tables := range [][2]*Table{{&from, &to}, {&to, &from}} {
+		alter := NewAlterTable(tables[0], tables[1])
+		if alter == nil || !alter.supported {
+			t.Fatal("Expected supported diff, but it was not")
+		}
+		stmt, err := alter.Statement(StatementModifiers{Flavor: flavor})
+		if expected := "ALTER TABLE `actor` DEFAULT COLLATE = " + tables[1].Collation; stmt != expected || err != nil {
+			t.Errorf("Unexpected return from Statement: %q / %v", stmt, err)
+		}
+	}
+}
+
+// TestTableAlterCharSetImplicit confirms that a column lacking an explicit
+// charset and collation is treated as using its table's default.
+func TestTableAlterCharSetImplicit(t *testing.T) {
+	from, to := aTable(1), aTable(1)
+	for _, col := range to.Columns {
+		if col.CharSet == to.CharSet {
+			col.CharSet, col.Collation = "", ""
+		}
+	}
+	to.CreateStatement = ""
+	if tableAlters, supported := from.Diff(&to); len(tableAlters) != 0 || !supported {
+		t.Errorf("Expected no differences, instead found %d alters / supported=%t", len(tableAlters), supported)
+	}
+	if tableAlters, supported := to.Diff(&from); len(tableAlters) != 0 || !supported {
+		t.Errorf("Expected no differences, instead found %d alters / supported=%t", len(tableAlters), supported)
+	}
+
+	// If the inheriting column's table has a different default, the column's
+	// effective charset differs
+	to.CharSet, to.Collation = "latin1", "latin1_swedish_ci"
+	alter := NewAlterTable(&from, &to)
+	stmt, _ := alter.Statement(StatementModifiers{AllowUnsafe: true})
+	if !strings.Contains(stmt, "MODIFY COLUMN `first_name`") {
+		t.Errorf("Expected first_name to be modified, instead found statement %q", stmt)
+	}
+}
+
+// TestTableAlterConvertCharSet confirms that StatementModifiers.PreferConvertCharSet
+// results in use of CONVERT TO CHARACTER SET, but only when all text columns
+// inherit the table's default.
+func TestTableAlterConvertCharSet(t *testing.T) {
+	getTables := func() (from, to Table) {
+		from, to = aTable(1), aTable(1)
+		to.CharSet, to.Collation = "utf8mb4", "utf8mb4_general_ci"
+		for _, col := range to.Columns {
+			if col.CharSet != "" {
+				col.CharSet, col.Collation = to.CharSet, to.Collation
+			}
+		}
+		to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+		return from, to
+	}
+	from, to := getTables()
+	alter := NewAlterTable(&from, &to)
+	if len(alter.alterClauses) == 0 {
+		t.Fatal("Expected clauses, but found none")
+	} else if _, ok := alter.alterClauses[0].(ConvertCharSet); !ok {
+		t.Fatalf("Expected first clause to be ConvertCharSet, instead found %T", alter.alterClauses[0])
+	}
+
+	// Default behavior is unchanged: DEFAULT CHARACTER SET with column modifications
+	mods := StatementModifiers{AllowUnsafe: true}
+	stmt, err := alter.Statement(mods)
+	if err != nil || !strings.HasPrefix(stmt, "ALTER TABLE `actor` DEFAULT CHARACTER SET = utf8mb4 COLLATE = utf8mb4_general_ci, MODIFY COLUMN `first_name`") {
+		t.Errorf("Unexpected return from Statement: %q / %v", stmt, err)
+	}
+
+	if summary := alter.alterClauses[0].Summary(mods); summary.Type != "ChangeCharSet" || summary.Unsafe {
+		t.Errorf("Expected summary to match ChangeCharSet, instead found %+v", summary)
+	}
+
+	// With PreferConvertCharSet, a single CONVERT TO clause is used, and it is
+	// considered unsafe
+	mods.PreferConvertCharSet = true
+	stmt, err = alter.Statement(mods)
+	if expected := "ALTER TABLE `actor` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci"; stmt != expected || err != nil {
+		t.Errorf("Unexpected return from Statement: %q / %v", stmt, err)
+	}
+	if summary := alter.alterClauses[0].Summary(mods); summary.Type != "ConvertCharSet" || !summary.Unsafe {
+		t.Errorf("Expected summary to reflect ConvertCharSet, instead found %+v", summary)
+	}
+	mods.AllowUnsafe = false
+	if _, err := alter.Statement(mods); !IsUnsafeDiff(err) {
+		t.Errorf("Expected unsafe error, instead found %v", err)
+	}
+	mods.AllowUnsafe = true
+
+	// Columns with other changes are still modified alongside CONVERT TO
+	to.Columns[1].Type = ParseColumnType("varchar(50)")
+	to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+	stmt, _ = NewAlterTable(&from, &to).Statement(mods)
+	if expected := "ALTER TABLE `actor` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci, MODIFY COLUMN `first_name` varchar(50) NOT NULL"; stmt != expected {
+		t.Errorf("Unexpected statement.\nExpected: %s\nFound:    %s", expected, stmt)
+	}
+
+	// If any column has an explicit non-default charset, or a TEXT column would be
+	// converted to a larger type, fall back to per-column modifications
+	for _, mutate := range []func(*Table, *Table){
+		func(from, to *Table) {
+			to.Columns[4].CharSet, to.Columns[4].Collation, to.Columns[4].ShowCharSet = "latin1", "latin1_swedish_ci", true
+		},
+		func(from, to *Table) {
+			from.Columns[4].Type, to.Columns[4].Type = ParseColumnType("text"), ParseColumnType("text")
+		},
+	} {
+		from, to := getTables()
+		mutate(&from, &to)
+		from.CreateStatement = from.GeneratedCreateStatement(FlavorUnknown)
+		to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+		alter := NewAlterTable(&from, &to)
+		if _, ok := alter.alterClauses[0].(ChangeCharSet); !ok {
+			t.Errorf("Expected first clause to be ChangeCharSet, instead found %T", alter.alterClauses[0])
+		}
+		if stmt, _ := alter.Statement(mods); strings.Contains(stmt, "CONVERT TO") || !strings.Contains(stmt, "MODIFY COLUMN") {
+			t.Errorf("Unexpected statement: %s", stmt)
+		}
+	}
+}
+
+// TestTableAlterUTF8Alias confirms that a table using the "utf8" charset alias
+// throughout does not generate any DDL when compared to the same table
+// introspected from a flavor which reports "utf8mb3" instead.
+func TestTableAlterUTF8Alias(t *testing.T) {
+	oldFlavor, newFlavor := ParseFlavor("mysql:5.7"), ParseFlavor("mysql:8.0.32")
+	from, to := aTableForFlavor(oldFlavor, 1), aTableForFlavor(newFlavor, 1)
+	if from.CharSet != "utf8" || to.CharSet != "utf8mb3" || from.Columns[1].CharSet != "utf8" || to.Columns[1].CharSet != "utf8mb3" {
+		t.Fatal("Test assumptions about aTableForFlavor charsets are incorrect")
+	}
+	for _, pair := range [][2]*Table{{&from, &to}, {&to, &from}} {
+		alter := NewAlterTable(pair[0], pair[1])
+		if alter == nil {
+			continue
+		}
+		for _, flavor := range []Flavor{oldFlavor, newFlavor} {
+			mods := StatementModifiers{Flavor: flavor}
+			if stmt, err := alter.Statement(mods); stmt != "" || err != nil {
+				t.Errorf("Expected no DDL for flavor %s, instead found %q, err=%v", flavor, stmt, err)
+			}
+		}
+	}
+
+	// Normalization should only affect comparison, not rendering
+	if from.GeneratedCreateStatement(oldFlavor) != from.CreateStatement || to.GeneratedCreateStatement(newFlavor) != to.CreateStatement {
+		t.Error("Expected GeneratedCreateStatement to retain each flavor's charset naming")
+	}
+}
+
 func TestTableAlterChangeCreateOptions(t *testing.T) {
 	getTableWithCreateOptions := func(createOptions string) Table {
 		t := aTable(1)
@@ -1360,6 +1928,462 @@ func TestTableAlterChangeCreateOptions(t *testing.T) {
 	to = getTableWithCreateOptions("STATS_AUTO_RECALC=1 ROW_FORMAT=DYNAMIC AVG_ROW_LENGTH=200")
 	assertChangeCreateOptions(&from, &to, "STATS_AUTO_RECALC=1 ROW_FORMAT=DYNAMIC STATS_PERSISTENT=DEFAULT MAX_ROWS=0")
 	assertChangeCreateOptions(&to, &from, "STATS_AUTO_RECALC=DEFAULT ROW_FORMAT=REDUNDANT STATS_PERSISTENT=1 MAX_ROWS=1000")
+
+	// MariaDB encryption, including rekey operations
+	from = getTableWithCreateOptions("")
+	to = getTableWithCreateOptions("`ENCRYPTED`=YES `ENCRYPTION_KEY_ID`=2")
+	assertChangeCreateOptions(&from, &to, "`ENCRYPTED`=YES `ENCRYPTION_KEY_ID`=2")
+	assertChangeCreateOptions(&to, &from, "`ENCRYPTED`=DEFAULT `ENCRYPTION_KEY_ID`=DEFAULT")
+	from = getTableWithCreateOptions("`encrypted`=YES `encryption_key_id`=1")
+	assertChangeCreateOptions(&from, &to, "`ENCRYPTION_KEY_ID`=2")
+	assertChangeCreateOptions(&to, &from, "`ENCRYPTION_KEY_ID`=1")
+
+	// Page compression, which uses entirely different syntax in MySQL vs MariaDB
+	from = getTableWithCreateOptions("")
+	to = getTableWithCreateOptions("COMPRESSION='zlib'")
+	assertChangeCreateOptions(&from, &to, "COMPRESSION='zlib'")
+	assertChangeCreateOptions(&to, &from, "COMPRESSION=''")
+	from = getTableWithCreateOptions("COMPRESSION='lz4'")
+	assertChangeCreateOptions(&from, &to, "COMPRESSION='zlib'")
+	from = getTableWithCreateOptions("")
+	to = getTableWithCreateOptions("`PAGE_COMPRESSED`=1 `PAGE_COMPRESSION_LEVEL`=9")
+	assertChangeCreateOptions(&from, &to, "`PAGE_COMPRESSED`=1 `PAGE_COMPRESSION_LEVEL`=9")
+	assertChangeCreateOptions(&to, &from, "`PAGE_COMPRESSED`=DEFAULT `PAGE_COMPRESSION_LEVEL`=DEFAULT")
+	from = getTableWithCreateOptions("`page_compressed`=1 `page_compression_level`=3")
+	assertChangeCreateOptions(&from, &to, "`PAGE_COMPRESSION_LEVEL`=9")
+
+	// MySQL encryption, where removing the option entirely requires an explicit
+	// ENCRYPTION='N'
+	from = getTableWithCreateOptions("")
+	to = getTableWithCreateOptions("ENCRYPTION='Y'")
+	assertChangeCreateOptions(&from, &to, "ENCRYPTION='Y'")
+	assertChangeCreateOptions(&to, &from, "ENCRYPTION='N'")
+	from = getTableWithCreateOptions("ENCRYPTION='N'")
+	assertChangeCreateOptions(&from, &to, "ENCRYPTION='Y'")
+	assertChangeCreateOptions(&to, &from, "ENCRYPTION='N'")
+
+	// An explicit ENCRYPTION='N' is equivalent to the option being absent in
+	// both directions, but only if the flavor is known and the server does not
+	// encrypt tables by default. Otherwise, explicitly disabling encryption is
+	// distinct from inheriting the default.
+	to = getTableWithCreateOptions("")
+	mysql8 := ParseFlavor("mysql:8.0")
+	for _, td := range []*TableDiff{NewAlterTable(&from, &to), NewAlterTable(&to, &from)} {
+		if stmt, err := td.Statement(StatementModifiers{Flavor: mysql8}); stmt != "" || err != nil {
+			t.Errorf("Expected ENCRYPTION='N' to be equivalent to absent, instead found statement %q / %v", stmt, err)
+		}
+		for _, mods := range []StatementModifiers{{}, {Flavor: mysql8, DefaultTableEncryption: true}} {
+			if stmt, err := td.Statement(mods); !strings.Contains(stmt, "ENCRYPTION='N'") || err != nil {
+				t.Errorf("Expected ENCRYPTION='N' to be distinct from absent with %+v, instead found statement %q / %v", mods, stmt, err)
+			}
+		}
+	}
+
+	// Storage sizing options, including normalization of explicit zero values
+	from = getTableWithCreateOptions("MAX_ROWS=1000000 AVG_ROW_LENGTH=500")
+	to = getTableWithCreateOptions("MAX_ROWS=1000000 AVG_ROW_LENGTH=300")
+	assertChangeCreateOptions(&from, &to, "AVG_ROW_LENGTH=300")
+	to = getTableWithCreateOptions("MAX_ROWS=1000000 AVG_ROW_LENGTH=0")
+	assertChangeCreateOptions(&from, &to, "AVG_ROW_LENGTH=0")
+	cco := ChangeCreateOptions{NewCreateOptions: "AVG_ROW_LENGTH=0 MAX_ROWS=0"}
+	if clause := cco.Clause(StatementModifiers{}); clause != "" {
+		t.Errorf("Expected explicit zero values to be equivalent to unset, instead found clause %q", clause)
+	}
+
+	// Persistent statistics options, where an explicit DEFAULT is equivalent to
+	// the option being absent
+	cco = ChangeCreateOptions{
+		OldCreateOptions: "STATS_PERSISTENT=DEFAULT STATS_AUTO_RECALC=DEFAULT",
+		NewCreateOptions: "STATS_SAMPLE_PAGES=default",
+	}
+	if clause := cco.Clause(StatementModifiers{}); clause != "" {
+		t.Errorf("Expected explicit DEFAULT values to be equivalent to unset, instead found clause %q", clause)
+	}
+	from = getTableWithCreateOptions("")
+	to = getTableWithCreateOptions("STATS_PERSISTENT=0 STATS_AUTO_RECALC=1 STATS_SAMPLE_PAGES=40")
+	assertChangeCreateOptions(&from, &to, "STATS_PERSISTENT=0 STATS_AUTO_RECALC=1 STATS_SAMPLE_PAGES=40")
+	assertChangeCreateOptions(&to, &from, "STATS_PERSISTENT=DEFAULT STATS_AUTO_RECALC=DEFAULT STATS_SAMPLE_PAGES=DEFAULT")
+	from = getTableWithCreateOptions("STATS_PERSISTENT=1 STATS_AUTO_RECALC=1 STATS_SAMPLE_PAGES=DEFAULT")
+	assertChangeCreateOptions(&from, &to, "STATS_PERSISTENT=0 STATS_SAMPLE_PAGES=40")
+
+	// KEY_BLOCK_SIZE implies ROW_FORMAT=COMPRESSED for InnoDB, but only if
+	// KEY_BLOCK_SIZE is present on both sides
+	from = getTableWithCreateOptions("ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8")
+	to = getTableWithCreateOptions("KEY_BLOCK_SIZE=4")
+	assertChangeCreateOptions(&from, &to, "KEY_BLOCK_SIZE=4")
+	assertChangeCreateOptions(&to, &from, "KEY_BLOCK_SIZE=8")
+	to = getTableWithCreateOptions("")
+	assertChangeCreateOptions(&from, &to, "ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0")
+	for _, cco := range []ChangeCreateOptions{
+		{OldCreateOptions: "ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8", NewCreateOptions: "KEY_BLOCK_SIZE=8", innoDB: true},
+		{OldCreateOptions: "KEY_BLOCK_SIZE=8", NewCreateOptions: "ROW_FORMAT=compressed KEY_BLOCK_SIZE=8", innoDB: true},
+	} {
+		if clause := cco.Clause(StatementModifiers{}); clause != "" {
+			t.Errorf("Expected implied ROW_FORMAT=COMPRESSED to be equivalent to explicit, instead found clause %q", clause)
+		}
+		cco.innoDB = false
+		if clause := cco.Clause(StatementModifiers{}); clause == "" {
+			t.Error("Expected ROW_FORMAT difference to be retained for non-InnoDB table, but clause was empty")
+		}
+	}
+	cco = ChangeCreateOptions{OldCreateOptions: "KEY_BLOCK_SIZE=8", NewCreateOptions: "ROW_FORMAT=COMPRESSED", innoDB: true}
+	if clause := cco.Clause(StatementModifiers{}); clause == "" {
+		t.Error("Expected clause when KEY_BLOCK_SIZE is only present on one side, but clause was empty")
+	}
+}
+
+func TestTableSystemVersioning(t *testing.T) {
+	flavor := ParseFlavor("mariadb:10.6")
+	getTable := func(versioned, explicitPeriod bool) *Table {
+		table := &Table{
+			Name:      "versioned",
+			Engine:    "InnoDB",
+			CharSet:   "latin1",
+			Collation: "latin1_swedish_ci",
+			Columns: []*Column{
+				{Name: "id", Type: ParseColumnType("int(10) unsigned")},
+			},
+			SystemVersioned: versioned,
+		}
+		table.PrimaryKey = primaryKey(table.Columns[0])
+		if explicitPeriod {
+			table.Columns = append(table.Columns,
+				&Column{Name: "row_start", Type: ParseColumnType("timestamp(6)"), Invisible: true, SystemTime: "ROW START"},
+				&Column{Name: "row_end", Type: ParseColumnType("timestamp(6)"), Invisible: true, SystemTime: "ROW END"},
+			)
+		}
+		table.CreateStatement = table.GeneratedCreateStatement(flavor)
+		return table
+	}
+
+	explicit := getTable(true, true)
+	expected := "CREATE TABLE `versioned` (\n" +
+		"  `id` int(10) unsigned NOT NULL,\n" +
+		"  `row_start` timestamp(6) GENERATED ALWAYS AS ROW START INVISIBLE,\n" +
+		"  `row_end` timestamp(6) GENERATED ALWAYS AS ROW END INVISIBLE,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  PERIOD FOR SYSTEM_TIME (`row_start`, `row_end`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1 WITH SYSTEM VERSIONING"
+	if actual := explicit.CreateStatement; actual != expected {
+		t.Errorf("Unexpected GeneratedCreateStatement: expected\n%s\nfound\n%s", expected, actual)
+	}
+	if rowStart, rowEnd := explicit.SystemTimePeriod(); rowStart != explicit.Columns[1] || rowEnd != explicit.Columns[2] {
+		t.Errorf("Unexpected return from SystemTimePeriod: %v, %v", rowStart, rowEnd)
+	}
+	implicit := getTable(true, false)
+	if rowStart, rowEnd := implicit.SystemTimePeriod(); rowStart != nil || rowEnd != nil {
+		t.Errorf("Unexpected return from SystemTimePeriod: %v, %v", rowStart, rowEnd)
+	}
+	unversioned := getTable(false, false)
+	mods := StatementModifiers{Flavor: flavor}
+
+	// Adding and dropping system versioning with implicit period columns
+	clauses, supported := unversioned.Diff(implicit)
+	if len(clauses) != 1 || !supported {
+		t.Fatalf("Unexpected return from Diff: %d clauses, supported=%t", len(clauses), supported)
+	} else if clause := clauses[0].Clause(mods); clause != "ADD SYSTEM VERSIONING" {
+		t.Errorf("Unexpected clause: %q", clause)
+	} else if unsafe, _ := clauses[0].(Unsafer).Unsafe(mods); unsafe {
+		t.Error("Expected adding system versioning to be safe, but it was not")
+	}
+	clauses, supported = implicit.Diff(unversioned)
+	if len(clauses) != 1 || !supported {
+		t.Fatalf("Unexpected return from Diff: %d clauses, supported=%t", len(clauses), supported)
+	} else if clause := clauses[0].Clause(mods); clause != "DROP SYSTEM VERSIONING" {
+		t.Errorf("Unexpected clause: %q", clause)
+	} else if unsafe, _ := clauses[0].(Unsafer).Unsafe(mods); !unsafe {
+		t.Error("Expected dropping system versioning to be unsafe, but it was not")
+	}
+
+	// Adding system versioning along with new explicit period columns
+	td := NewAlterTable(unversioned, explicit)
+	stmt, err := td.Statement(mods)
+	expectedStmt := "ALTER TABLE `versioned` ADD COLUMN `row_start` timestamp(6) GENERATED ALWAYS AS ROW START INVISIBLE, ADD COLUMN `row_end` timestamp(6) GENERATED ALWAYS AS ROW END INVISIBLE, ADD PERIOD FOR SYSTEM_TIME(`row_start`, `row_end`), ADD SYSTEM VERSIONING"
+	if err != nil || stmt != expectedStmt {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if summaries := td.ClauseSummaries(mods); len(summaries) != 4 || summaries[2].Type != "AddSystemTimePeriod" || summaries[3].Type != "ChangeSystemVersioning" {
+		t.Errorf("Expected period and versioning to be separate clauses, instead found %+v", summaries)
+	}
+
+	// Altering the structure of an already-versioned table requires keeping the
+	// existing history, but adding or dropping versioning itself does not
+	widened := getTable(true, false)
+	widened.Columns = append(widened.Columns, &Column{
+		Name:     "name",
+		Type:     ParseColumnType("varchar(30)"),
+		Nullable: true,
+		Default:  "NULL",
+	})
+	widened.CreateStatement = widened.GeneratedCreateStatement(flavor)
+	td = NewAlterTable(implicit, widened)
+	if stmt, err := td.Statement(mods); err != nil || stmt != "SET STATEMENT system_versioning_alter_history=KEEP FOR ALTER TABLE `versioned` ADD COLUMN `name` varchar(30) DEFAULT NULL" {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if clauses, err := td.Clauses(mods); err != nil || clauses != "ADD COLUMN `name` varchar(30) DEFAULT NULL" {
+		t.Errorf("Unexpected return from Clauses: %q, %v", clauses, err)
+	}
+	if stmt, _ := NewAlterTable(implicit, unversioned).Statement(StatementModifiers{Flavor: flavor, AllowUnsafe: true}); stmt != "ALTER TABLE `versioned` DROP SYSTEM VERSIONING" {
+		t.Errorf("Unexpected return from Statement: %q", stmt)
+	}
+
+	// Dropping system versioning from a table with explicit period columns is
+	// not supported yet, nor is switching between implicit and explicit columns
+	if _, supported := explicit.Diff(unversioned); supported {
+		t.Error("Expected diff dropping versioning with explicit period columns to be unsupported")
+	} else if reasons := NewAlterTable(explicit, unversioned).UnsupportedReasons(); len(reasons) != 1 || !strings.Contains(reasons[0], "removing system versioning") {
+		t.Errorf("Unexpected return from UnsupportedReasons: %q", reasons)
+	}
+	if _, supported := implicit.Diff(explicit); supported {
+		t.Error("Expected diff from implicit to explicit period columns to be unsupported")
+	} else if reasons := NewAlterTable(implicit, explicit).UnsupportedReasons(); len(reasons) != 1 || !strings.Contains(reasons[0], "explicit row start/end columns of a system-versioned table") {
+		t.Errorf("Unexpected return from UnsupportedReasons: %q", reasons)
+	}
+}
+
+func TestTableApplicationPeriod(t *testing.T) {
+	flavor := ParseFlavor("mariadb:10.6")
+	table := periodTable(flavor)
+	if actual := table.GeneratedCreateStatement(flavor); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match expectation:\n%s\n%s", actual, table.CreateStatement)
+	}
+
+	// Adding the period along with a WITHOUT OVERLAPS index: period must be added
+	// first. Dropping them: period must be dropped last.
+	noPeriod := periodTable(flavor)
+	noPeriod.ApplicationPeriod = nil
+	noPeriod.PrimaryKey = primaryKey(noPeriod.Columns[0])
+	noPeriod.CreateStatement = noPeriod.GeneratedCreateStatement(flavor)
+	cases := []struct {
+		from, to *Table
+		expected string
+	}{
+		{&noPeriod, &table, "ALTER TABLE `rooms` ADD PERIOD FOR `stay` (`checkin`, `checkout`), DROP PRIMARY KEY, ADD PRIMARY KEY (`room_id`,`stay` WITHOUT OVERLAPS)"},
+		{&table, &noPeriod, "ALTER TABLE `rooms` DROP PRIMARY KEY, ADD PRIMARY KEY (`room_id`), DROP PERIOD FOR `stay`"},
+	}
+	mods := StatementModifiers{Flavor: flavor}
+	for _, c := range cases {
+		tableDiff := NewAlterTable(c.from, c.to)
+		if tableDiff == nil || !tableDiff.supported {
+			t.Fatalf("Expected supported diff, instead found %+v", tableDiff)
+		}
+		if stmt, err := tableDiff.Statement(mods); err != nil || stmt != c.expected {
+			t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+		}
+	}
+
+	// Only the WITHOUT OVERLAPS attribute changing: index must be rebuilt, and
+	// is not equivalent to the index without it
+	plainPK := periodTable(flavor)
+	plainPK.PrimaryKey = primaryKey(plainPK.Columns[0])
+	plainPK.CreateStatement = plainPK.GeneratedCreateStatement(flavor)
+	if table.PrimaryKey.Equivalent(plainPK.PrimaryKey) || table.PrimaryKey.RedundantTo(plainPK.PrimaryKey) {
+		t.Error("Expected WITHOUT OVERLAPS to affect index equivalence, but it did not")
+	}
+	if clauses, supported := table.Diff(&plainPK); len(clauses) != 2 || !supported {
+		t.Errorf("Unexpected return from Diff: %+v, %t", clauses, supported)
+	}
+
+	// Modifying an existing period is not supported
+	changedPeriod := periodTable(flavor)
+	changedPeriod.ApplicationPeriod.EndColumn = "checkin"
+	changedPeriod.ApplicationPeriod.StartColumn = "checkout"
+	changedPeriod.CreateStatement = changedPeriod.GeneratedCreateStatement(flavor)
+	if _, supported := table.Diff(&changedPeriod); supported {
+		t.Error("Expected modification of application-time period to be unsupported, but it was supported")
+	}
+	expectedReasons := []string{"modifying application-time period `stay` in-place is not supported"}
+	if reasons := NewAlterTable(&table, &changedPeriod).UnsupportedReasons(); !slices.Equal(reasons, expectedReasons) {
+		t.Errorf("Unexpected return from UnsupportedReasons: %q", reasons)
+	}
+}
+
+func TestTableAvgRowLength(t *testing.T) {
+	table := aTable(1)
+	if avgRowLength, meaningful := table.AvgRowLength(); avgRowLength != 0 || meaningful {
+		t.Errorf("Unexpected return from AvgRowLength(): %d, %t", avgRowLength, meaningful)
+	}
+	table.CreateOptions = "MAX_ROWS=1000000 AVG_ROW_LENGTH=500"
+	if avgRowLength, meaningful := table.AvgRowLength(); avgRowLength != 500 || meaningful {
+		t.Errorf("Unexpected return from AvgRowLength(): %d, %t", avgRowLength, meaningful)
+	}
+	table.Engine = "MyISAM"
+	if avgRowLength, meaningful := table.AvgRowLength(); avgRowLength != 500 || !meaningful {
+		t.Errorf("Unexpected return from AvgRowLength(): %d, %t", avgRowLength, meaningful)
+	}
+}
+
+func TestTableRowLimits(t *testing.T) {
+	table := aTable(1)
+	if minRows, maxRows := table.RowLimits(); minRows != 0 || maxRows != 0 {
+		t.Errorf("Unexpected return from RowLimits(): %d, %d", minRows, maxRows)
+	}
+	table.Engine = "MEMORY"
+	table.CreateOptions = "MIN_ROWS=100 MAX_ROWS=50000 AVG_ROW_LENGTH=500"
+	if minRows, maxRows := table.RowLimits(); minRows != 100 || maxRows != 50000 {
+		t.Errorf("Unexpected return from RowLimits(): %d, %d", minRows, maxRows)
+	}
+	table.CreateStatement = table.GeneratedCreateStatement(FlavorUnknown)
+	if !strings.HasSuffix(table.CreateStatement, " MIN_ROWS=100 MAX_ROWS=50000 AVG_ROW_LENGTH=500") {
+		t.Errorf("Unexpected CREATE TABLE: %s", table.CreateStatement)
+	}
+
+	// Removing the options should reset them to their defaults
+	other := aTable(1)
+	other.Engine = "MEMORY"
+	other.CreateOptions = "MAX_ROWS=50000 AVG_ROW_LENGTH=500"
+	other.CreateStatement = other.GeneratedCreateStatement(FlavorUnknown)
+	clauses, supported := table.Diff(&other)
+	if !supported || len(clauses) != 1 {
+		t.Fatalf("Unexpected return from Diff: %d clauses, supported=%t", len(clauses), supported)
+	}
+	if clause := clauses[0].Clause(StatementModifiers{}); clause != "MIN_ROWS=0" {
+		t.Errorf("Unexpected clause: %q", clause)
+	}
+}
+
+func TestTableMergeOptions(t *testing.T) {
+	member := anotherTable()
+	member.Engine = "MyISAM"
+	merge := member
+	merge.Name = "log_all"
+	merge.Engine = "MRG_MyISAM"
+	merge.MergeOptions = &MergeOptions{InsertMethod: "LAST", Union: []string{"log_2023", "log_2024"}}
+	create := merge.GeneratedCreateStatement(FlavorUnknown)
+	if !strings.HasSuffix(create, ") ENGINE=MRG_MyISAM DEFAULT CHARSET=latin1 INSERT_METHOD=LAST UNION=(`log_2023`,`log_2024`)") {
+		t.Errorf("Unexpected CREATE TABLE: %s", create)
+	}
+
+	// Options are only rendered for MERGE tables
+	member.MergeOptions = merge.MergeOptions
+	if create := member.GeneratedCreateStatement(FlavorUnknown); strings.Contains(create, "UNION") {
+		t.Errorf("Unexpected CREATE TABLE for non-MERGE table: %s", create)
+	}
+
+	merge.CreateStatement = merge.GeneratedCreateStatement(FlavorUnknown)
+	cases := []struct {
+		newOptions *MergeOptions
+		expected   string
+	}{
+		{&MergeOptions{InsertMethod: "LAST", Union: []string{"log_2023", "log_2024"}}, ""},
+		{&MergeOptions{InsertMethod: "FIRST", Union: []string{"log_2023", "log_2024"}}, "INSERT_METHOD=FIRST"},
+		{&MergeOptions{InsertMethod: "LAST", Union: []string{"log_2023", "log_2024", "log_2025"}}, "UNION=(`log_2023`,`log_2024`,`log_2025`)"},
+		{&MergeOptions{Union: []string{"log_2024"}}, "INSERT_METHOD=NO UNION=(`log_2024`)"},
+		{nil, "INSERT_METHOD=NO UNION=()"},
+	}
+	for _, c := range cases {
+		to := merge
+		to.MergeOptions = c.newOptions
+		to.CreateStatement = to.GeneratedCreateStatement(FlavorUnknown)
+		clauses, supported := merge.Diff(&to)
+		if !supported {
+			t.Errorf("Expected diff to %+v to be supported, but it was not", c.newOptions)
+		} else if c.expected == "" && len(clauses) > 0 {
+			t.Errorf("Expected no clauses, instead found %d", len(clauses))
+		} else if c.expected != "" && (len(clauses) != 1 || clauses[0].Clause(StatementModifiers{}) != c.expected) {
+			t.Errorf("Expected clause %q, instead found %+v", c.expected, clauses)
+		}
+	}
+}
+
+func TestTableEncryption(t *testing.T) {
+	cases := map[string]TableEncryption{
+		"":                                      {},
+		"ROW_FORMAT=DYNAMIC":                    {},
+		"ENCRYPTION='Y'":                        {MySQLEncryption: "Y"},
+		"ENCRYPTION='N' ROW_FORMAT=COMPACT":     {MySQLEncryption: "N"},
+		"`ENCRYPTED`=YES":                       {MariaDBEncrypted: "YES"},
+		"`encrypted`=yes `ENCRYPTION_KEY_ID`=2": {MariaDBEncrypted: "YES", MariaDBKeyID: 2},
+		"`ENCRYPTED`=NO":                        {MariaDBEncrypted: "NO"},
+		"`ENCRYPTION_KEY_ID`=33":                {MariaDBKeyID: 33},
+	}
+	for createOptions, expected := range cases {
+		table := aTable(1)
+		table.CreateOptions = createOptions
+		if actual := table.Encryption(); actual != expected {
+			t.Errorf("Unexpected return from Encryption() with create options %q: expected %+v, found %+v", createOptions, expected, actual)
+		}
+		if actual := table.Encryption().Encrypted(); actual != (expected.MySQLEncryption == "Y" || expected.MariaDBEncrypted == "YES") {
+			t.Errorf("Unexpected return from Encrypted() with create options %q: found %t", createOptions, actual)
+		}
+	}
+}
+
+func TestTableStatistics(t *testing.T) {
+	cases := map[string]TableStatistics{
+		"":                                       {},
+		"ROW_FORMAT=DYNAMIC":                     {},
+		"STATS_PERSISTENT=1":                     {Persistent: "1"},
+		"STATS_PERSISTENT=0 STATS_AUTO_RECALC=1": {Persistent: "0", AutoRecalc: "1"},
+		"STATS_PERSISTENT=DEFAULT STATS_AUTO_RECALC=0": {AutoRecalc: "0"},
+		"STATS_SAMPLE_PAGES=100 ROW_FORMAT=COMPACT":    {SamplePages: "100"},
+		"stats_sample_pages=default":                   {},
+	}
+	for createOptions, expected := range cases {
+		table := aTable(1)
+		table.CreateOptions = createOptions
+		if actual := table.Statistics(); actual != expected {
+			t.Errorf("Unexpected return from Statistics() with create options %q: expected %+v, found %+v", createOptions, expected, actual)
+		}
+	}
+}
+
+func TestTablePageCompression(t *testing.T) {
+	cases := map[string]TablePageCompression{
+		"":                                     {},
+		"ROW_FORMAT=DYNAMIC":                   {},
+		"COMPRESSION='zlib'":                   {MySQLAlgorithm: "zlib"},
+		"COMPRESSION='LZ4' STATS_PERSISTENT=1": {MySQLAlgorithm: "lz4"},
+		"COMPRESSION='None'":                   {MySQLAlgorithm: "none"},
+		"`PAGE_COMPRESSED`=1":                  {MariaDBEnabled: true},
+		"`page_compressed`='ON' `PAGE_COMPRESSION_LEVEL`=9": {MariaDBEnabled: true, MariaDBLevel: 9},
+		"`PAGE_COMPRESSED`=0":                               {},
+		"`PAGE_COMPRESSION_LEVEL`=3":                        {MariaDBLevel: 3},
+	}
+	for createOptions, expected := range cases {
+		table := aTable(1)
+		table.CreateOptions = createOptions
+		if actual := table.PageCompression(); actual != expected {
+			t.Errorf("Unexpected return from PageCompression() with create options %q: expected %+v, found %+v", createOptions, expected, actual)
+		}
+	}
+}
+
+func TestTableCreateOptionsOrder(t *testing.T) {
+	mysql, mariadb := ParseFlavor("mysql:8.0"), ParseFlavor("mariadb:10.6")
+	cases := []struct {
+		flavor   Flavor
+		input    string
+		expected string
+	}{
+		{mysql, "", ""},
+		{mysql, "ROW_FORMAT=DYNAMIC", "ROW_FORMAT=DYNAMIC"},
+		{mysql, "ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 STATS_PERSISTENT=1", "STATS_PERSISTENT=1 ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8"},
+		{mysql, "ENCRYPTION='Y' AVG_ROW_LENGTH=500 MAX_ROWS=1000000 CHECKSUM=1", "MAX_ROWS=1000000 AVG_ROW_LENGTH=500 CHECKSUM=1 ENCRYPTION='Y'"},
+		{mysql, "COMPRESSION='zlib' foo=bar STATS_PERSISTENT=1", "COMPRESSION='zlib' foo=bar STATS_PERSISTENT=1"},
+		{mariadb, "TRANSACTIONAL=1 PAGE_CHECKSUM=1 ROW_FORMAT=PAGE", "PAGE_CHECKSUM=1 ROW_FORMAT=PAGE TRANSACTIONAL=1"},
+		{mariadb, "ROW_FORMAT=DYNAMIC `ENCRYPTED`=YES STATS_PERSISTENT=0", "ROW_FORMAT=DYNAMIC `ENCRYPTED`=YES STATS_PERSISTENT=0"},
+		{FlavorUnknown, "ROW_FORMAT=DYNAMIC STATS_PERSISTENT=1", "ROW_FORMAT=DYNAMIC STATS_PERSISTENT=1"},
+	}
+	for _, c := range cases {
+		if actual := orderedCreateOptions(c.input, c.flavor); actual != c.expected {
+			t.Errorf("Unexpected result from orderedCreateOptions(%q, %s): expected %q, found %q", c.input, c.flavor, c.expected, actual)
+		}
+	}
+
+	// Options already in server order must round-trip through
+	// GeneratedCreateStatement unchanged
+	table := aTableForFlavor(mysql, 1)
+	table.CreateOptions = "STATS_PERSISTENT=1 CHECKSUM=1 ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8"
+	table.CreateStatement = table.GeneratedCreateStatement(mysql)
+	if !strings.Contains(table.CreateStatement, " "+table.CreateOptions) {
+		t.Errorf("Expected create options to be preserved as-is, but CREATE is %s", table.CreateStatement)
+	}
+	scrambled := table
+	scrambled.CreateOptions = "KEY_BLOCK_SIZE=8 ROW_FORMAT=COMPRESSED CHECKSUM=1 STATS_PERSISTENT=1"
+	if actual := scrambled.GeneratedCreateStatement(mysql); actual != table.CreateStatement {
+		t.Errorf("Expected scrambled create options to render in server order; instead found %s", actual)
+	}
 }
 
 func TestTableAlterChangeComment(t *testing.T) {
@@ -1416,6 +2440,65 @@ func TestTableAlterChangeComment(t *testing.T) {
 	assertChangeCommentLax(&to, &from, true)
 }
 
+func TestTableAlterEngineAttributes(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0.32")
+	mods := StatementModifiers{Flavor: flavor}
+	from := aTableForFlavor(flavor, 1)
+	to := aTableForFlavor(flavor, 1)
+	assertStatement := func(expected string) {
+		t.Helper()
+		to.CreateStatement = to.GeneratedCreateStatement(flavor)
+		td := NewAlterTable(&from, &to)
+		if td == nil {
+			t.Fatal("Expected NewAlterTable to return a diff, but it returned nil")
+		} else if !td.supported {
+			t.Fatal("Expected diff to be supported, but it was not")
+		}
+		if stmt, err := td.Statement(mods); err != nil {
+			t.Errorf("Unexpected error from Statement: %v", err)
+		} else if stmt != expected {
+			t.Errorf("Unexpected result from Statement\nexpected: %s\nfound:    %s", expected, stmt)
+		}
+	}
+
+	// Table-level changes
+	from.EngineAttribute = `{"a": 1, "b": "x"}`
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	to.EngineAttribute = `{"b":"x","a":1}`
+	assertStatement("") // only differs in JSON formatting and key order
+	to.EngineAttribute = `{"a": 2, "b": "x's"}`
+	assertStatement("ALTER TABLE `actor` ENGINE_ATTRIBUTE='{\"a\": 2, \"b\": \"x''s\"}'")
+	to.EngineAttribute = from.EngineAttribute
+	to.SecondaryEngineAttribute = `{"c": true}`
+	assertStatement("ALTER TABLE `actor` SECONDARY_ENGINE_ATTRIBUTE='{\"c\": true}'")
+	mods.IgnoreTableOptions = []string{"secondary_engine_attribute"}
+	assertStatement("")
+	mods.IgnoreTableOptions = nil
+	mods.Flavor = ParseFlavor("mysql:8.0.20")
+	assertStatement("")
+	mods.Flavor = flavor
+	to.SecondaryEngineAttribute = ""
+
+	// Column-level changes
+	to.Columns[1].EngineAttribute = `{"k": [1, 2]}`
+	assertStatement("ALTER TABLE `actor` MODIFY COLUMN `first_name` varchar(45) NOT NULL /*!80021 ENGINE_ATTRIBUTE '{\"k\": [1, 2]}' */")
+	from.Columns[1].EngineAttribute = `{ "k" : [1,2] }`
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	assertStatement("")
+	from.Columns[1].EngineAttribute = `{"k": [2, 1]}` // array order is significant
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	assertStatement("ALTER TABLE `actor` MODIFY COLUMN `first_name` varchar(45) NOT NULL /*!80021 ENGINE_ATTRIBUTE '{\"k\": [1, 2]}' */")
+	from.Columns[1].EngineAttribute = to.Columns[1].EngineAttribute
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+
+	// Index-level changes
+	to.SecondaryIndexes[0].SecondaryEngineAttribute = `{"z": null}`
+	assertStatement("ALTER TABLE `actor` DROP KEY `idx_ssn`, ADD UNIQUE KEY `idx_ssn` (`ssn`) /*!80021 SECONDARY_ENGINE_ATTRIBUTE '{\"z\": null}' */")
+	from.SecondaryIndexes[0].SecondaryEngineAttribute = `{"z":null}`
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	assertStatement("")
+}
+
 func TestTableAlterTablespace(t *testing.T) {
 	getTableWithTablespace := func(tablespace string) *Table {
 		t := aTable(123)
@@ -1448,6 +2531,26 @@ func TestTableAlterTablespace(t *testing.T) {
 	assertChangeTablespace(explicitFPT, noTablespace, true, "") // no way to remove an explicit tablespace clause, but diff still supported
 	assertChangeTablespace(explicitFPT, explicitFPT, false, "")
 	assertChangeTablespace(explicitFPT, explicitSys, true, "TABLESPACE `innodb_system`")
+
+	// Moving between tablespaces is a rebuild, and is only emitted for flavors
+	// supporting general tablespaces
+	ct := ChangeTablespace{NewTablespace: "ts1"}
+	if !ct.RebuildsTable() {
+		t.Error("Expected ChangeTablespace to rebuild the table, but RebuildsTable returned false")
+	}
+	if (ChangeTablespace{}).RebuildsTable() {
+		t.Error("Expected no-op ChangeTablespace not to rebuild the table, but RebuildsTable returned true")
+	}
+	for flavorStr, expected := range map[string]string{
+		"mysql:5.6":     "",
+		"mysql:5.7":     "TABLESPACE `ts1`",
+		"mysql:8.0":     "TABLESPACE `ts1`",
+		"mariadb:10.11": "",
+	} {
+		if actual := ct.Clause(StatementModifiers{Flavor: ParseFlavor(flavorStr)}); actual != expected {
+			t.Errorf("Flavor %s: expected clause %q, instead found %q", flavorStr, expected, actual)
+		}
+	}
 }
 
 func TestTableAlterUnsupportedTable(t *testing.T) {
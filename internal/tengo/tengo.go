@@ -21,6 +21,9 @@ const (
 	ObjectTypeTable    ObjectType = "table"
 	ObjectTypeProc     ObjectType = "procedure"
 	ObjectTypeFunc     ObjectType = "function"
+	ObjectTypeSequence ObjectType = "sequence"
+	ObjectTypeTrigger  ObjectType = "trigger"
+	ObjectTypeView     ObjectType = "view"
 )
 
 // Caps returns the object type as an uppercase string.
@@ -172,6 +172,9 @@ func flavorTestFiles(flavor Flavor) []string {
 		// other flavors may support FT parsers but don't ship with any alternatives;
 		// other flavors do not support TABLESPACE clauses in InnoDB tables
 		result = append(result, "ft-parser.sql", "inno-tablespace.sql")
+
+		// JSON and spatial subtypes with varying nullability
+		result = append(result, "json.sql")
 	}
 
 	if flavor.IsPercona() && flavor.MinMySQL(5, 6, 33) {
@@ -503,6 +506,59 @@ func supportedTableForFlavor(flavor Flavor) Table {
 	return table
 }
 
+// periodTable returns a table using a MariaDB application-time period, along
+// with a primary key using WITHOUT OVERLAPS.
+func periodTable(flavor Flavor) Table {
+	columns := []*Column{
+		{
+			Name: "room_id",
+			Type: ParseColumnType("int(10) unsigned"),
+		},
+		{
+			Name: "checkin",
+			Type: ParseColumnType("date"),
+		},
+		{
+			Name: "checkout",
+			Type: ParseColumnType("date"),
+		},
+	}
+	pk := primaryKey(columns[0])
+	pk.WithoutOverlaps = "stay"
+	secondaryIndex := &Index{
+		Name:  "checkin",
+		Parts: []IndexPart{{ColumnName: "checkin"}},
+		Type:  "BTREE",
+	}
+	stmt := "CREATE TABLE `rooms` (\n" +
+		"  `room_id` int(10) unsigned NOT NULL,\n" +
+		"  `checkin` date NOT NULL,\n" +
+		"  `checkout` date NOT NULL,\n" +
+		"  PRIMARY KEY (`room_id`,`stay` WITHOUT OVERLAPS),\n" +
+		"  KEY `checkin` (`checkin`),\n" +
+		"  PERIOD FOR `stay` (`checkin`, `checkout`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1"
+	table := Table{
+		Name:              "rooms",
+		Engine:            "InnoDB",
+		CharSet:           "latin1",
+		Collation:         "latin1_swedish_ci",
+		ShowCollation:     flavor.AlwaysShowCollate(),
+		Columns:           columns,
+		PrimaryKey:        pk,
+		SecondaryIndexes:  []*Index{secondaryIndex},
+		ApplicationPeriod: &ApplicationPeriod{Name: "stay", StartColumn: "checkin", EndColumn: "checkout"},
+		CreateStatement:   stmt,
+	}
+	if flavor.OmitIntDisplayWidth() {
+		stripIntDisplayWidths(&table, flavor)
+	}
+	if table.ShowCollation {
+		table.CreateStatement += " COLLATE=latin1_swedish_ci"
+	}
+	return table
+}
+
 func foreignKeyTable() Table {
 	columns := []*Column{
 		{
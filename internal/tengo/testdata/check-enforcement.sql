@@ -0,0 +1,31 @@
+# Two versions of a table which only differ in whether one check constraint is
+# enforced. Since MySQL requires check constraint names to be unique within a
+# schema, each version is placed in a separate schema.
+
+# This file is only used in MySQL 8.0.16+, since MariaDB does not support the
+# NOT ENFORCED modifier.
+
+SET foreign_key_checks=0;
+
+CREATE DATABASE checkenforced;
+CREATE DATABASE checknotenforced;
+
+use checkenforced
+
+CREATE TABLE check_enforcement (
+	id int unsigned NOT NULL,
+	qty int NOT NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT qty_positive CHECK (qty > 0),
+	CONSTRAINT qty_limit CHECK (qty < 1000)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+use checknotenforced
+
+CREATE TABLE check_enforcement (
+	id int unsigned NOT NULL,
+	qty int NOT NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT qty_positive CHECK (qty > 0),
+	CONSTRAINT qty_limit CHECK (qty < 1000) NOT ENFORCED
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
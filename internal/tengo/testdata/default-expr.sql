@@ -22,6 +22,10 @@ CREATE TABLE testdefaults (
 	l timestamp default current_timestamp(),
 	m timestamp(4) default current_timestamp(4),
 	n text default (concat(d, ' world''s €')),
+	o date default (cast(now() as date)),
+	p json default (json_array()),
+	q json NOT NULL default (json_object('k', 'v')),
+	r mediumtext default (repeat('x', 3)),
 	PRIMARY KEY (pk)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
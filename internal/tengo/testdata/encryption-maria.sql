@@ -0,0 +1,12 @@
+# Tables using InnoDB data-at-rest encryption with MariaDB formatting. This
+# requires a key management plugin (such as file_key_management) to be loaded,
+# so it is not included in the standard set of flavor test files; tests using
+# it must skip if encryption is unavailable.
+# Keep in sync with integration.sql's actor_in_film
+use testing
+CREATE TABLE actor_in_film_enc (
+	actor_id smallint(5) unsigned NOT NULL,
+	film_name varchar(60) NOT NULL,
+	PRIMARY KEY (actor_id,film_name),
+	KEY film_name (film_name)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci ENCRYPTED=YES ENCRYPTION_KEY_ID=2;
@@ -0,0 +1,19 @@
+# Tables using InnoDB data-at-rest encryption with MySQL formatting. This
+# requires a keyring component or plugin to be loaded, so it is not included in
+# the standard set of flavor test files; tests using it must skip if encryption
+# is unavailable.
+# Keep in sync with integration.sql's actor_in_film
+use testing
+CREATE TABLE actor_in_film_enc (
+	actor_id smallint(5) unsigned NOT NULL,
+	film_name varchar(60) NOT NULL,
+	PRIMARY KEY (actor_id,film_name),
+	KEY film_name (film_name)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1 ENCRYPTION='Y';
+
+CREATE TABLE actor_in_film_noenc (
+	actor_id smallint(5) unsigned NOT NULL,
+	film_name varchar(60) NOT NULL,
+	PRIMARY KEY (actor_id,film_name),
+	KEY film_name (film_name)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1 ENCRYPTION='N';
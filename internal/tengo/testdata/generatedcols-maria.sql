@@ -18,6 +18,18 @@ CREATE TABLE staff (
 	last_name varchar(40),
 	full_name varchar(162) AS (CONCAT(first_name, ' ', middle_name, ' ', last_name, '€')) VIRTUAL COMMENT 'hello world',
 	full_name_nonull varchar(162) AS (CONCAT(first_name, ' ', IFNULL(middle_name, ''), ' ', IFNULL(last_name, ''))) STORED,
+	last_name_upper varchar(40) AS (UPPER(last_name)) PERSISTENT,
 	PRIMARY KEY (id),
 	KEY name (full_name_nonull)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE staff_virtidx (
+	id int unsigned NOT NULL auto_increment,
+	email varchar(100) NOT NULL,
+	email_domain varchar(100) AS (SUBSTRING_INDEX(email, '@', -1)) VIRTUAL,
+	email_lower varchar(100) AS (LOWER(email)) VIRTUAL,
+	PRIMARY KEY (id),
+	KEY domain (email_domain),
+	UNIQUE KEY email_lower (email_lower),
+	KEY domain_id (email_domain(20), id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -19,3 +19,14 @@ CREATE TABLE staff (
 	PRIMARY KEY (id),
 	UNIQUE KEY name (full_name_nonull)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE staff_virtidx (
+	id int unsigned NOT NULL auto_increment,
+	email varchar(100) NOT NULL,
+	email_domain varchar(100) AS (SUBSTRING_INDEX(email, '@', -1)) VIRTUAL,
+	email_lower varchar(100) AS (LOWER(email)) VIRTUAL NOT NULL,
+	PRIMARY KEY (id),
+	KEY domain (email_domain),
+	UNIQUE KEY email_lower (email_lower),
+	KEY domain_id (email_domain(20), id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -13,3 +13,12 @@ CREATE TABLE explicit_tablespace_sys (
 	name varchar(30),
 	primary key (id)
 ) tablespace `innodb_system` auto_increment = 456;
+
+CREATE TABLE explicit_tablespace_parts (
+	id int unsigned not null,
+	name varchar(30),
+	primary key (id)
+) PARTITION BY RANGE (id) (
+	PARTITION p0 VALUES LESS THAN (1000) TABLESPACE = innodb_file_per_table,
+	PARTITION p1 VALUES LESS THAN MAXVALUE
+);
@@ -24,6 +24,12 @@ CREATE TABLE eww_myisam (
 	PRIMARY KEY (id)
 ) ENGINE=MyISAM;
 
+CREATE TABLE myisam_sized (
+	id int unsigned NOT NULL,
+	body text,
+	PRIMARY KEY (id)
+) ENGINE=MyISAM MAX_ROWS=1000000 AVG_ROW_LENGTH=500;
+
 CREATE TABLE ft_test (
 	id int unsigned not null auto_increment,
 	body varchar(2000),
@@ -31,6 +37,14 @@ CREATE TABLE ft_test (
 	/*!50601 FULLTEXT */ KEY ftbody (body)
 ) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci;
 
+# InnoDB treats a MERGE_THRESHOLD in an index comment as a functional setting
+CREATE TABLE merge_threshold (
+	id int unsigned NOT NULL,
+	name varchar(80),
+	PRIMARY KEY (id),
+	KEY name_idx (name) COMMENT 'MERGE_THRESHOLD=40'
+) ENGINE=InnoDB;
+
 # MySQL 8 handles these incorrectly in information_schema, so this tests
 # introspection from SHOW CREATE TABLE instead
 CREATE TABLE bin_defaults (
@@ -0,0 +1,14 @@
+# Table using JSON columns with a mix of nullability, as well as a spatial
+# subtype column. This is only used in MySQL 5.7+, since MariaDB's JSON type is
+# just an alias for LONGTEXT with a json_valid check constraint.
+
+use testing;
+CREATE TABLE has_json (
+	id int unsigned NOT NULL,
+	j1 json,
+	j2 json NOT NULL,
+	j3 json NULL,
+	pt point,
+	pt2 point NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB;
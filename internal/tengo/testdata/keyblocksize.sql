@@ -0,0 +1,25 @@
+# Tables using KEY_BLOCK_SIZE at the table level (compressed InnoDB, with and
+# without an explicit ROW_FORMAT) and at the index level (MyISAM)
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE kbs_implicit (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB KEY_BLOCK_SIZE=8;
+
+CREATE TABLE kbs_explicit (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8;
+
+CREATE TABLE kbs_myisam (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id),
+	KEY idx_name (name) KEY_BLOCK_SIZE=4096
+) ENGINE=MyISAM;
@@ -0,0 +1,24 @@
+# MEMORY tables using table-level MAX_ROWS and MIN_ROWS options, as well as a
+# legacy MyISAM table which also sets AVG_ROW_LENGTH
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE sessions_mem (
+	id int unsigned NOT NULL,
+	token char(32) NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=MEMORY MAX_ROWS=50000 MIN_ROWS=100;
+
+CREATE TABLE sessions_mem_nolimit (
+	id int unsigned NOT NULL,
+	token char(32) NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=MEMORY;
+
+CREATE TABLE legacy_sized (
+	id int unsigned NOT NULL,
+	body text,
+	PRIMARY KEY (id)
+) ENGINE=MyISAM MAX_ROWS=4000000000 MIN_ROWS=10 AVG_ROW_LENGTH=2048;
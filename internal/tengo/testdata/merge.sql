@@ -0,0 +1,19 @@
+# MyISAM MERGE table with two member tables
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE log_2023 (
+	id int unsigned NOT NULL,
+	msg varchar(100),
+	KEY (id)
+) ENGINE=MyISAM DEFAULT CHARSET=latin1;
+
+CREATE TABLE log_2024 LIKE log_2023;
+
+CREATE TABLE log_all (
+	id int unsigned NOT NULL,
+	msg varchar(100),
+	KEY (id)
+) ENGINE=MERGE DEFAULT CHARSET=latin1 UNION=(log_2023,log_2024) INSERT_METHOD=LAST;
@@ -25,6 +25,15 @@ CREATE TABLE prangecol (
 	PARTITION p3 VALUES LESS THAN (MAXVALUE,MAXVALUE,MAXVALUE)
 );
 
+CREATE TABLE prangecolmax (
+	a INT,
+	b INT
+) PARTITION BY RANGE COLUMNS(a, b) (
+	PARTITION p0 VALUES LESS THAN (100, 50),
+	PARTITION p1 VALUES LESS THAN (100, maxvalue),
+	PARTITION p2 VALUES LESS THAN (MAXVALUE, MAXVALUE)
+);
+
 CREATE TABLE plist (
 	id   INT,
 	name VARCHAR(35)
@@ -67,6 +76,19 @@ CREATE TABLE pkeypk (
 ) ENGINE=InnoDB
 PARTITION BY KEY() PARTITIONS 3;
 
+CREATE TABLE pkeypkalgo1 (
+	a int NOT NULL,
+	b int NOT NULL,
+	PRIMARY KEY (a, b)
+) ENGINE=InnoDB
+PARTITION BY KEY ALGORITHM=1 () PARTITIONS 3;
+
+CREATE TABLE plinearkeypkalgo2 (
+	a int NOT NULL,
+	PRIMARY KEY (a)
+) ENGINE=InnoDB
+PARTITION BY LINEAR KEY ALGORITHM=2 () PARTITIONS 4;
+
 CREATE TABLE plinearkey (col1 INT, col2 CHAR(5), col3 DATE)
 PARTITION BY LINEAR KEY (col1) PARTITIONS 2;
 
@@ -101,3 +123,16 @@ PARTITIONS 4 (
 	PARTITION p3
 );
 
+
+# Partition names intentionally not in alphabetical order, to confirm that
+# introspection preserves definition order
+CREATE TABLE prangeorder (
+	id int unsigned NOT NULL,
+	created_year smallint unsigned NOT NULL,
+	PRIMARY KEY (id, created_year)
+) PARTITION BY RANGE (created_year) (
+	PARTITION pz_old VALUES LESS THAN (2000),
+	PARTITION pm_mid VALUES LESS THAN (2010),
+	PARTITION pa_new VALUES LESS THAN (2020),
+	PARTITION p_future VALUES LESS THAN MAXVALUE
+);
@@ -0,0 +1,24 @@
+# MariaDB application-time periods (MariaDB 10.4+) and WITHOUT OVERLAPS unique
+# indexes (MariaDB 10.5+). These are not included in the standard set of flavor
+# test files, since they are MariaDB-specific.
+use testing
+
+CREATE TABLE rooms (
+	room_id int unsigned NOT NULL,
+	guest varchar(40) NOT NULL,
+	checkin date NOT NULL,
+	checkout date NOT NULL,
+	PERIOD FOR stay (checkin, checkout),
+	PRIMARY KEY (room_id, stay WITHOUT OVERLAPS),
+	UNIQUE KEY guest_stay (guest, stay WITHOUT OVERLAPS),
+	KEY checkin (checkin)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+CREATE TABLE rooms_noperiod (
+	room_id int unsigned NOT NULL,
+	guest varchar(40) NOT NULL,
+	checkin date NOT NULL,
+	checkout date NOT NULL,
+	PRIMARY KEY (room_id),
+	KEY checkin (checkin)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
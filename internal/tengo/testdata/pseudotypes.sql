@@ -0,0 +1,21 @@
+# Tables using the SERIAL and BOOL/BOOLEAN column type synonyms. Aside from
+# these synonyms, each CREATE TABLE is written in the canonical SHOW CREATE
+# TABLE format, so that it can also be parsed by ParseCreateTable and compared
+# against the server's own expansion of the synonyms.
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE `pseudo_flags` (
+  `code` char(10) NOT NULL,
+  `enabled` bool NOT NULL,
+  `seq` SERIAL COMMENT 'sequence',
+  `archived` BOOLEAN DEFAULT NULL,
+  PRIMARY KEY (`code`),
+  KEY `seq` (`seq`,`enabled`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+CREATE TABLE `pseudo_serial_only` (
+  `id` serial
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,27 @@
+# InnoDB tables created while the server's default row format is COMPACT, as
+# in MySQL 5.6 and MariaDB 10.1, or newer servers configured to match. Only
+# usable with servers that have the innodb_default_row_format variable; the
+# caller must restore its original value afterwards.
+
+SET GLOBAL innodb_default_row_format=COMPACT;
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE rf_compact (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=COMPACT;
+
+CREATE TABLE rf_dynamic (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=DYNAMIC;
+
+CREATE TABLE rf_implicit (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB;
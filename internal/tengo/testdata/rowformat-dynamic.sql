@@ -0,0 +1,27 @@
+# InnoDB tables created while the server's default row format is DYNAMIC, as
+# in MySQL 5.7.9+ and MariaDB 10.2.2+. Only usable with servers that have the
+# innodb_default_row_format variable; the caller must restore its original
+# value afterwards.
+
+SET GLOBAL innodb_default_row_format=DYNAMIC;
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE rf_compact (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=COMPACT;
+
+CREATE TABLE rf_dynamic (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=DYNAMIC;
+
+CREATE TABLE rf_implicit (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB;
@@ -0,0 +1,24 @@
+# InnoDB tables with explicit row formats, corresponding to the default row
+# format of older servers (COMPACT) and newer servers (DYNAMIC)
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE rf_compact (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=COMPACT;
+
+CREATE TABLE rf_dynamic (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB ROW_FORMAT=DYNAMIC;
+
+CREATE TABLE rf_implicit (
+	id int unsigned NOT NULL,
+	name varchar(30),
+	PRIMARY KEY (id)
+) ENGINE=InnoDB;
@@ -0,0 +1,26 @@
+# MariaDB system-versioned tables (MariaDB 10.3+), using both implicit and
+# explicit period columns. These are not included in the standard set of flavor
+# test files, since MariaDB disallows most ALTERs of system-versioned tables by
+# default.
+use testing
+
+CREATE TABLE versioned_implicit (
+	id int unsigned NOT NULL,
+	name varchar(30) NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1 WITH SYSTEM VERSIONING;
+
+CREATE TABLE versioned_explicit (
+	id int unsigned NOT NULL,
+	name varchar(30) NOT NULL,
+	row_start timestamp(6) GENERATED ALWAYS AS ROW START INVISIBLE,
+	row_end timestamp(6) GENERATED ALWAYS AS ROW END INVISIBLE,
+	PRIMARY KEY (id),
+	PERIOD FOR SYSTEM_TIME (row_start, row_end)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1 WITH SYSTEM VERSIONING;
+
+CREATE TABLE unversioned (
+	id int unsigned NOT NULL,
+	name varchar(30) NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,22 @@
+# TIMESTAMP columns with legacy zero-date defaults. The default of
+# ts_implicit.updated_at depends on explicit_defaults_for_timestamp: it is an
+# implicit zero-date if disabled, or no default at all if enabled.
+
+SET foreign_key_checks=0;
+SET sql_mode='';
+
+use testing
+
+CREATE TABLE ts_legacy (
+	id int unsigned NOT NULL,
+	created_at timestamp NOT NULL DEFAULT 0,
+	updated_at timestamp(3) NOT NULL DEFAULT '0000-00-00 00:00:00.000',
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE ts_implicit (
+	id int unsigned NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NOT NULL,
+	PRIMARY KEY (id)
+);
@@ -0,0 +1,381 @@
+package tengo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
+)
+
+// Trigger represents a trigger on a table.
+type Trigger struct {
+	Name            string  `json:"name"`
+	TableName       string  `json:"tableName"`
+	Timing          string  `json:"timing"`                // BEFORE or AFTER
+	Event           string  `json:"event"`                 // INSERT, UPDATE, or DELETE
+	ActionOrder     int     `json:"actionOrder,omitempty"` // 1-based position among the table's triggers with same timing and event; always 0 in flavors lacking multiple triggers per event (MySQL 5.6 and earlier)
+	Body            string  `json:"body"`                  // Has correct escaping despite I_S mutilating it
+	Definer         Definer `json:"definer"`
+	SQLMode         string  `json:"sqlMode"`    // sql_mode in effect at creation time
+	CreateStatement string  `json:"showCreate"` // canonical CREATE TRIGGER, without any FOLLOWS or PRECEDES clause
+}
+
+// ObjectKey returns a value useful for uniquely refering to a Trigger within a
+// single Schema, for example as a map key.
+func (trig *Trigger) ObjectKey() ObjectKey {
+	if trig == nil {
+		return ObjectKey{}
+	}
+	return ObjectKey{
+		Type: ObjectTypeTrigger,
+		Name: trig.Name,
+	}
+}
+
+// Def returns the trigger's CREATE statement as a string.
+func (trig *Trigger) Def() string {
+	return trig.CreateStatement
+}
+
+// DefinerUser returns the trigger's DEFINER, implementing the StoredObject
+// interface.
+func (trig *Trigger) DefinerUser() string {
+	return trig.Definer.String()
+}
+
+// Definition generates and returns a canonical CREATE TRIGGER statement based
+// on the Trigger's Go field values. The formatting matches that of SHOW CREATE
+// TRIGGER. Trigger ordering is not included, since FOLLOWS and PRECEDES clauses
+// are only meaningful relative to other triggers; see TriggerDiff.Statement.
+func (trig *Trigger) Definition() string {
+	return trig.head() + trig.Body
+}
+
+// head returns the portion of a CREATE statement prior to the body.
+func (trig *Trigger) head() string {
+	var definer string
+	if trig.Definer != "" {
+		definer = trig.Definer.Clause() + " "
+	}
+	return fmt.Sprintf("CREATE %sTRIGGER %s %s %s ON %s FOR EACH ROW ",
+		definer,
+		EscapeIdentifier(trig.Name),
+		trig.Timing,
+		trig.Event,
+		EscapeIdentifier(trig.TableName))
+}
+
+// Equals returns true if two triggers are identical, false otherwise.
+func (trig *Trigger) Equals(other *Trigger) bool {
+	// shortcut if both nil pointers, or both pointing to same underlying struct
+	if trig == other {
+		return true
+	}
+	// if one is nil, but the two pointers aren't equal, then one is non-nil
+	if trig == nil || other == nil {
+		return false
+	}
+
+	// All fields are simple scalars, so we can just use equality check once we
+	// know neither is nil
+	return *trig == *other
+}
+
+// sameEvent returns true if trig and other fire on the same table, at the same
+// timing, for the same event. Only triggers with the same event have an order
+// relative to each other.
+func (trig *Trigger) sameEvent(other *Trigger) bool {
+	return trig.TableName == other.TableName && trig.Timing == other.Timing && trig.Event == other.Event
+}
+
+// DropStatement returns a SQL statement that, if run, would drop this trigger.
+func (trig *Trigger) DropStatement() string {
+	return "DROP TRIGGER " + EscapeIdentifier(trig.Name)
+}
+
+var reTriggerBody = regexp.MustCompile(`(?is)^CREATE .*?TRIGGER .+? FOR EACH ROW (?:(?:FOLLOWS|PRECEDES) (?:` + "`(?:[^`]|``)+`" + `|\w+) )?`)
+
+// parseCreateStatement populates Body by parsing the supplied SHOW CREATE
+// TRIGGER output, and then sets CreateStatement to the canonical form of the
+// trigger's definition. Any FOLLOWS or PRECEDES clause is stripped, since the
+// trigger's order is tracked by ActionOrder instead.
+func (trig *Trigger) parseCreateStatement(showCreate, schema string) error {
+	loc := reTriggerBody.FindStringIndex(showCreate)
+	if loc == nil {
+		return fmt.Errorf("Failed to parse SHOW CREATE TRIGGER %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(trig.Name), showCreate)
+	}
+	trig.Body = showCreate[loc[1]:]
+	trig.CreateStatement = trig.Definition()
+	return nil
+}
+
+///// Diff logic ///////////////////////////////////////////////////////////////
+
+// TriggerDiff represents a difference between two triggers. Triggers cannot be
+// altered in-place, so a modification to an existing trigger is represented as
+// two separate TriggerDiffs: one DiffTypeDrop and one DiffTypeCreate. Flavors
+// that support CREATE OR REPLACE TRIGGER (MariaDB) will simply blank-out the
+// DROP portion of the pair.
+type TriggerDiff struct {
+	Type DiffType
+	From *Trigger
+	To   *Trigger
+
+	// For DiffTypeCreate, the trigger ordering clause needed to position To
+	// correctly relative to other triggers with the same event, if any. This is
+	// populated by NewSchemaDiff.
+	orderClause string
+}
+
+// ObjectKey returns a value representing the type and name of the trigger
+// being diff'ed. The name will be the From side trigger, unless this is a
+// Create, in which case the To side trigger name is used.
+func (td *TriggerDiff) ObjectKey() ObjectKey {
+	if td != nil && td.From != nil {
+		return td.From.ObjectKey()
+	} else if td != nil && td.To != nil {
+		return td.To.ObjectKey()
+	}
+	return ObjectKey{}
+}
+
+// DiffType returns the type of diff operation.
+func (td *TriggerDiff) DiffType() DiffType {
+	if td == nil {
+		return DiffTypeNone
+	}
+	return td.Type
+}
+
+// Statement returns the full DDL statement corresponding to the TriggerDiff. A
+// blank string may be returned if the mods indicate the statement should be
+// skipped. If the mods indicate the statement should be disallowed, it will
+// still be returned as-is, but the error will be non-nil. Be sure not to
+// ignore the error value of this method.
+func (td *TriggerDiff) Statement(mods StatementModifiers) (stmt string, err error) {
+	if td == nil {
+		return "", nil
+	}
+
+	// Detect special-case types of replacements, same as with routines
+	var metadataOnlyReplace, mariaReplace bool
+	if td.From != nil && td.To != nil { // related pair for a replacement
+		if td.From.CreateStatement == td.To.CreateStatement && td.orderClause == "" && td.From.ActionOrder == td.To.ActionOrder {
+			// Creation-time sql_mode changes are opt-in, same as with routines
+			if !mods.CompareMetadata {
+				return "", nil
+			}
+			metadataOnlyReplace = true
+		}
+		mariaReplace = mods.Flavor.IsMariaDB()
+	}
+
+	switch td.Type {
+	case DiffTypeDrop:
+		if mariaReplace {
+			return "", nil
+		}
+		stmt = td.From.DropStatement()
+		if metadataOnlyReplace {
+			stmt = "# Dropping and re-creating " + td.ObjectKey().String() + " to update metadata\n" + stmt
+		}
+		if !mods.AllowUnsafe {
+			if td.To == nil {
+				err = &UnsafeDiffError{
+					Reason: "Desired drop of " + td.ObjectKey().String() + " is risky, since any data invariants that it enforces will no longer be maintained.",
+				}
+			} else {
+				err = &UnsafeDiffError{
+					Reason: "Desired modification to " + td.ObjectKey().String() + " requires dropping and re-creating it, and writes to table " + EscapeIdentifier(td.From.TableName) + " during the brief moment after the DROP but before the re-CREATE will not fire the trigger.",
+				}
+			}
+		}
+		return stmt, err
+	case DiffTypeCreate:
+		stmt = td.To.head() + td.orderClause + td.To.Body
+		if mariaReplace {
+			stmt = strings.Replace(stmt, "CREATE ", "CREATE OR REPLACE ", 1)
+			if metadataOnlyReplace {
+				stmt = "# Replacing " + td.ObjectKey().String() + " to update metadata\n" + stmt
+			}
+		}
+		return stmt, nil
+	}
+	// DiffTypeAlter and DiffTypeRename not used, no equivalent syntax
+	return "", fmt.Errorf("Unsupported diff type %d", td.DiffType())
+}
+
+// IsCompoundStatement returns true if the diff is a compound CREATE statement,
+// requiring special delimiter handling.
+func (td *TriggerDiff) IsCompoundStatement() bool {
+	return td.Type == DiffTypeCreate && ParseStatementInString(td.To.CreateStatement).Compound
+}
+
+// compareTriggers returns the diffs needed to turn the triggers in from into
+// the triggers in to. All drops are returned prior to any creates. Drops are
+// sorted by name, and creates are sorted by table, timing, event, and then
+// action order, such that any trigger ordering clauses refer to triggers which
+// already exist.
+func compareTriggers(from, to *Schema) []*TriggerDiff {
+	var drops, creates []*TriggerDiff
+	fromByName := from.TriggersByName()
+	toByName := to.TriggersByName()
+
+	// An existing trigger must be replaced if anything other than its action
+	// order has changed, or if its position relative to other retained triggers
+	// has changed
+	unchanged := make(map[string]bool)
+	for name, fromTrig := range fromByName {
+		toTrig, stillExists := toByName[name]
+		if !stillExists {
+			drops = append(drops, &TriggerDiff{Type: DiffTypeDrop, From: fromTrig})
+			continue
+		}
+		fromCopy := *fromTrig
+		fromCopy.ActionOrder = toTrig.ActionOrder
+		unchanged[name] = fromCopy.Equals(toTrig)
+	}
+	prevUnchanged := func(s *Schema, trig *Trigger) string {
+		var prev *Trigger
+		for _, other := range s.Triggers {
+			if unchanged[other.Name] && other.sameEvent(trig) && other.ActionOrder < trig.ActionOrder && (prev == nil || other.ActionOrder > prev.ActionOrder) {
+				prev = other
+			}
+		}
+		if prev == nil {
+			return ""
+		}
+		return prev.Name
+	}
+	retained := make(map[string]bool, len(unchanged))
+	for name, fromTrig := range fromByName {
+		if toTrig, stillExists := toByName[name]; !stillExists {
+			continue
+		} else if unchanged[name] && prevUnchanged(from, fromTrig) == prevUnchanged(to, toTrig) {
+			retained[name] = true
+		} else {
+			drops = append(drops, &TriggerDiff{Type: DiffTypeDrop, From: fromTrig, To: toTrig})
+			creates = append(creates, &TriggerDiff{Type: DiffTypeCreate, From: fromTrig, To: toTrig})
+		}
+	}
+	for name, toTrig := range toByName {
+		if _, alreadyExists := fromByName[name]; !alreadyExists {
+			creates = append(creates, &TriggerDiff{Type: DiffTypeCreate, To: toTrig})
+		}
+	}
+
+	slices.SortFunc(drops, func(a, b *TriggerDiff) int {
+		return strings.Compare(a.From.Name, b.From.Name)
+	})
+	slices.SortFunc(creates, func(a, b *TriggerDiff) int {
+		if c := strings.Compare(a.To.TableName, b.To.TableName); c != 0 {
+			return c
+		} else if c := strings.Compare(a.To.Timing, b.To.Timing); c != 0 {
+			return c
+		} else if c := strings.Compare(a.To.Event, b.To.Event); c != 0 {
+			return c
+		}
+		return a.To.ActionOrder - b.To.ActionOrder
+	})
+	for _, create := range creates {
+		create.orderClause = triggerOrderClause(to, create.To, retained)
+	}
+	return append(drops, creates...)
+}
+
+// triggerOrderClause returns a FOLLOWS or PRECEDES clause, with a trailing
+// space, which positions trig correctly among the triggers with the same event
+// in s. Without such a clause, a new trigger is always placed after all
+// existing triggers with the same event. Triggers listed in retained are
+// assumed to already exist, and all other triggers are assumed to be created in
+// ascending order of ActionOrder.
+func triggerOrderClause(s *Schema, trig *Trigger, retained map[string]bool) string {
+	if trig.ActionOrder == 0 {
+		return ""
+	}
+	var firstRetained *Trigger
+	for _, other := range s.Triggers {
+		if other == trig || !other.sameEvent(trig) {
+			continue
+		} else if other.ActionOrder == trig.ActionOrder-1 {
+			// All earlier triggers already exist by the time trig is created
+			return "FOLLOWS " + EscapeIdentifier(other.Name) + " "
+		} else if retained[other.Name] && (firstRetained == nil || other.ActionOrder < firstRetained.ActionOrder) {
+			firstRetained = other
+		}
+	}
+	if firstRetained != nil && firstRetained.ActionOrder > trig.ActionOrder {
+		return "PRECEDES " + EscapeIdentifier(firstRetained.Name) + " "
+	}
+	return ""
+}
+
+///// Introspection logic //////////////////////////////////////////////////////
+
+func querySchemaTriggers(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor, failures *objectFailures) ([]*Trigger, error) {
+	var rawTriggers []struct {
+		Name        string `db:"trigger_name"`
+		TableName   string `db:"event_object_table"`
+		Timing      string `db:"action_timing"`
+		Event       string `db:"event_manipulation"`
+		ActionOrder int    `db:"action_order"`
+		Definer     string `db:"definer"`
+		SQLMode     string `db:"sql_mode"`
+	}
+	// As with routines, the trigger body is not queried from information_schema
+	// here, since action_statement is mangled in some flavors; SHOW CREATE
+	// TRIGGER is used instead.
+	query := `
+		SELECT SQL_BUFFER_RESULT
+		       t.trigger_name AS trigger_name,
+		       t.event_object_table AS event_object_table,
+		       UPPER(t.action_timing) AS action_timing,
+		       UPPER(t.event_manipulation) AS event_manipulation,
+		       t.action_order AS action_order,
+		       t.definer AS definer, t.sql_mode AS sql_mode
+		FROM   information_schema.triggers t
+		WHERE  t.trigger_schema = ?`
+	if err := db.SelectContext(ctx, &rawTriggers, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.triggers for schema %s: %s", schema, err)
+	}
+	triggers := make([]*Trigger, len(rawTriggers))
+	for n, rawTrigger := range rawTriggers {
+		triggers[n] = &Trigger{
+			Name:      rawTrigger.Name,
+			TableName: rawTrigger.TableName,
+			Timing:    rawTrigger.Timing,
+			Event:     rawTrigger.Event,
+			Definer:   Definer(rawTrigger.Definer),
+			SQLMode:   rawTrigger.SQLMode,
+		}
+		// MySQL 5.6 lacks support for multiple triggers per event, and always
+		// reports an action_order of 0
+		if flavor.MinMySQL(5, 7) || flavor.IsMariaDB() {
+			triggers[n].ActionOrder = rawTrigger.ActionOrder
+		}
+	}
+
+	g, subCtx := errgroup.WithContext(ctx)
+	for _, trig := range triggers {
+		g.Go(func() error {
+			var row struct {
+				CreateStatement string `db:"SQL Original Statement"`
+			}
+			query := "SHOW CREATE TRIGGER " + EscapeIdentifier(trig.Name)
+			if err := db.GetContext(subCtx, &row, query); err != nil {
+				failures.add(trig.ObjectKey(), fmt.Errorf("Error executing SHOW CREATE TRIGGER for %s.%s: %w", EscapeIdentifier(schema), EscapeIdentifier(trig.Name), err))
+			} else if err := trig.parseCreateStatement(normalizeBody(row.CreateStatement), schema); err != nil {
+				failures.add(trig.ObjectKey(), err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return withoutFailures(triggers, failures), nil
+}
@@ -0,0 +1,230 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func aTrigger(name, timing, event string, actionOrder int) Trigger {
+	trig := Trigger{
+		Name:        name,
+		TableName:   "actor",
+		Timing:      timing,
+		Event:       event,
+		ActionOrder: actionOrder,
+		Body:        "SET NEW.first_name = UPPER(NEW.first_name)",
+		Definer:     "root@%",
+		SQLMode:     "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION",
+	}
+	trig.CreateStatement = trig.Definition()
+	return trig
+}
+
+func TestTriggerParseCreateStatement(t *testing.T) {
+	expected := aTrigger("upcase", "BEFORE", "INSERT", 1)
+	expected.Body = "BEGIN\n  SET NEW.first_name = 'it''s';\nEND"
+	expected.CreateStatement = expected.Definition()
+	if expected.CreateStatement != "CREATE DEFINER=`root`@`%` TRIGGER `upcase` BEFORE INSERT ON `actor` FOR EACH ROW BEGIN\n  SET NEW.first_name = 'it''s';\nEND" {
+		t.Errorf("Unexpected result from Definition: %s", expected.CreateStatement)
+	}
+	inputs := []string{
+		expected.CreateStatement,
+		strings.Replace(expected.CreateStatement, "FOR EACH ROW ", "FOR EACH ROW FOLLOWS `other` ", 1),
+		strings.Replace(expected.CreateStatement, "FOR EACH ROW ", "FOR EACH ROW precedes other ", 1),
+	}
+	for _, input := range inputs {
+		trig := aTrigger("upcase", "BEFORE", "INSERT", 1)
+		if err := trig.parseCreateStatement(input, "testing"); err != nil {
+			t.Errorf("Unexpected error parsing %q: %v", input, err)
+		} else if !trig.Equals(&expected) {
+			t.Errorf("Unexpected result from parsing %q:\nexpected %+v\nfound    %+v", input, expected, trig)
+		}
+	}
+	trig := aTrigger("upcase", "BEFORE", "INSERT", 1)
+	if err := trig.parseCreateStatement("CREATE TABLE `upcase` (\n)", "testing"); err == nil {
+		t.Error("Expected error parsing invalid input, but err was nil")
+	}
+}
+
+func TestTriggerDiff(t *testing.T) {
+	from, to := aTrigger("upcase", "BEFORE", "INSERT", 1), aTrigger("upcase", "BEFORE", "INSERT", 1)
+	fromSchema := &Schema{Name: "s1", Triggers: []*Trigger{&from}}
+	toSchema := &Schema{Name: "s1", Triggers: []*Trigger{&to}}
+	if sd := fromSchema.Diff(toSchema); len(sd.TriggerDiffs) != 0 {
+		t.Errorf("Expected no diffs between identical triggers, instead found %d", len(sd.TriggerDiffs))
+	}
+
+	// Create and drop
+	sd := NewSchemaDiff(&Schema{Name: "s1"}, toSchema)
+	if len(sd.TriggerDiffs) != 1 || sd.TriggerDiffs[0].DiffType() != DiffTypeCreate {
+		t.Fatalf("Expected 1 TriggerDiff of type CREATE, instead found %+v", sd.TriggerDiffs)
+	} else if stmt, err := sd.TriggerDiffs[0].Statement(StatementModifiers{}); stmt != to.CreateStatement || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	sd = NewSchemaDiff(fromSchema, &Schema{Name: "s1"})
+	if len(sd.TriggerDiffs) != 1 || sd.TriggerDiffs[0].DiffType() != DiffTypeDrop {
+		t.Fatalf("Expected 1 TriggerDiff of type DROP, instead found %+v", sd.TriggerDiffs)
+	} else if stmt, err := sd.TriggerDiffs[0].Statement(StatementModifiers{}); stmt != "DROP TRIGGER `upcase`" || !IsUnsafeDiff(err) {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	} else if _, err := sd.TriggerDiffs[0].Statement(StatementModifiers{AllowUnsafe: true}); err != nil {
+		t.Errorf("Unexpected error from Statement with AllowUnsafe: %v", err)
+	}
+
+	// Body change: drop and re-create, or CREATE OR REPLACE in MariaDB
+	to.Body = "SET NEW.last_name = UPPER(NEW.last_name)"
+	to.CreateStatement = to.Definition()
+	sd = fromSchema.Diff(toSchema)
+	if len(sd.TriggerDiffs) != 2 || sd.TriggerDiffs[0].DiffType() != DiffTypeDrop || sd.TriggerDiffs[1].DiffType() != DiffTypeCreate {
+		t.Fatalf("Expected DROP and CREATE TriggerDiffs, instead found %+v", sd.TriggerDiffs)
+	}
+	mods := StatementModifiers{Flavor: ParseFlavor("mysql:8.0"), AllowUnsafe: true}
+	if stmt, err := sd.TriggerDiffs[0].Statement(mods); stmt != "DROP TRIGGER `upcase`" || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if stmt, err := sd.TriggerDiffs[1].Statement(mods); stmt != to.CreateStatement || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	mods.AllowUnsafe = false
+	if _, err := sd.TriggerDiffs[0].Statement(mods); !IsUnsafeDiff(err) {
+		t.Errorf("Expected unsafe error from replacement DROP, instead found %v", err)
+	}
+	mods.Flavor = ParseFlavor("mariadb:10.6")
+	if stmt, err := sd.TriggerDiffs[0].Statement(mods); stmt != "" || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+	if stmt, err := sd.TriggerDiffs[1].Statement(mods); !strings.HasPrefix(stmt, "CREATE OR REPLACE DEFINER=") || err != nil {
+		t.Errorf("Unexpected return from Statement: %q, %v", stmt, err)
+	}
+
+	// Metadata-only change is only emitted with CompareMetadata
+	to = aTrigger("upcase", "BEFORE", "INSERT", 1)
+	to.SQLMode = "NO_ENGINE_SUBSTITUTION"
+	sd = fromSchema.Diff(toSchema)
+	if len(sd.TriggerDiffs) != 2 {
+		t.Fatalf("Expected 2 TriggerDiffs, instead found %+v", sd.TriggerDiffs)
+	} else if stmt, _ := sd.TriggerDiffs[1].Statement(StatementModifiers{}); stmt != "" {
+		t.Errorf("Expected blank statement without CompareMetadata, instead found %q", stmt)
+	} else if stmt, _ := sd.TriggerDiffs[1].Statement(StatementModifiers{CompareMetadata: true}); stmt != to.CreateStatement {
+		t.Errorf("Unexpected statement with CompareMetadata: %q", stmt)
+	}
+}
+
+func TestTriggerDiffOrdering(t *testing.T) {
+	newSchema := func(triggers ...Trigger) *Schema {
+		s := &Schema{Name: "s1"}
+		for _, trig := range triggers {
+			s.Triggers = append(s.Triggers, &trig)
+		}
+		return s
+	}
+	statements := func(from, to *Schema) (result []string) {
+		t.Helper()
+		for _, td := range from.Diff(to).TriggerDiffs {
+			stmt, err := td.Statement(StatementModifiers{AllowUnsafe: true})
+			if err != nil {
+				t.Fatalf("Unexpected error from Statement: %v", err)
+			}
+			if strings.HasPrefix(stmt, "CREATE ") {
+				_, stmt, _ = strings.Cut(stmt, "TRIGGER ")
+				stmt, _, _ = strings.Cut(stmt, " SET ")
+			}
+			result = append(result, stmt)
+		}
+		return result
+	}
+	assertStatements := func(from, to *Schema, expected ...string) {
+		t.Helper()
+		actual := statements(from, to)
+		if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Unexpected statements.\nExpected:\n%s\nFound:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+		}
+	}
+	a1, b2, c3 := aTrigger("a", "BEFORE", "INSERT", 1), aTrigger("b", "BEFORE", "INSERT", 2), aTrigger("c", "BEFORE", "INSERT", 3)
+	after := aTrigger("after", "AFTER", "INSERT", 1)
+
+	// Adding a trigger at the end only requires a FOLLOWS clause; triggers for
+	// other events are unaffected
+	assertStatements(newSchema(a1, after), newSchema(a1, b2, after),
+		"`b` BEFORE INSERT ON `actor` FOR EACH ROW FOLLOWS `a`",
+	)
+
+	// Adding multiple triggers at the start requires PRECEDES for the first one,
+	// and then FOLLOWS for the subsequent one
+	a1.Name, b2.Name = "new1", "new2"
+	assertStatements(newSchema(aTrigger("c", "BEFORE", "INSERT", 1)), newSchema(a1, b2, c3),
+		"`new1` BEFORE INSERT ON `actor` FOR EACH ROW PRECEDES `c`",
+		"`new2` BEFORE INSERT ON `actor` FOR EACH ROW FOLLOWS `new1`",
+	)
+
+	// Dropping the first trigger changes the others' action order, but they
+	// don't need to be recreated
+	a1.Name, b2.Name = "a", "b"
+	assertStatements(newSchema(a1, b2, c3), newSchema(aTrigger("b", "BEFORE", "INSERT", 1), aTrigger("c", "BEFORE", "INSERT", 2)),
+		"DROP TRIGGER `a`",
+	)
+
+	// Swapping order requires recreating the swapped triggers
+	assertStatements(newSchema(a1, b2, c3), newSchema(a1, aTrigger("c", "BEFORE", "INSERT", 2), aTrigger("b", "BEFORE", "INSERT", 3)),
+		"DROP TRIGGER `b`",
+		"DROP TRIGGER `c`",
+		"`c` BEFORE INSERT ON `actor` FOR EACH ROW FOLLOWS `a`",
+		"`b` BEFORE INSERT ON `actor` FOR EACH ROW FOLLOWS `c`",
+	)
+}
+
+func (s TengoIntegrationSuite) TestTriggerIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	creates := []string{
+		"CREATE TRIGGER upcase BEFORE INSERT ON actor FOR EACH ROW SET NEW.first_name = UPPER(NEW.first_name)",
+		"CREATE TRIGGER quoting AFTER UPDATE ON actor FOR EACH ROW BEGIN\n  IF NEW.last_name = 'it''s' THEN\n    SET @x = 1;\n  END IF;\nEND",
+	}
+	if flavor.MinMySQL(5, 7) || flavor.IsMariaDB() {
+		creates = append(creates, "CREATE TRIGGER first BEFORE INSERT ON actor FOR EACH ROW PRECEDES upcase SET NEW.last_name = UPPER(NEW.last_name)")
+	}
+	for _, create := range creates {
+		if _, err := db.Exec(create); err != nil {
+			t.Fatalf("Unexpected error creating trigger: %v", err)
+		}
+	}
+
+	// Triggers are only introspected if explicitly enabled
+	if schema := s.GetSchema(t, "testing"); len(schema.Triggers) != 0 {
+		t.Errorf("Expected triggers to not be introspected by default, instead found %d", len(schema.Triggers))
+	}
+	s.d.SetTriggerIntrospection(true)
+	defer s.d.SetTriggerIntrospection(false)
+	schema := s.GetSchema(t, "testing")
+	if len(schema.Triggers) != len(creates) {
+		t.Fatalf("Expected schema to have %d triggers, instead found %d", len(creates), len(schema.Triggers))
+	}
+	triggers := schema.TriggersByName()
+	if trig := triggers["quoting"]; trig.Timing != "AFTER" || trig.Event != "UPDATE" || trig.TableName != "actor" || !strings.Contains(trig.Body, "'it''s'") || trig.Definer == "" {
+		t.Errorf("Unexpected introspection of trigger quoting: %+v", *trig)
+	}
+	if first := triggers["first"]; first != nil && (first.ActionOrder != 1 || triggers["upcase"].ActionOrder != 2) {
+		t.Errorf("Unexpected action orders: first=%d, upcase=%d", first.ActionOrder, triggers["upcase"].ActionOrder)
+	}
+
+	// Confirm that dropping and re-creating the triggers, using the generated
+	// DDL, results in the same introspected triggers
+	sd := NewSchemaDiff(&Schema{Name: "testing"}, schema)
+	for _, td := range NewSchemaDiff(schema, &Schema{Name: "testing"}).TriggerDiffs {
+		stmt, _ := td.Statement(StatementModifiers{Flavor: flavor, AllowUnsafe: true})
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+		}
+	}
+	for _, td := range sd.TriggerDiffs {
+		stmt, _ := td.Statement(StatementModifiers{Flavor: flavor})
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+		}
+	}
+	if sd := NewSchemaDiff(schema, s.GetSchema(t, "testing")); len(sd.TriggerDiffs) > 0 {
+		t.Errorf("Expected no trigger differences after re-creating triggers, instead found %+v", sd.TriggerDiffs)
+	}
+}
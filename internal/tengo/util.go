@@ -1,11 +1,14 @@
 package tengo
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -17,17 +20,93 @@ func EscapeIdentifier(input string) string {
 	return "`" + strings.ReplaceAll(input, "`", "``") + "`"
 }
 
-var replacerCreateTableString = strings.NewReplacer(`\`, `\\`, "\000", `\0`, "'", "''", "\n", `\n`, "\r", `\r`)
+// EscapeIdentifierANSI is equivalent to EscapeIdentifier, but for use when the
+// ANSI_QUOTES sql_mode is enabled: it doubles any double-quote characters in the
+// input string, and then returns the string wrapped in outer double quotes.
+// Backticks in the input are not special in this mode and are left as-is.
+func EscapeIdentifierANSI(input string) string {
+	return `"` + strings.ReplaceAll(input, `"`, `""`) + `"`
+}
+
+// EscapeQualifiedIdentifier returns a schema-qualified object name, with each
+// part escaped using EscapeIdentifier. If schema is an empty string, only the
+// escaped name is returned.
+func EscapeQualifiedIdentifier(schema, name string) string {
+	if schema == "" {
+		return EscapeIdentifier(name)
+	}
+	return EscapeIdentifier(schema) + "." + EscapeIdentifier(name)
+}
+
+var replacerCreateTableString = strings.NewReplacer(`\`, `\\`, "\000", `\0`, "'", "''", "\n", `\n`, "\r", `\r`, "\032", `\Z`)
 
 // EscapeValueForCreateTable returns the supplied value (typically obtained from
 // querying an information_schema table) escaped in the same manner as SHOW
 // CREATE TABLE would display it. Examples include default values, table
-// comments, column comments, index comments. This function does not wrap the
-// value in single quotes; the caller should do that as appropriate.
+// comments, column comments, index comments, partition comments. This function
+// does not wrap the value in single quotes; the caller should do that as
+// appropriate.
+// Matching the server's behavior, backslash, NUL, newline, carriage return, and
+// Ctrl-Z are backslash-escaped, and single quotes are doubled. Other bytes,
+// including double quotes and tabs, are left as-is since they are valid inside
+// a single-quoted string literal.
 func EscapeValueForCreateTable(input string) string {
 	return replacerCreateTableString.Replace(input)
 }
 
+// redactedOptions lists the names of options whose string values may contain
+// credentials. For example, a FEDERATED table's CONNECTION option may include
+// a remote user's password. These values are redacted by RedactSecrets.
+var redactedOptions = []string{"CONNECTION", "PASSWORD"}
+
+// RedactSecrets returns a copy of the supplied SQL, with the quoted value of
+// any option in redactedOptions replaced by a placeholder. This should be used
+// for any human-readable or JSON output of DDL, such as clause summaries or
+// error messages, but never for DDL that will actually be executed.
+// The input is tokenized, so an option name is only recognized as a bare word
+// followed by an optional "=" and then a string literal; occurrences inside
+// string literals, identifiers, or ordinary comments are left as-is. The
+// contents of version-gated comments are processed recursively.
+func RedactSecrets(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	lexer := NewLexer(strings.NewReader(input), "\000", 1024)
+	var optionName, sawEquals bool // state tracking for the previous non-filler token(s)
+	for {
+		val, typ, err := lexer.Scan()
+		if err != nil {
+			return b.String()
+		}
+		switch typ {
+		case TokenFiller:
+			transformVersionComments(&b, val, RedactSecrets)
+			continue // filler does not affect the state of the previous token
+		case TokenString:
+			if optionName {
+				b.WriteByte(val[0])
+				b.WriteString("<redacted>")
+				b.WriteByte(val[0])
+			} else {
+				b.Write(val)
+			}
+			optionName = false
+		case TokenSymbol:
+			b.Write(val)
+			optionName = optionName && !sawEquals && string(val) == "="
+			sawEquals = optionName
+		case TokenWord:
+			b.Write(val)
+			optionName = slices.ContainsFunc(redactedOptions, func(opt string) bool {
+				return strings.EqualFold(opt, string(val))
+			})
+			sawEquals = false
+		default:
+			b.Write(val)
+			optionName = false
+		}
+	}
+}
+
 // SplitHostOptionalPort takes an address string containing a hostname, ipv4
 // addr, or ipv6 addr; *optionally* followed by a colon and port number. It
 // splits the hostname portion from the port portion and returns them
@@ -311,3 +390,36 @@ func longestIncreasingSubsequence(input []int) []int {
 	}
 	return candidateLists[len(candidateLists)-1]
 }
+
+// engineAttributeClause returns an ENGINE_ATTRIBUTE or
+// SECONDARY_ENGINE_ATTRIBUTE clause in the format used by SHOW CREATE TABLE in
+// MySQL 8.0.21+, or an empty string if value is empty. The result has no
+// leading space. Table-level options use an equals sign between the option name
+// and value, whereas column-level and index-level options do not.
+func engineAttributeClause(name, value string, tableLevel bool) string {
+	if value == "" {
+		return ""
+	}
+	separator := " "
+	if tableLevel {
+		separator = "="
+	}
+	return "/*!80021 " + name + separator + "'" + EscapeValueForCreateTable(value) + "' */"
+}
+
+// engineAttributesEquivalent returns true if two ENGINE_ATTRIBUTE or
+// SECONDARY_ENGINE_ATTRIBUTE values represent the same JSON document, ignoring
+// differences in whitespace and object key order. If either value is not
+// valid JSON, the values are compared as plain strings.
+func engineAttributesEquivalent(a, b string) bool {
+	if a == b {
+		return true
+	} else if a == "" || b == "" {
+		return false
+	}
+	var docA, docB any
+	if json.Unmarshal([]byte(a), &docA) != nil || json.Unmarshal([]byte(b), &docB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(docA, docB)
+}
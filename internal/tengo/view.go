@@ -0,0 +1,314 @@
+package tengo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
+)
+
+// View represents a view in a schema.
+type View struct {
+	Name            string  `json:"name"`
+	Algorithm       string  `json:"algorithm"` // UNDEFINED, MERGE, or TEMPTABLE
+	Definer         Definer `json:"definer"`
+	SecurityType    string  `json:"securityType"`          // DEFINER or INVOKER
+	ColumnList      string  `json:"columnList,omitempty"`  // explicit column name list, without wrapping parens; only present in some flavors
+	Select          string  `json:"select"`                // expanded SELECT, with qualifiers referring to the view's own schema removed
+	CheckOption     string  `json:"checkOption,omitempty"` // CASCADED or LOCAL, or blank if no check option
+	CreateStatement string  `json:"showCreate"`            // canonical CREATE VIEW, with schema qualifiers normalized away
+}
+
+// ObjectKey returns a value useful for uniquely refering to a View within a
+// single Schema, for example as a map key.
+func (view *View) ObjectKey() ObjectKey {
+	if view == nil {
+		return ObjectKey{}
+	}
+	return ObjectKey{
+		Type: ObjectTypeView,
+		Name: view.Name,
+	}
+}
+
+// Def returns the view's CREATE statement as a string.
+func (view *View) Def() string {
+	return view.CreateStatement
+}
+
+// DefinerUser returns the view's DEFINER, implementing the StoredObject
+// interface.
+func (view *View) DefinerUser() string {
+	return view.Definer.String()
+}
+
+// Definition generates and returns a canonical CREATE VIEW statement based on
+// the View's Go field values. The formatting matches that of SHOW CREATE VIEW,
+// aside from the SELECT's removal of qualifiers for the view's own schema.
+func (view *View) Definition() string {
+	var definer, columnList, checkOption string
+	if view.Definer != "" {
+		definer = view.Definer.Clause() + " "
+	}
+	if view.ColumnList != "" {
+		columnList = " (" + view.ColumnList + ")"
+	}
+	if view.CheckOption != "" {
+		checkOption = " WITH " + view.CheckOption + " CHECK OPTION"
+	}
+	return fmt.Sprintf("CREATE ALGORITHM=%s %sSQL SECURITY %s VIEW %s%s AS %s%s",
+		view.Algorithm,
+		definer,
+		view.SecurityType,
+		EscapeIdentifier(view.Name),
+		columnList,
+		view.Select,
+		checkOption)
+}
+
+// Equals returns true if two views are identical, false otherwise.
+func (view *View) Equals(other *View) bool {
+	// shortcut if both nil pointers, or both pointing to same underlying struct
+	if view == other {
+		return true
+	}
+	// if one is nil, but the two pointers aren't equal, then one is non-nil
+	if view == nil || other == nil {
+		return false
+	}
+
+	// All fields are simple scalars, so we can just use equality check once we
+	// know neither is nil
+	return *view == *other
+}
+
+// DropStatement returns a SQL statement that, if run, would drop this view.
+func (view *View) DropStatement() string {
+	return "DROP VIEW " + EscapeIdentifier(view.Name)
+}
+
+var reViewCreate = regexp.MustCompile(`(?is)^CREATE ALGORITHM=(UNDEFINED|MERGE|TEMPTABLE) (?:DEFINER=\S+ )?SQL SECURITY (DEFINER|INVOKER) VIEW ` + "(?:`(?:[^`]|``)+`\\.)?`(?:[^`]|``)+`" + ` (?:\((.*?)\) )?AS (.*?)(?: WITH (CASCADED|LOCAL) CHECK OPTION)?$`)
+
+// parseCreateStatement populates the view's algorithm, security type, column
+// list, SELECT, and check option by parsing the supplied SHOW CREATE VIEW
+// output. The view's Name and Definer should already be set prior to calling
+// this method. CreateStatement is then set to a canonical CREATE VIEW, which
+// is comparable between views in different schemas.
+func (view *View) parseCreateStatement(showCreate, schema string) error {
+	matches := reViewCreate.FindStringSubmatch(showCreate)
+	if matches == nil {
+		return fmt.Errorf("Failed to parse SHOW CREATE VIEW %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(view.Name), showCreate)
+	}
+	view.Algorithm = strings.ToUpper(matches[1])
+	view.SecurityType = strings.ToUpper(matches[2])
+	view.ColumnList = matches[3]
+	view.Select = normalizeViewSelect(matches[4], schema)
+	view.CheckOption = strings.ToUpper(matches[5])
+	view.CreateStatement = view.Definition()
+	return nil
+}
+
+// normalizeViewSelect removes any qualifiers referring to schema from the
+// supplied SELECT. The server expands a view's SELECT to fully qualify all
+// table and column references, which would otherwise prevent comparison of
+// otherwise-identical views in different schemas, such as a workspace schema.
+// Qualifiers referring to other schemas, and any text inside of string
+// literals, are left as-is.
+func normalizeViewSelect(sel, schema string) string {
+	qualifier := EscapeIdentifier(schema) + "."
+	var b strings.Builder
+	for pos := 0; pos < len(sel); {
+		c := sel[pos]
+		if c != '\'' && c != '"' && c != '`' {
+			b.WriteByte(c)
+			pos++
+			continue
+		}
+		end := pos + 1
+		for end < len(sel) {
+			if sel[end] == '\\' && c != '`' {
+				end += 2
+			} else if sel[end] == c && end+1 < len(sel) && sel[end+1] == c {
+				end += 2 // doubled quote char inside of quoted value
+			} else if sel[end] == c {
+				end++
+				break
+			} else {
+				end++
+			}
+		}
+		end = min(end, len(sel))
+		if c == '`' && strings.HasPrefix(sel[pos:], qualifier) && (pos == 0 || sel[pos-1] != '.') {
+			pos += len(qualifier)
+			continue
+		}
+		b.WriteString(sel[pos:end])
+		pos = end
+	}
+	return b.String()
+}
+
+///// Diff logic ///////////////////////////////////////////////////////////////
+
+// ViewDiff represents a difference between two views. Modifications to an
+// existing view are represented as a single ViewDiff with DiffTypeAlter, which
+// emits a CREATE OR REPLACE VIEW statement.
+type ViewDiff struct {
+	Type DiffType
+	From *View
+	To   *View
+}
+
+// ObjectKey returns a value representing the type and name of the view being
+// diff'ed. The name will be the From side view, unless this is a Create, in
+// which case the To side view name is used.
+func (vd *ViewDiff) ObjectKey() ObjectKey {
+	if vd != nil && vd.From != nil {
+		return vd.From.ObjectKey()
+	} else if vd != nil && vd.To != nil {
+		return vd.To.ObjectKey()
+	}
+	return ObjectKey{}
+}
+
+// DiffType returns the type of diff operation.
+func (vd *ViewDiff) DiffType() DiffType {
+	if vd == nil {
+		return DiffTypeNone
+	}
+	return vd.Type
+}
+
+// Statement returns the full DDL statement corresponding to the ViewDiff. A
+// blank string may be returned if the mods indicate the statement should be
+// skipped. If the mods indicate the statement should be disallowed, it will
+// still be returned as-is, but the error will be non-nil. Be sure not to
+// ignore the error value of this method.
+func (vd *ViewDiff) Statement(mods StatementModifiers) (string, error) {
+	if vd == nil {
+		return "", nil
+	}
+	switch vd.Type {
+	case DiffTypeCreate:
+		return vd.To.CreateStatement, nil
+	case DiffTypeDrop:
+		var err error
+		if !mods.AllowUnsafe {
+			err = &UnsafeDiffError{
+				Reason: "Desired drop of " + vd.ObjectKey().String() + " is risky, since you must first ensure that it is not used in any application queries, or referenced by other views or routines.",
+			}
+		}
+		return vd.From.DropStatement(), err
+	case DiffTypeAlter:
+		// Both MySQL and MariaDB support CREATE OR REPLACE VIEW, which atomically
+		// swaps in the new definition
+		return strings.Replace(vd.To.CreateStatement, "CREATE ", "CREATE OR REPLACE ", 1), nil
+	}
+	// DiffTypeRename not used, no equivalent syntax
+	return "", fmt.Errorf("Unsupported diff type %d", vd.DiffType())
+}
+
+// compareViews returns the diffs needed to turn the views in from into the
+// views in to. Drops are returned prior to creates and alters. Creates and
+// alters are ordered such that any view referenced by another view's SELECT
+// comes first, and are otherwise sorted by name.
+func compareViews(from, to *Schema) (viewDiffs []*ViewDiff) {
+	fromByName := from.ViewsByName()
+	toByName := to.ViewsByName()
+	var pending []*ViewDiff
+	for name, fromView := range fromByName {
+		if _, stillExists := toByName[name]; !stillExists {
+			viewDiffs = append(viewDiffs, &ViewDiff{Type: DiffTypeDrop, From: fromView})
+		}
+	}
+	for name, toView := range toByName {
+		if fromView, alreadyExists := fromByName[name]; !alreadyExists {
+			pending = append(pending, &ViewDiff{Type: DiffTypeCreate, To: toView})
+		} else if !fromView.Equals(toView) {
+			pending = append(pending, &ViewDiff{Type: DiffTypeAlter, From: fromView, To: toView})
+		}
+	}
+	byName := func(a, b *ViewDiff) int {
+		return strings.Compare(a.ObjectKey().Name, b.ObjectKey().Name)
+	}
+	slices.SortFunc(viewDiffs, byName)
+	slices.SortFunc(pending, byName)
+
+	// Repeatedly emit the first pending diff which does not reference any other
+	// pending diff's view. If none qualify (which should only be possible with
+	// false-positive references, since views cannot be circular), fall back to
+	// emitting the first one by name.
+	for len(pending) > 0 {
+		next := 0
+		for n, vd := range pending {
+			if !slices.ContainsFunc(pending, func(other *ViewDiff) bool {
+				return other != vd && vd.To.referencesView(other.To.Name)
+			}) {
+				next = n
+				break
+			}
+		}
+		viewDiffs = append(viewDiffs, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+	return viewDiffs
+}
+
+// referencesView returns true if the view's SELECT appears to refer to a table
+// or view with the supplied name in the same schema. This may return false
+// positives, for example if a column alias or other schema's table has the
+// same name.
+func (view *View) referencesView(name string) bool {
+	return strings.Contains(view.Select, EscapeIdentifier(name))
+}
+
+///// Introspection logic //////////////////////////////////////////////////////
+
+func querySchemaViews(ctx context.Context, db *sqlx.DB, schema string, failures *objectFailures) ([]*View, error) {
+	var rawViews []struct {
+		Name    string `db:"table_name"`
+		Definer string `db:"definer"`
+	}
+	// The view's SELECT is not queried from information_schema here, since
+	// view_definition lacks the algorithm and column list; SHOW CREATE VIEW is
+	// used instead.
+	query := `
+		SELECT SQL_BUFFER_RESULT
+		       v.table_name AS table_name, v.definer AS definer
+		FROM   information_schema.views v
+		WHERE  v.table_schema = ?`
+	if err := db.SelectContext(ctx, &rawViews, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.views for schema %s: %s", schema, err)
+	}
+	views := make([]*View, len(rawViews))
+	for n, rawView := range rawViews {
+		views[n] = &View{
+			Name:    rawView.Name,
+			Definer: Definer(rawView.Definer),
+		}
+	}
+
+	g, subCtx := errgroup.WithContext(ctx)
+	for _, view := range views {
+		g.Go(func() error {
+			var row struct {
+				CreateStatement string `db:"Create View"`
+			}
+			query := "SHOW CREATE VIEW " + EscapeIdentifier(view.Name)
+			if err := db.GetContext(subCtx, &row, query); err != nil {
+				failures.add(view.ObjectKey(), fmt.Errorf("Error executing SHOW CREATE VIEW for %s.%s: %w", EscapeIdentifier(schema), EscapeIdentifier(view.Name), err))
+			} else if err := view.parseCreateStatement(strings.ReplaceAll(row.CreateStatement, "\r\n", "\n"), schema); err != nil {
+				failures.add(view.ObjectKey(), err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return withoutFailures(views, failures), nil
+}
@@ -0,0 +1,222 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func aView(name string) View {
+	view := View{
+		Name:         name,
+		Algorithm:    "UNDEFINED",
+		Definer:      "root@%",
+		SecurityType: "DEFINER",
+		Select:       "select `actor`.`actor_id` AS `actor_id`,`actor`.`last_name` AS `last_name` from `actor` where (`actor`.`last_name` <> 'it''s')",
+	}
+	view.CreateStatement = view.Definition()
+	return view
+}
+
+func TestViewParseCreateStatement(t *testing.T) {
+	expected := aView("actor_names")
+	if expected.CreateStatement != "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `actor_names` AS "+expected.Select {
+		t.Errorf("Unexpected result from Definition: %s", expected.CreateStatement)
+	}
+	qualifiedSelect := strings.ReplaceAll(expected.Select, "`actor`", "`testing`.`actor`")
+	cases := []struct {
+		input       string
+		algorithm   string
+		columnList  string
+		checkOption string
+	}{
+		{"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `actor_names` AS " + qualifiedSelect, "UNDEFINED", "", ""},
+		{"CREATE ALGORITHM=MERGE DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `actor_names` AS " + qualifiedSelect + " WITH CASCADED CHECK OPTION", "MERGE", "", "CASCADED"},
+		{"CREATE ALGORITHM=TEMPTABLE DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `actor_names` AS " + qualifiedSelect + " WITH LOCAL CHECK OPTION", "TEMPTABLE", "", "LOCAL"},
+		{"CREATE ALGORITHM=MERGE DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `actor_names` (`id`,`name`) AS " + qualifiedSelect + " WITH LOCAL CHECK OPTION", "MERGE", "`id`,`name`", "LOCAL"},
+	}
+	for _, c := range cases {
+		view := View{Name: "actor_names", Definer: "root@%"}
+		if err := view.parseCreateStatement(c.input, "testing"); err != nil {
+			t.Errorf("Unexpected error parsing %q: %v", c.input, err)
+			continue
+		}
+		expected.Algorithm, expected.ColumnList, expected.CheckOption = c.algorithm, c.columnList, c.checkOption
+		expected.CreateStatement = expected.Definition()
+		if !view.Equals(&expected) {
+			t.Errorf("Unexpected result from parsing %q:\nexpected %+v\nfound    %+v", c.input, expected, view)
+		}
+	}
+	view := View{Name: "actor_names"}
+	if err := view.parseCreateStatement("CREATE TABLE `actor_names` (\n)", "testing"); err == nil {
+		t.Error("Expected error parsing invalid input, but err was nil")
+	}
+}
+
+func TestNormalizeViewSelect(t *testing.T) {
+	cases := map[string]string{
+		"select `testing`.`actor`.`actor_id` AS `actor_id` from `testing`.`actor`":          "select `actor`.`actor_id` AS `actor_id` from `actor`",
+		"select `other`.`actor`.`actor_id` AS `actor_id` from `other`.`actor`":              "select `other`.`actor`.`actor_id` AS `actor_id` from `other`.`actor`",
+		"select 'it''s `testing`.' AS `a`,\"`testing`.\" AS `b` from `testing`.`t`":         "select 'it''s `testing`.' AS `a`,\"`testing`.\" AS `b` from `t`",
+		"select 'back\\'slash `testing`.' AS `a` from `testing`.`testing`":                  "select 'back\\'slash `testing`.' AS `a` from `testing`",
+		"select `testing`.`testing`.`testing` AS `testing` from `testing`.`testing`":        "select `testing`.`testing` AS `testing` from `testing`",
+		"select `test``ing`.`a` AS `a` from `test``ing`":                                    "select `test``ing`.`a` AS `a` from `test``ing`",
+		"select `testing`.`actor`.`actor_id` AS `actor_id` from (`testing`.`actor` join x)": "select `actor`.`actor_id` AS `actor_id` from (`actor` join x)",
+	}
+	for input, expected := range cases {
+		if actual := normalizeViewSelect(input, "testing"); actual != expected {
+			t.Errorf("Unexpected result from normalizeViewSelect(%q):\nexpected %q\nfound    %q", input, expected, actual)
+		}
+	}
+}
+
+func TestViewDiff(t *testing.T) {
+	from, to := aView("actor_names"), aView("actor_names")
+	fromSchema := &Schema{Name: "s1", Views: []*View{&from}}
+	toSchema := &Schema{Name: "s1", Views: []*View{&to}}
+	if sd := fromSchema.Diff(toSchema); len(sd.ViewDiffs) != 0 {
+		t.Errorf("Expected no diffs between identical views, instead found %d", len(sd.ViewDiffs))
+	}
+
+	// Create and drop
+	sd := NewSchemaDiff(&Schema{Name: "s1"}, toSchema)
+	if len(sd.ViewDiffs) != 1 || sd.ViewDiffs[0].DiffType() != DiffTypeCreate {
+		t.Fatalf("Expected 1 ViewDiff of type CREATE, instead found %+v", sd.ViewDiffs)
+	}
+	if stmt, err := sd.ViewDiffs[0].Statement(StatementModifiers{}); stmt != to.CreateStatement || err != nil {
+		t.Errorf("Unexpected return from Statement: %s / %v", stmt, err)
+	}
+	sd = NewSchemaDiff(fromSchema, &Schema{Name: "s1"})
+	if len(sd.ViewDiffs) != 1 || sd.ViewDiffs[0].DiffType() != DiffTypeDrop {
+		t.Fatalf("Expected 1 ViewDiff of type DROP, instead found %+v", sd.ViewDiffs)
+	}
+	if stmt, err := sd.ViewDiffs[0].Statement(StatementModifiers{}); stmt != "DROP VIEW `actor_names`" || !IsUnsafeDiff(err) {
+		t.Errorf("Unexpected return from Statement: %s / %v", stmt, err)
+	}
+	if stmt, err := sd.ViewDiffs[0].Statement(StatementModifiers{AllowUnsafe: true}); stmt == "" || err != nil {
+		t.Errorf("Unexpected return from Statement: %s / %v", stmt, err)
+	}
+
+	// Modifications to the algorithm, check option, or SELECT all use CREATE OR
+	// REPLACE, which is safe in all flavors
+	modifiers := []func(*View){
+		func(v *View) { v.Algorithm = "MERGE" },
+		func(v *View) { v.Algorithm = "TEMPTABLE" },
+		func(v *View) { v.CheckOption = "CASCADED" },
+		func(v *View) { v.CheckOption = "LOCAL" },
+		func(v *View) { v.SecurityType = "INVOKER" },
+		func(v *View) { v.Definer = "app@%" },
+		func(v *View) { v.Select = strings.Replace(v.Select, "<>", "=", 1) },
+	}
+	for _, modifier := range modifiers {
+		to = aView("actor_names")
+		modifier(&to)
+		to.CreateStatement = to.Definition()
+		sd = NewSchemaDiff(fromSchema, toSchema)
+		if len(sd.ViewDiffs) != 1 || sd.ViewDiffs[0].DiffType() != DiffTypeAlter {
+			t.Fatalf("Expected 1 ViewDiff of type ALTER, instead found %+v", sd.ViewDiffs)
+		}
+		for _, flavor := range []string{"mysql:5.7", "mysql:8.0", "mariadb:10.6"} {
+			mods := StatementModifiers{Flavor: ParseFlavor(flavor)}
+			if stmt, err := sd.ViewDiffs[0].Statement(mods); err != nil || stmt != "CREATE OR REPLACE "+strings.TrimPrefix(to.CreateStatement, "CREATE ") {
+				t.Errorf("Unexpected return from Statement: %s / %v", stmt, err)
+			}
+		}
+	}
+
+	// Drops sorted before creates and alters, each by name
+	other, another := aView("b_view"), aView("a_view")
+	fromSchema.Views = []*View{&from, &other}
+	toSchema.Views = []*View{&to, &another}
+	sd = NewSchemaDiff(fromSchema, toSchema)
+	var keys []string
+	for _, vd := range sd.ViewDiffs {
+		keys = append(keys, vd.DiffType().String()+" "+vd.ObjectKey().Name)
+	}
+	if actual := strings.Join(keys, ","); actual != "DROP b_view,CREATE a_view,ALTER actor_names" {
+		t.Errorf("Unexpected diff order: %s", actual)
+	}
+
+	// Views referenced by other views are created first
+	outer, middle, inner := aView("a_outer"), aView("b_middle"), aView("c_inner")
+	outer.Select = "select `b_middle`.`actor_id` AS `actor_id` from `b_middle`"
+	middle.Select = "select `c_inner`.`actor_id` AS `actor_id` from `c_inner`"
+	toSchema.Views = []*View{&outer, &middle, &inner, &to}
+	sd = NewSchemaDiff(&Schema{Name: "s1"}, toSchema)
+	keys = nil
+	for _, vd := range sd.ViewDiffs {
+		keys = append(keys, vd.ObjectKey().Name)
+	}
+	if actual := strings.Join(keys, ","); actual != "actor_names,c_inner,b_middle,a_outer" {
+		t.Errorf("Unexpected diff order: %s", actual)
+	}
+}
+
+func (s TengoIntegrationSuite) TestViewIntrospection(t *testing.T) {
+	flavor := s.d.Flavor()
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	creates := []string{
+		"CREATE VIEW plain AS SELECT actor_id, last_name FROM actor WHERE last_name <> 'it''s'",
+		"CREATE ALGORITHM=MERGE SQL SECURITY INVOKER VIEW merged AS SELECT a.actor_id FROM testing.actor a WHERE a.actor_id > 10 WITH LOCAL CHECK OPTION",
+		"CREATE ALGORITHM=TEMPTABLE VIEW temp (id, name) AS SELECT actor_id, first_name FROM actor",
+		"CREATE VIEW checked AS SELECT actor_id, first_name FROM actor WHERE actor_id < 100 WITH CASCADED CHECK OPTION",
+	}
+	for _, create := range creates {
+		if _, err := db.Exec(create); err != nil {
+			t.Fatalf("Unexpected error creating view: %v", err)
+		}
+	}
+
+	// Views are only introspected if explicitly enabled
+	if schema := s.GetSchema(t, "testing"); len(schema.Views) != 0 {
+		t.Errorf("Expected views to not be introspected by default, instead found %d", len(schema.Views))
+	}
+	s.d.SetViewIntrospection(true)
+	defer s.d.SetViewIntrospection(false)
+	schema := s.GetSchema(t, "testing")
+	if len(schema.Views) != len(creates) {
+		t.Fatalf("Expected schema to have %d views, instead found %d", len(creates), len(schema.Views))
+	}
+	if schema.HasTable("plain") {
+		t.Error("Expected views to be excluded from schema tables")
+	}
+	views := schema.ViewsByName()
+	if v := views["merged"]; v.Algorithm != "MERGE" || v.SecurityType != "INVOKER" || v.CheckOption != "LOCAL" || strings.Contains(v.Select, "`testing`.") || v.Definer == "" {
+		t.Errorf("Unexpected introspection of view merged: %+v", *v)
+	}
+	if v := views["temp"]; v.Algorithm != "TEMPTABLE" || v.CheckOption != "" {
+		t.Errorf("Unexpected introspection of view temp: %+v", *v)
+	}
+	if v := views["checked"]; v.Algorithm != "UNDEFINED" || v.CheckOption != "CASCADED" {
+		t.Errorf("Unexpected introspection of view checked: %+v", *v)
+	}
+	if v := views["plain"]; v.Algorithm != "UNDEFINED" || v.CheckOption != "" || !strings.Contains(v.Select, "<>") {
+		t.Errorf("Unexpected introspection of view plain: %+v", *v)
+	}
+
+	// Confirm that views introspected from a different schema are considered
+	// identical, despite SHOW CREATE VIEW qualifying table references with the
+	// schema name
+	if _, err := db.Exec("CREATE DATABASE testing_views"); err != nil {
+		t.Fatalf("Unexpected error creating database: %v", err)
+	}
+	defer db.Exec("DROP DATABASE testing_views")
+	otherDB, err := s.d.CachedConnectionPool("testing_views", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	if _, err := otherDB.Exec("CREATE TABLE actor LIKE testing.actor"); err != nil {
+		t.Fatalf("Unexpected error creating table: %v", err)
+	}
+	for _, vd := range NewSchemaDiff(&Schema{Name: "testing_views"}, schema).ViewDiffs {
+		stmt, _ := vd.Statement(StatementModifiers{Flavor: flavor})
+		if _, err := otherDB.Exec(stmt); err != nil {
+			t.Fatalf("Unexpected error executing %q: %v", stmt, err)
+		}
+	}
+	if sd := NewSchemaDiff(schema, s.GetSchema(t, "testing_views")); len(sd.ViewDiffs) > 0 {
+		t.Errorf("Expected no view differences between schemas, instead found %+v", sd.ViewDiffs)
+	}
+}
@@ -1645,8 +1645,9 @@ func (s SkeemaIntegrationSuite) TestPartitioning(t *testing.T) {
 	fs.WriteTestFile(t, "mydb/analytics/activity.sql", contents2Part)
 	s.handleCommand(t, CodeSuccess, "mydb/analytics", "skeema diff --partitioning=remove")
 	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --partitioning=keep")
-	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff") // default is keep
-	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --partitioning=modify")
+	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff")                 // default is keep
+	s.handleCommand(t, CodeFatalError, "mydb/analytics", "skeema diff --partitioning=modify") // repartitioning is unsafe
+	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --allow-unsafe --partitioning=modify")
 	s.handleCommand(t, CodeBadConfig, "mydb/analytics", "skeema diff --partitioning=invalid")
 
 	// At this point we haven't pushed yet, but pull should leave the file
@@ -1713,7 +1714,8 @@ func (s SkeemaIntegrationSuite) TestPartitioning(t *testing.T) {
 	fs.WriteTestFile(t, "mydb/analytics/activity.sql", contentsNoPart)
 	s.handleCommand(t, CodeSuccess, "mydb/analytics", "skeema diff --partitioning=keep")
 	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --partitioning=remove")
-	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --partitioning=modify")
+	s.handleCommand(t, CodeFatalError, "mydb/analytics", "skeema diff --partitioning=modify") // repartitioning is unsafe
+	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --allow-unsafe --partitioning=modify")
 	// Note: didn't push the above change
 
 	// Rewrite activity.sql to have 3 partitions, still by range, as well as a new
@@ -1737,7 +1739,8 @@ func (s SkeemaIntegrationSuite) TestPartitioning(t *testing.T) {
 	// keep, repartition with modify, or departition with remove.
 	fs.WriteTestFile(t, "mydb/analytics/activity.sql", contentsHashPart)
 	s.handleCommand(t, CodeSuccess, "mydb/analytics", "skeema diff --partitioning=keep")
-	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --partitioning=modify")
+	s.handleCommand(t, CodeFatalError, "mydb/analytics", "skeema diff --partitioning=modify") // repartitioning is unsafe
+	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --allow-unsafe --partitioning=modify")
 	s.handleCommand(t, CodeDifferencesFound, "mydb/analytics", "skeema diff --partitioning=remove")
 	// Note: didn't push the above change yet
 